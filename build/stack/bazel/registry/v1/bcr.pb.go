@@ -29,6 +29,9 @@ const (
 	RepositoryType_REPOSITORY_TYPE_UNKNOWN RepositoryType = 0
 	RepositoryType_GITHUB                  RepositoryType = 1
 	RepositoryType_GITLAB                  RepositoryType = 2
+	RepositoryType_GITEA                   RepositoryType = 3
+	RepositoryType_BITBUCKET               RepositoryType = 4
+	RepositoryType_SOURCEHUT               RepositoryType = 5
 )
 
 // Enum value maps for RepositoryType.
@@ -37,11 +40,17 @@ var (
 		0: "REPOSITORY_TYPE_UNKNOWN",
 		1: "GITHUB",
 		2: "GITLAB",
+		3: "GITEA",
+		4: "BITBUCKET",
+		5: "SOURCEHUT",
 	}
 	RepositoryType_value = map[string]int32{
 		"REPOSITORY_TYPE_UNKNOWN": 0,
 		"GITHUB":                  1,
 		"GITLAB":                  2,
+		"GITEA":                   3,
+		"BITBUCKET":               4,
+		"SOURCEHUT":               5,
 	}
 )
 
@@ -72,6 +81,363 @@ func (RepositoryType) EnumDescriptor() ([]byte, []int) {
 	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{0}
 }
 
+type CacheEntryType int32
+
+const (
+	CacheEntryType_CACHE_ENTRY_TYPE_UNKNOWN        CacheEntryType = 0
+	CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS     CacheEntryType = 1
+	CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA CacheEntryType = 2
+	CacheEntryType_CACHE_ENTRY_BAZEL_RELEASE       CacheEntryType = 3
+	CacheEntryType_CACHE_ENTRY_SOURCE_COMMIT       CacheEntryType = 4
+)
+
+// Enum value maps for CacheEntryType.
+var (
+	CacheEntryType_name = map[int32]string{
+		0: "CACHE_ENTRY_TYPE_UNKNOWN",
+		1: "CACHE_ENTRY_RESOURCE_STATUS",
+		2: "CACHE_ENTRY_REPOSITORY_METADATA",
+		3: "CACHE_ENTRY_BAZEL_RELEASE",
+		4: "CACHE_ENTRY_SOURCE_COMMIT",
+	}
+	CacheEntryType_value = map[string]int32{
+		"CACHE_ENTRY_TYPE_UNKNOWN":        0,
+		"CACHE_ENTRY_RESOURCE_STATUS":     1,
+		"CACHE_ENTRY_REPOSITORY_METADATA": 2,
+		"CACHE_ENTRY_BAZEL_RELEASE":       3,
+		"CACHE_ENTRY_SOURCE_COMMIT":       4,
+	}
+)
+
+func (x CacheEntryType) Enum() *CacheEntryType {
+	p := new(CacheEntryType)
+	*p = x
+	return p
+}
+
+func (x CacheEntryType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CacheEntryType) Descriptor() protoreflect.EnumDescriptor {
+	return file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[1].Descriptor()
+}
+
+func (CacheEntryType) Type() protoreflect.EnumType {
+	return &file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[1]
+}
+
+func (x CacheEntryType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CacheEntryType.Descriptor instead.
+func (CacheEntryType) EnumDescriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{1}
+}
+
+type ArchiveVerificationStatus int32
+
+const (
+	ArchiveVerificationStatus_ARCHIVE_VERIFICATION_STATUS_UNKNOWN ArchiveVerificationStatus = 0
+	ArchiveVerificationStatus_ARCHIVE_VERIFIED                    ArchiveVerificationStatus = 1
+	ArchiveVerificationStatus_ARCHIVE_INTEGRITY_MISMATCH          ArchiveVerificationStatus = 2
+	ArchiveVerificationStatus_ARCHIVE_STRIP_PREFIX_MISSING        ArchiveVerificationStatus = 3
+	ArchiveVerificationStatus_ARCHIVE_UNAVAILABLE                 ArchiveVerificationStatus = 4
+	ArchiveVerificationStatus_ARCHIVE_MALFORMED_INTEGRITY         ArchiveVerificationStatus = 5
+)
+
+// Enum value maps for ArchiveVerificationStatus.
+var (
+	ArchiveVerificationStatus_name = map[int32]string{
+		0: "ARCHIVE_VERIFICATION_STATUS_UNKNOWN",
+		1: "ARCHIVE_VERIFIED",
+		2: "ARCHIVE_INTEGRITY_MISMATCH",
+		3: "ARCHIVE_STRIP_PREFIX_MISSING",
+		4: "ARCHIVE_UNAVAILABLE",
+		5: "ARCHIVE_MALFORMED_INTEGRITY",
+	}
+	ArchiveVerificationStatus_value = map[string]int32{
+		"ARCHIVE_VERIFICATION_STATUS_UNKNOWN": 0,
+		"ARCHIVE_VERIFIED":                    1,
+		"ARCHIVE_INTEGRITY_MISMATCH":          2,
+		"ARCHIVE_STRIP_PREFIX_MISSING":        3,
+		"ARCHIVE_UNAVAILABLE":                 4,
+		"ARCHIVE_MALFORMED_INTEGRITY":         5,
+	}
+)
+
+func (x ArchiveVerificationStatus) Enum() *ArchiveVerificationStatus {
+	p := new(ArchiveVerificationStatus)
+	*p = x
+	return p
+}
+
+func (x ArchiveVerificationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ArchiveVerificationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[2].Descriptor()
+}
+
+func (ArchiveVerificationStatus) Type() protoreflect.EnumType {
+	return &file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[2]
+}
+
+func (x ArchiveVerificationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ArchiveVerificationStatus.Descriptor instead.
+func (ArchiveVerificationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{2}
+}
+
+type FileIntegrityStatus int32
+
+const (
+	FileIntegrityStatus_FILE_INTEGRITY_STATUS_UNKNOWN FileIntegrityStatus = 0
+	FileIntegrityStatus_FILE_INTEGRITY_VERIFIED       FileIntegrityStatus = 1
+	FileIntegrityStatus_FILE_INTEGRITY_MISMATCH       FileIntegrityStatus = 2
+	FileIntegrityStatus_FILE_INTEGRITY_FILE_MISSING   FileIntegrityStatus = 3
+	FileIntegrityStatus_FILE_INTEGRITY_MALFORMED      FileIntegrityStatus = 4
+)
+
+// Enum value maps for FileIntegrityStatus.
+var (
+	FileIntegrityStatus_name = map[int32]string{
+		0: "FILE_INTEGRITY_STATUS_UNKNOWN",
+		1: "FILE_INTEGRITY_VERIFIED",
+		2: "FILE_INTEGRITY_MISMATCH",
+		3: "FILE_INTEGRITY_FILE_MISSING",
+		4: "FILE_INTEGRITY_MALFORMED",
+	}
+	FileIntegrityStatus_value = map[string]int32{
+		"FILE_INTEGRITY_STATUS_UNKNOWN": 0,
+		"FILE_INTEGRITY_VERIFIED":       1,
+		"FILE_INTEGRITY_MISMATCH":       2,
+		"FILE_INTEGRITY_FILE_MISSING":   3,
+		"FILE_INTEGRITY_MALFORMED":      4,
+	}
+)
+
+func (x FileIntegrityStatus) Enum() *FileIntegrityStatus {
+	p := new(FileIntegrityStatus)
+	*p = x
+	return p
+}
+
+func (x FileIntegrityStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FileIntegrityStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[3].Descriptor()
+}
+
+func (FileIntegrityStatus) Type() protoreflect.EnumType {
+	return &file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[3]
+}
+
+func (x FileIntegrityStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FileIntegrityStatus.Descriptor instead.
+func (FileIntegrityStatus) EnumDescriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{3}
+}
+
+type AttestationVerificationStatus int32
+
+const (
+	AttestationVerificationStatus_ATTESTATION_VERIFICATION_STATUS_UNKNOWN AttestationVerificationStatus = 0
+	AttestationVerificationStatus_ATTESTATION_VERIFIED                    AttestationVerificationStatus = 1
+	AttestationVerificationStatus_ATTESTATION_UNAVAILABLE                 AttestationVerificationStatus = 2
+	AttestationVerificationStatus_ATTESTATION_INTEGRITY_MISMATCH          AttestationVerificationStatus = 3
+	AttestationVerificationStatus_ATTESTATION_MALFORMED_INTEGRITY         AttestationVerificationStatus = 4
+	AttestationVerificationStatus_ATTESTATION_MALFORMED_BUNDLE            AttestationVerificationStatus = 5
+	AttestationVerificationStatus_ATTESTATION_SIGNATURE_INVALID           AttestationVerificationStatus = 6
+	AttestationVerificationStatus_ATTESTATION_SUBJECT_MISMATCH            AttestationVerificationStatus = 7
+	AttestationVerificationStatus_ATTESTATION_SUBJECT_UNAVAILABLE         AttestationVerificationStatus = 8
+	AttestationVerificationStatus_ATTESTATION_IDENTITY_MISMATCH           AttestationVerificationStatus = 9
+	AttestationVerificationStatus_ATTESTATION_IDENTITY_UNCHECKED          AttestationVerificationStatus = 10
+)
+
+// Enum value maps for AttestationVerificationStatus.
+var (
+	AttestationVerificationStatus_name = map[int32]string{
+		0:  "ATTESTATION_VERIFICATION_STATUS_UNKNOWN",
+		1:  "ATTESTATION_VERIFIED",
+		2:  "ATTESTATION_UNAVAILABLE",
+		3:  "ATTESTATION_INTEGRITY_MISMATCH",
+		4:  "ATTESTATION_MALFORMED_INTEGRITY",
+		5:  "ATTESTATION_MALFORMED_BUNDLE",
+		6:  "ATTESTATION_SIGNATURE_INVALID",
+		7:  "ATTESTATION_SUBJECT_MISMATCH",
+		8:  "ATTESTATION_SUBJECT_UNAVAILABLE",
+		9:  "ATTESTATION_IDENTITY_MISMATCH",
+		10: "ATTESTATION_IDENTITY_UNCHECKED",
+	}
+	AttestationVerificationStatus_value = map[string]int32{
+		"ATTESTATION_VERIFICATION_STATUS_UNKNOWN": 0,
+		"ATTESTATION_VERIFIED":                    1,
+		"ATTESTATION_UNAVAILABLE":                 2,
+		"ATTESTATION_INTEGRITY_MISMATCH":          3,
+		"ATTESTATION_MALFORMED_INTEGRITY":         4,
+		"ATTESTATION_MALFORMED_BUNDLE":            5,
+		"ATTESTATION_SIGNATURE_INVALID":           6,
+		"ATTESTATION_SUBJECT_MISMATCH":            7,
+		"ATTESTATION_SUBJECT_UNAVAILABLE":         8,
+		"ATTESTATION_IDENTITY_MISMATCH":           9,
+		"ATTESTATION_IDENTITY_UNCHECKED":          10,
+	}
+)
+
+func (x AttestationVerificationStatus) Enum() *AttestationVerificationStatus {
+	p := new(AttestationVerificationStatus)
+	*p = x
+	return p
+}
+
+func (x AttestationVerificationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AttestationVerificationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[4].Descriptor()
+}
+
+func (AttestationVerificationStatus) Type() protoreflect.EnumType {
+	return &file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[4]
+}
+
+func (x AttestationVerificationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AttestationVerificationStatus.Descriptor instead.
+func (AttestationVerificationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{4}
+}
+
+type DiagnosticSeverity int32
+
+const (
+	DiagnosticSeverity_DIAGNOSTIC_SEVERITY_UNKNOWN DiagnosticSeverity = 0
+	DiagnosticSeverity_WARNING                     DiagnosticSeverity = 1
+	DiagnosticSeverity_ERROR                       DiagnosticSeverity = 2
+)
+
+// Enum value maps for DiagnosticSeverity.
+var (
+	DiagnosticSeverity_name = map[int32]string{
+		0: "DIAGNOSTIC_SEVERITY_UNKNOWN",
+		1: "WARNING",
+		2: "ERROR",
+	}
+	DiagnosticSeverity_value = map[string]int32{
+		"DIAGNOSTIC_SEVERITY_UNKNOWN": 0,
+		"WARNING":                     1,
+		"ERROR":                       2,
+	}
+)
+
+func (x DiagnosticSeverity) Enum() *DiagnosticSeverity {
+	p := new(DiagnosticSeverity)
+	*p = x
+	return p
+}
+
+func (x DiagnosticSeverity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiagnosticSeverity) Descriptor() protoreflect.EnumDescriptor {
+	return file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[5].Descriptor()
+}
+
+func (DiagnosticSeverity) Type() protoreflect.EnumType {
+	return &file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[5]
+}
+
+func (x DiagnosticSeverity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiagnosticSeverity.Descriptor instead.
+func (DiagnosticSeverity) EnumDescriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{5}
+}
+
+type CacheMissKind int32
+
+const (
+	CacheMissKind_CACHE_MISS_KIND_UNKNOWN        CacheMissKind = 0
+	CacheMissKind_CACHE_MISS_BACKUP_REGISTRY     CacheMissKind = 1
+	CacheMissKind_CACHE_MISS_BAZEL_RELEASES      CacheMissKind = 2
+	CacheMissKind_CACHE_MISS_REPOSITORY_METADATA CacheMissKind = 3
+	CacheMissKind_CACHE_MISS_DOCS_URL_STATUS     CacheMissKind = 4
+	CacheMissKind_CACHE_MISS_SOURCE_URL_STATUS   CacheMissKind = 5
+	CacheMissKind_CACHE_MISS_SOURCE_COMMIT_SHA   CacheMissKind = 6
+	CacheMissKind_CACHE_MISS_SOURCE_ARCHIVE      CacheMissKind = 7
+	CacheMissKind_CACHE_MISS_ATTESTATION_BUNDLE  CacheMissKind = 8
+)
+
+// Enum value maps for CacheMissKind.
+var (
+	CacheMissKind_name = map[int32]string{
+		0: "CACHE_MISS_KIND_UNKNOWN",
+		1: "CACHE_MISS_BACKUP_REGISTRY",
+		2: "CACHE_MISS_BAZEL_RELEASES",
+		3: "CACHE_MISS_REPOSITORY_METADATA",
+		4: "CACHE_MISS_DOCS_URL_STATUS",
+		5: "CACHE_MISS_SOURCE_URL_STATUS",
+		6: "CACHE_MISS_SOURCE_COMMIT_SHA",
+		7: "CACHE_MISS_SOURCE_ARCHIVE",
+		8: "CACHE_MISS_ATTESTATION_BUNDLE",
+	}
+	CacheMissKind_value = map[string]int32{
+		"CACHE_MISS_KIND_UNKNOWN":        0,
+		"CACHE_MISS_BACKUP_REGISTRY":     1,
+		"CACHE_MISS_BAZEL_RELEASES":      2,
+		"CACHE_MISS_REPOSITORY_METADATA": 3,
+		"CACHE_MISS_DOCS_URL_STATUS":     4,
+		"CACHE_MISS_SOURCE_URL_STATUS":   5,
+		"CACHE_MISS_SOURCE_COMMIT_SHA":   6,
+		"CACHE_MISS_SOURCE_ARCHIVE":      7,
+		"CACHE_MISS_ATTESTATION_BUNDLE":  8,
+	}
+)
+
+func (x CacheMissKind) Enum() *CacheMissKind {
+	p := new(CacheMissKind)
+	*p = x
+	return p
+}
+
+func (x CacheMissKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CacheMissKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[6].Descriptor()
+}
+
+func (CacheMissKind) Type() protoreflect.EnumType {
+	return &file_build_stack_bazel_registry_v1_bcr_proto_enumTypes[6]
+}
+
+func (x CacheMissKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CacheMissKind.Descriptor instead.
+func (CacheMissKind) EnumDescriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{6}
+}
+
 type Registry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Modules       []*Module              `protobuf:"bytes,1,rep,name=modules,proto3" json:"modules,omitempty"`
@@ -165,13 +531,16 @@ func (x *Registry) GetCommitDate() string {
 }
 
 type Module struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	Name               string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Metadata           *ModuleMetadata        `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	Versions           []*ModuleVersion       `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty"`
-	RepositoryMetadata *RepositoryMetadata    `protobuf:"bytes,4,opt,name=repository_metadata,json=repositoryMetadata,proto3" json:"repository_metadata,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	state                   protoimpl.MessageState   `protogen:"open.v1"`
+	Name                    string                   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata                *ModuleMetadata          `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Versions                []*ModuleVersion         `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty"`
+	RepositoryMetadata      *RepositoryMetadata      `protobuf:"bytes,4,opt,name=repository_metadata,json=repositoryMetadata,proto3" json:"repository_metadata,omitempty"`
+	ReverseDependencyCounts *ReverseDependencyCounts `protobuf:"bytes,5,opt,name=reverse_dependency_counts,json=reverseDependencyCounts,proto3" json:"reverse_dependency_counts,omitempty"`
+	Registry                string                   `protobuf:"bytes,6,opt,name=registry,proto3" json:"registry,omitempty"`
+	Health                  *ModuleHealth            `protobuf:"bytes,7,opt,name=health,proto3" json:"health,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
 func (x *Module) Reset() {
@@ -232,31 +601,50 @@ func (x *Module) GetRepositoryMetadata() *RepositoryMetadata {
 	return nil
 }
 
-type Maintainer struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Github        string                 `protobuf:"bytes,3,opt,name=github,proto3" json:"github,omitempty"`
-	DoNotNotify   bool                   `protobuf:"varint,4,opt,name=do_not_notify,json=doNotNotify,proto3" json:"do_not_notify,omitempty"`
-	GithubUserId  int32                  `protobuf:"varint,5,opt,name=github_user_id,json=githubUserId,proto3" json:"github_user_id,omitempty"`
+func (x *Module) GetReverseDependencyCounts() *ReverseDependencyCounts {
+	if x != nil {
+		return x.ReverseDependencyCounts
+	}
+	return nil
+}
+
+func (x *Module) GetRegistry() string {
+	if x != nil {
+		return x.Registry
+	}
+	return ""
+}
+
+func (x *Module) GetHealth() *ModuleHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+type ModuleHealth struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Score         int32                    `protobuf:"varint,1,opt,name=score,proto3" json:"score,omitempty"`
+	Components    []*ModuleHealthComponent `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty"`
+	ReferenceDate string                   `protobuf:"bytes,3,opt,name=reference_date,json=referenceDate,proto3" json:"reference_date,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Maintainer) Reset() {
-	*x = Maintainer{}
+func (x *ModuleHealth) Reset() {
+	*x = ModuleHealth{}
 	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Maintainer) String() string {
+func (x *ModuleHealth) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Maintainer) ProtoMessage() {}
+func (*ModuleHealth) ProtoMessage() {}
 
-func (x *Maintainer) ProtoReflect() protoreflect.Message {
+func (x *ModuleHealth) ProtoReflect() protoreflect.Message {
 	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -268,72 +656,56 @@ func (x *Maintainer) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Maintainer.ProtoReflect.Descriptor instead.
-func (*Maintainer) Descriptor() ([]byte, []int) {
+// Deprecated: Use ModuleHealth.ProtoReflect.Descriptor instead.
+func (*ModuleHealth) Descriptor() ([]byte, []int) {
 	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Maintainer) GetEmail() string {
+func (x *ModuleHealth) GetScore() int32 {
 	if x != nil {
-		return x.Email
+		return x.Score
 	}
-	return ""
+	return 0
 }
 
-func (x *Maintainer) GetName() string {
+func (x *ModuleHealth) GetComponents() []*ModuleHealthComponent {
 	if x != nil {
-		return x.Name
+		return x.Components
 	}
-	return ""
+	return nil
 }
 
-func (x *Maintainer) GetGithub() string {
+func (x *ModuleHealth) GetReferenceDate() string {
 	if x != nil {
-		return x.Github
+		return x.ReferenceDate
 	}
 	return ""
 }
 
-func (x *Maintainer) GetDoNotNotify() bool {
-	if x != nil {
-		return x.DoNotNotify
-	}
-	return false
-}
-
-func (x *Maintainer) GetGithubUserId() int32 {
-	if x != nil {
-		return x.GithubUserId
-	}
-	return 0
-}
-
-type ModuleMetadata struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Homepage       string                 `protobuf:"bytes,1,opt,name=homepage,proto3" json:"homepage,omitempty"`
-	Maintainers    []*Maintainer          `protobuf:"bytes,2,rep,name=maintainers,proto3" json:"maintainers,omitempty"`
-	Repository     []string               `protobuf:"bytes,3,rep,name=repository,proto3" json:"repository,omitempty"`
-	Versions       []string               `protobuf:"bytes,4,rep,name=versions,proto3" json:"versions,omitempty"`
-	YankedVersions map[string]string      `protobuf:"bytes,5,rep,name=yanked_versions,json=yankedVersions,proto3" json:"yanked_versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Deprecated     string                 `protobuf:"bytes,6,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type ModuleHealthComponent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Score         int32                  `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	MaxScore      int32                  `protobuf:"varint,3,opt,name=max_score,json=maxScore,proto3" json:"max_score,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ModuleMetadata) Reset() {
-	*x = ModuleMetadata{}
+func (x *ModuleHealthComponent) Reset() {
+	*x = ModuleHealthComponent{}
 	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ModuleMetadata) String() string {
+func (x *ModuleHealthComponent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ModuleMetadata) ProtoMessage() {}
+func (*ModuleHealthComponent) ProtoMessage() {}
 
-func (x *ModuleMetadata) ProtoReflect() protoreflect.Message {
+func (x *ModuleHealthComponent) ProtoReflect() protoreflect.Message {
 	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -345,49 +717,195 @@ func (x *ModuleMetadata) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ModuleMetadata.ProtoReflect.Descriptor instead.
-func (*ModuleMetadata) Descriptor() ([]byte, []int) {
+// Deprecated: Use ModuleHealthComponent.ProtoReflect.Descriptor instead.
+func (*ModuleHealthComponent) Descriptor() ([]byte, []int) {
 	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ModuleMetadata) GetHomepage() string {
+func (x *ModuleHealthComponent) GetName() string {
 	if x != nil {
-		return x.Homepage
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ModuleMetadata) GetMaintainers() []*Maintainer {
-	if x != nil {
-		return x.Maintainers
-	}
-	return nil
-}
-
-func (x *ModuleMetadata) GetRepository() []string {
-	if x != nil {
-		return x.Repository
-	}
-	return nil
-}
-
-func (x *ModuleMetadata) GetVersions() []string {
+func (x *ModuleHealthComponent) GetScore() int32 {
 	if x != nil {
-		return x.Versions
+		return x.Score
 	}
-	return nil
+	return 0
 }
 
-func (x *ModuleMetadata) GetYankedVersions() map[string]string {
+func (x *ModuleHealthComponent) GetMaxScore() int32 {
 	if x != nil {
-		return x.YankedVersions
+		return x.MaxScore
 	}
-	return nil
+	return 0
 }
 
-func (x *ModuleMetadata) GetDeprecated() string {
+func (x *ModuleHealthComponent) GetReason() string {
 	if x != nil {
-		return x.Deprecated
+		return x.Reason
+	}
+	return ""
+}
+
+type Maintainer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Github        string                 `protobuf:"bytes,3,opt,name=github,proto3" json:"github,omitempty"`
+	DoNotNotify   bool                   `protobuf:"varint,4,opt,name=do_not_notify,json=doNotNotify,proto3" json:"do_not_notify,omitempty"`
+	GithubUserId  int32                  `protobuf:"varint,5,opt,name=github_user_id,json=githubUserId,proto3" json:"github_user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Maintainer) Reset() {
+	*x = Maintainer{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Maintainer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Maintainer) ProtoMessage() {}
+
+func (x *Maintainer) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Maintainer.ProtoReflect.Descriptor instead.
+func (*Maintainer) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Maintainer) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Maintainer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Maintainer) GetGithub() string {
+	if x != nil {
+		return x.Github
+	}
+	return ""
+}
+
+func (x *Maintainer) GetDoNotNotify() bool {
+	if x != nil {
+		return x.DoNotNotify
+	}
+	return false
+}
+
+func (x *Maintainer) GetGithubUserId() int32 {
+	if x != nil {
+		return x.GithubUserId
+	}
+	return 0
+}
+
+type ModuleMetadata struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Homepage       string                 `protobuf:"bytes,1,opt,name=homepage,proto3" json:"homepage,omitempty"`
+	Maintainers    []*Maintainer          `protobuf:"bytes,2,rep,name=maintainers,proto3" json:"maintainers,omitempty"`
+	Repository     []string               `protobuf:"bytes,3,rep,name=repository,proto3" json:"repository,omitempty"`
+	Versions       []string               `protobuf:"bytes,4,rep,name=versions,proto3" json:"versions,omitempty"`
+	YankedVersions map[string]string      `protobuf:"bytes,5,rep,name=yanked_versions,json=yankedVersions,proto3" json:"yanked_versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Deprecated     string                 `protobuf:"bytes,6,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ModuleMetadata) Reset() {
+	*x = ModuleMetadata{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModuleMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleMetadata) ProtoMessage() {}
+
+func (x *ModuleMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleMetadata.ProtoReflect.Descriptor instead.
+func (*ModuleMetadata) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ModuleMetadata) GetHomepage() string {
+	if x != nil {
+		return x.Homepage
+	}
+	return ""
+}
+
+func (x *ModuleMetadata) GetMaintainers() []*Maintainer {
+	if x != nil {
+		return x.Maintainers
+	}
+	return nil
+}
+
+func (x *ModuleMetadata) GetRepository() []string {
+	if x != nil {
+		return x.Repository
+	}
+	return nil
+}
+
+func (x *ModuleMetadata) GetVersions() []string {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *ModuleMetadata) GetYankedVersions() map[string]string {
+	if x != nil {
+		return x.YankedVersions
+	}
+	return nil
+}
+
+func (x *ModuleMetadata) GetDeprecated() string {
+	if x != nil {
+		return x.Deprecated
 	}
 	return ""
 }
@@ -402,13 +920,22 @@ type RepositoryMetadata struct {
 	Languages       map[string]int32       `protobuf:"bytes,6,rep,name=languages,proto3" json:"languages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
 	PrimaryLanguage string                 `protobuf:"bytes,7,opt,name=primary_language,json=primaryLanguage,proto3" json:"primary_language,omitempty"`
 	CanonicalName   string                 `protobuf:"bytes,8,opt,name=canonical_name,json=canonicalName,proto3" json:"canonical_name,omitempty"`
+	Host            string                 `protobuf:"bytes,9,opt,name=host,proto3" json:"host,omitempty"`
+	License         string                 `protobuf:"bytes,10,opt,name=license,proto3" json:"license,omitempty"`
+	Topics          []string               `protobuf:"bytes,11,rep,name=topics,proto3" json:"topics,omitempty"`
+	Archived        bool                   `protobuf:"varint,12,opt,name=archived,proto3" json:"archived,omitempty"`
+	Disabled        bool                   `protobuf:"varint,13,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	DefaultBranch   string                 `protobuf:"bytes,14,opt,name=default_branch,json=defaultBranch,proto3" json:"default_branch,omitempty"`
+	PushedAt        string                 `protobuf:"bytes,15,opt,name=pushed_at,json=pushedAt,proto3" json:"pushed_at,omitempty"`
+	OpenIssues      int32                  `protobuf:"varint,16,opt,name=open_issues,json=openIssues,proto3" json:"open_issues,omitempty"`
+	LatestRelease   string                 `protobuf:"bytes,17,opt,name=latest_release,json=latestRelease,proto3" json:"latest_release,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *RepositoryMetadata) Reset() {
 	*x = RepositoryMetadata{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[4]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -420,7 +947,7 @@ func (x *RepositoryMetadata) String() string {
 func (*RepositoryMetadata) ProtoMessage() {}
 
 func (x *RepositoryMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[4]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -433,7 +960,7 @@ func (x *RepositoryMetadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RepositoryMetadata.ProtoReflect.Descriptor instead.
 func (*RepositoryMetadata) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{4}
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *RepositoryMetadata) GetType() RepositoryType {
@@ -492,48 +1019,67 @@ func (x *RepositoryMetadata) GetCanonicalName() string {
 	return ""
 }
 
-type RepositoryMetadataSet struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	RepositoryMetadata []*RepositoryMetadata  `protobuf:"bytes,1,rep,name=repository_metadata,json=repositoryMetadata,proto3" json:"repository_metadata,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+func (x *RepositoryMetadata) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
 }
 
-func (x *RepositoryMetadataSet) Reset() {
-	*x = RepositoryMetadataSet{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[5]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *RepositoryMetadata) GetLicense() string {
+	if x != nil {
+		return x.License
+	}
+	return ""
 }
 
-func (x *RepositoryMetadataSet) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *RepositoryMetadata) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
 }
 
-func (*RepositoryMetadataSet) ProtoMessage() {}
+func (x *RepositoryMetadata) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
 
-func (x *RepositoryMetadataSet) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[5]
+func (x *RepositoryMetadata) GetDisabled() bool {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Disabled
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use RepositoryMetadataSet.ProtoReflect.Descriptor instead.
-func (*RepositoryMetadataSet) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{5}
+func (x *RepositoryMetadata) GetDefaultBranch() string {
+	if x != nil {
+		return x.DefaultBranch
+	}
+	return ""
 }
 
-func (x *RepositoryMetadataSet) GetRepositoryMetadata() []*RepositoryMetadata {
+func (x *RepositoryMetadata) GetPushedAt() string {
 	if x != nil {
-		return x.RepositoryMetadata
+		return x.PushedAt
 	}
-	return nil
+	return ""
+}
+
+func (x *RepositoryMetadata) GetOpenIssues() int32 {
+	if x != nil {
+		return x.OpenIssues
+	}
+	return 0
+}
+
+func (x *RepositoryMetadata) GetLatestRelease() string {
+	if x != nil {
+		return x.LatestRelease
+	}
+	return ""
 }
 
 type BazelRepositoryMetadata struct {
@@ -546,7 +1092,7 @@ type BazelRepositoryMetadata struct {
 
 func (x *BazelRepositoryMetadata) Reset() {
 	*x = BazelRepositoryMetadata{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[6]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -558,7 +1104,7 @@ func (x *BazelRepositoryMetadata) String() string {
 func (*BazelRepositoryMetadata) ProtoMessage() {}
 
 func (x *BazelRepositoryMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[6]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,7 +1117,7 @@ func (x *BazelRepositoryMetadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BazelRepositoryMetadata.ProtoReflect.Descriptor instead.
 func (*BazelRepositoryMetadata) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{6}
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *BazelRepositoryMetadata) GetRepositoryMetadata() *RepositoryMetadata {
@@ -599,7 +1145,7 @@ type BazelRelease struct {
 
 func (x *BazelRelease) Reset() {
 	*x = BazelRelease{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[7]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -611,7 +1157,7 @@ func (x *BazelRelease) String() string {
 func (*BazelRelease) ProtoMessage() {}
 
 func (x *BazelRelease) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[7]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -624,7 +1170,7 @@ func (x *BazelRelease) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BazelRelease.ProtoReflect.Descriptor instead.
 func (*BazelRelease) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{7}
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *BazelRelease) GetVersion() string {
@@ -648,28 +1194,30 @@ func (x *BazelRelease) GetCommit() *ModuleCommit {
 	return nil
 }
 
-type BazelReleaseSet struct {
+type RegistryCommit struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Release       []*BazelRelease        `protobuf:"bytes,1,rep,name=release,proto3" json:"release,omitempty"`
+	Registry      string                 `protobuf:"bytes,1,opt,name=registry,proto3" json:"registry,omitempty"`
+	Sha1          string                 `protobuf:"bytes,2,opt,name=sha1,proto3" json:"sha1,omitempty"`
+	Date          string                 `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BazelReleaseSet) Reset() {
-	*x = BazelReleaseSet{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[8]
+func (x *RegistryCommit) Reset() {
+	*x = RegistryCommit{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BazelReleaseSet) String() string {
+func (x *RegistryCommit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BazelReleaseSet) ProtoMessage() {}
+func (*RegistryCommit) ProtoMessage() {}
 
-func (x *BazelReleaseSet) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[8]
+func (x *RegistryCommit) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -680,42 +1228,116 @@ func (x *BazelReleaseSet) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BazelReleaseSet.ProtoReflect.Descriptor instead.
-func (*BazelReleaseSet) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use RegistryCommit.ProtoReflect.Descriptor instead.
+func (*RegistryCommit) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *BazelReleaseSet) GetRelease() []*BazelRelease {
+func (x *RegistryCommit) GetRegistry() string {
 	if x != nil {
-		return x.Release
+		return x.Registry
 	}
-	return nil
+	return ""
 }
 
-type ResourceStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	Code          int32                  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *RegistryCommit) GetSha1() string {
+	if x != nil {
+		return x.Sha1
+	}
+	return ""
 }
 
-func (x *ResourceStatus) Reset() {
-	*x = ResourceStatus{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[9]
+func (x *RegistryCommit) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type RegistryCommitSet struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Commit                   []*RegistryCommit      `protobuf:"bytes,1,rep,name=commit,proto3" json:"commit,omitempty"`
+	GeneratorFingerprint     string                 `protobuf:"bytes,2,opt,name=generator_fingerprint,json=generatorFingerprint,proto3" json:"generator_fingerprint,omitempty"`
+	ModuleConfigFingerprints map[string]string      `protobuf:"bytes,3,rep,name=module_config_fingerprints,json=moduleConfigFingerprints,proto3" json:"module_config_fingerprints,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *RegistryCommitSet) Reset() {
+	*x = RegistryCommitSet{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResourceStatus) String() string {
+func (x *RegistryCommitSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistryCommitSet) ProtoMessage() {}
+
+func (x *RegistryCommitSet) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistryCommitSet.ProtoReflect.Descriptor instead.
+func (*RegistryCommitSet) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RegistryCommitSet) GetCommit() []*RegistryCommit {
+	if x != nil {
+		return x.Commit
+	}
+	return nil
+}
+
+func (x *RegistryCommitSet) GetGeneratorFingerprint() string {
+	if x != nil {
+		return x.GeneratorFingerprint
+	}
+	return ""
+}
+
+func (x *RegistryCommitSet) GetModuleConfigFingerprints() map[string]string {
+	if x != nil {
+		return x.ModuleConfigFingerprints
+	}
+	return nil
+}
+
+type ResourceStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Code          int32                  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourceStatus) Reset() {
+	*x = ResourceStatus{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*ResourceStatus) ProtoMessage() {}
 
 func (x *ResourceStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[9]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -728,7 +1350,7 @@ func (x *ResourceStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResourceStatus.ProtoReflect.Descriptor instead.
 func (*ResourceStatus) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{9}
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ResourceStatus) GetUrl() string {
@@ -752,28 +1374,37 @@ func (x *ResourceStatus) GetMessage() string {
 	return ""
 }
 
-type ResourceStatusSet struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        []*ResourceStatus      `protobuf:"bytes,1,rep,name=status,proto3" json:"status,omitempty"`
+type CacheEntry struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Type      CacheEntryType         `protobuf:"varint,1,opt,name=type,proto3,enum=build.stack.bazel.registry.v1.CacheEntryType" json:"type,omitempty"`
+	Key       string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	FetchedAt string                 `protobuf:"bytes,3,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*CacheEntry_ResourceStatus
+	//	*CacheEntry_RepositoryMetadata
+	//	*CacheEntry_BazelRelease
+	//	*CacheEntry_CommitSha
+	Value         isCacheEntry_Value `protobuf_oneof:"value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResourceStatusSet) Reset() {
-	*x = ResourceStatusSet{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[10]
+func (x *CacheEntry) Reset() {
+	*x = CacheEntry{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResourceStatusSet) String() string {
+func (x *CacheEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResourceStatusSet) ProtoMessage() {}
+func (*CacheEntry) ProtoMessage() {}
 
-func (x *ResourceStatusSet) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[10]
+func (x *CacheEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -784,43 +1415,183 @@ func (x *ResourceStatusSet) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResourceStatusSet.ProtoReflect.Descriptor instead.
-func (*ResourceStatusSet) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use CacheEntry.ProtoReflect.Descriptor instead.
+func (*CacheEntry) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ResourceStatusSet) GetStatus() []*ResourceStatus {
+func (x *CacheEntry) GetType() CacheEntryType {
 	if x != nil {
-		return x.Status
+		return x.Type
+	}
+	return CacheEntryType_CACHE_ENTRY_TYPE_UNKNOWN
+}
+
+func (x *CacheEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CacheEntry) GetFetchedAt() string {
+	if x != nil {
+		return x.FetchedAt
+	}
+	return ""
+}
+
+func (x *CacheEntry) GetValue() isCacheEntry_Value {
+	if x != nil {
+		return x.Value
 	}
 	return nil
 }
 
-type ModuleSource struct {
-	state         protoimpl.MessageState   `protogen:"open.v1"`
-	Url           string                   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	Integrity     string                   `protobuf:"bytes,2,opt,name=integrity,proto3" json:"integrity,omitempty"`
-	StripPrefix   string                   `protobuf:"bytes,3,opt,name=strip_prefix,json=stripPrefix,proto3" json:"strip_prefix,omitempty"`
-	PatchStrip    int32                    `protobuf:"varint,4,opt,name=patch_strip,json=patchStrip,proto3" json:"patch_strip,omitempty"`
-	Patches       map[string]string        `protobuf:"bytes,5,rep,name=patches,proto3" json:"patches,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Overlay       map[string]string        `protobuf:"bytes,6,rep,name=overlay,proto3" json:"overlay,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	DocsUrl       string                   `protobuf:"bytes,7,opt,name=docs_url,json=docsUrl,proto3" json:"docs_url,omitempty"`
-	MirrorUrls    []string                 `protobuf:"bytes,8,rep,name=mirror_urls,json=mirrorUrls,proto3" json:"mirror_urls,omitempty"`
-	ArchiveType   string                   `protobuf:"bytes,9,opt,name=archive_type,json=archiveType,proto3" json:"archive_type,omitempty"`
-	Type          string                   `protobuf:"bytes,10,opt,name=type,proto3" json:"type,omitempty"`
-	Remote        string                   `protobuf:"bytes,11,opt,name=remote,proto3" json:"remote,omitempty"`
-	Commit        string                   `protobuf:"bytes,12,opt,name=commit,proto3" json:"commit,omitempty"`
-	Documentation *v1.ModuleVersionSymbols `protobuf:"bytes,13,opt,name=documentation,proto3" json:"documentation,omitempty"`
-	DocsUrlStatus *ResourceStatus          `protobuf:"bytes,14,opt,name=docs_url_status,json=docsUrlStatus,proto3" json:"docs_url_status,omitempty"`
-	UrlStatus     *ResourceStatus          `protobuf:"bytes,15,opt,name=url_status,json=urlStatus,proto3" json:"url_status,omitempty"`
-	CommitSha     string                   `protobuf:"bytes,16,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+func (x *CacheEntry) GetResourceStatus() *ResourceStatus {
+	if x != nil {
+		if x, ok := x.Value.(*CacheEntry_ResourceStatus); ok {
+			return x.ResourceStatus
+		}
+	}
+	return nil
+}
+
+func (x *CacheEntry) GetRepositoryMetadata() *RepositoryMetadata {
+	if x != nil {
+		if x, ok := x.Value.(*CacheEntry_RepositoryMetadata); ok {
+			return x.RepositoryMetadata
+		}
+	}
+	return nil
+}
+
+func (x *CacheEntry) GetBazelRelease() *BazelRelease {
+	if x != nil {
+		if x, ok := x.Value.(*CacheEntry_BazelRelease); ok {
+			return x.BazelRelease
+		}
+	}
+	return nil
+}
+
+func (x *CacheEntry) GetCommitSha() string {
+	if x != nil {
+		if x, ok := x.Value.(*CacheEntry_CommitSha); ok {
+			return x.CommitSha
+		}
+	}
+	return ""
+}
+
+type isCacheEntry_Value interface {
+	isCacheEntry_Value()
+}
+
+type CacheEntry_ResourceStatus struct {
+	ResourceStatus *ResourceStatus `protobuf:"bytes,4,opt,name=resource_status,json=resourceStatus,proto3,oneof"`
+}
+
+type CacheEntry_RepositoryMetadata struct {
+	RepositoryMetadata *RepositoryMetadata `protobuf:"bytes,5,opt,name=repository_metadata,json=repositoryMetadata,proto3,oneof"`
+}
+
+type CacheEntry_BazelRelease struct {
+	BazelRelease *BazelRelease `protobuf:"bytes,6,opt,name=bazel_release,json=bazelRelease,proto3,oneof"`
+}
+
+type CacheEntry_CommitSha struct {
+	CommitSha string `protobuf:"bytes,7,opt,name=commit_sha,json=commitSha,proto3,oneof"`
+}
+
+func (*CacheEntry_ResourceStatus) isCacheEntry_Value() {}
+
+func (*CacheEntry_RepositoryMetadata) isCacheEntry_Value() {}
+
+func (*CacheEntry_BazelRelease) isCacheEntry_Value() {}
+
+func (*CacheEntry_CommitSha) isCacheEntry_Value() {}
+
+type CacheStore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Entries       []*CacheEntry          `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *CacheStore) Reset() {
+	*x = CacheStore{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheStore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStore) ProtoMessage() {}
+
+func (x *CacheStore) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStore.ProtoReflect.Descriptor instead.
+func (*CacheStore) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CacheStore) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CacheStore) GetEntries() []*CacheEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ModuleSource struct {
+	state               protoimpl.MessageState    `protogen:"open.v1"`
+	Url                 string                    `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Integrity           string                    `protobuf:"bytes,2,opt,name=integrity,proto3" json:"integrity,omitempty"`
+	StripPrefix         string                    `protobuf:"bytes,3,opt,name=strip_prefix,json=stripPrefix,proto3" json:"strip_prefix,omitempty"`
+	PatchStrip          int32                     `protobuf:"varint,4,opt,name=patch_strip,json=patchStrip,proto3" json:"patch_strip,omitempty"`
+	Patches             map[string]string         `protobuf:"bytes,5,rep,name=patches,proto3" json:"patches,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Overlay             map[string]string         `protobuf:"bytes,6,rep,name=overlay,proto3" json:"overlay,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DocsUrl             string                    `protobuf:"bytes,7,opt,name=docs_url,json=docsUrl,proto3" json:"docs_url,omitempty"`
+	MirrorUrls          []string                  `protobuf:"bytes,8,rep,name=mirror_urls,json=mirrorUrls,proto3" json:"mirror_urls,omitempty"`
+	ArchiveType         string                    `protobuf:"bytes,9,opt,name=archive_type,json=archiveType,proto3" json:"archive_type,omitempty"`
+	Type                string                    `protobuf:"bytes,10,opt,name=type,proto3" json:"type,omitempty"`
+	Remote              string                    `protobuf:"bytes,11,opt,name=remote,proto3" json:"remote,omitempty"`
+	Commit              string                    `protobuf:"bytes,12,opt,name=commit,proto3" json:"commit,omitempty"`
+	Documentation       *v1.ModuleVersionSymbols  `protobuf:"bytes,13,opt,name=documentation,proto3" json:"documentation,omitempty"`
+	DocsUrlStatus       *ResourceStatus           `protobuf:"bytes,14,opt,name=docs_url_status,json=docsUrlStatus,proto3" json:"docs_url_status,omitempty"`
+	UrlStatus           *ResourceStatus           `protobuf:"bytes,15,opt,name=url_status,json=urlStatus,proto3" json:"url_status,omitempty"`
+	CommitSha           string                    `protobuf:"bytes,16,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+	PatchIntegrity      map[string]*FileIntegrity `protobuf:"bytes,17,rep,name=patch_integrity,json=patchIntegrity,proto3" json:"patch_integrity,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	OverlayIntegrity    map[string]*FileIntegrity `protobuf:"bytes,18,rep,name=overlay_integrity,json=overlayIntegrity,proto3" json:"overlay_integrity,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ArchiveVerification *ArchiveVerification      `protobuf:"bytes,19,opt,name=archive_verification,json=archiveVerification,proto3" json:"archive_verification,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
 func (x *ModuleSource) Reset() {
 	*x = ModuleSource{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[11]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -832,7 +1603,7 @@ func (x *ModuleSource) String() string {
 func (*ModuleSource) ProtoMessage() {}
 
 func (x *ModuleSource) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[11]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -845,7 +1616,7 @@ func (x *ModuleSource) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ModuleSource.ProtoReflect.Descriptor instead.
 func (*ModuleSource) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{11}
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ModuleSource) GetUrl() string {
@@ -960,29 +1731,51 @@ func (x *ModuleSource) GetCommitSha() string {
 	return ""
 }
 
-type Attestations struct {
-	state         protoimpl.MessageState               `protogen:"open.v1"`
-	MediaType     string                               `protobuf:"bytes,1,opt,name=media_type,json=mediaType,proto3" json:"media_type,omitempty"`
-	Attestations  map[string]*Attestations_Attestation `protobuf:"bytes,2,rep,name=attestations,proto3" json:"attestations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ModuleSource) GetPatchIntegrity() map[string]*FileIntegrity {
+	if x != nil {
+		return x.PatchIntegrity
+	}
+	return nil
 }
 
-func (x *Attestations) Reset() {
-	*x = Attestations{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[12]
+func (x *ModuleSource) GetOverlayIntegrity() map[string]*FileIntegrity {
+	if x != nil {
+		return x.OverlayIntegrity
+	}
+	return nil
+}
+
+func (x *ModuleSource) GetArchiveVerification() *ArchiveVerification {
+	if x != nil {
+		return x.ArchiveVerification
+	}
+	return nil
+}
+
+type ArchiveVerification struct {
+	state           protoimpl.MessageState    `protogen:"open.v1"`
+	Status          ArchiveVerificationStatus `protobuf:"varint,1,opt,name=status,proto3,enum=build.stack.bazel.registry.v1.ArchiveVerificationStatus" json:"status,omitempty"`
+	ActualIntegrity string                    `protobuf:"bytes,2,opt,name=actual_integrity,json=actualIntegrity,proto3" json:"actual_integrity,omitempty"`
+	Message         string                    `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ArchiveVerification) Reset() {
+	*x = ArchiveVerification{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Attestations) String() string {
+func (x *ArchiveVerification) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Attestations) ProtoMessage() {}
+func (*ArchiveVerification) ProtoMessage() {}
 
-func (x *Attestations) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[12]
+func (x *ArchiveVerification) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -993,60 +1786,55 @@ func (x *Attestations) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Attestations.ProtoReflect.Descriptor instead.
-func (*Attestations) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ArchiveVerification.ProtoReflect.Descriptor instead.
+func (*ArchiveVerification) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *Attestations) GetMediaType() string {
+func (x *ArchiveVerification) GetStatus() ArchiveVerificationStatus {
 	if x != nil {
-		return x.MediaType
+		return x.Status
+	}
+	return ArchiveVerificationStatus_ARCHIVE_VERIFICATION_STATUS_UNKNOWN
+}
+
+func (x *ArchiveVerification) GetActualIntegrity() string {
+	if x != nil {
+		return x.ActualIntegrity
 	}
 	return ""
 }
 
-func (x *Attestations) GetAttestations() map[string]*Attestations_Attestation {
+func (x *ArchiveVerification) GetMessage() string {
 	if x != nil {
-		return x.Attestations
+		return x.Message
 	}
-	return nil
+	return ""
 }
 
-type ModuleVersion struct {
-	state                protoimpl.MessageState      `protogen:"open.v1"`
-	Name                 string                      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Version              string                      `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	CompatibilityLevel   int32                       `protobuf:"varint,3,opt,name=compatibility_level,json=compatibilityLevel,proto3" json:"compatibility_level,omitempty"`
-	BazelCompatibility   []string                    `protobuf:"bytes,4,rep,name=bazel_compatibility,json=bazelCompatibility,proto3" json:"bazel_compatibility,omitempty"`
-	RepoName             string                      `protobuf:"bytes,5,opt,name=repo_name,json=repoName,proto3" json:"repo_name,omitempty"`
-	Deps                 []*ModuleDependency         `protobuf:"bytes,6,rep,name=deps,proto3" json:"deps,omitempty"`
-	Source               *ModuleSource               `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
-	Attestations         *Attestations               `protobuf:"bytes,8,opt,name=attestations,proto3" json:"attestations,omitempty"`
-	Presubmit            *Presubmit                  `protobuf:"bytes,9,opt,name=presubmit,proto3" json:"presubmit,omitempty"`
-	ToolchainsToRegister []string                    `protobuf:"bytes,10,rep,name=toolchains_to_register,json=toolchainsToRegister,proto3" json:"toolchains_to_register,omitempty"`
-	Override             []*ModuleDependencyOverride `protobuf:"bytes,11,rep,name=override,proto3" json:"override,omitempty"`
-	Commit               *ModuleCommit               `protobuf:"bytes,12,opt,name=commit,proto3" json:"commit,omitempty"`
-	RepositoryMetadata   *RepositoryMetadata         `protobuf:"bytes,13,opt,name=repository_metadata,json=repositoryMetadata,proto3" json:"repository_metadata,omitempty"`
-	IsLatestVersion      bool                        `protobuf:"varint,14,opt,name=is_latest_version,json=isLatestVersion,proto3" json:"is_latest_version,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+type FileIntegrity struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Status          FileIntegrityStatus    `protobuf:"varint,1,opt,name=status,proto3,enum=build.stack.bazel.registry.v1.FileIntegrityStatus" json:"status,omitempty"`
+	ActualIntegrity string                 `protobuf:"bytes,2,opt,name=actual_integrity,json=actualIntegrity,proto3" json:"actual_integrity,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *ModuleVersion) Reset() {
-	*x = ModuleVersion{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[13]
+func (x *FileIntegrity) Reset() {
+	*x = FileIntegrity{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ModuleVersion) String() string {
+func (x *FileIntegrity) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ModuleVersion) ProtoMessage() {}
+func (*FileIntegrity) ProtoMessage() {}
 
-func (x *ModuleVersion) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[13]
+func (x *FileIntegrity) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1057,44 +1845,217 @@ func (x *ModuleVersion) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ModuleVersion.ProtoReflect.Descriptor instead.
-func (*ModuleVersion) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use FileIntegrity.ProtoReflect.Descriptor instead.
+func (*FileIntegrity) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *ModuleVersion) GetName() string {
+func (x *FileIntegrity) GetStatus() FileIntegrityStatus {
 	if x != nil {
-		return x.Name
+		return x.Status
 	}
-	return ""
+	return FileIntegrityStatus_FILE_INTEGRITY_STATUS_UNKNOWN
 }
 
-func (x *ModuleVersion) GetVersion() string {
+func (x *FileIntegrity) GetActualIntegrity() string {
 	if x != nil {
-		return x.Version
+		return x.ActualIntegrity
 	}
 	return ""
 }
 
-func (x *ModuleVersion) GetCompatibilityLevel() int32 {
-	if x != nil {
-		return x.CompatibilityLevel
-	}
-	return 0
+type AttestationVerification struct {
+	state         protoimpl.MessageState        `protogen:"open.v1"`
+	Status        AttestationVerificationStatus `protobuf:"varint,1,opt,name=status,proto3,enum=build.stack.bazel.registry.v1.AttestationVerificationStatus" json:"status,omitempty"`
+	Message       string                        `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ModuleVersion) GetBazelCompatibility() []string {
-	if x != nil {
-		return x.BazelCompatibility
-	}
-	return nil
+func (x *AttestationVerification) Reset() {
+	*x = AttestationVerification{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ModuleVersion) GetRepoName() string {
-	if x != nil {
-		return x.RepoName
-	}
-	return ""
+func (x *AttestationVerification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttestationVerification) ProtoMessage() {}
+
+func (x *AttestationVerification) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttestationVerification.ProtoReflect.Descriptor instead.
+func (*AttestationVerification) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AttestationVerification) GetStatus() AttestationVerificationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AttestationVerificationStatus_ATTESTATION_VERIFICATION_STATUS_UNKNOWN
+}
+
+func (x *AttestationVerification) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type Attestations struct {
+	state         protoimpl.MessageState               `protogen:"open.v1"`
+	MediaType     string                               `protobuf:"bytes,1,opt,name=media_type,json=mediaType,proto3" json:"media_type,omitempty"`
+	Attestations  map[string]*Attestations_Attestation `protobuf:"bytes,2,rep,name=attestations,proto3" json:"attestations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attestations) Reset() {
+	*x = Attestations{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attestations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attestations) ProtoMessage() {}
+
+func (x *Attestations) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attestations.ProtoReflect.Descriptor instead.
+func (*Attestations) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Attestations) GetMediaType() string {
+	if x != nil {
+		return x.MediaType
+	}
+	return ""
+}
+
+func (x *Attestations) GetAttestations() map[string]*Attestations_Attestation {
+	if x != nil {
+		return x.Attestations
+	}
+	return nil
+}
+
+type ModuleVersion struct {
+	state                   protoimpl.MessageState      `protogen:"open.v1"`
+	Name                    string                      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version                 string                      `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	CompatibilityLevel      int32                       `protobuf:"varint,3,opt,name=compatibility_level,json=compatibilityLevel,proto3" json:"compatibility_level,omitempty"`
+	BazelCompatibility      []string                    `protobuf:"bytes,4,rep,name=bazel_compatibility,json=bazelCompatibility,proto3" json:"bazel_compatibility,omitempty"`
+	RepoName                string                      `protobuf:"bytes,5,opt,name=repo_name,json=repoName,proto3" json:"repo_name,omitempty"`
+	Deps                    []*ModuleDependency         `protobuf:"bytes,6,rep,name=deps,proto3" json:"deps,omitempty"`
+	Source                  *ModuleSource               `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
+	Attestations            *Attestations               `protobuf:"bytes,8,opt,name=attestations,proto3" json:"attestations,omitempty"`
+	Presubmit               *Presubmit                  `protobuf:"bytes,9,opt,name=presubmit,proto3" json:"presubmit,omitempty"`
+	ToolchainsToRegister    []string                    `protobuf:"bytes,10,rep,name=toolchains_to_register,json=toolchainsToRegister,proto3" json:"toolchains_to_register,omitempty"`
+	Override                []*ModuleDependencyOverride `protobuf:"bytes,11,rep,name=override,proto3" json:"override,omitempty"`
+	Commit                  *ModuleCommit               `protobuf:"bytes,12,opt,name=commit,proto3" json:"commit,omitempty"`
+	RepositoryMetadata      *RepositoryMetadata         `protobuf:"bytes,13,opt,name=repository_metadata,json=repositoryMetadata,proto3" json:"repository_metadata,omitempty"`
+	IsLatestVersion         bool                        `protobuf:"varint,14,opt,name=is_latest_version,json=isLatestVersion,proto3" json:"is_latest_version,omitempty"`
+	ResolutionError         *ResolutionError            `protobuf:"bytes,15,opt,name=resolution_error,json=resolutionError,proto3" json:"resolution_error,omitempty"`
+	BazelCompatibilityRange *BazelCompatibilityRange    `protobuf:"bytes,16,opt,name=bazel_compatibility_range,json=bazelCompatibilityRange,proto3" json:"bazel_compatibility_range,omitempty"`
+	DevDependencyUpgrades   []*DevDependencyUpgrade     `protobuf:"bytes,17,rep,name=dev_dependency_upgrades,json=devDependencyUpgrades,proto3" json:"dev_dependency_upgrades,omitempty"`
+	Registry                string                      `protobuf:"bytes,18,opt,name=registry,proto3" json:"registry,omitempty"`
+	Provenance              *SlsaProvenance             `protobuf:"bytes,19,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *ModuleVersion) Reset() {
+	*x = ModuleVersion{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModuleVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleVersion) ProtoMessage() {}
+
+func (x *ModuleVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleVersion.ProtoReflect.Descriptor instead.
+func (*ModuleVersion) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ModuleVersion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ModuleVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ModuleVersion) GetCompatibilityLevel() int32 {
+	if x != nil {
+		return x.CompatibilityLevel
+	}
+	return 0
+}
+
+func (x *ModuleVersion) GetBazelCompatibility() []string {
+	if x != nil {
+		return x.BazelCompatibility
+	}
+	return nil
+}
+
+func (x *ModuleVersion) GetRepoName() string {
+	if x != nil {
+		return x.RepoName
+	}
+	return ""
 }
 
 func (x *ModuleVersion) GetDeps() []*ModuleDependency {
@@ -1146,45 +2107,1220 @@ func (x *ModuleVersion) GetCommit() *ModuleCommit {
 	return nil
 }
 
-func (x *ModuleVersion) GetRepositoryMetadata() *RepositoryMetadata {
+func (x *ModuleVersion) GetRepositoryMetadata() *RepositoryMetadata {
+	if x != nil {
+		return x.RepositoryMetadata
+	}
+	return nil
+}
+
+func (x *ModuleVersion) GetIsLatestVersion() bool {
+	if x != nil {
+		return x.IsLatestVersion
+	}
+	return false
+}
+
+func (x *ModuleVersion) GetResolutionError() *ResolutionError {
+	if x != nil {
+		return x.ResolutionError
+	}
+	return nil
+}
+
+func (x *ModuleVersion) GetBazelCompatibilityRange() *BazelCompatibilityRange {
+	if x != nil {
+		return x.BazelCompatibilityRange
+	}
+	return nil
+}
+
+func (x *ModuleVersion) GetDevDependencyUpgrades() []*DevDependencyUpgrade {
+	if x != nil {
+		return x.DevDependencyUpgrades
+	}
+	return nil
+}
+
+func (x *ModuleVersion) GetRegistry() string {
+	if x != nil {
+		return x.Registry
+	}
+	return ""
+}
+
+func (x *ModuleVersion) GetProvenance() *SlsaProvenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+type SlsaProvenance struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	PredicateType     string                 `protobuf:"bytes,1,opt,name=predicate_type,json=predicateType,proto3" json:"predicate_type,omitempty"`
+	BuilderId         string                 `protobuf:"bytes,2,opt,name=builder_id,json=builderId,proto3" json:"builder_id,omitempty"`
+	SourceRepository  string                 `protobuf:"bytes,3,opt,name=source_repository,json=sourceRepository,proto3" json:"source_repository,omitempty"`
+	SourceRef         string                 `protobuf:"bytes,4,opt,name=source_ref,json=sourceRef,proto3" json:"source_ref,omitempty"`
+	SourceCommit      string                 `protobuf:"bytes,5,opt,name=source_commit,json=sourceCommit,proto3" json:"source_commit,omitempty"`
+	WorkflowPath      string                 `protobuf:"bytes,6,opt,name=workflow_path,json=workflowPath,proto3" json:"workflow_path,omitempty"`
+	BuildInvocationId string                 `protobuf:"bytes,7,opt,name=build_invocation_id,json=buildInvocationId,proto3" json:"build_invocation_id,omitempty"`
+	AttestedFiles     []string               `protobuf:"bytes,8,rep,name=attested_files,json=attestedFiles,proto3" json:"attested_files,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SlsaProvenance) Reset() {
+	*x = SlsaProvenance{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlsaProvenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlsaProvenance) ProtoMessage() {}
+
+func (x *SlsaProvenance) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlsaProvenance.ProtoReflect.Descriptor instead.
+func (*SlsaProvenance) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SlsaProvenance) GetPredicateType() string {
+	if x != nil {
+		return x.PredicateType
+	}
+	return ""
+}
+
+func (x *SlsaProvenance) GetBuilderId() string {
+	if x != nil {
+		return x.BuilderId
+	}
+	return ""
+}
+
+func (x *SlsaProvenance) GetSourceRepository() string {
+	if x != nil {
+		return x.SourceRepository
+	}
+	return ""
+}
+
+func (x *SlsaProvenance) GetSourceRef() string {
+	if x != nil {
+		return x.SourceRef
+	}
+	return ""
+}
+
+func (x *SlsaProvenance) GetSourceCommit() string {
+	if x != nil {
+		return x.SourceCommit
+	}
+	return ""
+}
+
+func (x *SlsaProvenance) GetWorkflowPath() string {
+	if x != nil {
+		return x.WorkflowPath
+	}
+	return ""
+}
+
+func (x *SlsaProvenance) GetBuildInvocationId() string {
+	if x != nil {
+		return x.BuildInvocationId
+	}
+	return ""
+}
+
+func (x *SlsaProvenance) GetAttestedFiles() []string {
+	if x != nil {
+		return x.AttestedFiles
+	}
+	return nil
+}
+
+type DevDependencyUpgrade struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModuleName    string                 `protobuf:"bytes,1,opt,name=module_name,json=moduleName,proto3" json:"module_name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	DevVersion    string                 `protobuf:"bytes,3,opt,name=dev_version,json=devVersion,proto3" json:"dev_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DevDependencyUpgrade) Reset() {
+	*x = DevDependencyUpgrade{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DevDependencyUpgrade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DevDependencyUpgrade) ProtoMessage() {}
+
+func (x *DevDependencyUpgrade) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DevDependencyUpgrade.ProtoReflect.Descriptor instead.
+func (*DevDependencyUpgrade) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DevDependencyUpgrade) GetModuleName() string {
+	if x != nil {
+		return x.ModuleName
+	}
+	return ""
+}
+
+func (x *DevDependencyUpgrade) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *DevDependencyUpgrade) GetDevVersion() string {
+	if x != nil {
+		return x.DevVersion
+	}
+	return ""
+}
+
+type BazelCompatibilityRange struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	MinVersion    string                         `protobuf:"bytes,1,opt,name=min_version,json=minVersion,proto3" json:"min_version,omitempty"`
+	MaxVersion    string                         `protobuf:"bytes,2,opt,name=max_version,json=maxVersion,proto3" json:"max_version,omitempty"`
+	VersionCount  int32                          `protobuf:"varint,3,opt,name=version_count,json=versionCount,proto3" json:"version_count,omitempty"`
+	NarrowedBy    []*BazelCompatibilityNarrowing `protobuf:"bytes,4,rep,name=narrowed_by,json=narrowedBy,proto3" json:"narrowed_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BazelCompatibilityRange) Reset() {
+	*x = BazelCompatibilityRange{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BazelCompatibilityRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BazelCompatibilityRange) ProtoMessage() {}
+
+func (x *BazelCompatibilityRange) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BazelCompatibilityRange.ProtoReflect.Descriptor instead.
+func (*BazelCompatibilityRange) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BazelCompatibilityRange) GetMinVersion() string {
+	if x != nil {
+		return x.MinVersion
+	}
+	return ""
+}
+
+func (x *BazelCompatibilityRange) GetMaxVersion() string {
+	if x != nil {
+		return x.MaxVersion
+	}
+	return ""
+}
+
+func (x *BazelCompatibilityRange) GetVersionCount() int32 {
+	if x != nil {
+		return x.VersionCount
+	}
+	return 0
+}
+
+func (x *BazelCompatibilityRange) GetNarrowedBy() []*BazelCompatibilityNarrowing {
+	if x != nil {
+		return x.NarrowedBy
+	}
+	return nil
+}
+
+type BazelCompatibilityNarrowing struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Module             string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	BazelCompatibility []string               `protobuf:"bytes,2,rep,name=bazel_compatibility,json=bazelCompatibility,proto3" json:"bazel_compatibility,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *BazelCompatibilityNarrowing) Reset() {
+	*x = BazelCompatibilityNarrowing{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BazelCompatibilityNarrowing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BazelCompatibilityNarrowing) ProtoMessage() {}
+
+func (x *BazelCompatibilityNarrowing) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BazelCompatibilityNarrowing.ProtoReflect.Descriptor instead.
+func (*BazelCompatibilityNarrowing) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BazelCompatibilityNarrowing) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *BazelCompatibilityNarrowing) GetBazelCompatibility() []string {
+	if x != nil {
+		return x.BazelCompatibility
+	}
+	return nil
+}
+
+type ResolutionError struct {
+	state         protoimpl.MessageState        `protogen:"open.v1"`
+	Message       string                        `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Conflicts     []*CompatibilityLevelConflict `protobuf:"bytes,2,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolutionError) Reset() {
+	*x = ResolutionError{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolutionError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolutionError) ProtoMessage() {}
+
+func (x *ResolutionError) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolutionError.ProtoReflect.Descriptor instead.
+func (*ResolutionError) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ResolutionError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ResolutionError) GetConflicts() []*CompatibilityLevelConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+type CompatibilityLevelConflict struct {
+	state         protoimpl.MessageState           `protogen:"open.v1"`
+	ModuleName    string                           `protobuf:"bytes,1,opt,name=module_name,json=moduleName,proto3" json:"module_name,omitempty"`
+	Requirements  []*CompatibilityLevelRequirement `protobuf:"bytes,2,rep,name=requirements,proto3" json:"requirements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompatibilityLevelConflict) Reset() {
+	*x = CompatibilityLevelConflict{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompatibilityLevelConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompatibilityLevelConflict) ProtoMessage() {}
+
+func (x *CompatibilityLevelConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompatibilityLevelConflict.ProtoReflect.Descriptor instead.
+func (*CompatibilityLevelConflict) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CompatibilityLevelConflict) GetModuleName() string {
+	if x != nil {
+		return x.ModuleName
+	}
+	return ""
+}
+
+func (x *CompatibilityLevelConflict) GetRequirements() []*CompatibilityLevelRequirement {
+	if x != nil {
+		return x.Requirements
+	}
+	return nil
+}
+
+type CompatibilityLevelRequirement struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CompatibilityLevel int32                  `protobuf:"varint,1,opt,name=compatibility_level,json=compatibilityLevel,proto3" json:"compatibility_level,omitempty"`
+	Version            string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Path               []string               `protobuf:"bytes,3,rep,name=path,proto3" json:"path,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CompatibilityLevelRequirement) Reset() {
+	*x = CompatibilityLevelRequirement{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompatibilityLevelRequirement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompatibilityLevelRequirement) ProtoMessage() {}
+
+func (x *CompatibilityLevelRequirement) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompatibilityLevelRequirement.ProtoReflect.Descriptor instead.
+func (*CompatibilityLevelRequirement) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CompatibilityLevelRequirement) GetCompatibilityLevel() int32 {
+	if x != nil {
+		return x.CompatibilityLevel
+	}
+	return 0
+}
+
+func (x *CompatibilityLevelRequirement) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *CompatibilityLevelRequirement) GetPath() []string {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+type ModuleCommit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sha1          string                 `protobuf:"bytes,1,opt,name=sha1,proto3" json:"sha1,omitempty"`
+	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	PullRequest   string                 `protobuf:"bytes,4,opt,name=pull_request,json=pullRequest,proto3" json:"pull_request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModuleCommit) Reset() {
+	*x = ModuleCommit{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModuleCommit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleCommit) ProtoMessage() {}
+
+func (x *ModuleCommit) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleCommit.ProtoReflect.Descriptor instead.
+func (*ModuleCommit) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ModuleCommit) GetSha1() string {
+	if x != nil {
+		return x.Sha1
+	}
+	return ""
+}
+
+func (x *ModuleCommit) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ModuleCommit) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ModuleCommit) GetPullRequest() string {
+	if x != nil {
+		return x.PullRequest
+	}
+	return ""
+}
+
+type ModuleDependencyOverride struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ModuleName string                 `protobuf:"bytes,5,opt,name=module_name,json=moduleName,proto3" json:"module_name,omitempty"`
+	Code       string                 `protobuf:"bytes,6,opt,name=code,proto3" json:"code,omitempty"`
+	// Types that are valid to be assigned to Override:
+	//
+	//	*ModuleDependencyOverride_GitOverride
+	//	*ModuleDependencyOverride_ArchiveOverride
+	//	*ModuleDependencyOverride_SingleVersionOverride
+	//	*ModuleDependencyOverride_LocalPathOverride
+	Override      isModuleDependencyOverride_Override `protobuf_oneof:"override"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModuleDependencyOverride) Reset() {
+	*x = ModuleDependencyOverride{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModuleDependencyOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleDependencyOverride) ProtoMessage() {}
+
+func (x *ModuleDependencyOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleDependencyOverride.ProtoReflect.Descriptor instead.
+func (*ModuleDependencyOverride) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ModuleDependencyOverride) GetModuleName() string {
+	if x != nil {
+		return x.ModuleName
+	}
+	return ""
+}
+
+func (x *ModuleDependencyOverride) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ModuleDependencyOverride) GetOverride() isModuleDependencyOverride_Override {
+	if x != nil {
+		return x.Override
+	}
+	return nil
+}
+
+func (x *ModuleDependencyOverride) GetGitOverride() *GitOverride {
+	if x != nil {
+		if x, ok := x.Override.(*ModuleDependencyOverride_GitOverride); ok {
+			return x.GitOverride
+		}
+	}
+	return nil
+}
+
+func (x *ModuleDependencyOverride) GetArchiveOverride() *ArchiveOverride {
+	if x != nil {
+		if x, ok := x.Override.(*ModuleDependencyOverride_ArchiveOverride); ok {
+			return x.ArchiveOverride
+		}
+	}
+	return nil
+}
+
+func (x *ModuleDependencyOverride) GetSingleVersionOverride() *SingleVersionOverride {
+	if x != nil {
+		if x, ok := x.Override.(*ModuleDependencyOverride_SingleVersionOverride); ok {
+			return x.SingleVersionOverride
+		}
+	}
+	return nil
+}
+
+func (x *ModuleDependencyOverride) GetLocalPathOverride() *LocalPathOverride {
+	if x != nil {
+		if x, ok := x.Override.(*ModuleDependencyOverride_LocalPathOverride); ok {
+			return x.LocalPathOverride
+		}
+	}
+	return nil
+}
+
+type isModuleDependencyOverride_Override interface {
+	isModuleDependencyOverride_Override()
+}
+
+type ModuleDependencyOverride_GitOverride struct {
+	GitOverride *GitOverride `protobuf:"bytes,1,opt,name=git_override,json=gitOverride,proto3,oneof"`
+}
+
+type ModuleDependencyOverride_ArchiveOverride struct {
+	ArchiveOverride *ArchiveOverride `protobuf:"bytes,2,opt,name=archive_override,json=archiveOverride,proto3,oneof"`
+}
+
+type ModuleDependencyOverride_SingleVersionOverride struct {
+	SingleVersionOverride *SingleVersionOverride `protobuf:"bytes,3,opt,name=single_version_override,json=singleVersionOverride,proto3,oneof"`
+}
+
+type ModuleDependencyOverride_LocalPathOverride struct {
+	LocalPathOverride *LocalPathOverride `protobuf:"bytes,4,opt,name=local_path_override,json=localPathOverride,proto3,oneof"`
+}
+
+func (*ModuleDependencyOverride_GitOverride) isModuleDependencyOverride_Override() {}
+
+func (*ModuleDependencyOverride_ArchiveOverride) isModuleDependencyOverride_Override() {}
+
+func (*ModuleDependencyOverride_SingleVersionOverride) isModuleDependencyOverride_Override() {}
+
+func (*ModuleDependencyOverride_LocalPathOverride) isModuleDependencyOverride_Override() {}
+
+type ModuleDependency struct {
+	state                 protoimpl.MessageState    `protogen:"open.v1"`
+	Name                  string                    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version               string                    `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	RepoName              string                    `protobuf:"bytes,3,opt,name=repo_name,json=repoName,proto3" json:"repo_name,omitempty"`
+	Dev                   bool                      `protobuf:"varint,4,opt,name=dev,proto3" json:"dev,omitempty"`
+	MaxCompatibilityLevel int32                     `protobuf:"varint,5,opt,name=max_compatibility_level,json=maxCompatibilityLevel,proto3" json:"max_compatibility_level,omitempty"`
+	Override              *ModuleDependencyOverride `protobuf:"bytes,6,opt,name=override,proto3" json:"override,omitempty"`
+	Unresolved            bool                      `protobuf:"varint,7,opt,name=unresolved,proto3" json:"unresolved,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ModuleDependency) Reset() {
+	*x = ModuleDependency{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModuleDependency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleDependency) ProtoMessage() {}
+
+func (x *ModuleDependency) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleDependency.ProtoReflect.Descriptor instead.
+func (*ModuleDependency) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ModuleDependency) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ModuleDependency) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ModuleDependency) GetRepoName() string {
+	if x != nil {
+		return x.RepoName
+	}
+	return ""
+}
+
+func (x *ModuleDependency) GetDev() bool {
+	if x != nil {
+		return x.Dev
+	}
+	return false
+}
+
+func (x *ModuleDependency) GetMaxCompatibilityLevel() int32 {
+	if x != nil {
+		return x.MaxCompatibilityLevel
+	}
+	return 0
+}
+
+func (x *ModuleDependency) GetOverride() *ModuleDependencyOverride {
+	if x != nil {
+		return x.Override
+	}
+	return nil
+}
+
+func (x *ModuleDependency) GetUnresolved() bool {
+	if x != nil {
+		return x.Unresolved
+	}
+	return false
+}
+
+type GitOverride struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commit        string                 `protobuf:"bytes,1,opt,name=commit,proto3" json:"commit,omitempty"`
+	PatchStrip    int32                  `protobuf:"varint,2,opt,name=patch_strip,json=patchStrip,proto3" json:"patch_strip,omitempty"`
+	Patches       []string               `protobuf:"bytes,3,rep,name=patches,proto3" json:"patches,omitempty"`
+	Remote        string                 `protobuf:"bytes,4,opt,name=remote,proto3" json:"remote,omitempty"`
+	Branch        string                 `protobuf:"bytes,5,opt,name=branch,proto3" json:"branch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GitOverride) Reset() {
+	*x = GitOverride{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GitOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GitOverride) ProtoMessage() {}
+
+func (x *GitOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GitOverride.ProtoReflect.Descriptor instead.
+func (*GitOverride) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GitOverride) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *GitOverride) GetPatchStrip() int32 {
+	if x != nil {
+		return x.PatchStrip
+	}
+	return 0
+}
+
+func (x *GitOverride) GetPatches() []string {
+	if x != nil {
+		return x.Patches
+	}
+	return nil
+}
+
+func (x *GitOverride) GetRemote() string {
+	if x != nil {
+		return x.Remote
+	}
+	return ""
+}
+
+func (x *GitOverride) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+type ArchiveOverride struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Integrity     string                 `protobuf:"bytes,1,opt,name=integrity,proto3" json:"integrity,omitempty"`
+	PatchStrip    int32                  `protobuf:"varint,2,opt,name=patch_strip,json=patchStrip,proto3" json:"patch_strip,omitempty"`
+	Patches       []string               `protobuf:"bytes,3,rep,name=patches,proto3" json:"patches,omitempty"`
+	StripPrefix   string                 `protobuf:"bytes,4,opt,name=strip_prefix,json=stripPrefix,proto3" json:"strip_prefix,omitempty"`
+	Urls          []string               `protobuf:"bytes,5,rep,name=urls,proto3" json:"urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveOverride) Reset() {
+	*x = ArchiveOverride{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveOverride) ProtoMessage() {}
+
+func (x *ArchiveOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveOverride.ProtoReflect.Descriptor instead.
+func (*ArchiveOverride) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ArchiveOverride) GetIntegrity() string {
+	if x != nil {
+		return x.Integrity
+	}
+	return ""
+}
+
+func (x *ArchiveOverride) GetPatchStrip() int32 {
+	if x != nil {
+		return x.PatchStrip
+	}
+	return 0
+}
+
+func (x *ArchiveOverride) GetPatches() []string {
+	if x != nil {
+		return x.Patches
+	}
+	return nil
+}
+
+func (x *ArchiveOverride) GetStripPrefix() string {
+	if x != nil {
+		return x.StripPrefix
+	}
+	return ""
+}
+
+func (x *ArchiveOverride) GetUrls() []string {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+type SingleVersionOverride struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PatchStrip    int32                  `protobuf:"varint,1,opt,name=patch_strip,json=patchStrip,proto3" json:"patch_strip,omitempty"`
+	Patches       []string               `protobuf:"bytes,2,rep,name=patches,proto3" json:"patches,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SingleVersionOverride) Reset() {
+	*x = SingleVersionOverride{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SingleVersionOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SingleVersionOverride) ProtoMessage() {}
+
+func (x *SingleVersionOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SingleVersionOverride.ProtoReflect.Descriptor instead.
+func (*SingleVersionOverride) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SingleVersionOverride) GetPatchStrip() int32 {
+	if x != nil {
+		return x.PatchStrip
+	}
+	return 0
+}
+
+func (x *SingleVersionOverride) GetPatches() []string {
+	if x != nil {
+		return x.Patches
+	}
+	return nil
+}
+
+func (x *SingleVersionOverride) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type LocalPathOverride struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocalPathOverride) Reset() {
+	*x = LocalPathOverride{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocalPathOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocalPathOverride) ProtoMessage() {}
+
+func (x *LocalPathOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocalPathOverride.ProtoReflect.Descriptor instead.
+func (*LocalPathOverride) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LocalPathOverride) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type Presubmit struct {
+	state         protoimpl.MessageState              `protogen:"open.v1"`
+	BcrTestModule *Presubmit_BcrTestModule            `protobuf:"bytes,1,opt,name=bcr_test_module,json=bcrTestModule,proto3" json:"bcr_test_module,omitempty"`
+	Matrix        *Presubmit_PresubmitMatrix          `protobuf:"bytes,2,opt,name=matrix,proto3" json:"matrix,omitempty"`
+	Tasks         map[string]*Presubmit_PresubmitTask `protobuf:"bytes,3,rep,name=tasks,proto3" json:"tasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Presubmit) Reset() {
+	*x = Presubmit{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Presubmit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Presubmit) ProtoMessage() {}
+
+func (x *Presubmit) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Presubmit.ProtoReflect.Descriptor instead.
+func (*Presubmit) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Presubmit) GetBcrTestModule() *Presubmit_BcrTestModule {
+	if x != nil {
+		return x.BcrTestModule
+	}
+	return nil
+}
+
+func (x *Presubmit) GetMatrix() *Presubmit_PresubmitMatrix {
+	if x != nil {
+		return x.Matrix
+	}
+	return nil
+}
+
+func (x *Presubmit) GetTasks() map[string]*Presubmit_PresubmitTask {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type DependencyTreeNode struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ModuleVersion    *ModuleVersion         `protobuf:"bytes,1,opt,name=module_version,json=moduleVersion,proto3" json:"module_version,omitempty"`
+	RequestedVersion string                 `protobuf:"bytes,2,opt,name=requested_version,json=requestedVersion,proto3" json:"requested_version,omitempty"`
+	Upgraded         bool                   `protobuf:"varint,3,opt,name=upgraded,proto3" json:"upgraded,omitempty"`
+	Dev              bool                   `protobuf:"varint,4,opt,name=dev,proto3" json:"dev,omitempty"`
+	Children         []*DependencyTreeNode  `protobuf:"bytes,5,rep,name=children,proto3" json:"children,omitempty"`
+	Pruned           bool                   `protobuf:"varint,6,opt,name=pruned,proto3" json:"pruned,omitempty"`
+	OverrideType     string                 `protobuf:"bytes,7,opt,name=override_type,json=overrideType,proto3" json:"override_type,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DependencyTreeNode) Reset() {
+	*x = DependencyTreeNode{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DependencyTreeNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DependencyTreeNode) ProtoMessage() {}
+
+func (x *DependencyTreeNode) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DependencyTreeNode.ProtoReflect.Descriptor instead.
+func (*DependencyTreeNode) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *DependencyTreeNode) GetModuleVersion() *ModuleVersion {
+	if x != nil {
+		return x.ModuleVersion
+	}
+	return nil
+}
+
+func (x *DependencyTreeNode) GetRequestedVersion() string {
+	if x != nil {
+		return x.RequestedVersion
+	}
+	return ""
+}
+
+func (x *DependencyTreeNode) GetUpgraded() bool {
+	if x != nil {
+		return x.Upgraded
+	}
+	return false
+}
+
+func (x *DependencyTreeNode) GetDev() bool {
+	if x != nil {
+		return x.Dev
+	}
+	return false
+}
+
+func (x *DependencyTreeNode) GetChildren() []*DependencyTreeNode {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *DependencyTreeNode) GetPruned() bool {
 	if x != nil {
-		return x.RepositoryMetadata
+		return x.Pruned
 	}
-	return nil
+	return false
 }
 
-func (x *ModuleVersion) GetIsLatestVersion() bool {
+func (x *DependencyTreeNode) GetOverrideType() string {
 	if x != nil {
-		return x.IsLatestVersion
+		return x.OverrideType
 	}
-	return false
+	return ""
 }
 
-type ModuleCommit struct {
+type DependencyTree struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Sha1          string                 `protobuf:"bytes,1,opt,name=sha1,proto3" json:"sha1,omitempty"`
-	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	PullRequest   string                 `protobuf:"bytes,4,opt,name=pull_request,json=pullRequest,proto3" json:"pull_request,omitempty"`
+	ModuleVersion *ModuleVersion         `protobuf:"bytes,1,opt,name=module_version,json=moduleVersion,proto3" json:"module_version,omitempty"`
+	Children      []*DependencyTreeNode  `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ModuleCommit) Reset() {
-	*x = ModuleCommit{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[14]
+func (x *DependencyTree) Reset() {
+	*x = DependencyTree{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ModuleCommit) String() string {
+func (x *DependencyTree) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ModuleCommit) ProtoMessage() {}
+func (*DependencyTree) ProtoMessage() {}
 
-func (x *ModuleCommit) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[14]
+func (x *DependencyTree) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1195,69 +3331,99 @@ func (x *ModuleCommit) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ModuleCommit.ProtoReflect.Descriptor instead.
-func (*ModuleCommit) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use DependencyTree.ProtoReflect.Descriptor instead.
+func (*DependencyTree) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *ModuleCommit) GetSha1() string {
+func (x *DependencyTree) GetModuleVersion() *ModuleVersion {
 	if x != nil {
-		return x.Sha1
+		return x.ModuleVersion
 	}
-	return ""
+	return nil
 }
 
-func (x *ModuleCommit) GetDate() string {
+func (x *DependencyTree) GetChildren() []*DependencyTreeNode {
 	if x != nil {
-		return x.Date
+		return x.Children
 	}
-	return ""
+	return nil
 }
 
-func (x *ModuleCommit) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
+type ReverseDependencyIndex struct {
+	state          protoimpl.MessageState     `protogen:"open.v1"`
+	ModuleVersions []*ModuleVersionDependents `protobuf:"bytes,1,rep,name=module_versions,json=moduleVersions,proto3" json:"module_versions,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ModuleCommit) GetPullRequest() string {
+func (x *ReverseDependencyIndex) Reset() {
+	*x = ReverseDependencyIndex{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReverseDependencyIndex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReverseDependencyIndex) ProtoMessage() {}
+
+func (x *ReverseDependencyIndex) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[37]
 	if x != nil {
-		return x.PullRequest
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-type ModuleDependencyOverride struct {
-	state      protoimpl.MessageState `protogen:"open.v1"`
-	ModuleName string                 `protobuf:"bytes,5,opt,name=module_name,json=moduleName,proto3" json:"module_name,omitempty"`
-	Code       string                 `protobuf:"bytes,6,opt,name=code,proto3" json:"code,omitempty"`
-	// Types that are valid to be assigned to Override:
-	//
-	//	*ModuleDependencyOverride_GitOverride
-	//	*ModuleDependencyOverride_ArchiveOverride
-	//	*ModuleDependencyOverride_SingleVersionOverride
-	//	*ModuleDependencyOverride_LocalPathOverride
-	Override      isModuleDependencyOverride_Override `protobuf_oneof:"override"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Deprecated: Use ReverseDependencyIndex.ProtoReflect.Descriptor instead.
+func (*ReverseDependencyIndex) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *ModuleDependencyOverride) Reset() {
-	*x = ModuleDependencyOverride{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[15]
+func (x *ReverseDependencyIndex) GetModuleVersions() []*ModuleVersionDependents {
+	if x != nil {
+		return x.ModuleVersions
+	}
+	return nil
+}
+
+type ModuleVersionDependents struct {
+	state               protoimpl.MessageState   `protogen:"open.v1"`
+	Name                string                   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version             string                   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Direct              []string                 `protobuf:"bytes,3,rep,name=direct,proto3" json:"direct,omitempty"`
+	DirectDev           []string                 `protobuf:"bytes,4,rep,name=direct_dev,json=directDev,proto3" json:"direct_dev,omitempty"`
+	TransitiveLatest    []string                 `protobuf:"bytes,5,rep,name=transitive_latest,json=transitiveLatest,proto3" json:"transitive_latest,omitempty"`
+	TransitiveDevLatest []string                 `protobuf:"bytes,6,rep,name=transitive_dev_latest,json=transitiveDevLatest,proto3" json:"transitive_dev_latest,omitempty"`
+	Counts              *ReverseDependencyCounts `protobuf:"bytes,7,opt,name=counts,proto3" json:"counts,omitempty"`
+	Transitive          []string                 `protobuf:"bytes,8,rep,name=transitive,proto3" json:"transitive,omitempty"`
+	TransitiveDev       []string                 `protobuf:"bytes,9,rep,name=transitive_dev,json=transitiveDev,proto3" json:"transitive_dev,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ModuleVersionDependents) Reset() {
+	*x = ModuleVersionDependents{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ModuleDependencyOverride) String() string {
+func (x *ModuleVersionDependents) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ModuleDependencyOverride) ProtoMessage() {}
+func (*ModuleVersionDependents) ProtoMessage() {}
 
-func (x *ModuleDependencyOverride) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[15]
+func (x *ModuleVersionDependents) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1268,124 +3434,103 @@ func (x *ModuleDependencyOverride) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ModuleDependencyOverride.ProtoReflect.Descriptor instead.
-func (*ModuleDependencyOverride) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ModuleVersionDependents.ProtoReflect.Descriptor instead.
+func (*ModuleVersionDependents) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *ModuleDependencyOverride) GetModuleName() string {
+func (x *ModuleVersionDependents) GetName() string {
 	if x != nil {
-		return x.ModuleName
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ModuleDependencyOverride) GetCode() string {
+func (x *ModuleVersionDependents) GetVersion() string {
 	if x != nil {
-		return x.Code
+		return x.Version
 	}
 	return ""
 }
 
-func (x *ModuleDependencyOverride) GetOverride() isModuleDependencyOverride_Override {
+func (x *ModuleVersionDependents) GetDirect() []string {
 	if x != nil {
-		return x.Override
+		return x.Direct
 	}
 	return nil
 }
 
-func (x *ModuleDependencyOverride) GetGitOverride() *GitOverride {
+func (x *ModuleVersionDependents) GetDirectDev() []string {
 	if x != nil {
-		if x, ok := x.Override.(*ModuleDependencyOverride_GitOverride); ok {
-			return x.GitOverride
-		}
+		return x.DirectDev
 	}
 	return nil
 }
 
-func (x *ModuleDependencyOverride) GetArchiveOverride() *ArchiveOverride {
+func (x *ModuleVersionDependents) GetTransitiveLatest() []string {
 	if x != nil {
-		if x, ok := x.Override.(*ModuleDependencyOverride_ArchiveOverride); ok {
-			return x.ArchiveOverride
-		}
+		return x.TransitiveLatest
 	}
 	return nil
 }
 
-func (x *ModuleDependencyOverride) GetSingleVersionOverride() *SingleVersionOverride {
+func (x *ModuleVersionDependents) GetTransitiveDevLatest() []string {
 	if x != nil {
-		if x, ok := x.Override.(*ModuleDependencyOverride_SingleVersionOverride); ok {
-			return x.SingleVersionOverride
-		}
+		return x.TransitiveDevLatest
 	}
 	return nil
 }
 
-func (x *ModuleDependencyOverride) GetLocalPathOverride() *LocalPathOverride {
+func (x *ModuleVersionDependents) GetCounts() *ReverseDependencyCounts {
 	if x != nil {
-		if x, ok := x.Override.(*ModuleDependencyOverride_LocalPathOverride); ok {
-			return x.LocalPathOverride
-		}
+		return x.Counts
 	}
 	return nil
 }
 
-type isModuleDependencyOverride_Override interface {
-	isModuleDependencyOverride_Override()
-}
-
-type ModuleDependencyOverride_GitOverride struct {
-	GitOverride *GitOverride `protobuf:"bytes,1,opt,name=git_override,json=gitOverride,proto3,oneof"`
-}
-
-type ModuleDependencyOverride_ArchiveOverride struct {
-	ArchiveOverride *ArchiveOverride `protobuf:"bytes,2,opt,name=archive_override,json=archiveOverride,proto3,oneof"`
-}
-
-type ModuleDependencyOverride_SingleVersionOverride struct {
-	SingleVersionOverride *SingleVersionOverride `protobuf:"bytes,3,opt,name=single_version_override,json=singleVersionOverride,proto3,oneof"`
+func (x *ModuleVersionDependents) GetTransitive() []string {
+	if x != nil {
+		return x.Transitive
+	}
+	return nil
 }
 
-type ModuleDependencyOverride_LocalPathOverride struct {
-	LocalPathOverride *LocalPathOverride `protobuf:"bytes,4,opt,name=local_path_override,json=localPathOverride,proto3,oneof"`
+func (x *ModuleVersionDependents) GetTransitiveDev() []string {
+	if x != nil {
+		return x.TransitiveDev
+	}
+	return nil
 }
 
-func (*ModuleDependencyOverride_GitOverride) isModuleDependencyOverride_Override() {}
-
-func (*ModuleDependencyOverride_ArchiveOverride) isModuleDependencyOverride_Override() {}
-
-func (*ModuleDependencyOverride_SingleVersionOverride) isModuleDependencyOverride_Override() {}
-
-func (*ModuleDependencyOverride_LocalPathOverride) isModuleDependencyOverride_Override() {}
-
-type ModuleDependency struct {
-	state                 protoimpl.MessageState    `protogen:"open.v1"`
-	Name                  string                    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Version               string                    `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	RepoName              string                    `protobuf:"bytes,3,opt,name=repo_name,json=repoName,proto3" json:"repo_name,omitempty"`
-	Dev                   bool                      `protobuf:"varint,4,opt,name=dev,proto3" json:"dev,omitempty"`
-	MaxCompatibilityLevel int32                     `protobuf:"varint,5,opt,name=max_compatibility_level,json=maxCompatibilityLevel,proto3" json:"max_compatibility_level,omitempty"`
-	Override              *ModuleDependencyOverride `protobuf:"bytes,6,opt,name=override,proto3" json:"override,omitempty"`
-	Unresolved            bool                      `protobuf:"varint,7,opt,name=unresolved,proto3" json:"unresolved,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+type ReverseDependencyCounts struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Direct              int32                  `protobuf:"varint,1,opt,name=direct,proto3" json:"direct,omitempty"`
+	DirectDev           int32                  `protobuf:"varint,2,opt,name=direct_dev,json=directDev,proto3" json:"direct_dev,omitempty"`
+	Transitive          int32                  `protobuf:"varint,3,opt,name=transitive,proto3" json:"transitive,omitempty"`
+	TransitiveDev       int32                  `protobuf:"varint,4,opt,name=transitive_dev,json=transitiveDev,proto3" json:"transitive_dev,omitempty"`
+	DirectLatest        int32                  `protobuf:"varint,5,opt,name=direct_latest,json=directLatest,proto3" json:"direct_latest,omitempty"`
+	DirectDevLatest     int32                  `protobuf:"varint,6,opt,name=direct_dev_latest,json=directDevLatest,proto3" json:"direct_dev_latest,omitempty"`
+	TransitiveLatest    int32                  `protobuf:"varint,7,opt,name=transitive_latest,json=transitiveLatest,proto3" json:"transitive_latest,omitempty"`
+	TransitiveDevLatest int32                  `protobuf:"varint,8,opt,name=transitive_dev_latest,json=transitiveDevLatest,proto3" json:"transitive_dev_latest,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
-func (x *ModuleDependency) Reset() {
-	*x = ModuleDependency{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[16]
+func (x *ReverseDependencyCounts) Reset() {
+	*x = ReverseDependencyCounts{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ModuleDependency) String() string {
+func (x *ReverseDependencyCounts) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ModuleDependency) ProtoMessage() {}
+func (*ReverseDependencyCounts) ProtoMessage() {}
 
-func (x *ModuleDependency) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[16]
+func (x *ReverseDependencyCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1396,86 +3541,89 @@ func (x *ModuleDependency) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ModuleDependency.ProtoReflect.Descriptor instead.
-func (*ModuleDependency) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use ReverseDependencyCounts.ProtoReflect.Descriptor instead.
+func (*ReverseDependencyCounts) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *ModuleDependency) GetName() string {
+func (x *ReverseDependencyCounts) GetDirect() int32 {
 	if x != nil {
-		return x.Name
+		return x.Direct
 	}
-	return ""
+	return 0
 }
 
-func (x *ModuleDependency) GetVersion() string {
+func (x *ReverseDependencyCounts) GetDirectDev() int32 {
 	if x != nil {
-		return x.Version
+		return x.DirectDev
 	}
-	return ""
+	return 0
 }
 
-func (x *ModuleDependency) GetRepoName() string {
+func (x *ReverseDependencyCounts) GetTransitive() int32 {
 	if x != nil {
-		return x.RepoName
+		return x.Transitive
 	}
-	return ""
+	return 0
 }
 
-func (x *ModuleDependency) GetDev() bool {
+func (x *ReverseDependencyCounts) GetTransitiveDev() int32 {
 	if x != nil {
-		return x.Dev
+		return x.TransitiveDev
 	}
-	return false
+	return 0
 }
 
-func (x *ModuleDependency) GetMaxCompatibilityLevel() int32 {
+func (x *ReverseDependencyCounts) GetDirectLatest() int32 {
 	if x != nil {
-		return x.MaxCompatibilityLevel
+		return x.DirectLatest
 	}
 	return 0
 }
 
-func (x *ModuleDependency) GetOverride() *ModuleDependencyOverride {
+func (x *ReverseDependencyCounts) GetDirectDevLatest() int32 {
 	if x != nil {
-		return x.Override
+		return x.DirectDevLatest
 	}
-	return nil
+	return 0
 }
 
-func (x *ModuleDependency) GetUnresolved() bool {
+func (x *ReverseDependencyCounts) GetTransitiveLatest() int32 {
 	if x != nil {
-		return x.Unresolved
+		return x.TransitiveLatest
 	}
-	return false
+	return 0
 }
 
-type GitOverride struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Commit        string                 `protobuf:"bytes,1,opt,name=commit,proto3" json:"commit,omitempty"`
-	PatchStrip    int32                  `protobuf:"varint,2,opt,name=patch_strip,json=patchStrip,proto3" json:"patch_strip,omitempty"`
-	Patches       []string               `protobuf:"bytes,3,rep,name=patches,proto3" json:"patches,omitempty"`
-	Remote        string                 `protobuf:"bytes,4,opt,name=remote,proto3" json:"remote,omitempty"`
-	Branch        string                 `protobuf:"bytes,5,opt,name=branch,proto3" json:"branch,omitempty"`
+func (x *ReverseDependencyCounts) GetTransitiveDevLatest() int32 {
+	if x != nil {
+		return x.TransitiveDevLatest
+	}
+	return 0
+}
+
+type ModuleDependencyCycleReport struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Cycles        []*ModuleDependencyCycle `protobuf:"bytes,1,rep,name=cycles,proto3" json:"cycles,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GitOverride) Reset() {
-	*x = GitOverride{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[17]
+func (x *ModuleDependencyCycleReport) Reset() {
+	*x = ModuleDependencyCycleReport{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GitOverride) String() string {
+func (x *ModuleDependencyCycleReport) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GitOverride) ProtoMessage() {}
+func (*ModuleDependencyCycleReport) ProtoMessage() {}
 
-func (x *GitOverride) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[17]
+func (x *ModuleDependencyCycleReport) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1486,72 +3634,44 @@ func (x *GitOverride) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GitOverride.ProtoReflect.Descriptor instead.
-func (*GitOverride) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{17}
-}
-
-func (x *GitOverride) GetCommit() string {
-	if x != nil {
-		return x.Commit
-	}
-	return ""
-}
-
-func (x *GitOverride) GetPatchStrip() int32 {
-	if x != nil {
-		return x.PatchStrip
-	}
-	return 0
-}
-
-func (x *GitOverride) GetPatches() []string {
-	if x != nil {
-		return x.Patches
-	}
-	return nil
-}
-
-func (x *GitOverride) GetRemote() string {
-	if x != nil {
-		return x.Remote
-	}
-	return ""
+// Deprecated: Use ModuleDependencyCycleReport.ProtoReflect.Descriptor instead.
+func (*ModuleDependencyCycleReport) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *GitOverride) GetBranch() string {
+func (x *ModuleDependencyCycleReport) GetCycles() []*ModuleDependencyCycle {
 	if x != nil {
-		return x.Branch
+		return x.Cycles
 	}
-	return ""
+	return nil
 }
 
-type ArchiveOverride struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Integrity     string                 `protobuf:"bytes,1,opt,name=integrity,proto3" json:"integrity,omitempty"`
-	PatchStrip    int32                  `protobuf:"varint,2,opt,name=patch_strip,json=patchStrip,proto3" json:"patch_strip,omitempty"`
-	Patches       []string               `protobuf:"bytes,3,rep,name=patches,proto3" json:"patches,omitempty"`
-	StripPrefix   string                 `protobuf:"bytes,4,opt,name=strip_prefix,json=stripPrefix,proto3" json:"strip_prefix,omitempty"`
-	Urls          []string               `protobuf:"bytes,5,rep,name=urls,proto3" json:"urls,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ModuleDependencyCycle struct {
+	state          protoimpl.MessageState       `protogen:"open.v1"`
+	Name           string                       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Modules        []string                     `protobuf:"bytes,2,rep,name=modules,proto3" json:"modules,omitempty"`
+	Paths          []*ModuleDependencyCyclePath `protobuf:"bytes,3,rep,name=paths,proto3" json:"paths,omitempty"`
+	DevOnly        bool                         `protobuf:"varint,4,opt,name=dev_only,json=devOnly,proto3" json:"dev_only,omitempty"`
+	LatestVersions []string                     `protobuf:"bytes,5,rep,name=latest_versions,json=latestVersions,proto3" json:"latest_versions,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ArchiveOverride) Reset() {
-	*x = ArchiveOverride{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[18]
+func (x *ModuleDependencyCycle) Reset() {
+	*x = ModuleDependencyCycle{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ArchiveOverride) String() string {
+func (x *ModuleDependencyCycle) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ArchiveOverride) ProtoMessage() {}
+func (*ModuleDependencyCycle) ProtoMessage() {}
 
-func (x *ArchiveOverride) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[18]
+func (x *ModuleDependencyCycle) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1562,70 +3682,69 @@ func (x *ArchiveOverride) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ArchiveOverride.ProtoReflect.Descriptor instead.
-func (*ArchiveOverride) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use ModuleDependencyCycle.ProtoReflect.Descriptor instead.
+func (*ModuleDependencyCycle) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *ArchiveOverride) GetIntegrity() string {
+func (x *ModuleDependencyCycle) GetName() string {
 	if x != nil {
-		return x.Integrity
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ArchiveOverride) GetPatchStrip() int32 {
+func (x *ModuleDependencyCycle) GetModules() []string {
 	if x != nil {
-		return x.PatchStrip
+		return x.Modules
 	}
-	return 0
+	return nil
 }
 
-func (x *ArchiveOverride) GetPatches() []string {
+func (x *ModuleDependencyCycle) GetPaths() []*ModuleDependencyCyclePath {
 	if x != nil {
-		return x.Patches
+		return x.Paths
 	}
 	return nil
 }
 
-func (x *ArchiveOverride) GetStripPrefix() string {
+func (x *ModuleDependencyCycle) GetDevOnly() bool {
 	if x != nil {
-		return x.StripPrefix
+		return x.DevOnly
 	}
-	return ""
+	return false
 }
 
-func (x *ArchiveOverride) GetUrls() []string {
+func (x *ModuleDependencyCycle) GetLatestVersions() []string {
 	if x != nil {
-		return x.Urls
+		return x.LatestVersions
 	}
 	return nil
 }
 
-type SingleVersionOverride struct {
+type ModuleDependencyCyclePath struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PatchStrip    int32                  `protobuf:"varint,1,opt,name=patch_strip,json=patchStrip,proto3" json:"patch_strip,omitempty"`
-	Patches       []string               `protobuf:"bytes,2,rep,name=patches,proto3" json:"patches,omitempty"`
-	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Modules       []string               `protobuf:"bytes,1,rep,name=modules,proto3" json:"modules,omitempty"`
+	Dev           bool                   `protobuf:"varint,2,opt,name=dev,proto3" json:"dev,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SingleVersionOverride) Reset() {
-	*x = SingleVersionOverride{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[19]
+func (x *ModuleDependencyCyclePath) Reset() {
+	*x = ModuleDependencyCyclePath{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SingleVersionOverride) String() string {
+func (x *ModuleDependencyCyclePath) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SingleVersionOverride) ProtoMessage() {}
+func (*ModuleDependencyCyclePath) ProtoMessage() {}
 
-func (x *SingleVersionOverride) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[19]
+func (x *ModuleDependencyCyclePath) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1636,54 +3755,52 @@ func (x *SingleVersionOverride) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SingleVersionOverride.ProtoReflect.Descriptor instead.
-func (*SingleVersionOverride) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{19}
-}
-
-func (x *SingleVersionOverride) GetPatchStrip() int32 {
-	if x != nil {
-		return x.PatchStrip
-	}
-	return 0
+// Deprecated: Use ModuleDependencyCyclePath.ProtoReflect.Descriptor instead.
+func (*ModuleDependencyCyclePath) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *SingleVersionOverride) GetPatches() []string {
+func (x *ModuleDependencyCyclePath) GetModules() []string {
 	if x != nil {
-		return x.Patches
+		return x.Modules
 	}
 	return nil
 }
 
-func (x *SingleVersionOverride) GetVersion() string {
+func (x *ModuleDependencyCyclePath) GetDev() bool {
 	if x != nil {
-		return x.Version
+		return x.Dev
 	}
-	return ""
+	return false
 }
 
-type LocalPathOverride struct {
+type RegistryDiagnostic struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	File          string                 `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	ModuleName    string                 `protobuf:"bytes,2,opt,name=module_name,json=moduleName,proto3" json:"module_name,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Severity      DiagnosticSeverity     `protobuf:"varint,4,opt,name=severity,proto3,enum=build.stack.bazel.registry.v1.DiagnosticSeverity" json:"severity,omitempty"`
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Check         string                 `protobuf:"bytes,6,opt,name=check,proto3" json:"check,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LocalPathOverride) Reset() {
-	*x = LocalPathOverride{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[20]
+func (x *RegistryDiagnostic) Reset() {
+	*x = RegistryDiagnostic{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LocalPathOverride) String() string {
+func (x *RegistryDiagnostic) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LocalPathOverride) ProtoMessage() {}
+func (*RegistryDiagnostic) ProtoMessage() {}
 
-func (x *LocalPathOverride) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[20]
+func (x *RegistryDiagnostic) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1694,106 +3811,75 @@ func (x *LocalPathOverride) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LocalPathOverride.ProtoReflect.Descriptor instead.
-func (*LocalPathOverride) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use RegistryDiagnostic.ProtoReflect.Descriptor instead.
+func (*RegistryDiagnostic) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *LocalPathOverride) GetPath() string {
+func (x *RegistryDiagnostic) GetFile() string {
 	if x != nil {
-		return x.Path
+		return x.File
 	}
 	return ""
 }
 
-type Presubmit struct {
-	state         protoimpl.MessageState              `protogen:"open.v1"`
-	BcrTestModule *Presubmit_BcrTestModule            `protobuf:"bytes,1,opt,name=bcr_test_module,json=bcrTestModule,proto3" json:"bcr_test_module,omitempty"`
-	Matrix        *Presubmit_PresubmitMatrix          `protobuf:"bytes,2,opt,name=matrix,proto3" json:"matrix,omitempty"`
-	Tasks         map[string]*Presubmit_PresubmitTask `protobuf:"bytes,3,rep,name=tasks,proto3" json:"tasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *Presubmit) Reset() {
-	*x = Presubmit{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[21]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *Presubmit) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*Presubmit) ProtoMessage() {}
-
-func (x *Presubmit) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[21]
+func (x *RegistryDiagnostic) GetModuleName() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ModuleName
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use Presubmit.ProtoReflect.Descriptor instead.
-func (*Presubmit) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{21}
+func (x *RegistryDiagnostic) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
 }
 
-func (x *Presubmit) GetBcrTestModule() *Presubmit_BcrTestModule {
+func (x *RegistryDiagnostic) GetSeverity() DiagnosticSeverity {
 	if x != nil {
-		return x.BcrTestModule
+		return x.Severity
 	}
-	return nil
+	return DiagnosticSeverity_DIAGNOSTIC_SEVERITY_UNKNOWN
 }
 
-func (x *Presubmit) GetMatrix() *Presubmit_PresubmitMatrix {
+func (x *RegistryDiagnostic) GetMessage() string {
 	if x != nil {
-		return x.Matrix
+		return x.Message
 	}
-	return nil
+	return ""
 }
 
-func (x *Presubmit) GetTasks() map[string]*Presubmit_PresubmitTask {
+func (x *RegistryDiagnostic) GetCheck() string {
 	if x != nil {
-		return x.Tasks
+		return x.Check
 	}
-	return nil
+	return ""
 }
 
-type DependencyTreeNode struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	ModuleVersion    *ModuleVersion         `protobuf:"bytes,1,opt,name=module_version,json=moduleVersion,proto3" json:"module_version,omitempty"`
-	RequestedVersion string                 `protobuf:"bytes,2,opt,name=requested_version,json=requestedVersion,proto3" json:"requested_version,omitempty"`
-	Upgraded         bool                   `protobuf:"varint,3,opt,name=upgraded,proto3" json:"upgraded,omitempty"`
-	Dev              bool                   `protobuf:"varint,4,opt,name=dev,proto3" json:"dev,omitempty"`
-	Children         []*DependencyTreeNode  `protobuf:"bytes,5,rep,name=children,proto3" json:"children,omitempty"`
-	Pruned           bool                   `protobuf:"varint,6,opt,name=pruned,proto3" json:"pruned,omitempty"`
-	OverrideType     string                 `protobuf:"bytes,7,opt,name=override_type,json=overrideType,proto3" json:"override_type,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+type RegistryDiagnosticReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Diagnostics   []*RegistryDiagnostic  `protobuf:"bytes,1,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DependencyTreeNode) Reset() {
-	*x = DependencyTreeNode{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[22]
+func (x *RegistryDiagnosticReport) Reset() {
+	*x = RegistryDiagnosticReport{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DependencyTreeNode) String() string {
+func (x *RegistryDiagnosticReport) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DependencyTreeNode) ProtoMessage() {}
+func (*RegistryDiagnosticReport) ProtoMessage() {}
 
-func (x *DependencyTreeNode) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[22]
+func (x *RegistryDiagnosticReport) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1804,83 +3890,100 @@ func (x *DependencyTreeNode) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DependencyTreeNode.ProtoReflect.Descriptor instead.
-func (*DependencyTreeNode) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use RegistryDiagnosticReport.ProtoReflect.Descriptor instead.
+func (*RegistryDiagnosticReport) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *DependencyTreeNode) GetModuleVersion() *ModuleVersion {
+func (x *RegistryDiagnosticReport) GetDiagnostics() []*RegistryDiagnostic {
 	if x != nil {
-		return x.ModuleVersion
+		return x.Diagnostics
 	}
 	return nil
 }
 
-func (x *DependencyTreeNode) GetRequestedVersion() string {
-	if x != nil {
-		return x.RequestedVersion
-	}
-	return ""
+type CacheMiss struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Kind           CacheMissKind          `protobuf:"varint,1,opt,name=kind,proto3,enum=build.stack.bazel.registry.v1.CacheMissKind" json:"kind,omitempty"`
+	Key            string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	ModuleVersions []string               `protobuf:"bytes,3,rep,name=module_versions,json=moduleVersions,proto3" json:"module_versions,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *DependencyTreeNode) GetUpgraded() bool {
-	if x != nil {
-		return x.Upgraded
-	}
-	return false
+func (x *CacheMiss) Reset() {
+	*x = CacheMiss{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *DependencyTreeNode) GetDev() bool {
+func (x *CacheMiss) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheMiss) ProtoMessage() {}
+
+func (x *CacheMiss) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[45]
 	if x != nil {
-		return x.Dev
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *DependencyTreeNode) GetChildren() []*DependencyTreeNode {
+// Deprecated: Use CacheMiss.ProtoReflect.Descriptor instead.
+func (*CacheMiss) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *CacheMiss) GetKind() CacheMissKind {
 	if x != nil {
-		return x.Children
+		return x.Kind
 	}
-	return nil
+	return CacheMissKind_CACHE_MISS_KIND_UNKNOWN
 }
 
-func (x *DependencyTreeNode) GetPruned() bool {
+func (x *CacheMiss) GetKey() string {
 	if x != nil {
-		return x.Pruned
+		return x.Key
 	}
-	return false
+	return ""
 }
 
-func (x *DependencyTreeNode) GetOverrideType() string {
+func (x *CacheMiss) GetModuleVersions() []string {
 	if x != nil {
-		return x.OverrideType
+		return x.ModuleVersions
 	}
-	return ""
+	return nil
 }
 
-type DependencyTree struct {
+type CacheMissReport struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ModuleVersion *ModuleVersion         `protobuf:"bytes,1,opt,name=module_version,json=moduleVersion,proto3" json:"module_version,omitempty"`
-	Children      []*DependencyTreeNode  `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
+	Misses        []*CacheMiss           `protobuf:"bytes,1,rep,name=misses,proto3" json:"misses,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DependencyTree) Reset() {
-	*x = DependencyTree{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[23]
+func (x *CacheMissReport) Reset() {
+	*x = CacheMissReport{}
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DependencyTree) String() string {
+func (x *CacheMissReport) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DependencyTree) ProtoMessage() {}
+func (*CacheMissReport) ProtoMessage() {}
 
-func (x *DependencyTree) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[23]
+func (x *CacheMissReport) ProtoReflect() protoreflect.Message {
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1891,36 +3994,30 @@ func (x *DependencyTree) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DependencyTree.ProtoReflect.Descriptor instead.
-func (*DependencyTree) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{23}
-}
-
-func (x *DependencyTree) GetModuleVersion() *ModuleVersion {
-	if x != nil {
-		return x.ModuleVersion
-	}
-	return nil
+// Deprecated: Use CacheMissReport.ProtoReflect.Descriptor instead.
+func (*CacheMissReport) Descriptor() ([]byte, []int) {
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *DependencyTree) GetChildren() []*DependencyTreeNode {
+func (x *CacheMissReport) GetMisses() []*CacheMiss {
 	if x != nil {
-		return x.Children
+		return x.Misses
 	}
 	return nil
 }
 
 type Attestations_Attestation struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	Integrity     string                 `protobuf:"bytes,2,opt,name=integrity,proto3" json:"integrity,omitempty"`
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Url           string                   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Integrity     string                   `protobuf:"bytes,2,opt,name=integrity,proto3" json:"integrity,omitempty"`
+	Verification  *AttestationVerification `protobuf:"bytes,3,opt,name=verification,proto3" json:"verification,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Attestations_Attestation) Reset() {
 	*x = Attestations_Attestation{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[28]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1932,7 +4029,7 @@ func (x *Attestations_Attestation) String() string {
 func (*Attestations_Attestation) ProtoMessage() {}
 
 func (x *Attestations_Attestation) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[28]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1945,7 +4042,7 @@ func (x *Attestations_Attestation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Attestations_Attestation.ProtoReflect.Descriptor instead.
 func (*Attestations_Attestation) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{12, 0}
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{18, 0}
 }
 
 func (x *Attestations_Attestation) GetUrl() string {
@@ -1962,6 +4059,13 @@ func (x *Attestations_Attestation) GetIntegrity() string {
 	return ""
 }
 
+func (x *Attestations_Attestation) GetVerification() *AttestationVerification {
+	if x != nil {
+		return x.Verification
+	}
+	return nil
+}
+
 type Presubmit_BcrTestModule struct {
 	state         protoimpl.MessageState              `protogen:"open.v1"`
 	ModulePath    string                              `protobuf:"bytes,1,opt,name=module_path,json=modulePath,proto3" json:"module_path,omitempty"`
@@ -1973,7 +4077,7 @@ type Presubmit_BcrTestModule struct {
 
 func (x *Presubmit_BcrTestModule) Reset() {
 	*x = Presubmit_BcrTestModule{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[30]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1985,7 +4089,7 @@ func (x *Presubmit_BcrTestModule) String() string {
 func (*Presubmit_BcrTestModule) ProtoMessage() {}
 
 func (x *Presubmit_BcrTestModule) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[30]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1998,7 +4102,7 @@ func (x *Presubmit_BcrTestModule) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Presubmit_BcrTestModule.ProtoReflect.Descriptor instead.
 func (*Presubmit_BcrTestModule) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{21, 0}
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{34, 0}
 }
 
 func (x *Presubmit_BcrTestModule) GetModulePath() string {
@@ -2032,7 +4136,7 @@ type Presubmit_PresubmitMatrix struct {
 
 func (x *Presubmit_PresubmitMatrix) Reset() {
 	*x = Presubmit_PresubmitMatrix{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[31]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2044,7 +4148,7 @@ func (x *Presubmit_PresubmitMatrix) String() string {
 func (*Presubmit_PresubmitMatrix) ProtoMessage() {}
 
 func (x *Presubmit_PresubmitMatrix) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[31]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2057,7 +4161,7 @@ func (x *Presubmit_PresubmitMatrix) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Presubmit_PresubmitMatrix.ProtoReflect.Descriptor instead.
 func (*Presubmit_PresubmitMatrix) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{21, 1}
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{34, 1}
 }
 
 func (x *Presubmit_PresubmitMatrix) GetPlatform() []string {
@@ -2089,7 +4193,7 @@ type Presubmit_PresubmitTask struct {
 
 func (x *Presubmit_PresubmitTask) Reset() {
 	*x = Presubmit_PresubmitTask{}
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[32]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2101,7 +4205,7 @@ func (x *Presubmit_PresubmitTask) String() string {
 func (*Presubmit_PresubmitTask) ProtoMessage() {}
 
 func (x *Presubmit_PresubmitTask) ProtoReflect() protoreflect.Message {
-	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[32]
+	mi := &file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2114,7 +4218,7 @@ func (x *Presubmit_PresubmitTask) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Presubmit_PresubmitTask.ProtoReflect.Descriptor instead.
 func (*Presubmit_PresubmitTask) Descriptor() ([]byte, []int) {
-	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{21, 2}
+	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP(), []int{34, 2}
 }
 
 func (x *Presubmit_PresubmitTask) GetName() string {
@@ -2180,12 +4284,26 @@ const file_build_stack_bazel_registry_v1_bcr_proto_rawDesc = "" +
 	"commit_sha\x18\x05 \x01(\tR\tcommitSha\x12%\n" +
 	"\x0ecommit_message\x18\x06 \x01(\tR\rcommitMessage\x12\x1f\n" +
 	"\vcommit_date\x18\a \x01(\tR\n" +
-	"commitDate\"\x95\x02\n" +
+	"commitDate\"\xea\x03\n" +
 	"\x06Module\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12I\n" +
 	"\bmetadata\x18\x02 \x01(\v2-.build.stack.bazel.registry.v1.ModuleMetadataR\bmetadata\x12H\n" +
 	"\bversions\x18\x03 \x03(\v2,.build.stack.bazel.registry.v1.ModuleVersionR\bversions\x12b\n" +
-	"\x13repository_metadata\x18\x04 \x01(\v21.build.stack.bazel.registry.v1.RepositoryMetadataR\x12repositoryMetadata\"\x98\x01\n" +
+	"\x13repository_metadata\x18\x04 \x01(\v21.build.stack.bazel.registry.v1.RepositoryMetadataR\x12repositoryMetadata\x12r\n" +
+	"\x19reverse_dependency_counts\x18\x05 \x01(\v26.build.stack.bazel.registry.v1.ReverseDependencyCountsR\x17reverseDependencyCounts\x12\x1a\n" +
+	"\bregistry\x18\x06 \x01(\tR\bregistry\x12C\n" +
+	"\x06health\x18\a \x01(\v2+.build.stack.bazel.registry.v1.ModuleHealthR\x06health\"\xa1\x01\n" +
+	"\fModuleHealth\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x05R\x05score\x12T\n" +
+	"\n" +
+	"components\x18\x02 \x03(\v24.build.stack.bazel.registry.v1.ModuleHealthComponentR\n" +
+	"components\x12%\n" +
+	"\x0ereference_date\x18\x03 \x01(\tR\rreferenceDate\"v\n" +
+	"\x15ModuleHealthComponent\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x05R\x05score\x12\x1b\n" +
+	"\tmax_score\x18\x03 \x01(\x05R\bmaxScore\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"\x98\x01\n" +
 	"\n" +
 	"Maintainer\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x12\n" +
@@ -2206,7 +4324,7 @@ const file_build_stack_bazel_registry_v1_bcr_proto_rawDesc = "" +
 	"deprecated\x1aA\n" +
 	"\x13YankedVersionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc1\x03\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcb\x05\n" +
 	"\x12RepositoryMetadata\x12A\n" +
 	"\x04type\x18\x01 \x01(\x0e2-.build.stack.bazel.registry.v1.RepositoryTypeR\x04type\x12\"\n" +
 	"\forganization\x18\x02 \x01(\tR\forganization\x12\x12\n" +
@@ -2217,27 +4335,59 @@ const file_build_stack_bazel_registry_v1_bcr_proto_rawDesc = "" +
 	"stargazers\x12^\n" +
 	"\tlanguages\x18\x06 \x03(\v2@.build.stack.bazel.registry.v1.RepositoryMetadata.LanguagesEntryR\tlanguages\x12)\n" +
 	"\x10primary_language\x18\a \x01(\tR\x0fprimaryLanguage\x12%\n" +
-	"\x0ecanonical_name\x18\b \x01(\tR\rcanonicalName\x1a<\n" +
+	"\x0ecanonical_name\x18\b \x01(\tR\rcanonicalName\x12\x12\n" +
+	"\x04host\x18\t \x01(\tR\x04host\x12\x18\n" +
+	"\alicense\x18\n" +
+	" \x01(\tR\alicense\x12\x16\n" +
+	"\x06topics\x18\v \x03(\tR\x06topics\x12\x1a\n" +
+	"\barchived\x18\f \x01(\bR\barchived\x12\x1a\n" +
+	"\bdisabled\x18\r \x01(\bR\bdisabled\x12%\n" +
+	"\x0edefault_branch\x18\x0e \x01(\tR\rdefaultBranch\x12\x1b\n" +
+	"\tpushed_at\x18\x0f \x01(\tR\bpushedAt\x12\x1f\n" +
+	"\vopen_issues\x18\x10 \x01(\x05R\n" +
+	"openIssues\x12%\n" +
+	"\x0elatest_release\x18\x11 \x01(\tR\rlatestRelease\x1a<\n" +
 	"\x0eLanguagesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"{\n" +
-	"\x15RepositoryMetadataSet\x12b\n" +
-	"\x13repository_metadata\x18\x01 \x03(\v21.build.stack.bazel.registry.v1.RepositoryMetadataR\x12repositoryMetadata\"\xc4\x01\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xc4\x01\n" +
 	"\x17BazelRepositoryMetadata\x12b\n" +
 	"\x13repository_metadata\x18\x01 \x01(\v21.build.stack.bazel.registry.v1.RepositoryMetadataR\x12repositoryMetadata\x12E\n" +
 	"\arelease\x18\x02 \x03(\v2+.build.stack.bazel.registry.v1.BazelReleaseR\arelease\"\x7f\n" +
 	"\fBazelRelease\x12\x18\n" +
 	"\aversion\x18\x01 \x01(\tR\aversion\x12\x10\n" +
 	"\x03url\x18\x02 \x01(\tR\x03url\x12C\n" +
-	"\x06commit\x18\x03 \x01(\v2+.build.stack.bazel.registry.v1.ModuleCommitR\x06commit\"X\n" +
-	"\x0fBazelReleaseSet\x12E\n" +
-	"\arelease\x18\x01 \x03(\v2+.build.stack.bazel.registry.v1.BazelReleaseR\arelease\"P\n" +
+	"\x06commit\x18\x03 \x01(\v2+.build.stack.bazel.registry.v1.ModuleCommitR\x06commit\"T\n" +
+	"\x0eRegistryCommit\x12\x1a\n" +
+	"\bregistry\x18\x01 \x01(\tR\bregistry\x12\x12\n" +
+	"\x04sha1\x18\x02 \x01(\tR\x04sha1\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\"\xeb\x02\n" +
+	"\x11RegistryCommitSet\x12E\n" +
+	"\x06commit\x18\x01 \x03(\v2-.build.stack.bazel.registry.v1.RegistryCommitR\x06commit\x123\n" +
+	"\x15generator_fingerprint\x18\x02 \x01(\tR\x14generatorFingerprint\x12\x8c\x01\n" +
+	"\x1amodule_config_fingerprints\x18\x03 \x03(\v2N.build.stack.bazel.registry.v1.RegistryCommitSet.ModuleConfigFingerprintsEntryR\x18moduleConfigFingerprints\x1aK\n" +
+	"\x1dModuleConfigFingerprintsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"P\n" +
 	"\x0eResourceStatus\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\x12\x12\n" +
 	"\x04code\x18\x02 \x01(\x05R\x04code\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"Z\n" +
-	"\x11ResourceStatusSet\x12E\n" +
-	"\x06status\x18\x01 \x03(\v2-.build.stack.bazel.registry.v1.ResourceStatusR\x06status\"\xe2\x06\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xbe\x03\n" +
+	"\n" +
+	"CacheEntry\x12A\n" +
+	"\x04type\x18\x01 \x01(\x0e2-.build.stack.bazel.registry.v1.CacheEntryTypeR\x04type\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x1d\n" +
+	"\n" +
+	"fetched_at\x18\x03 \x01(\tR\tfetchedAt\x12X\n" +
+	"\x0fresource_status\x18\x04 \x01(\v2-.build.stack.bazel.registry.v1.ResourceStatusH\x00R\x0eresourceStatus\x12d\n" +
+	"\x13repository_metadata\x18\x05 \x01(\v21.build.stack.bazel.registry.v1.RepositoryMetadataH\x00R\x12repositoryMetadata\x12R\n" +
+	"\rbazel_release\x18\x06 \x01(\v2+.build.stack.bazel.registry.v1.BazelReleaseH\x00R\fbazelRelease\x12\x1f\n" +
+	"\n" +
+	"commit_sha\x18\a \x01(\tH\x00R\tcommitShaB\a\n" +
+	"\x05value\"k\n" +
+	"\n" +
+	"CacheStore\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12C\n" +
+	"\aentries\x18\x02 \x03(\v2).build.stack.bazel.registry.v1.CacheEntryR\aentries\"\x87\v\n" +
 	"\fModuleSource\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1c\n" +
 	"\tintegrity\x18\x02 \x01(\tR\tintegrity\x12!\n" +
@@ -2259,23 +4409,43 @@ const file_build_stack_bazel_registry_v1_bcr_proto_rawDesc = "" +
 	"\n" +
 	"url_status\x18\x0f \x01(\v2-.build.stack.bazel.registry.v1.ResourceStatusR\turlStatus\x12\x1d\n" +
 	"\n" +
-	"commit_sha\x18\x10 \x01(\tR\tcommitSha\x1a:\n" +
+	"commit_sha\x18\x10 \x01(\tR\tcommitSha\x12h\n" +
+	"\x0fpatch_integrity\x18\x11 \x03(\v2?.build.stack.bazel.registry.v1.ModuleSource.PatchIntegrityEntryR\x0epatchIntegrity\x12n\n" +
+	"\x11overlay_integrity\x18\x12 \x03(\v2A.build.stack.bazel.registry.v1.ModuleSource.OverlayIntegrityEntryR\x10overlayIntegrity\x12e\n" +
+	"\x14archive_verification\x18\x13 \x01(\v22.build.stack.bazel.registry.v1.ArchiveVerificationR\x13archiveVerification\x1a:\n" +
 	"\fPatchesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
 	"\fOverlayEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc9\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1ao\n" +
+	"\x13PatchIntegrityEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12B\n" +
+	"\x05value\x18\x02 \x01(\v2,.build.stack.bazel.registry.v1.FileIntegrityR\x05value:\x028\x01\x1aq\n" +
+	"\x15OverlayIntegrityEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12B\n" +
+	"\x05value\x18\x02 \x01(\v2,.build.stack.bazel.registry.v1.FileIntegrityR\x05value:\x028\x01\"\xac\x01\n" +
+	"\x13ArchiveVerification\x12P\n" +
+	"\x06status\x18\x01 \x01(\x0e28.build.stack.bazel.registry.v1.ArchiveVerificationStatusR\x06status\x12)\n" +
+	"\x10actual_integrity\x18\x02 \x01(\tR\x0factualIntegrity\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x86\x01\n" +
+	"\rFileIntegrity\x12J\n" +
+	"\x06status\x18\x01 \x01(\x0e22.build.stack.bazel.registry.v1.FileIntegrityStatusR\x06status\x12)\n" +
+	"\x10actual_integrity\x18\x02 \x01(\tR\x0factualIntegrity\"\x89\x01\n" +
+	"\x17AttestationVerification\x12T\n" +
+	"\x06status\x18\x01 \x01(\x0e2<.build.stack.bazel.registry.v1.AttestationVerificationStatusR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xa6\x03\n" +
 	"\fAttestations\x12\x1d\n" +
 	"\n" +
 	"media_type\x18\x01 \x01(\tR\tmediaType\x12a\n" +
-	"\fattestations\x18\x02 \x03(\v2=.build.stack.bazel.registry.v1.Attestations.AttestationsEntryR\fattestations\x1a=\n" +
+	"\fattestations\x18\x02 \x03(\v2=.build.stack.bazel.registry.v1.Attestations.AttestationsEntryR\fattestations\x1a\x99\x01\n" +
 	"\vAttestation\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1c\n" +
-	"\tintegrity\x18\x02 \x01(\tR\tintegrity\x1ax\n" +
+	"\tintegrity\x18\x02 \x01(\tR\tintegrity\x12Z\n" +
+	"\fverification\x18\x03 \x01(\v26.build.stack.bazel.registry.v1.AttestationVerificationR\fverification\x1ax\n" +
 	"\x11AttestationsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12M\n" +
-	"\x05value\x18\x02 \x01(\v27.build.stack.bazel.registry.v1.Attestations.AttestationR\x05value:\x028\x01\"\xbf\x06\n" +
+	"\x05value\x18\x02 \x01(\v27.build.stack.bazel.registry.v1.Attestations.AttestationR\x05value:\x028\x01\"\xe6\t\n" +
 	"\rModuleVersion\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12/\n" +
@@ -2291,7 +4461,53 @@ const file_build_stack_bazel_registry_v1_bcr_proto_rawDesc = "" +
 	"\boverride\x18\v \x03(\v27.build.stack.bazel.registry.v1.ModuleDependencyOverrideR\boverride\x12C\n" +
 	"\x06commit\x18\f \x01(\v2+.build.stack.bazel.registry.v1.ModuleCommitR\x06commit\x12b\n" +
 	"\x13repository_metadata\x18\r \x01(\v21.build.stack.bazel.registry.v1.RepositoryMetadataR\x12repositoryMetadata\x12*\n" +
-	"\x11is_latest_version\x18\x0e \x01(\bR\x0fisLatestVersion\"s\n" +
+	"\x11is_latest_version\x18\x0e \x01(\bR\x0fisLatestVersion\x12Y\n" +
+	"\x10resolution_error\x18\x0f \x01(\v2..build.stack.bazel.registry.v1.ResolutionErrorR\x0fresolutionError\x12r\n" +
+	"\x19bazel_compatibility_range\x18\x10 \x01(\v26.build.stack.bazel.registry.v1.BazelCompatibilityRangeR\x17bazelCompatibilityRange\x12k\n" +
+	"\x17dev_dependency_upgrades\x18\x11 \x03(\v23.build.stack.bazel.registry.v1.DevDependencyUpgradeR\x15devDependencyUpgrades\x12\x1a\n" +
+	"\bregistry\x18\x12 \x01(\tR\bregistry\x12M\n" +
+	"\n" +
+	"provenance\x18\x13 \x01(\v2-.build.stack.bazel.registry.v1.SlsaProvenanceR\n" +
+	"provenance\"\xc3\x02\n" +
+	"\x0eSlsaProvenance\x12%\n" +
+	"\x0epredicate_type\x18\x01 \x01(\tR\rpredicateType\x12\x1d\n" +
+	"\n" +
+	"builder_id\x18\x02 \x01(\tR\tbuilderId\x12+\n" +
+	"\x11source_repository\x18\x03 \x01(\tR\x10sourceRepository\x12\x1d\n" +
+	"\n" +
+	"source_ref\x18\x04 \x01(\tR\tsourceRef\x12#\n" +
+	"\rsource_commit\x18\x05 \x01(\tR\fsourceCommit\x12#\n" +
+	"\rworkflow_path\x18\x06 \x01(\tR\fworkflowPath\x12.\n" +
+	"\x13build_invocation_id\x18\a \x01(\tR\x11buildInvocationId\x12%\n" +
+	"\x0eattested_files\x18\b \x03(\tR\rattestedFiles\"r\n" +
+	"\x14DevDependencyUpgrade\x12\x1f\n" +
+	"\vmodule_name\x18\x01 \x01(\tR\n" +
+	"moduleName\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x1f\n" +
+	"\vdev_version\x18\x03 \x01(\tR\n" +
+	"devVersion\"\xdd\x01\n" +
+	"\x17BazelCompatibilityRange\x12\x1f\n" +
+	"\vmin_version\x18\x01 \x01(\tR\n" +
+	"minVersion\x12\x1f\n" +
+	"\vmax_version\x18\x02 \x01(\tR\n" +
+	"maxVersion\x12#\n" +
+	"\rversion_count\x18\x03 \x01(\x05R\fversionCount\x12[\n" +
+	"\vnarrowed_by\x18\x04 \x03(\v2:.build.stack.bazel.registry.v1.BazelCompatibilityNarrowingR\n" +
+	"narrowedBy\"f\n" +
+	"\x1bBazelCompatibilityNarrowing\x12\x16\n" +
+	"\x06module\x18\x01 \x01(\tR\x06module\x12/\n" +
+	"\x13bazel_compatibility\x18\x02 \x03(\tR\x12bazelCompatibility\"\x84\x01\n" +
+	"\x0fResolutionError\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12W\n" +
+	"\tconflicts\x18\x02 \x03(\v29.build.stack.bazel.registry.v1.CompatibilityLevelConflictR\tconflicts\"\x9f\x01\n" +
+	"\x1aCompatibilityLevelConflict\x12\x1f\n" +
+	"\vmodule_name\x18\x01 \x01(\tR\n" +
+	"moduleName\x12`\n" +
+	"\frequirements\x18\x02 \x03(\v2<.build.stack.bazel.registry.v1.CompatibilityLevelRequirementR\frequirements\"~\n" +
+	"\x1dCompatibilityLevelRequirement\x12/\n" +
+	"\x13compatibility_level\x18\x01 \x01(\x05R\x12compatibilityLevel\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x12\n" +
+	"\x04path\x18\x03 \x03(\tR\x04path\"s\n" +
 	"\fModuleCommit\x12\x12\n" +
 	"\x04sha1\x18\x01 \x01(\tR\x04sha1\x12\x12\n" +
 	"\x04date\x18\x02 \x01(\tR\x04date\x12\x18\n" +
@@ -2378,13 +4594,116 @@ const file_build_stack_bazel_registry_v1_bcr_proto_rawDesc = "" +
 	"\roverride_type\x18\a \x01(\tR\foverrideType\"\xb4\x01\n" +
 	"\x0eDependencyTree\x12S\n" +
 	"\x0emodule_version\x18\x01 \x01(\v2,.build.stack.bazel.registry.v1.ModuleVersionR\rmoduleVersion\x12M\n" +
-	"\bchildren\x18\x02 \x03(\v21.build.stack.bazel.registry.v1.DependencyTreeNodeR\bchildren*E\n" +
+	"\bchildren\x18\x02 \x03(\v21.build.stack.bazel.registry.v1.DependencyTreeNodeR\bchildren\"y\n" +
+	"\x16ReverseDependencyIndex\x12_\n" +
+	"\x0fmodule_versions\x18\x01 \x03(\v26.build.stack.bazel.registry.v1.ModuleVersionDependentsR\x0emoduleVersions\"\xf6\x02\n" +
+	"\x17ModuleVersionDependents\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x16\n" +
+	"\x06direct\x18\x03 \x03(\tR\x06direct\x12\x1d\n" +
+	"\n" +
+	"direct_dev\x18\x04 \x03(\tR\tdirectDev\x12+\n" +
+	"\x11transitive_latest\x18\x05 \x03(\tR\x10transitiveLatest\x122\n" +
+	"\x15transitive_dev_latest\x18\x06 \x03(\tR\x13transitiveDevLatest\x12N\n" +
+	"\x06counts\x18\a \x01(\v26.build.stack.bazel.registry.v1.ReverseDependencyCountsR\x06counts\x12\x1e\n" +
+	"\n" +
+	"transitive\x18\b \x03(\tR\n" +
+	"transitive\x12%\n" +
+	"\x0etransitive_dev\x18\t \x03(\tR\rtransitiveDev\"\xc9\x02\n" +
+	"\x17ReverseDependencyCounts\x12\x16\n" +
+	"\x06direct\x18\x01 \x01(\x05R\x06direct\x12\x1d\n" +
+	"\n" +
+	"direct_dev\x18\x02 \x01(\x05R\tdirectDev\x12\x1e\n" +
+	"\n" +
+	"transitive\x18\x03 \x01(\x05R\n" +
+	"transitive\x12%\n" +
+	"\x0etransitive_dev\x18\x04 \x01(\x05R\rtransitiveDev\x12#\n" +
+	"\rdirect_latest\x18\x05 \x01(\x05R\fdirectLatest\x12*\n" +
+	"\x11direct_dev_latest\x18\x06 \x01(\x05R\x0fdirectDevLatest\x12+\n" +
+	"\x11transitive_latest\x18\a \x01(\x05R\x10transitiveLatest\x122\n" +
+	"\x15transitive_dev_latest\x18\b \x01(\x05R\x13transitiveDevLatest\"k\n" +
+	"\x1bModuleDependencyCycleReport\x12L\n" +
+	"\x06cycles\x18\x01 \x03(\v24.build.stack.bazel.registry.v1.ModuleDependencyCycleR\x06cycles\"\xd9\x01\n" +
+	"\x15ModuleDependencyCycle\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\amodules\x18\x02 \x03(\tR\amodules\x12N\n" +
+	"\x05paths\x18\x03 \x03(\v28.build.stack.bazel.registry.v1.ModuleDependencyCyclePathR\x05paths\x12\x19\n" +
+	"\bdev_only\x18\x04 \x01(\bR\adevOnly\x12'\n" +
+	"\x0flatest_versions\x18\x05 \x03(\tR\x0elatestVersions\"G\n" +
+	"\x19ModuleDependencyCyclePath\x12\x18\n" +
+	"\amodules\x18\x01 \x03(\tR\amodules\x12\x10\n" +
+	"\x03dev\x18\x02 \x01(\bR\x03dev\"\xe2\x01\n" +
+	"\x12RegistryDiagnostic\x12\x12\n" +
+	"\x04file\x18\x01 \x01(\tR\x04file\x12\x1f\n" +
+	"\vmodule_name\x18\x02 \x01(\tR\n" +
+	"moduleName\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12M\n" +
+	"\bseverity\x18\x04 \x01(\x0e21.build.stack.bazel.registry.v1.DiagnosticSeverityR\bseverity\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12\x14\n" +
+	"\x05check\x18\x06 \x01(\tR\x05check\"o\n" +
+	"\x18RegistryDiagnosticReport\x12S\n" +
+	"\vdiagnostics\x18\x01 \x03(\v21.build.stack.bazel.registry.v1.RegistryDiagnosticR\vdiagnostics\"\x88\x01\n" +
+	"\tCacheMiss\x12@\n" +
+	"\x04kind\x18\x01 \x01(\x0e2,.build.stack.bazel.registry.v1.CacheMissKindR\x04kind\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12'\n" +
+	"\x0fmodule_versions\x18\x03 \x03(\tR\x0emoduleVersions\"S\n" +
+	"\x0fCacheMissReport\x12@\n" +
+	"\x06misses\x18\x01 \x03(\v2(.build.stack.bazel.registry.v1.CacheMissR\x06misses*n\n" +
 	"\x0eRepositoryType\x12\x1b\n" +
 	"\x17REPOSITORY_TYPE_UNKNOWN\x10\x00\x12\n" +
 	"\n" +
 	"\x06GITHUB\x10\x01\x12\n" +
 	"\n" +
-	"\x06GITLAB\x10\x02BJZHgithub.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1;bzpbb\x06proto3"
+	"\x06GITLAB\x10\x02\x12\t\n" +
+	"\x05GITEA\x10\x03\x12\r\n" +
+	"\tBITBUCKET\x10\x04\x12\r\n" +
+	"\tSOURCEHUT\x10\x05*\xb2\x01\n" +
+	"\x0eCacheEntryType\x12\x1c\n" +
+	"\x18CACHE_ENTRY_TYPE_UNKNOWN\x10\x00\x12\x1f\n" +
+	"\x1bCACHE_ENTRY_RESOURCE_STATUS\x10\x01\x12#\n" +
+	"\x1fCACHE_ENTRY_REPOSITORY_METADATA\x10\x02\x12\x1d\n" +
+	"\x19CACHE_ENTRY_BAZEL_RELEASE\x10\x03\x12\x1d\n" +
+	"\x19CACHE_ENTRY_SOURCE_COMMIT\x10\x04*\xd6\x01\n" +
+	"\x19ArchiveVerificationStatus\x12'\n" +
+	"#ARCHIVE_VERIFICATION_STATUS_UNKNOWN\x10\x00\x12\x14\n" +
+	"\x10ARCHIVE_VERIFIED\x10\x01\x12\x1e\n" +
+	"\x1aARCHIVE_INTEGRITY_MISMATCH\x10\x02\x12 \n" +
+	"\x1cARCHIVE_STRIP_PREFIX_MISSING\x10\x03\x12\x17\n" +
+	"\x13ARCHIVE_UNAVAILABLE\x10\x04\x12\x1f\n" +
+	"\x1bARCHIVE_MALFORMED_INTEGRITY\x10\x05*\xb1\x01\n" +
+	"\x13FileIntegrityStatus\x12!\n" +
+	"\x1dFILE_INTEGRITY_STATUS_UNKNOWN\x10\x00\x12\x1b\n" +
+	"\x17FILE_INTEGRITY_VERIFIED\x10\x01\x12\x1b\n" +
+	"\x17FILE_INTEGRITY_MISMATCH\x10\x02\x12\x1f\n" +
+	"\x1bFILE_INTEGRITY_FILE_MISSING\x10\x03\x12\x1c\n" +
+	"\x18FILE_INTEGRITY_MALFORMED\x10\x04*\x9f\x03\n" +
+	"\x1dAttestationVerificationStatus\x12+\n" +
+	"'ATTESTATION_VERIFICATION_STATUS_UNKNOWN\x10\x00\x12\x18\n" +
+	"\x14ATTESTATION_VERIFIED\x10\x01\x12\x1b\n" +
+	"\x17ATTESTATION_UNAVAILABLE\x10\x02\x12\"\n" +
+	"\x1eATTESTATION_INTEGRITY_MISMATCH\x10\x03\x12#\n" +
+	"\x1fATTESTATION_MALFORMED_INTEGRITY\x10\x04\x12 \n" +
+	"\x1cATTESTATION_MALFORMED_BUNDLE\x10\x05\x12!\n" +
+	"\x1dATTESTATION_SIGNATURE_INVALID\x10\x06\x12 \n" +
+	"\x1cATTESTATION_SUBJECT_MISMATCH\x10\a\x12#\n" +
+	"\x1fATTESTATION_SUBJECT_UNAVAILABLE\x10\b\x12!\n" +
+	"\x1dATTESTATION_IDENTITY_MISMATCH\x10\t\x12\"\n" +
+	"\x1eATTESTATION_IDENTITY_UNCHECKED\x10\n" +
+	"*M\n" +
+	"\x12DiagnosticSeverity\x12\x1f\n" +
+	"\x1bDIAGNOSTIC_SEVERITY_UNKNOWN\x10\x00\x12\v\n" +
+	"\aWARNING\x10\x01\x12\t\n" +
+	"\x05ERROR\x10\x02*\xb5\x02\n" +
+	"\rCacheMissKind\x12\x1b\n" +
+	"\x17CACHE_MISS_KIND_UNKNOWN\x10\x00\x12\x1e\n" +
+	"\x1aCACHE_MISS_BACKUP_REGISTRY\x10\x01\x12\x1d\n" +
+	"\x19CACHE_MISS_BAZEL_RELEASES\x10\x02\x12\"\n" +
+	"\x1eCACHE_MISS_REPOSITORY_METADATA\x10\x03\x12\x1e\n" +
+	"\x1aCACHE_MISS_DOCS_URL_STATUS\x10\x04\x12 \n" +
+	"\x1cCACHE_MISS_SOURCE_URL_STATUS\x10\x05\x12 \n" +
+	"\x1cCACHE_MISS_SOURCE_COMMIT_SHA\x10\x06\x12\x1d\n" +
+	"\x19CACHE_MISS_SOURCE_ARCHIVE\x10\a\x12!\n" +
+	"\x1dCACHE_MISS_ATTESTATION_BUNDLE\x10\bBJZHgithub.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1;bzpbb\x06proto3"
 
 var (
 	file_build_stack_bazel_registry_v1_bcr_proto_rawDescOnce sync.Once
@@ -2398,97 +4717,160 @@ func file_build_stack_bazel_registry_v1_bcr_proto_rawDescGZIP() []byte {
 	return file_build_stack_bazel_registry_v1_bcr_proto_rawDescData
 }
 
-var file_build_stack_bazel_registry_v1_bcr_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_build_stack_bazel_registry_v1_bcr_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
+var file_build_stack_bazel_registry_v1_bcr_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_build_stack_bazel_registry_v1_bcr_proto_msgTypes = make([]protoimpl.MessageInfo, 61)
 var file_build_stack_bazel_registry_v1_bcr_proto_goTypes = []any{
-	(RepositoryType)(0),               // 0: build.stack.bazel.registry.v1.RepositoryType
-	(*Registry)(nil),                  // 1: build.stack.bazel.registry.v1.Registry
-	(*Module)(nil),                    // 2: build.stack.bazel.registry.v1.Module
-	(*Maintainer)(nil),                // 3: build.stack.bazel.registry.v1.Maintainer
-	(*ModuleMetadata)(nil),            // 4: build.stack.bazel.registry.v1.ModuleMetadata
-	(*RepositoryMetadata)(nil),        // 5: build.stack.bazel.registry.v1.RepositoryMetadata
-	(*RepositoryMetadataSet)(nil),     // 6: build.stack.bazel.registry.v1.RepositoryMetadataSet
-	(*BazelRepositoryMetadata)(nil),   // 7: build.stack.bazel.registry.v1.BazelRepositoryMetadata
-	(*BazelRelease)(nil),              // 8: build.stack.bazel.registry.v1.BazelRelease
-	(*BazelReleaseSet)(nil),           // 9: build.stack.bazel.registry.v1.BazelReleaseSet
-	(*ResourceStatus)(nil),            // 10: build.stack.bazel.registry.v1.ResourceStatus
-	(*ResourceStatusSet)(nil),         // 11: build.stack.bazel.registry.v1.ResourceStatusSet
-	(*ModuleSource)(nil),              // 12: build.stack.bazel.registry.v1.ModuleSource
-	(*Attestations)(nil),              // 13: build.stack.bazel.registry.v1.Attestations
-	(*ModuleVersion)(nil),             // 14: build.stack.bazel.registry.v1.ModuleVersion
-	(*ModuleCommit)(nil),              // 15: build.stack.bazel.registry.v1.ModuleCommit
-	(*ModuleDependencyOverride)(nil),  // 16: build.stack.bazel.registry.v1.ModuleDependencyOverride
-	(*ModuleDependency)(nil),          // 17: build.stack.bazel.registry.v1.ModuleDependency
-	(*GitOverride)(nil),               // 18: build.stack.bazel.registry.v1.GitOverride
-	(*ArchiveOverride)(nil),           // 19: build.stack.bazel.registry.v1.ArchiveOverride
-	(*SingleVersionOverride)(nil),     // 20: build.stack.bazel.registry.v1.SingleVersionOverride
-	(*LocalPathOverride)(nil),         // 21: build.stack.bazel.registry.v1.LocalPathOverride
-	(*Presubmit)(nil),                 // 22: build.stack.bazel.registry.v1.Presubmit
-	(*DependencyTreeNode)(nil),        // 23: build.stack.bazel.registry.v1.DependencyTreeNode
-	(*DependencyTree)(nil),            // 24: build.stack.bazel.registry.v1.DependencyTree
-	nil,                               // 25: build.stack.bazel.registry.v1.ModuleMetadata.YankedVersionsEntry
-	nil,                               // 26: build.stack.bazel.registry.v1.RepositoryMetadata.LanguagesEntry
-	nil,                               // 27: build.stack.bazel.registry.v1.ModuleSource.PatchesEntry
-	nil,                               // 28: build.stack.bazel.registry.v1.ModuleSource.OverlayEntry
-	(*Attestations_Attestation)(nil),  // 29: build.stack.bazel.registry.v1.Attestations.Attestation
-	nil,                               // 30: build.stack.bazel.registry.v1.Attestations.AttestationsEntry
-	(*Presubmit_BcrTestModule)(nil),   // 31: build.stack.bazel.registry.v1.Presubmit.BcrTestModule
-	(*Presubmit_PresubmitMatrix)(nil), // 32: build.stack.bazel.registry.v1.Presubmit.PresubmitMatrix
-	(*Presubmit_PresubmitTask)(nil),   // 33: build.stack.bazel.registry.v1.Presubmit.PresubmitTask
-	nil,                               // 34: build.stack.bazel.registry.v1.Presubmit.TasksEntry
-	nil,                               // 35: build.stack.bazel.registry.v1.Presubmit.BcrTestModule.TasksEntry
-	(*v1.ModuleVersionSymbols)(nil),   // 36: build.stack.bazel.symbol.v1.ModuleVersionSymbols
+	(RepositoryType)(0),                   // 0: build.stack.bazel.registry.v1.RepositoryType
+	(CacheEntryType)(0),                   // 1: build.stack.bazel.registry.v1.CacheEntryType
+	(ArchiveVerificationStatus)(0),        // 2: build.stack.bazel.registry.v1.ArchiveVerificationStatus
+	(FileIntegrityStatus)(0),              // 3: build.stack.bazel.registry.v1.FileIntegrityStatus
+	(AttestationVerificationStatus)(0),    // 4: build.stack.bazel.registry.v1.AttestationVerificationStatus
+	(DiagnosticSeverity)(0),               // 5: build.stack.bazel.registry.v1.DiagnosticSeverity
+	(CacheMissKind)(0),                    // 6: build.stack.bazel.registry.v1.CacheMissKind
+	(*Registry)(nil),                      // 7: build.stack.bazel.registry.v1.Registry
+	(*Module)(nil),                        // 8: build.stack.bazel.registry.v1.Module
+	(*ModuleHealth)(nil),                  // 9: build.stack.bazel.registry.v1.ModuleHealth
+	(*ModuleHealthComponent)(nil),         // 10: build.stack.bazel.registry.v1.ModuleHealthComponent
+	(*Maintainer)(nil),                    // 11: build.stack.bazel.registry.v1.Maintainer
+	(*ModuleMetadata)(nil),                // 12: build.stack.bazel.registry.v1.ModuleMetadata
+	(*RepositoryMetadata)(nil),            // 13: build.stack.bazel.registry.v1.RepositoryMetadata
+	(*BazelRepositoryMetadata)(nil),       // 14: build.stack.bazel.registry.v1.BazelRepositoryMetadata
+	(*BazelRelease)(nil),                  // 15: build.stack.bazel.registry.v1.BazelRelease
+	(*RegistryCommit)(nil),                // 16: build.stack.bazel.registry.v1.RegistryCommit
+	(*RegistryCommitSet)(nil),             // 17: build.stack.bazel.registry.v1.RegistryCommitSet
+	(*ResourceStatus)(nil),                // 18: build.stack.bazel.registry.v1.ResourceStatus
+	(*CacheEntry)(nil),                    // 19: build.stack.bazel.registry.v1.CacheEntry
+	(*CacheStore)(nil),                    // 20: build.stack.bazel.registry.v1.CacheStore
+	(*ModuleSource)(nil),                  // 21: build.stack.bazel.registry.v1.ModuleSource
+	(*ArchiveVerification)(nil),           // 22: build.stack.bazel.registry.v1.ArchiveVerification
+	(*FileIntegrity)(nil),                 // 23: build.stack.bazel.registry.v1.FileIntegrity
+	(*AttestationVerification)(nil),       // 24: build.stack.bazel.registry.v1.AttestationVerification
+	(*Attestations)(nil),                  // 25: build.stack.bazel.registry.v1.Attestations
+	(*ModuleVersion)(nil),                 // 26: build.stack.bazel.registry.v1.ModuleVersion
+	(*SlsaProvenance)(nil),                // 27: build.stack.bazel.registry.v1.SlsaProvenance
+	(*DevDependencyUpgrade)(nil),          // 28: build.stack.bazel.registry.v1.DevDependencyUpgrade
+	(*BazelCompatibilityRange)(nil),       // 29: build.stack.bazel.registry.v1.BazelCompatibilityRange
+	(*BazelCompatibilityNarrowing)(nil),   // 30: build.stack.bazel.registry.v1.BazelCompatibilityNarrowing
+	(*ResolutionError)(nil),               // 31: build.stack.bazel.registry.v1.ResolutionError
+	(*CompatibilityLevelConflict)(nil),    // 32: build.stack.bazel.registry.v1.CompatibilityLevelConflict
+	(*CompatibilityLevelRequirement)(nil), // 33: build.stack.bazel.registry.v1.CompatibilityLevelRequirement
+	(*ModuleCommit)(nil),                  // 34: build.stack.bazel.registry.v1.ModuleCommit
+	(*ModuleDependencyOverride)(nil),      // 35: build.stack.bazel.registry.v1.ModuleDependencyOverride
+	(*ModuleDependency)(nil),              // 36: build.stack.bazel.registry.v1.ModuleDependency
+	(*GitOverride)(nil),                   // 37: build.stack.bazel.registry.v1.GitOverride
+	(*ArchiveOverride)(nil),               // 38: build.stack.bazel.registry.v1.ArchiveOverride
+	(*SingleVersionOverride)(nil),         // 39: build.stack.bazel.registry.v1.SingleVersionOverride
+	(*LocalPathOverride)(nil),             // 40: build.stack.bazel.registry.v1.LocalPathOverride
+	(*Presubmit)(nil),                     // 41: build.stack.bazel.registry.v1.Presubmit
+	(*DependencyTreeNode)(nil),            // 42: build.stack.bazel.registry.v1.DependencyTreeNode
+	(*DependencyTree)(nil),                // 43: build.stack.bazel.registry.v1.DependencyTree
+	(*ReverseDependencyIndex)(nil),        // 44: build.stack.bazel.registry.v1.ReverseDependencyIndex
+	(*ModuleVersionDependents)(nil),       // 45: build.stack.bazel.registry.v1.ModuleVersionDependents
+	(*ReverseDependencyCounts)(nil),       // 46: build.stack.bazel.registry.v1.ReverseDependencyCounts
+	(*ModuleDependencyCycleReport)(nil),   // 47: build.stack.bazel.registry.v1.ModuleDependencyCycleReport
+	(*ModuleDependencyCycle)(nil),         // 48: build.stack.bazel.registry.v1.ModuleDependencyCycle
+	(*ModuleDependencyCyclePath)(nil),     // 49: build.stack.bazel.registry.v1.ModuleDependencyCyclePath
+	(*RegistryDiagnostic)(nil),            // 50: build.stack.bazel.registry.v1.RegistryDiagnostic
+	(*RegistryDiagnosticReport)(nil),      // 51: build.stack.bazel.registry.v1.RegistryDiagnosticReport
+	(*CacheMiss)(nil),                     // 52: build.stack.bazel.registry.v1.CacheMiss
+	(*CacheMissReport)(nil),               // 53: build.stack.bazel.registry.v1.CacheMissReport
+	nil,                                   // 54: build.stack.bazel.registry.v1.ModuleMetadata.YankedVersionsEntry
+	nil,                                   // 55: build.stack.bazel.registry.v1.RepositoryMetadata.LanguagesEntry
+	nil,                                   // 56: build.stack.bazel.registry.v1.RegistryCommitSet.ModuleConfigFingerprintsEntry
+	nil,                                   // 57: build.stack.bazel.registry.v1.ModuleSource.PatchesEntry
+	nil,                                   // 58: build.stack.bazel.registry.v1.ModuleSource.OverlayEntry
+	nil,                                   // 59: build.stack.bazel.registry.v1.ModuleSource.PatchIntegrityEntry
+	nil,                                   // 60: build.stack.bazel.registry.v1.ModuleSource.OverlayIntegrityEntry
+	(*Attestations_Attestation)(nil),      // 61: build.stack.bazel.registry.v1.Attestations.Attestation
+	nil,                                   // 62: build.stack.bazel.registry.v1.Attestations.AttestationsEntry
+	(*Presubmit_BcrTestModule)(nil),       // 63: build.stack.bazel.registry.v1.Presubmit.BcrTestModule
+	(*Presubmit_PresubmitMatrix)(nil),     // 64: build.stack.bazel.registry.v1.Presubmit.PresubmitMatrix
+	(*Presubmit_PresubmitTask)(nil),       // 65: build.stack.bazel.registry.v1.Presubmit.PresubmitTask
+	nil,                                   // 66: build.stack.bazel.registry.v1.Presubmit.TasksEntry
+	nil,                                   // 67: build.stack.bazel.registry.v1.Presubmit.BcrTestModule.TasksEntry
+	(*v1.ModuleVersionSymbols)(nil),       // 68: build.stack.bazel.symbol.v1.ModuleVersionSymbols
 }
 var file_build_stack_bazel_registry_v1_bcr_proto_depIdxs = []int32{
-	2,  // 0: build.stack.bazel.registry.v1.Registry.modules:type_name -> build.stack.bazel.registry.v1.Module
-	4,  // 1: build.stack.bazel.registry.v1.Module.metadata:type_name -> build.stack.bazel.registry.v1.ModuleMetadata
-	14, // 2: build.stack.bazel.registry.v1.Module.versions:type_name -> build.stack.bazel.registry.v1.ModuleVersion
-	5,  // 3: build.stack.bazel.registry.v1.Module.repository_metadata:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata
-	3,  // 4: build.stack.bazel.registry.v1.ModuleMetadata.maintainers:type_name -> build.stack.bazel.registry.v1.Maintainer
-	25, // 5: build.stack.bazel.registry.v1.ModuleMetadata.yanked_versions:type_name -> build.stack.bazel.registry.v1.ModuleMetadata.YankedVersionsEntry
-	0,  // 6: build.stack.bazel.registry.v1.RepositoryMetadata.type:type_name -> build.stack.bazel.registry.v1.RepositoryType
-	26, // 7: build.stack.bazel.registry.v1.RepositoryMetadata.languages:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata.LanguagesEntry
-	5,  // 8: build.stack.bazel.registry.v1.RepositoryMetadataSet.repository_metadata:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata
-	5,  // 9: build.stack.bazel.registry.v1.BazelRepositoryMetadata.repository_metadata:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata
-	8,  // 10: build.stack.bazel.registry.v1.BazelRepositoryMetadata.release:type_name -> build.stack.bazel.registry.v1.BazelRelease
-	15, // 11: build.stack.bazel.registry.v1.BazelRelease.commit:type_name -> build.stack.bazel.registry.v1.ModuleCommit
-	8,  // 12: build.stack.bazel.registry.v1.BazelReleaseSet.release:type_name -> build.stack.bazel.registry.v1.BazelRelease
-	10, // 13: build.stack.bazel.registry.v1.ResourceStatusSet.status:type_name -> build.stack.bazel.registry.v1.ResourceStatus
-	27, // 14: build.stack.bazel.registry.v1.ModuleSource.patches:type_name -> build.stack.bazel.registry.v1.ModuleSource.PatchesEntry
-	28, // 15: build.stack.bazel.registry.v1.ModuleSource.overlay:type_name -> build.stack.bazel.registry.v1.ModuleSource.OverlayEntry
-	36, // 16: build.stack.bazel.registry.v1.ModuleSource.documentation:type_name -> build.stack.bazel.symbol.v1.ModuleVersionSymbols
-	10, // 17: build.stack.bazel.registry.v1.ModuleSource.docs_url_status:type_name -> build.stack.bazel.registry.v1.ResourceStatus
-	10, // 18: build.stack.bazel.registry.v1.ModuleSource.url_status:type_name -> build.stack.bazel.registry.v1.ResourceStatus
-	30, // 19: build.stack.bazel.registry.v1.Attestations.attestations:type_name -> build.stack.bazel.registry.v1.Attestations.AttestationsEntry
-	17, // 20: build.stack.bazel.registry.v1.ModuleVersion.deps:type_name -> build.stack.bazel.registry.v1.ModuleDependency
-	12, // 21: build.stack.bazel.registry.v1.ModuleVersion.source:type_name -> build.stack.bazel.registry.v1.ModuleSource
-	13, // 22: build.stack.bazel.registry.v1.ModuleVersion.attestations:type_name -> build.stack.bazel.registry.v1.Attestations
-	22, // 23: build.stack.bazel.registry.v1.ModuleVersion.presubmit:type_name -> build.stack.bazel.registry.v1.Presubmit
-	16, // 24: build.stack.bazel.registry.v1.ModuleVersion.override:type_name -> build.stack.bazel.registry.v1.ModuleDependencyOverride
-	15, // 25: build.stack.bazel.registry.v1.ModuleVersion.commit:type_name -> build.stack.bazel.registry.v1.ModuleCommit
-	5,  // 26: build.stack.bazel.registry.v1.ModuleVersion.repository_metadata:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata
-	18, // 27: build.stack.bazel.registry.v1.ModuleDependencyOverride.git_override:type_name -> build.stack.bazel.registry.v1.GitOverride
-	19, // 28: build.stack.bazel.registry.v1.ModuleDependencyOverride.archive_override:type_name -> build.stack.bazel.registry.v1.ArchiveOverride
-	20, // 29: build.stack.bazel.registry.v1.ModuleDependencyOverride.single_version_override:type_name -> build.stack.bazel.registry.v1.SingleVersionOverride
-	21, // 30: build.stack.bazel.registry.v1.ModuleDependencyOverride.local_path_override:type_name -> build.stack.bazel.registry.v1.LocalPathOverride
-	16, // 31: build.stack.bazel.registry.v1.ModuleDependency.override:type_name -> build.stack.bazel.registry.v1.ModuleDependencyOverride
-	31, // 32: build.stack.bazel.registry.v1.Presubmit.bcr_test_module:type_name -> build.stack.bazel.registry.v1.Presubmit.BcrTestModule
-	32, // 33: build.stack.bazel.registry.v1.Presubmit.matrix:type_name -> build.stack.bazel.registry.v1.Presubmit.PresubmitMatrix
-	34, // 34: build.stack.bazel.registry.v1.Presubmit.tasks:type_name -> build.stack.bazel.registry.v1.Presubmit.TasksEntry
-	14, // 35: build.stack.bazel.registry.v1.DependencyTreeNode.module_version:type_name -> build.stack.bazel.registry.v1.ModuleVersion
-	23, // 36: build.stack.bazel.registry.v1.DependencyTreeNode.children:type_name -> build.stack.bazel.registry.v1.DependencyTreeNode
-	14, // 37: build.stack.bazel.registry.v1.DependencyTree.module_version:type_name -> build.stack.bazel.registry.v1.ModuleVersion
-	23, // 38: build.stack.bazel.registry.v1.DependencyTree.children:type_name -> build.stack.bazel.registry.v1.DependencyTreeNode
-	29, // 39: build.stack.bazel.registry.v1.Attestations.AttestationsEntry.value:type_name -> build.stack.bazel.registry.v1.Attestations.Attestation
-	32, // 40: build.stack.bazel.registry.v1.Presubmit.BcrTestModule.matrix:type_name -> build.stack.bazel.registry.v1.Presubmit.PresubmitMatrix
-	35, // 41: build.stack.bazel.registry.v1.Presubmit.BcrTestModule.tasks:type_name -> build.stack.bazel.registry.v1.Presubmit.BcrTestModule.TasksEntry
-	33, // 42: build.stack.bazel.registry.v1.Presubmit.TasksEntry.value:type_name -> build.stack.bazel.registry.v1.Presubmit.PresubmitTask
-	33, // 43: build.stack.bazel.registry.v1.Presubmit.BcrTestModule.TasksEntry.value:type_name -> build.stack.bazel.registry.v1.Presubmit.PresubmitTask
-	44, // [44:44] is the sub-list for method output_type
-	44, // [44:44] is the sub-list for method input_type
-	44, // [44:44] is the sub-list for extension type_name
-	44, // [44:44] is the sub-list for extension extendee
-	0,  // [0:44] is the sub-list for field type_name
+	8,  // 0: build.stack.bazel.registry.v1.Registry.modules:type_name -> build.stack.bazel.registry.v1.Module
+	12, // 1: build.stack.bazel.registry.v1.Module.metadata:type_name -> build.stack.bazel.registry.v1.ModuleMetadata
+	26, // 2: build.stack.bazel.registry.v1.Module.versions:type_name -> build.stack.bazel.registry.v1.ModuleVersion
+	13, // 3: build.stack.bazel.registry.v1.Module.repository_metadata:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata
+	46, // 4: build.stack.bazel.registry.v1.Module.reverse_dependency_counts:type_name -> build.stack.bazel.registry.v1.ReverseDependencyCounts
+	9,  // 5: build.stack.bazel.registry.v1.Module.health:type_name -> build.stack.bazel.registry.v1.ModuleHealth
+	10, // 6: build.stack.bazel.registry.v1.ModuleHealth.components:type_name -> build.stack.bazel.registry.v1.ModuleHealthComponent
+	11, // 7: build.stack.bazel.registry.v1.ModuleMetadata.maintainers:type_name -> build.stack.bazel.registry.v1.Maintainer
+	54, // 8: build.stack.bazel.registry.v1.ModuleMetadata.yanked_versions:type_name -> build.stack.bazel.registry.v1.ModuleMetadata.YankedVersionsEntry
+	0,  // 9: build.stack.bazel.registry.v1.RepositoryMetadata.type:type_name -> build.stack.bazel.registry.v1.RepositoryType
+	55, // 10: build.stack.bazel.registry.v1.RepositoryMetadata.languages:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata.LanguagesEntry
+	13, // 11: build.stack.bazel.registry.v1.BazelRepositoryMetadata.repository_metadata:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata
+	15, // 12: build.stack.bazel.registry.v1.BazelRepositoryMetadata.release:type_name -> build.stack.bazel.registry.v1.BazelRelease
+	34, // 13: build.stack.bazel.registry.v1.BazelRelease.commit:type_name -> build.stack.bazel.registry.v1.ModuleCommit
+	16, // 14: build.stack.bazel.registry.v1.RegistryCommitSet.commit:type_name -> build.stack.bazel.registry.v1.RegistryCommit
+	56, // 15: build.stack.bazel.registry.v1.RegistryCommitSet.module_config_fingerprints:type_name -> build.stack.bazel.registry.v1.RegistryCommitSet.ModuleConfigFingerprintsEntry
+	1,  // 16: build.stack.bazel.registry.v1.CacheEntry.type:type_name -> build.stack.bazel.registry.v1.CacheEntryType
+	18, // 17: build.stack.bazel.registry.v1.CacheEntry.resource_status:type_name -> build.stack.bazel.registry.v1.ResourceStatus
+	13, // 18: build.stack.bazel.registry.v1.CacheEntry.repository_metadata:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata
+	15, // 19: build.stack.bazel.registry.v1.CacheEntry.bazel_release:type_name -> build.stack.bazel.registry.v1.BazelRelease
+	19, // 20: build.stack.bazel.registry.v1.CacheStore.entries:type_name -> build.stack.bazel.registry.v1.CacheEntry
+	57, // 21: build.stack.bazel.registry.v1.ModuleSource.patches:type_name -> build.stack.bazel.registry.v1.ModuleSource.PatchesEntry
+	58, // 22: build.stack.bazel.registry.v1.ModuleSource.overlay:type_name -> build.stack.bazel.registry.v1.ModuleSource.OverlayEntry
+	68, // 23: build.stack.bazel.registry.v1.ModuleSource.documentation:type_name -> build.stack.bazel.symbol.v1.ModuleVersionSymbols
+	18, // 24: build.stack.bazel.registry.v1.ModuleSource.docs_url_status:type_name -> build.stack.bazel.registry.v1.ResourceStatus
+	18, // 25: build.stack.bazel.registry.v1.ModuleSource.url_status:type_name -> build.stack.bazel.registry.v1.ResourceStatus
+	59, // 26: build.stack.bazel.registry.v1.ModuleSource.patch_integrity:type_name -> build.stack.bazel.registry.v1.ModuleSource.PatchIntegrityEntry
+	60, // 27: build.stack.bazel.registry.v1.ModuleSource.overlay_integrity:type_name -> build.stack.bazel.registry.v1.ModuleSource.OverlayIntegrityEntry
+	22, // 28: build.stack.bazel.registry.v1.ModuleSource.archive_verification:type_name -> build.stack.bazel.registry.v1.ArchiveVerification
+	2,  // 29: build.stack.bazel.registry.v1.ArchiveVerification.status:type_name -> build.stack.bazel.registry.v1.ArchiveVerificationStatus
+	3,  // 30: build.stack.bazel.registry.v1.FileIntegrity.status:type_name -> build.stack.bazel.registry.v1.FileIntegrityStatus
+	4,  // 31: build.stack.bazel.registry.v1.AttestationVerification.status:type_name -> build.stack.bazel.registry.v1.AttestationVerificationStatus
+	62, // 32: build.stack.bazel.registry.v1.Attestations.attestations:type_name -> build.stack.bazel.registry.v1.Attestations.AttestationsEntry
+	36, // 33: build.stack.bazel.registry.v1.ModuleVersion.deps:type_name -> build.stack.bazel.registry.v1.ModuleDependency
+	21, // 34: build.stack.bazel.registry.v1.ModuleVersion.source:type_name -> build.stack.bazel.registry.v1.ModuleSource
+	25, // 35: build.stack.bazel.registry.v1.ModuleVersion.attestations:type_name -> build.stack.bazel.registry.v1.Attestations
+	41, // 36: build.stack.bazel.registry.v1.ModuleVersion.presubmit:type_name -> build.stack.bazel.registry.v1.Presubmit
+	35, // 37: build.stack.bazel.registry.v1.ModuleVersion.override:type_name -> build.stack.bazel.registry.v1.ModuleDependencyOverride
+	34, // 38: build.stack.bazel.registry.v1.ModuleVersion.commit:type_name -> build.stack.bazel.registry.v1.ModuleCommit
+	13, // 39: build.stack.bazel.registry.v1.ModuleVersion.repository_metadata:type_name -> build.stack.bazel.registry.v1.RepositoryMetadata
+	31, // 40: build.stack.bazel.registry.v1.ModuleVersion.resolution_error:type_name -> build.stack.bazel.registry.v1.ResolutionError
+	29, // 41: build.stack.bazel.registry.v1.ModuleVersion.bazel_compatibility_range:type_name -> build.stack.bazel.registry.v1.BazelCompatibilityRange
+	28, // 42: build.stack.bazel.registry.v1.ModuleVersion.dev_dependency_upgrades:type_name -> build.stack.bazel.registry.v1.DevDependencyUpgrade
+	27, // 43: build.stack.bazel.registry.v1.ModuleVersion.provenance:type_name -> build.stack.bazel.registry.v1.SlsaProvenance
+	30, // 44: build.stack.bazel.registry.v1.BazelCompatibilityRange.narrowed_by:type_name -> build.stack.bazel.registry.v1.BazelCompatibilityNarrowing
+	32, // 45: build.stack.bazel.registry.v1.ResolutionError.conflicts:type_name -> build.stack.bazel.registry.v1.CompatibilityLevelConflict
+	33, // 46: build.stack.bazel.registry.v1.CompatibilityLevelConflict.requirements:type_name -> build.stack.bazel.registry.v1.CompatibilityLevelRequirement
+	37, // 47: build.stack.bazel.registry.v1.ModuleDependencyOverride.git_override:type_name -> build.stack.bazel.registry.v1.GitOverride
+	38, // 48: build.stack.bazel.registry.v1.ModuleDependencyOverride.archive_override:type_name -> build.stack.bazel.registry.v1.ArchiveOverride
+	39, // 49: build.stack.bazel.registry.v1.ModuleDependencyOverride.single_version_override:type_name -> build.stack.bazel.registry.v1.SingleVersionOverride
+	40, // 50: build.stack.bazel.registry.v1.ModuleDependencyOverride.local_path_override:type_name -> build.stack.bazel.registry.v1.LocalPathOverride
+	35, // 51: build.stack.bazel.registry.v1.ModuleDependency.override:type_name -> build.stack.bazel.registry.v1.ModuleDependencyOverride
+	63, // 52: build.stack.bazel.registry.v1.Presubmit.bcr_test_module:type_name -> build.stack.bazel.registry.v1.Presubmit.BcrTestModule
+	64, // 53: build.stack.bazel.registry.v1.Presubmit.matrix:type_name -> build.stack.bazel.registry.v1.Presubmit.PresubmitMatrix
+	66, // 54: build.stack.bazel.registry.v1.Presubmit.tasks:type_name -> build.stack.bazel.registry.v1.Presubmit.TasksEntry
+	26, // 55: build.stack.bazel.registry.v1.DependencyTreeNode.module_version:type_name -> build.stack.bazel.registry.v1.ModuleVersion
+	42, // 56: build.stack.bazel.registry.v1.DependencyTreeNode.children:type_name -> build.stack.bazel.registry.v1.DependencyTreeNode
+	26, // 57: build.stack.bazel.registry.v1.DependencyTree.module_version:type_name -> build.stack.bazel.registry.v1.ModuleVersion
+	42, // 58: build.stack.bazel.registry.v1.DependencyTree.children:type_name -> build.stack.bazel.registry.v1.DependencyTreeNode
+	45, // 59: build.stack.bazel.registry.v1.ReverseDependencyIndex.module_versions:type_name -> build.stack.bazel.registry.v1.ModuleVersionDependents
+	46, // 60: build.stack.bazel.registry.v1.ModuleVersionDependents.counts:type_name -> build.stack.bazel.registry.v1.ReverseDependencyCounts
+	48, // 61: build.stack.bazel.registry.v1.ModuleDependencyCycleReport.cycles:type_name -> build.stack.bazel.registry.v1.ModuleDependencyCycle
+	49, // 62: build.stack.bazel.registry.v1.ModuleDependencyCycle.paths:type_name -> build.stack.bazel.registry.v1.ModuleDependencyCyclePath
+	5,  // 63: build.stack.bazel.registry.v1.RegistryDiagnostic.severity:type_name -> build.stack.bazel.registry.v1.DiagnosticSeverity
+	50, // 64: build.stack.bazel.registry.v1.RegistryDiagnosticReport.diagnostics:type_name -> build.stack.bazel.registry.v1.RegistryDiagnostic
+	6,  // 65: build.stack.bazel.registry.v1.CacheMiss.kind:type_name -> build.stack.bazel.registry.v1.CacheMissKind
+	52, // 66: build.stack.bazel.registry.v1.CacheMissReport.misses:type_name -> build.stack.bazel.registry.v1.CacheMiss
+	23, // 67: build.stack.bazel.registry.v1.ModuleSource.PatchIntegrityEntry.value:type_name -> build.stack.bazel.registry.v1.FileIntegrity
+	23, // 68: build.stack.bazel.registry.v1.ModuleSource.OverlayIntegrityEntry.value:type_name -> build.stack.bazel.registry.v1.FileIntegrity
+	24, // 69: build.stack.bazel.registry.v1.Attestations.Attestation.verification:type_name -> build.stack.bazel.registry.v1.AttestationVerification
+	61, // 70: build.stack.bazel.registry.v1.Attestations.AttestationsEntry.value:type_name -> build.stack.bazel.registry.v1.Attestations.Attestation
+	64, // 71: build.stack.bazel.registry.v1.Presubmit.BcrTestModule.matrix:type_name -> build.stack.bazel.registry.v1.Presubmit.PresubmitMatrix
+	67, // 72: build.stack.bazel.registry.v1.Presubmit.BcrTestModule.tasks:type_name -> build.stack.bazel.registry.v1.Presubmit.BcrTestModule.TasksEntry
+	65, // 73: build.stack.bazel.registry.v1.Presubmit.TasksEntry.value:type_name -> build.stack.bazel.registry.v1.Presubmit.PresubmitTask
+	65, // 74: build.stack.bazel.registry.v1.Presubmit.BcrTestModule.TasksEntry.value:type_name -> build.stack.bazel.registry.v1.Presubmit.PresubmitTask
+	75, // [75:75] is the sub-list for method output_type
+	75, // [75:75] is the sub-list for method input_type
+	75, // [75:75] is the sub-list for extension type_name
+	75, // [75:75] is the sub-list for extension extendee
+	0,  // [0:75] is the sub-list for field type_name
 }
 
 func init() { file_build_stack_bazel_registry_v1_bcr_proto_init() }
@@ -2496,7 +4878,13 @@ func file_build_stack_bazel_registry_v1_bcr_proto_init() {
 	if File_build_stack_bazel_registry_v1_bcr_proto != nil {
 		return
 	}
-	file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[15].OneofWrappers = []any{
+	file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[12].OneofWrappers = []any{
+		(*CacheEntry_ResourceStatus)(nil),
+		(*CacheEntry_RepositoryMetadata)(nil),
+		(*CacheEntry_BazelRelease)(nil),
+		(*CacheEntry_CommitSha)(nil),
+	}
+	file_build_stack_bazel_registry_v1_bcr_proto_msgTypes[28].OneofWrappers = []any{
 		(*ModuleDependencyOverride_GitOverride)(nil),
 		(*ModuleDependencyOverride_ArchiveOverride)(nil),
 		(*ModuleDependencyOverride_SingleVersionOverride)(nil),
@@ -2507,8 +4895,8 @@ func file_build_stack_bazel_registry_v1_bcr_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_build_stack_bazel_registry_v1_bcr_proto_rawDesc), len(file_build_stack_bazel_registry_v1_bcr_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   35,
+			NumEnums:      7,
+			NumMessages:   61,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,193 @@
+// bcrcache inspects and prunes the cache store written by the bcr gazelle
+// extension (--cache-file).
+//
+// Usage:
+//
+//	bcrcache inspect --cache_file=FILE [--type=TYPE] [--list] [--expired]
+//	bcrcache prune --cache_file=FILE [--dry_run]
+//
+// Both subcommands accept --ttl=CLASS=DURATION (repeatable) to override the
+// default times to live.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/cachestore"
+)
+
+const toolName = "bcrcache"
+
+type Config struct {
+	Command   string
+	CacheFile string
+	TTLs      cachestore.TTLs
+	Type      string
+	List      bool
+	Expired   bool
+	DryRun    bool
+}
+
+func main() {
+	log.SetPrefix(toolName + ": ")
+	log.SetOutput(os.Stderr)
+	log.SetFlags(0) // don't print timestamps
+
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	cfg, err := parseFlags(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse args: %v", err)
+	}
+
+	if cfg.CacheFile == "" {
+		return fmt.Errorf("cache_file is required")
+	}
+	if _, err := os.Stat(cfg.CacheFile); err != nil {
+		return fmt.Errorf("failed to read cache file: %v", err)
+	}
+
+	store, err := cachestore.ReadFile(cfg.CacheFile, cfg.TTLs)
+	if err != nil {
+		return fmt.Errorf("failed to read cache file: %v", err)
+	}
+
+	switch cfg.Command {
+	case "inspect":
+		return inspect(stdout, store, cfg)
+	case "prune":
+		return prune(stdout, store, cfg)
+	default:
+		return fmt.Errorf("unknown command %q (want inspect or prune)", cfg.Command)
+	}
+}
+
+func parseFlags(args []string) (cfg Config, err error) {
+	if len(args) == 0 {
+		err = fmt.Errorf("missing command (want inspect or prune)")
+		return
+	}
+	cfg.Command = args[0]
+	cfg.TTLs = cachestore.DefaultTTLs()
+
+	fs := flag.NewFlagSet(toolName+" "+cfg.Command, flag.ExitOnError)
+	fs.StringVar(&cfg.CacheFile, "cache_file", "", "the cache store to read (.json or .pb) (required)")
+	fs.Var(cfg.TTLs, "ttl", "time to live of a class of entries, as CLASS=DURATION (e.g. repository_metadata=7d); repeatable. Classes: resource_status, resource_failure, repository_metadata, bazel_release, source_commit")
+	fs.StringVar(&cfg.Type, "type", "", "inspect: only the entries of this type (resource_status, repository_metadata, bazel_release or source_commit)")
+	fs.BoolVar(&cfg.List, "list", false, "inspect: list the entries, not only the summary")
+	fs.BoolVar(&cfg.Expired, "expired", false, "inspect: only the expired entries")
+	fs.BoolVar(&cfg.DryRun, "dry_run", false, "prune: report the expired entries without removing them")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s inspect|prune --cache_file=FILE [OPTIONS]\n\n", toolName)
+		fmt.Fprintln(fs.Output(), "Summarizes the cache entries by class (inspect), or removes the expired ones (prune).")
+		fs.PrintDefaults()
+	}
+
+	err = fs.Parse(args[1:])
+	return
+}
+
+// classSummary are the counts and the fetch time range of the entries of a
+// class.
+type classSummary struct {
+	entries, expired int
+	oldest, newest   string
+}
+
+func inspect(w io.Writer, store *cachestore.Store, cfg Config) error {
+	typ := bzpb.CacheEntryType_CACHE_ENTRY_TYPE_UNKNOWN
+	if cfg.Type != "" {
+		var err error
+		if typ, err = cachestore.ParseEntryType(cfg.Type); err != nil {
+			return err
+		}
+	}
+
+	summaries := make(map[cachestore.Class]*classSummary)
+	var listed []*bzpb.CacheEntry
+	for _, entry := range store.Entries(typ) {
+		expired := store.Expired(entry)
+		if cfg.Expired && !expired {
+			continue
+		}
+		class := cachestore.ClassOf(entry)
+		summary, ok := summaries[class]
+		if !ok {
+			summary = &classSummary{oldest: entry.FetchedAt, newest: entry.FetchedAt}
+			summaries[class] = summary
+		}
+		summary.entries++
+		if expired {
+			summary.expired++
+		}
+		// RFC3339 UTC timestamps sort lexically
+		summary.oldest = min(summary.oldest, entry.FetchedAt)
+		summary.newest = max(summary.newest, entry.FetchedAt)
+		listed = append(listed, entry)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if cfg.List {
+		for _, entry := range listed {
+			state := "fresh"
+			if store.Expired(entry) {
+				state = "expired"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", cachestore.FormatEntryType(entry.Type), entry.Key, entry.FetchedAt, state)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	fmt.Fprintln(tw, "CLASS\tENTRIES\tEXPIRED\tTTL\tOLDEST\tNEWEST")
+	total := &classSummary{}
+	for _, class := range cachestore.Classes {
+		summary, ok := summaries[class]
+		if !ok {
+			continue
+		}
+		ttl := "never"
+		if d := store.TTLs[class]; d > 0 {
+			ttl = cachestore.FormatDuration(d)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\n", class, summary.entries, summary.expired, ttl, summary.oldest, summary.newest)
+		total.entries += summary.entries
+		total.expired += summary.expired
+	}
+	fmt.Fprintf(tw, "total\t%d\t%d\t\t\t\n", total.entries, total.expired)
+
+	return tw.Flush()
+}
+
+func prune(w io.Writer, store *cachestore.Store, cfg Config) error {
+	total := store.Len()
+
+	if cfg.DryRun {
+		expired := 0
+		for _, entry := range store.Entries(bzpb.CacheEntryType_CACHE_ENTRY_TYPE_UNKNOWN) {
+			if store.Expired(entry) {
+				expired++
+			}
+		}
+		fmt.Fprintf(w, "would prune %d of %d entries from %s\n", expired, total, cfg.CacheFile)
+		return nil
+	}
+
+	n := store.Prune()
+	if store.Modified() {
+		if err := store.WriteFile(cfg.CacheFile); err != nil {
+			return fmt.Errorf("failed to write cache file: %v", err)
+		}
+	}
+	fmt.Fprintf(w, "pruned %d of %d entries from %s\n", n, total, cfg.CacheFile)
+	return nil
+}
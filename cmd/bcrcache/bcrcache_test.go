@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/cachestore"
+)
+
+func writeCache(t *testing.T) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "cache.json")
+	old := time.Now().Add(-60 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	store := cachestore.New(nil)
+	for _, entry := range []*bzpb.CacheEntry{
+		cachestore.ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com/ok", Code: 200}),
+		cachestore.ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com/old", Code: 200}),
+		cachestore.ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com/404", Code: 404}),
+		cachestore.RepositoryMetadataEntry("github:org/repo", &bzpb.RepositoryMetadata{}),
+	} {
+		if entry.Key != "https://example.com/ok" {
+			entry.FetchedAt = old
+		}
+		store.Put(entry)
+	}
+	if err := store.WriteFile(filename); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestInspect(t *testing.T) {
+	filename := writeCache(t)
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		want []string
+		skip []string
+	}{
+		{
+			name: "summary",
+			want: []string{"resource_status ", "resource_failure ", "repository_metadata ", "total  "},
+			skip: []string{"https://example.com/ok"},
+		},
+		{
+			name: "list expired",
+			args: []string{"--list", "--expired"},
+			want: []string{"https://example.com/old", "https://example.com/404", "expired"},
+			skip: []string{"https://example.com/ok"},
+		},
+		{
+			name: "list type",
+			args: []string{"--list", "--type=repository_metadata"},
+			want: []string{"github:org/repo"},
+			skip: []string{"https://example.com/ok", "resource_status "},
+		},
+		{
+			name: "ttl override",
+			args: []string{"--ttl=resource_status=0", "--ttl=repository_metadata=90d"},
+			want: []string{"never", "90d"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			args := append([]string{"inspect", "--cache_file=" + filename}, tc.args...)
+			if err := run(args, &out); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("expected output to contain %q:\n%s", want, out.String())
+				}
+			}
+			for _, skip := range tc.skip {
+				if strings.Contains(out.String(), skip) {
+					t.Errorf("expected output not to contain %q:\n%s", skip, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	filename := writeCache(t)
+
+	var out bytes.Buffer
+	if err := run([]string{"prune", "--cache_file=" + filename, "--dry_run"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.HasPrefix(got, "would prune 3 of 4 entries") {
+		t.Errorf("unexpected dry run output: %q", got)
+	}
+
+	out.Reset()
+	if err := run([]string{"prune", "--cache_file=" + filename}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.HasPrefix(got, "pruned 3 of 4 entries") {
+		t.Errorf("unexpected prune output: %q", got)
+	}
+
+	store, err := cachestore.ReadFile(filename, cachestore.DefaultTTLs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 1 || store.Get(bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, "https://example.com/ok") == nil {
+		t.Errorf("unexpected entries after pruning: %v", store.Entries(bzpb.CacheEntryType_CACHE_ENTRY_TYPE_UNKNOWN))
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	filename := writeCache(t)
+	for _, args := range [][]string{
+		{},
+		{"inspect"},
+		{"inspect", "--cache_file=" + filepath.Join(t.TempDir(), "missing.json")},
+		{"inspect", "--cache_file=" + filename, "--type=unknown"},
+		{"compact", "--cache_file=" + filename},
+	} {
+		if err := run(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("run(%q): expected an error", args)
+		}
+	}
+}
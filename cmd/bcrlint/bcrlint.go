@@ -0,0 +1,166 @@
+// bcrlint checks the consistency of a registry directory: the files of each
+// module must parse and agree with each other and with the directory layout.
+// It is meant to run as a presubmit check of a registry.
+//
+// Exit codes: 0 if no errors were found, 1 if there were errors (or warnings
+// with --warnings_as_errors), 2 if the registry could not be checked.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/archive"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const toolName = "bcrlint"
+
+const (
+	exitOK       = 0
+	exitProblems = 1
+	exitFailure  = 2
+)
+
+type Config struct {
+	RegistryRoot     string
+	Format           string
+	OutputFile       string
+	WarningsAsErrors bool
+	VerifyArchives   bool
+	ArchiveCacheDir  string
+	ModuleNames      []string
+}
+
+func main() {
+	log.SetPrefix(toolName + ": ")
+	log.SetOutput(os.Stderr)
+	log.SetFlags(0) // don't print timestamps
+
+	code, err := run(os.Args[1:], os.Stdout)
+	if err != nil {
+		log.Print(err)
+		os.Exit(exitFailure)
+	}
+	os.Exit(code)
+}
+
+func run(args []string, stdout io.Writer) (int, error) {
+	cfg, err := parseFlags(args)
+	if err != nil {
+		return exitFailure, fmt.Errorf("failed to parse args: %v", err)
+	}
+
+	if cfg.RegistryRoot == "" {
+		return exitFailure, fmt.Errorf("registry_root is required")
+	}
+
+	var verifier *archive.Verifier
+	if cfg.VerifyArchives {
+		verifier = &archive.Verifier{Download: true}
+		if cfg.ArchiveCacheDir != "" {
+			verifier.Cache = &archive.Cache{Dir: cfg.ArchiveCacheDir}
+		}
+	}
+
+	diagnostics, err := lintRegistry(cfg.RegistryRoot, cfg.ModuleNames, verifier)
+	if err != nil {
+		return exitFailure, err
+	}
+	report := &bzpb.RegistryDiagnosticReport{Diagnostics: diagnostics}
+
+	switch cfg.Format {
+	case "text":
+		writeText(stdout, report)
+	case "json":
+		if err := writeJSON(stdout, report); err != nil {
+			return exitFailure, err
+		}
+	default:
+		return exitFailure, fmt.Errorf("unknown format %q (want text or json)", cfg.Format)
+	}
+
+	if cfg.OutputFile != "" {
+		if err := protoutil.WriteFile(cfg.OutputFile, report); err != nil {
+			return exitFailure, fmt.Errorf("failed to write output file: %v", err)
+		}
+	}
+
+	return exitCode(report, cfg.WarningsAsErrors), nil
+}
+
+func parseFlags(args []string) (cfg Config, err error) {
+	fs := flag.NewFlagSet(toolName, flag.ExitOnError)
+	fs.StringVar(&cfg.RegistryRoot, "registry_root", "", "the registry directory to check, containing the modules/ directory (required)")
+	fs.StringVar(&cfg.Format, "format", "text", "output format: text or json")
+	fs.StringVar(&cfg.OutputFile, "output_file", "", "optional file to also write the report to (.json or .pb)")
+	fs.BoolVar(&cfg.VerifyArchives, "verify_archives", false, "download the source archives (or read them from --archive_cache_dir) and verify their integrity and strip_prefix")
+	fs.StringVar(&cfg.ArchiveCacheDir, "archive_cache_dir", "", "content-addressed archive cache, in the layout of bazel's --repository_cache (verified downloads are added to it)")
+	fs.BoolVar(&cfg.WarningsAsErrors, "warnings_as_errors", false, "exit with a non-zero code on warnings too")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s --registry_root=DIR [OPTIONS] [MODULE_NAME...]\n\n", toolName)
+		fmt.Fprintln(fs.Output(), "Checks all modules of the registry, or only the given ones.")
+		fs.PrintDefaults()
+	}
+
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+
+	cfg.ModuleNames = fs.Args()
+
+	return
+}
+
+// writeText writes one line per diagnostic followed by a summary.
+func writeText(w io.Writer, report *bzpb.RegistryDiagnosticReport) {
+	for _, d := range report.Diagnostics {
+		fmt.Fprintf(w, "%s: %s: %s (%s)\n", d.File, severityName(d.Severity), d.Message, d.Check)
+	}
+	fmt.Fprintf(w, "%d errors, %d warnings\n",
+		count(report, bzpb.DiagnosticSeverity_ERROR), count(report, bzpb.DiagnosticSeverity_WARNING))
+}
+
+// writeJSON writes the report as (multiline) JSON.
+func writeJSON(w io.Writer, report *bzpb.RegistryDiagnosticReport) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func severityName(severity bzpb.DiagnosticSeverity) string {
+	switch severity {
+	case bzpb.DiagnosticSeverity_ERROR:
+		return "error"
+	case bzpb.DiagnosticSeverity_WARNING:
+		return "warning"
+	}
+	return "unknown"
+}
+
+func count(report *bzpb.RegistryDiagnosticReport, severity bzpb.DiagnosticSeverity) (n int) {
+	for _, d := range report.Diagnostics {
+		if d.Severity == severity {
+			n++
+		}
+	}
+	return
+}
+
+func exitCode(report *bzpb.RegistryDiagnosticReport, warningsAsErrors bool) int {
+	if count(report, bzpb.DiagnosticSeverity_ERROR) > 0 {
+		return exitProblems
+	}
+	if warningsAsErrors && count(report, bzpb.DiagnosticSeverity_WARNING) > 0 {
+		return exitProblems
+	}
+	return exitOK
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testIntegrity is the integrity of an empty file
+const testIntegrity = "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+
+// writeRegistry writes the files (path relative to the registry root =>
+// content) to a temporary registry directory.
+func writeRegistry(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		filename := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func moduleBazel(name, version string, compatibilityLevel int) string {
+	return fmt.Sprintf("module(name = %q, version = %q, compatibility_level = %d)\n", name, version, compatibilityLevel)
+}
+
+func sourceJSON(integrity string, patches ...string) string {
+	var entries []string
+	for _, patch := range patches {
+		entries = append(entries, `"`+patch+`": "`+testIntegrity+`"`)
+	}
+	return `{"url": "https://example.com/a.tar.gz", "integrity": "` + integrity + `", "patches": {` + strings.Join(entries, ", ") + `}}`
+}
+
+func TestLintRegistry(t *testing.T) {
+	root := writeRegistry(t, map[string]string{
+		// a consistent module
+		"modules/good/metadata.json":              `{"versions": ["1.0.0", "1.1.0"], "yanked_versions": {"1.0.0": "broken"}}`,
+		"modules/good/1.0.0/MODULE.bazel":         moduleBazel("good", "1.0.0", 1),
+		"modules/good/1.0.0/source.json":          sourceJSON(testIntegrity, "fix.patch"),
+		"modules/good/1.0.0/patches/fix.patch":    "",
+		"modules/good/1.1.0/MODULE.bazel":         moduleBazel("good", "1.1.0", 1),
+		"modules/good/1.1.0/source.json":          sourceJSON(testIntegrity),
+		"modules/good/1.1.0/presubmit.yml":        "matrix:\n  platform: [debian10]\n",
+		"modules/good/1.1.0/attestations.json":    `{"mediaType": "application/vnd.build.bazel.registry.attestation+json;version=1.0.0", "attestations": {}}`,
+		"modules/bad/metadata.json":               `{"versions": ["1.0.0", "2.0.0", "3.0.0"], "yanked_versions": {"0.1.0": "gone"}}`,
+		"modules/bad/1.0.0/MODULE.bazel":          moduleBazel("bad", "1.0.0", 2),
+		"modules/bad/1.0.0/source.json":           sourceJSON("sha256-tooshort", "missing.patch", "../../../good/1.0.0/patches/fix.patch"),
+		"modules/bad/2.0.0/MODULE.bazel":          moduleBazel("bad", "2.0.1", 1),
+		"modules/bad/2.0.0/source.json":           sourceJSON(testIntegrity, "changed.patch"),
+		"modules/bad/2.0.0/patches/changed.patch": "not empty",
+		"modules/bad/4.0.0/MODULE.bazel":          moduleBazel("other", "4.0.0", 2),
+		"modules/bad/4.0.0/source.json":           "{",
+		"modules/bad/docs/README.md":              "not a version directory",
+	})
+
+	diagnostics, err := lintRegistry(root, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	var escaping bool
+	for _, d := range diagnostics {
+		if d.ModuleName == "good" {
+			t.Errorf("unexpected diagnostic for the good module: %v", d)
+		}
+		got = append(got, d.File+" "+d.Check)
+		// the patch of the good module must not be verified in its place
+		escaping = escaping || strings.Contains(d.Message, "../../../good/1.0.0/patches/fix.patch is not a local path")
+	}
+	if !escaping {
+		t.Errorf("missing diagnostic of the patch name that is not a local path")
+	}
+	for _, want := range []string{
+		"modules/bad/1.0.0/source.json integrity",
+		"modules/bad/1.0.0/source.json source-files",
+		"modules/bad/2.0.0/MODULE.bazel module-path",
+		"modules/bad/2.0.0/MODULE.bazel compatibility-level",
+		"modules/bad/2.0.0/patches/changed.patch integrity",
+		"modules/bad/4.0.0/MODULE.bazel module-path",
+		"modules/bad/4.0.0/source.json read",
+		"modules/bad/metadata.json metadata-versions",
+		"modules/bad/metadata.json yanked-versions",
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("missing diagnostic %q in %v", want, got)
+		}
+	}
+	files := make([]string, len(diagnostics))
+	for i, d := range diagnostics {
+		files[i] = d.File
+	}
+	if !slices.IsSorted(files) {
+		t.Errorf("diagnostics are not sorted by file: %v", files)
+	}
+}
+
+func TestRun(t *testing.T) {
+	root := writeRegistry(t, map[string]string{
+		"modules/good/metadata.json":      `{"versions": ["1.0.0"]}`,
+		"modules/good/1.0.0/MODULE.bazel": moduleBazel("good", "1.0.0", 0),
+		"modules/good/1.0.0/source.json":  sourceJSON(testIntegrity),
+		"modules/dup/metadata.json":       `{"versions": ["1.0.0", "1.0.0"]}`,
+		"modules/dup/1.0.0/MODULE.bazel":  moduleBazel("dup", "1.0.0", 0),
+		"modules/dup/1.0.0/source.json":   sourceJSON(testIntegrity),
+		"modules/bad/metadata.json":       `{"versions": []}`,
+		"modules/bad/1.0.0/MODULE.bazel":  moduleBazel("bad", "1.0.0", 0),
+		"modules/bad/1.0.0/source.json":   sourceJSON(testIntegrity),
+	})
+
+	for name, tc := range map[string]struct {
+		args     []string
+		wantCode int
+		wantOut  string
+	}{
+		"clean": {
+			args:     []string{"--registry_root=" + root, "good"},
+			wantCode: exitOK,
+			wantOut:  "0 errors, 0 warnings",
+		},
+		"warnings": {
+			args:     []string{"--registry_root=" + root, "dup"},
+			wantCode: exitOK,
+			wantOut:  "0 errors, 1 warnings",
+		},
+		"warnings as errors": {
+			args:     []string{"--registry_root=" + root, "--warnings_as_errors", "dup"},
+			wantCode: exitProblems,
+		},
+		"errors": {
+			args:     []string{"--registry_root=" + root},
+			wantCode: exitProblems,
+			wantOut:  "modules/bad/metadata.json: error: version directory 1.0.0 is not listed in versions (metadata-versions)",
+		},
+		"json": {
+			args:     []string{"--registry_root=" + root, "--format=json", "bad"},
+			wantCode: exitProblems,
+			wantOut:  `"metadata-versions"`,
+		},
+		"unknown module": {
+			args:     []string{"--registry_root=" + root, "nope"},
+			wantCode: exitFailure,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			code, err := run(tc.args, &out)
+			if code != tc.wantCode {
+				t.Errorf("run() = %d (%v), want %d", code, err, tc.wantCode)
+			}
+			if !strings.Contains(out.String(), tc.wantOut) {
+				t.Errorf("output %q does not contain %q", out.String(), tc.wantOut)
+			}
+		})
+	}
+}
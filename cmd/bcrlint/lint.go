@@ -0,0 +1,266 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/archive"
+	"github.com/bazel-contrib/bcr-frontend/pkg/attestationsjson"
+	"github.com/bazel-contrib/bcr-frontend/pkg/metadatajson"
+	"github.com/bazel-contrib/bcr-frontend/pkg/modulebazel"
+	"github.com/bazel-contrib/bcr-frontend/pkg/presubmityml"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sourcejson"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
+	"github.com/bazel-contrib/bcr-frontend/pkg/versionutil"
+)
+
+// Names of the checks, reported in RegistryDiagnostic.check
+const (
+	checkRead               = "read"
+	checkMetadataVersions   = "metadata-versions"
+	checkModulePath         = "module-path"
+	checkCompatibilityLevel = "compatibility-level"
+	checkIntegrity          = "integrity"
+	checkSourceFiles        = "source-files"
+	checkYankedVersions     = "yanked-versions"
+	checkArchive            = "archive"
+)
+
+// linter checks the cross-file invariants of a registry directory.
+type linter struct {
+	registryRoot string
+	verifier     *archive.Verifier // verifies the source archives, if not nil
+	diagnostics  []*bzpb.RegistryDiagnostic
+}
+
+// report records a problem for the file (relative to the registry root).
+func (l *linter) report(check string, severity bzpb.DiagnosticSeverity, file, moduleName, version, format string, args ...any) {
+	l.diagnostics = append(l.diagnostics, &bzpb.RegistryDiagnostic{
+		File:       file,
+		ModuleName: moduleName,
+		Version:    version,
+		Severity:   severity,
+		Message:    fmt.Sprintf(format, args...),
+		Check:      check,
+	})
+}
+
+// lintRegistry checks the given modules of the registry (all if empty) and
+// returns the problems found, sorted by file.
+func lintRegistry(registryRoot string, moduleNames []string, verifier *archive.Verifier) ([]*bzpb.RegistryDiagnostic, error) {
+	l := &linter{registryRoot: registryRoot, verifier: verifier}
+
+	if len(moduleNames) == 0 {
+		entries, err := os.ReadDir(filepath.Join(registryRoot, "modules"))
+		if err != nil {
+			return nil, fmt.Errorf("reading modules: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				moduleNames = append(moduleNames, entry.Name())
+			}
+		}
+	}
+
+	for _, name := range moduleNames {
+		if err := l.lintModule(name); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(l.diagnostics, func(a, b *bzpb.RegistryDiagnostic) int {
+		return cmp.Or(strings.Compare(a.File, b.File), strings.Compare(a.Message, b.Message))
+	})
+	return l.diagnostics, nil
+}
+
+// lintModule checks the module directory modules/NAME.
+func (l *linter) lintModule(name string) error {
+	moduleDir := filepath.Join("modules", name)
+	entries, err := os.ReadDir(filepath.Join(l.registryRoot, moduleDir))
+	if err != nil {
+		return fmt.Errorf("reading module %s: %w", name, err)
+	}
+
+	// version directories are the ones with a MODULE.bazel file
+	var versionDirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && l.exists(filepath.Join(moduleDir, entry.Name(), "MODULE.bazel")) {
+			versionDirs = append(versionDirs, entry.Name())
+		}
+	}
+
+	metadataFile := filepath.Join(moduleDir, "metadata.json")
+	metadata, err := metadatajson.ReadFile(filepath.Join(l.registryRoot, metadataFile))
+	if err != nil {
+		l.report(checkRead, bzpb.DiagnosticSeverity_ERROR, metadataFile, name, "", "%v", err)
+	} else {
+		l.lintMetadata(name, metadataFile, metadata, versionDirs)
+	}
+
+	modules := make(map[string]*bzpb.ModuleVersion)
+	for _, version := range versionDirs {
+		if module := l.lintModuleVersion(name, version); module != nil {
+			modules[version] = module
+		}
+	}
+	l.lintCompatibilityLevels(name, modules)
+
+	return nil
+}
+
+// lintMetadata checks that the versions listed in metadata.json match the
+// version directories and that yanked versions exist.
+func (l *linter) lintMetadata(name, file string, metadata *bzpb.ModuleMetadata, versionDirs []string) {
+	listed := make(map[string]bool)
+	for _, version := range metadata.Versions {
+		if listed[version] {
+			l.report(checkMetadataVersions, bzpb.DiagnosticSeverity_WARNING, file, name, version, "version %s is listed more than once", version)
+		}
+		listed[version] = true
+		if !slices.Contains(versionDirs, version) {
+			l.report(checkMetadataVersions, bzpb.DiagnosticSeverity_ERROR, file, name, version, "version %s is listed, but %s/%s/MODULE.bazel does not exist", version, name, version)
+		}
+	}
+	for _, version := range versionDirs {
+		if !listed[version] {
+			l.report(checkMetadataVersions, bzpb.DiagnosticSeverity_ERROR, file, name, version, "version directory %s is not listed in versions", version)
+		}
+	}
+
+	for version := range metadata.YankedVersions {
+		if !listed[version] {
+			l.report(checkYankedVersions, bzpb.DiagnosticSeverity_ERROR, file, name, version, "yanked version %s is not listed in versions", version)
+		}
+	}
+}
+
+// lintModuleVersion checks the version directory modules/NAME/VERSION and
+// returns the parsed MODULE.bazel (nil if it could not be read).
+func (l *linter) lintModuleVersion(name, version string) *bzpb.ModuleVersion {
+	versionDir := filepath.Join("modules", name, version)
+
+	moduleFile := filepath.Join(versionDir, "MODULE.bazel")
+	module, err := modulebazel.ExecFile(filepath.Join(l.registryRoot, moduleFile))
+	if err != nil {
+		l.report(checkRead, bzpb.DiagnosticSeverity_ERROR, moduleFile, name, version, "%v", err)
+	} else {
+		if module.Name != name {
+			l.report(checkModulePath, bzpb.DiagnosticSeverity_ERROR, moduleFile, name, version, "module name %q does not match the directory name %q", module.Name, name)
+		}
+		if module.Version != version {
+			l.report(checkModulePath, bzpb.DiagnosticSeverity_ERROR, moduleFile, name, version, "module version %q does not match the directory name %q", module.Version, version)
+		}
+	}
+
+	sourceFile := filepath.Join(versionDir, "source.json")
+	if !l.exists(sourceFile) {
+		l.report(checkSourceFiles, bzpb.DiagnosticSeverity_ERROR, sourceFile, name, version, "source.json does not exist")
+	} else if source, err := sourcejson.ReadFile(filepath.Join(l.registryRoot, sourceFile)); err != nil {
+		l.report(checkRead, bzpb.DiagnosticSeverity_ERROR, sourceFile, name, version, "%v", err)
+	} else {
+		l.lintSource(name, version, sourceFile, source)
+	}
+
+	if file := filepath.Join(versionDir, "presubmit.yml"); l.exists(file) {
+		if _, err := presubmityml.ReadFile(filepath.Join(l.registryRoot, file)); err != nil {
+			l.report(checkRead, bzpb.DiagnosticSeverity_WARNING, file, name, version, "%v", err)
+		}
+	}
+
+	if file := filepath.Join(versionDir, "attestations.json"); l.exists(file) {
+		if _, err := attestationsjson.ReadFile(filepath.Join(l.registryRoot, file)); err != nil {
+			l.report(checkRead, bzpb.DiagnosticSeverity_ERROR, file, name, version, "%v", err)
+		}
+	}
+
+	return module
+}
+
+// lintSource checks the integrity strings of source.json and verifies the
+// patch and overlay files it lists against their integrity.
+func (l *linter) lintSource(name, version, file string, source *bzpb.ModuleSource) {
+	versionDir := filepath.Dir(file)
+
+	// git_repository sources are pinned by commit rather than integrity
+	if source.Type != "git_repository" {
+		if _, err := sri.Parse(source.Integrity); err != nil {
+			l.report(checkIntegrity, bzpb.DiagnosticSeverity_ERROR, file, name, version, "source: %v", err)
+		}
+	}
+
+	if l.verifier != nil && source.Url != "" && source.Type != "git_repository" {
+		l.lintArchive(name, version, file, source)
+	}
+
+	sourcejson.VerifyFiles(filepath.Join(l.registryRoot, versionDir), source)
+	for _, kind := range []struct {
+		dir     string
+		files   map[string]string
+		results map[string]*bzpb.FileIntegrity
+	}{
+		{dir: "patches", files: source.Patches, results: source.PatchIntegrity},
+		{dir: "overlay", files: source.Overlay, results: source.OverlayIntegrity},
+	} {
+		for _, filename := range slices.Sorted(maps.Keys(kind.results)) {
+			path := filepath.Join(versionDir, kind.dir, filename)
+			switch result := kind.results[filename]; result.Status {
+			case bzpb.FileIntegrityStatus_FILE_INTEGRITY_MALFORMED:
+				if !filepath.IsLocal(filename) {
+					l.report(checkSourceFiles, bzpb.DiagnosticSeverity_ERROR, file, name, version, "%s %s is not a local path", kind.dir, filename)
+					continue
+				}
+				_, err := sri.Parse(kind.files[filename])
+				l.report(checkIntegrity, bzpb.DiagnosticSeverity_ERROR, file, name, version, "%s %s: %v", kind.dir, filename, err)
+			case bzpb.FileIntegrityStatus_FILE_INTEGRITY_FILE_MISSING:
+				l.report(checkSourceFiles, bzpb.DiagnosticSeverity_ERROR, file, name, version, "%s %s does not exist", kind.dir, path)
+			case bzpb.FileIntegrityStatus_FILE_INTEGRITY_MISMATCH:
+				l.report(checkIntegrity, bzpb.DiagnosticSeverity_ERROR, path, name, version, "integrity mismatch: source.json lists %s, the file has %s", kind.files[filename], result.ActualIntegrity)
+			}
+		}
+	}
+}
+
+// lintArchive verifies the source archive against the integrity and
+// strip_prefix.  Archives that cannot be fetched are only warnings.
+func (l *linter) lintArchive(name, version, file string, source *bzpb.ModuleSource) {
+	result := l.verifier.Verify(context.Background(), source)
+	switch result.Status {
+	case bzpb.ArchiveVerificationStatus_ARCHIVE_VERIFIED, bzpb.ArchiveVerificationStatus_ARCHIVE_MALFORMED_INTEGRITY:
+		// malformed integrity strings are reported by the integrity check
+	case bzpb.ArchiveVerificationStatus_ARCHIVE_INTEGRITY_MISMATCH:
+		l.report(checkArchive, bzpb.DiagnosticSeverity_ERROR, file, name, version, "integrity mismatch: the archive has integrity %s", result.ActualIntegrity)
+	case bzpb.ArchiveVerificationStatus_ARCHIVE_UNAVAILABLE:
+		l.report(checkArchive, bzpb.DiagnosticSeverity_WARNING, file, name, version, "archive could not be verified: %s", result.Message)
+	default:
+		l.report(checkArchive, bzpb.DiagnosticSeverity_ERROR, file, name, version, "%s", result.Message)
+	}
+}
+
+// lintCompatibilityLevels checks that the compatibility_level never
+// decreases from one version to the next.  The modules are keyed by the
+// version of their directory.
+func (l *linter) lintCompatibilityLevels(name string, modules map[string]*bzpb.ModuleVersion) {
+	versions := slices.SortedFunc(maps.Keys(modules), versionutil.Compare)
+	for i := 1; i < len(versions); i++ {
+		prev, next := modules[versions[i-1]], modules[versions[i]]
+		if next.CompatibilityLevel < prev.CompatibilityLevel {
+			file := filepath.Join("modules", name, versions[i], "MODULE.bazel")
+			l.report(checkCompatibilityLevel, bzpb.DiagnosticSeverity_ERROR, file, name, versions[i],
+				"compatibility_level %d is lower than %d of the previous version %s", next.CompatibilityLevel, prev.CompatibilityLevel, versions[i-1])
+		}
+	}
+}
+
+// exists reports whether the file (relative to the registry root) exists.
+func (l *linter) exists(file string) bool {
+	_, err := os.Stat(filepath.Join(l.registryRoot, file))
+	return err == nil
+}
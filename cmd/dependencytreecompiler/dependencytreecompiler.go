@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/paramsfile"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+)
+
+const toolName = "dependencytreecompiler"
+
+type Config struct {
+	RegistryFile string
+	OutputDir    string
+	Mvs          paramsfile.StringSlice
+	MvsMerged    paramsfile.StringSlice
+	MvsOverrides paramsfile.StringSlice
+}
+
+func main() {
+	log.SetPrefix(toolName + ": ")
+	log.SetOutput(os.Stderr)
+	log.SetFlags(0) // don't print timestamps
+
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string) error {
+	parsedArgs, err := paramsfile.ReadArgsParamsFile(args)
+	if err != nil {
+		return fmt.Errorf("failed to read params file: %v", err)
+	}
+
+	cfg, err := parseFlags(parsedArgs)
+	if err != nil {
+		return fmt.Errorf("failed to parse args: %v", err)
+	}
+
+	if cfg.RegistryFile == "" {
+		return fmt.Errorf("registry_file is required")
+	}
+	if cfg.OutputDir == "" {
+		return fmt.Errorf("output_dir is required")
+	}
+
+	var registry bzpb.Registry
+	if err := protoutil.ReadFile(cfg.RegistryFile, &registry); err != nil {
+		return fmt.Errorf("reading %s: %v", cfg.RegistryFile, err)
+	}
+
+	selections := make(map[string]*selection)
+	selectionFor := func(id string) *selection {
+		s, ok := selections[id]
+		if !ok {
+			s = newSelection()
+			selections[id] = s
+		}
+		return s
+	}
+	for _, arg := range cfg.Mvs {
+		root, name, version, err := parseSelectedModule(arg)
+		if err != nil {
+			return err
+		}
+		selectionFor(root).mvs[name] = version
+	}
+	for _, arg := range cfg.MvsMerged {
+		root, name, version, err := parseSelectedModule(arg)
+		if err != nil {
+			return err
+		}
+		selectionFor(root).mvsMerged[name] = version
+	}
+	for _, arg := range cfg.MvsOverrides {
+		root, name, overrideType, err := parseMvsOverride(arg)
+		if err != nil {
+			return err
+		}
+		selectionFor(root).overrides[name] = overrideType
+	}
+
+	modules := make(map[string]*bzpb.ModuleVersion)
+	for _, module := range registry.Modules {
+		for _, mv := range module.Versions {
+			modules[moduleID(mv.Name, mv.Version)] = mv
+		}
+	}
+
+	var count int
+	for _, module := range registry.Modules {
+		for _, mv := range module.Versions {
+			id := moduleID(mv.Name, mv.Version)
+			tree := buildDependencyTree(mv, modules, selectionFor(id))
+
+			filename := filepath.Join(cfg.OutputDir, mv.Name, mv.Version, "dependencytree.pb")
+			if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+				return fmt.Errorf("creating output directory for %s: %v", id, err)
+			}
+			if err := protoutil.WriteFile(filename, tree); err != nil {
+				return fmt.Errorf("writing %s: %v", filename, err)
+			}
+			count++
+		}
+	}
+
+	log.Printf("Compiled %d dependency trees", count)
+	return nil
+}
+
+func parseFlags(args []string) (cfg Config, err error) {
+	fs := flag.NewFlagSet(toolName, flag.ExitOnError)
+	fs.StringVar(&cfg.RegistryFile, "registry_file", "", "the registry protobuf file to read (required)")
+	fs.StringVar(&cfg.OutputDir, "output_dir", "", "the directory to write NAME/VERSION/dependencytree.pb files to (required)")
+	fs.Var(&cfg.Mvs, "mvs", "selected version for a root module version, as ROOT_NAME@ROOT_VERSION=NAME@VERSION (repeatable)")
+	fs.Var(&cfg.MvsMerged, "mvs_merged", "selected version of the regular and root dev deps for a root module version, as ROOT_NAME@ROOT_VERSION=NAME@VERSION (repeatable)")
+	fs.Var(&cfg.MvsOverrides, "mvs_override", "override applied during MVS for a root module version, as ROOT_NAME@ROOT_VERSION=NAME=TYPE (repeatable)")
+	fs.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s @PARAMS_FILE", toolName)
+		fs.PrintDefaults()
+	}
+
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+
+	return
+}
+
+// parseSelectedModule parses a value like "rules_go@0.50.1=bazel_skylib@1.7.1"
+func parseSelectedModule(arg string) (root, name, version string, err error) {
+	root, selected, ok := strings.Cut(arg, "=")
+	if !ok {
+		return "", "", "", fmt.Errorf("malformed selected module (want ROOT=NAME@VERSION): %q", arg)
+	}
+	name, version, ok = strings.Cut(selected, "@")
+	if !ok || name == "" {
+		return "", "", "", fmt.Errorf("malformed selected module (want ROOT=NAME@VERSION): %q", arg)
+	}
+	return root, name, version, nil
+}
+
+// parseMvsOverride parses a value like "rules_go@0.50.1=bazel_skylib=git"
+func parseMvsOverride(arg string) (root, name, overrideType string, err error) {
+	parts := strings.SplitN(arg, "=", 3)
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("malformed override (want ROOT=NAME=TYPE): %q", arg)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func moduleID(name, version string) string {
+	return name + "@" + version
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/versionutil"
+)
+
+// selection is the MVS result computed by gazelle for a single root module
+// version.
+type selection struct {
+	// mvs maps module name -> selected version for regular deps
+	mvs map[string]string
+	// mvsMerged maps module name -> selected version for the regular deps
+	// together with the dev deps of the root (the versions bazel selects
+	// when the module is the root), used for dev deps
+	mvsMerged map[string]string
+	// overrides maps module name -> override type ("single_version", "git",
+	// "archive", "local_path")
+	overrides map[string]string
+}
+
+func newSelection() *selection {
+	return &selection{
+		mvs:       make(map[string]string),
+		mvsMerged: make(map[string]string),
+		overrides: make(map[string]string),
+	}
+}
+
+// selectedVersion returns the version MVS selected for the named module,
+// falling back to the requested version if the module is not part of the
+// selection (e.g. an unresolved dependency).
+func (s *selection) selectedVersion(name, requested string, dev bool) string {
+	primary, secondary := s.mvs, s.mvsMerged
+	if dev {
+		primary, secondary = s.mvsMerged, s.mvs
+	}
+	if version, ok := primary[name]; ok {
+		return version
+	}
+	if version, ok := secondary[name]; ok {
+		return version
+	}
+	return requested
+}
+
+// buildDependencyTree materializes the MVS-resolved dependency tree of the
+// given root module version.  Each module version is expanded only at its
+// first (shallowest) occurrence; later occurrences are marked as pruned.
+// Dev dependencies are only followed for the root, as bazel ignores them
+// for non-root modules.
+func buildDependencyTree(root *bzpb.ModuleVersion, modules map[string]*bzpb.ModuleVersion, sel *selection) *bzpb.DependencyTree {
+	tree := &bzpb.DependencyTree{
+		ModuleVersion: summarizeModuleVersion(root),
+	}
+
+	type pending struct {
+		node   *bzpb.DependencyTreeNode
+		module *bzpb.ModuleVersion
+	}
+
+	expanded := map[string]bool{moduleID(root.Name, root.Version): true}
+	var queue []pending
+
+	makeNode := func(dep *bzpb.ModuleDependency, dev bool) *bzpb.DependencyTreeNode {
+		version := sel.selectedVersion(dep.Name, dep.Version, dev)
+		node := &bzpb.DependencyTreeNode{
+			RequestedVersion: dep.Version,
+			Upgraded:         version != "" && dep.Version != "" && versionutil.Compare(version, dep.Version) > 0,
+			Dev:              dev,
+			OverrideType:     sel.overrides[dep.Name],
+		}
+
+		module, ok := modules[moduleID(dep.Name, version)]
+		if !ok {
+			// unknown to the registry (unresolved or non-registry override)
+			node.ModuleVersion = &bzpb.ModuleVersion{Name: dep.Name, Version: version}
+			return node
+		}
+		node.ModuleVersion = summarizeModuleVersion(module)
+
+		id := moduleID(module.Name, module.Version)
+		if expanded[id] {
+			node.Pruned = hasRegularDeps(module)
+			return node
+		}
+		expanded[id] = true
+		queue = append(queue, pending{node: node, module: module})
+		return node
+	}
+
+	for _, dep := range root.Deps {
+		if dep.Name == root.Name {
+			continue
+		}
+		tree.Children = append(tree.Children, makeNode(dep, dep.Dev))
+	}
+
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, dep := range p.module.Deps {
+			if dep.Dev || dep.Name == root.Name {
+				continue
+			}
+			p.node.Children = append(p.node.Children, makeNode(dep, p.node.Dev))
+		}
+	}
+
+	return tree
+}
+
+// summarizeModuleVersion returns a copy of the module version having only
+// the fields needed to render a tree node.  The full ModuleVersion (source,
+// presubmit, documentation...) is much too large to repeat for every node.
+func summarizeModuleVersion(mv *bzpb.ModuleVersion) *bzpb.ModuleVersion {
+	return &bzpb.ModuleVersion{
+		Name:               mv.Name,
+		Version:            mv.Version,
+		CompatibilityLevel: mv.CompatibilityLevel,
+		BazelCompatibility: mv.BazelCompatibility,
+		RepoName:           mv.RepoName,
+		IsLatestVersion:    mv.IsLatestVersion,
+	}
+}
+
+func hasRegularDeps(mv *bzpb.ModuleVersion) bool {
+	for _, dep := range mv.Deps {
+		if !dep.Dev {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBuildDependencyTree(t *testing.T) {
+	dep := func(name, version string, dev bool) *bzpb.ModuleDependency {
+		return &bzpb.ModuleDependency{Name: name, Version: version, Dev: dev}
+	}
+	modules := map[string]*bzpb.ModuleVersion{
+		"b@1.0": {Name: "b", Version: "1.0", Deps: []*bzpb.ModuleDependency{dep("d", "1.0", false), dep("t", "1.0", true)}},
+		"c@1.0": {Name: "c", Version: "1.0", Deps: []*bzpb.ModuleDependency{dep("d", "1.1", false)}},
+		"d@1.1": {Name: "d", Version: "1.1", Deps: []*bzpb.ModuleDependency{dep("e", "1.0", false)}},
+		"e@1.0": {Name: "e", Version: "1.0"},
+		"t@2.0": {Name: "t", Version: "2.0"},
+	}
+	root := &bzpb.ModuleVersion{
+		Name:    "a",
+		Version: "1.0",
+		Deps: []*bzpb.ModuleDependency{
+			dep("b", "1.0", false),
+			dep("c", "1.0", false),
+			dep("g", "", false),
+			dep("t", "2.0", true),
+		},
+	}
+	sel := newSelection()
+	sel.mvs = map[string]string{"b": "1.0", "c": "1.0", "d": "1.1", "e": "1.0", "g": ""}
+	sel.mvsMerged = map[string]string{"b": "1.0", "c": "1.0", "d": "1.1", "e": "1.0", "g": "", "t": "2.0"}
+	sel.overrides = map[string]string{"g": "git"}
+
+	got := buildDependencyTree(root, modules, sel)
+
+	mv := func(name, version string) *bzpb.ModuleVersion {
+		return &bzpb.ModuleVersion{Name: name, Version: version}
+	}
+	want := &bzpb.DependencyTree{
+		ModuleVersion: mv("a", "1.0"),
+		Children: []*bzpb.DependencyTreeNode{
+			{
+				ModuleVersion:    mv("b", "1.0"),
+				RequestedVersion: "1.0",
+				Children: []*bzpb.DependencyTreeNode{
+					{
+						ModuleVersion:    mv("d", "1.1"),
+						RequestedVersion: "1.0",
+						Upgraded:         true,
+						Children: []*bzpb.DependencyTreeNode{
+							{ModuleVersion: mv("e", "1.0"), RequestedVersion: "1.0"},
+						},
+					},
+				},
+			},
+			{
+				ModuleVersion:    mv("c", "1.0"),
+				RequestedVersion: "1.0",
+				Children: []*bzpb.DependencyTreeNode{
+					{ModuleVersion: mv("d", "1.1"), RequestedVersion: "1.1", Pruned: true},
+				},
+			},
+			{ModuleVersion: mv("g", ""), OverrideType: "git"},
+			{ModuleVersion: mv("t", "2.0"), RequestedVersion: "2.0", Dev: true},
+		},
+	}
+
+	if !proto.Equal(got, want) {
+		t.Errorf("tree mismatch:\ngot:\n%s\nwant:\n%s", protoutil.FormatProtoText(got), protoutil.FormatProtoText(want))
+	}
+}
+
+// TestBuildDependencyTreeNonRootDevDeps checks that the dev subtrees use the
+// versions bazel selects: the dev deps of non-root modules are ignored.
+func TestBuildDependencyTreeNonRootDevDeps(t *testing.T) {
+	dep := func(name, version string, dev bool) *bzpb.ModuleDependency {
+		return &bzpb.ModuleDependency{Name: name, Version: version, Dev: dev}
+	}
+	modules := map[string]*bzpb.ModuleVersion{
+		// the dev dep of b on t@2.0 is ignored when a is the root
+		"b@1.0": {Name: "b", Version: "1.0", Deps: []*bzpb.ModuleDependency{dep("t", "2.0", true)}},
+		"t@1.0": {Name: "t", Version: "1.0", Deps: []*bzpb.ModuleDependency{dep("u", "1.0", false)}},
+		"t@2.0": {Name: "t", Version: "2.0"},
+		"u@1.0": {Name: "u", Version: "1.0"},
+	}
+	root := &bzpb.ModuleVersion{
+		Name:    "a",
+		Version: "1.0",
+		Deps: []*bzpb.ModuleDependency{
+			dep("b", "1.0", false),
+			dep("t", "1.0", true),
+		},
+	}
+	sel := newSelection()
+	sel.mvs = map[string]string{"b": "1.0"}
+	sel.mvsMerged = map[string]string{"b": "1.0", "t": "1.0", "u": "1.0"}
+
+	got := buildDependencyTree(root, modules, sel)
+
+	mv := func(name, version string) *bzpb.ModuleVersion {
+		return &bzpb.ModuleVersion{Name: name, Version: version}
+	}
+	want := &bzpb.DependencyTree{
+		ModuleVersion: mv("a", "1.0"),
+		Children: []*bzpb.DependencyTreeNode{
+			{ModuleVersion: mv("b", "1.0"), RequestedVersion: "1.0"},
+			{
+				ModuleVersion:    mv("t", "1.0"),
+				RequestedVersion: "1.0",
+				Dev:              true,
+				Children: []*bzpb.DependencyTreeNode{
+					{ModuleVersion: mv("u", "1.0"), RequestedVersion: "1.0", Dev: true},
+				},
+			},
+		},
+	}
+
+	if !proto.Equal(got, want) {
+		t.Errorf("tree mismatch:\ngot:\n%s\nwant:\n%s", protoutil.FormatProtoText(got), protoutil.FormatProtoText(want))
+	}
+}
+
+func TestParseSelectedModule(t *testing.T) {
+	root, name, version, err := parseSelectedModule("rules_go@0.50.1=bazel_skylib@1.7.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != "rules_go@0.50.1" || name != "bazel_skylib" || version != "1.7.1" {
+		t.Errorf("got (%q, %q, %q)", root, name, version)
+	}
+	if _, _, _, err := parseSelectedModule("rules_go@0.50.1"); err == nil {
+		t.Error("expected error for missing selected module")
+	}
+}
@@ -6,12 +6,16 @@ import (
 	"log"
 	"os"
 	"slices"
+	"strconv"
+	"strings"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
 	"github.com/bazel-contrib/bcr-frontend/pkg/metadatajson"
 	"github.com/bazel-contrib/bcr-frontend/pkg/paramsfile"
 	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
 	"github.com/bazel-contrib/bcr-frontend/pkg/repositorymetadatajson"
+	"github.com/bazel-contrib/bcr-frontend/pkg/versionutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 const toolName = "modulecompiler"
@@ -22,6 +26,8 @@ type Config struct {
 	RepositoryMetadataFile string
 	SourceJsonFile         string
 	PresubmitYmlFile       string
+	Registry               string
+	ReverseDependencyCount paramsfile.StringSlice
 	ModuleVersionFiles     []string
 }
 
@@ -57,6 +63,7 @@ func run(args []string) error {
 	}
 
 	var module bzpb.Module
+	module.Registry = cfg.Registry
 
 	metadata, err := metadatajson.ReadFile(cfg.ModuleMetadataFile)
 	if err != nil {
@@ -72,39 +79,27 @@ func run(args []string) error {
 		module.RepositoryMetadata = md
 	}
 
+	if len(cfg.ReverseDependencyCount) > 0 {
+		counts, err := parseReverseDependencyCounts(cfg.ReverseDependencyCount)
+		if err != nil {
+			return err
+		}
+		module.ReverseDependencyCounts = counts
+	}
+
 	for _, file := range cfg.ModuleVersionFiles {
 		var version bzpb.ModuleVersion
 		if err := protoutil.ReadFile(file, &version); err != nil {
 			return fmt.Errorf("reading %s: %v", file, err)
 		}
 		module.Name = version.Name
+		version.Registry = cfg.Registry
 		module.Versions = append(module.Versions, &version)
 	}
 
-	// Sort versions according to moduleMetadata.Versions order (latest last),
-	// then reverse to put latest first
-	versionOrder := make(map[string]int)
-	for i, v := range metadata.Versions {
-		versionOrder[v] = i
-	}
-
-	slices.SortFunc(module.Versions, func(a, b *bzpb.ModuleVersion) int {
-		aOrder, aExists := versionOrder[a.Version]
-		bOrder, bExists := versionOrder[b.Version]
-
-		// If version not in metadata, put at end
-		if !aExists && !bExists {
-			return 0
-		}
-		if !aExists {
-			return 1
-		}
-		if !bExists {
-			return -1
-		}
-
-		// Compare by metadata order (reversed - higher index = newer = comes first)
-		return bOrder - aOrder
+	// Sort versions by version precedence, latest first
+	slices.SortStableFunc(module.Versions, func(a, b *bzpb.ModuleVersion) int {
+		return versionutil.Compare(b.Version, a.Version)
 	})
 
 	// Write the compiled ModuleVersion to output file
@@ -121,6 +116,8 @@ func parseFlags(args []string) (cfg Config, err error) {
 	fs.StringVar(&cfg.ModuleMetadataFile, "module_metadata_file", "", "the metadata.json file to read (required)")
 	fs.StringVar(&cfg.RepositoryMetadataFile, "repository_metadata_file", "", "the repository.json file to read (optional)")
 	fs.StringVar(&cfg.OutputFile, "output_file", "", "the output file to write")
+	fs.StringVar(&cfg.Registry, "registry", "", "name of the registry providing the module (optional)")
+	fs.Var(&cfg.ReverseDependencyCount, "reverse_dependency_count", "reverse dependency count in the form FIELD=COUNT, where FIELD is a ReverseDependencyCounts field name (repeatable)")
 	fs.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s @PARAMS_FILE", toolName)
 		fs.PrintDefaults()
@@ -134,3 +131,29 @@ func parseFlags(args []string) (cfg Config, err error) {
 
 	return
 }
+
+// parseReverseDependencyCounts parses FIELD=COUNT entries into a
+// ReverseDependencyCounts message.
+func parseReverseDependencyCounts(entries []string) (*bzpb.ReverseDependencyCounts, error) {
+	counts := &bzpb.ReverseDependencyCounts{}
+	msg := counts.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+
+	for _, entry := range entries {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid reverse_dependency_count %q (want FIELD=COUNT)", entry)
+		}
+		fd := fields.ByName(protoreflect.Name(key))
+		if fd == nil || fd.Kind() != protoreflect.Int32Kind {
+			return nil, fmt.Errorf("invalid reverse_dependency_count %q: unknown field %q", entry, key)
+		}
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reverse_dependency_count %q: %v", entry, err)
+		}
+		msg.Set(fd, protoreflect.ValueOfInt32(int32(n)))
+	}
+
+	return counts, nil
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
@@ -21,22 +22,43 @@ import (
 const toolName = "moduleversioncompiler"
 
 type Config struct {
-	OutputFile               string
-	ModuleBazelFile          string
-	SourceJsonFile           string
-	AttestationsJsonFile     string
-	PresubmitYmlFile         string
-	ModuleVersionSymbolsFile string
-	CommitSha1               string
-	CommitDate               string
-	CommitMessage            string
-	UnresolvedDeps           string
-	UrlStatusCode            int
-	UrlStatusMessage         string
-	DocsUrlStatusCode        int
-	DocsUrlStatusMessage     string
-	SourceCommitSha          string
-	IsLatestVersion          bool
+	OutputFile                   string
+	ModuleBazelFile              string
+	SourceJsonFile               string
+	AttestationsJsonFile         string
+	PresubmitYmlFile             string
+	ModuleVersionSymbolsFile     string
+	CommitSha1                   string
+	CommitDate                   string
+	CommitMessage                string
+	UnresolvedDeps               string
+	UrlStatusCode                int
+	UrlStatusMessage             string
+	DocsUrlStatusCode            int
+	DocsUrlStatusMessage         string
+	SourceCommitSha              string
+	IsLatestVersion              bool
+	ResolutionConflicts          paramsfile.StringSlice
+	CompatibleBazelMinVersion    string
+	CompatibleBazelMaxVersion    string
+	CompatibleBazelVersionCount  int
+	BazelCompatibilityNarrowedBy paramsfile.StringSlice
+	MvsDevUpgrades               paramsfile.StringSlice
+	PatchIntegrity               paramsfile.StringSlice
+	OverlayIntegrity             paramsfile.StringSlice
+	ArchiveVerificationStatus    string
+	ArchiveActualIntegrity       string
+	ArchiveVerificationMessage   string
+	AttestationVerification      paramsfile.StringSlice
+	AttestationMessages          paramsfile.StringSlice
+	ProvenancePredicateType      string
+	ProvenanceBuilderId          string
+	ProvenanceSourceRepository   string
+	ProvenanceSourceRef          string
+	ProvenanceSourceCommit       string
+	ProvenanceWorkflowPath       string
+	ProvenanceBuildInvocationId  string
+	ProvenanceAttestedFiles      paramsfile.StringSlice
 }
 
 func main() {
@@ -84,6 +106,23 @@ func run(args []string) error {
 		}
 		module.Source = source
 		module.Source.CommitSha = cfg.SourceCommitSha
+		if module.Source.PatchIntegrity, err = parseFileIntegrity(cfg.PatchIntegrity); err != nil {
+			return err
+		}
+		if module.Source.OverlayIntegrity, err = parseFileIntegrity(cfg.OverlayIntegrity); err != nil {
+			return err
+		}
+		if cfg.ArchiveVerificationStatus != "" {
+			status, ok := bzpb.ArchiveVerificationStatus_value[cfg.ArchiveVerificationStatus]
+			if !ok {
+				return fmt.Errorf("unknown archive verification status: %q", cfg.ArchiveVerificationStatus)
+			}
+			module.Source.ArchiveVerification = &bzpb.ArchiveVerification{
+				Status:          bzpb.ArchiveVerificationStatus(status),
+				ActualIntegrity: cfg.ArchiveActualIntegrity,
+				Message:         cfg.ArchiveVerificationMessage,
+			}
+		}
 		if module.Source.Url != "" {
 			module.Source.UrlStatus = &bzpb.ResourceStatus{
 				Url:     module.Source.Url,
@@ -124,9 +163,26 @@ func run(args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to read presubmit.yml: %v", err)
 		}
+		if err := parseAttestationVerification(attestations, cfg.AttestationVerification, cfg.AttestationMessages); err != nil {
+			return err
+		}
 		module.Attestations = attestations
 	}
 
+	// Add the SLSA provenance of the verified attestations (optional)
+	if cfg.ProvenancePredicateType != "" {
+		module.Provenance = &bzpb.SlsaProvenance{
+			PredicateType:     cfg.ProvenancePredicateType,
+			BuilderId:         cfg.ProvenanceBuilderId,
+			SourceRepository:  cfg.ProvenanceSourceRepository,
+			SourceRef:         cfg.ProvenanceSourceRef,
+			SourceCommit:      cfg.ProvenanceSourceCommit,
+			WorkflowPath:      cfg.ProvenanceWorkflowPath,
+			BuildInvocationId: cfg.ProvenanceBuildInvocationId,
+			AttestedFiles:     cfg.ProvenanceAttestedFiles,
+		}
+	}
+
 	// Add commit information (optional)
 	if cfg.CommitSha1 != "" && cfg.CommitDate != "" {
 		module.Commit = &bzpb.ModuleCommit{
@@ -145,6 +201,30 @@ func run(args []string) error {
 		}
 	}
 
+	if len(cfg.ResolutionConflicts) > 0 {
+		resolutionError, err := parseResolutionConflicts(cfg.ResolutionConflicts)
+		if err != nil {
+			return fmt.Errorf("failed to parse resolution conflicts: %v", err)
+		}
+		module.ResolutionError = resolutionError
+	}
+
+	if len(cfg.MvsDevUpgrades) > 0 {
+		upgrades, err := parseDevDependencyUpgrades(cfg.MvsDevUpgrades)
+		if err != nil {
+			return fmt.Errorf("failed to parse dev dependency upgrades: %v", err)
+		}
+		module.DevDependencyUpgrades = upgrades
+	}
+
+	if cfg.CompatibleBazelVersionCount >= 0 {
+		compatibilityRange, err := parseBazelCompatibilityRange(cfg.CompatibleBazelMinVersion, cfg.CompatibleBazelMaxVersion, cfg.CompatibleBazelVersionCount, cfg.BazelCompatibilityNarrowedBy)
+		if err != nil {
+			return fmt.Errorf("failed to parse bazel compatibility range: %v", err)
+		}
+		module.BazelCompatibilityRange = compatibilityRange
+	}
+
 	// Write the compiled ModuleVersion to output file
 	if err := protoutil.WriteFile(cfg.OutputFile, module); err != nil {
 		return fmt.Errorf("failed to write output file: %v", err)
@@ -172,6 +252,27 @@ func parseFlags(args []string) (cfg Config, err error) {
 	fs.StringVar(&cfg.DocsUrlStatusMessage, "docs_url_status_message", "", "HTTP status message for the docs URL (optional)")
 	fs.StringVar(&cfg.SourceCommitSha, "source_commit_sha", "", "the git commit SHA for the source URL (resolved from tags/releases, optional)")
 	fs.BoolVar(&cfg.IsLatestVersion, "is_latest_version", false, "if true, marks this module version as the latest one")
+	fs.Var(&cfg.ResolutionConflicts, "resolution_conflict", "dependency path requiring a conflicting compatibility level, as 'a@1.0 -> b@2.0 (compatibility_level=2)' (repeatable)")
+	fs.Var(&cfg.PatchIntegrity, "patch_integrity", "verification result of a patch file, as 'FILENAME=STATUS' or 'FILENAME=STATUS ACTUAL_INTEGRITY' (repeatable)")
+	fs.Var(&cfg.OverlayIntegrity, "overlay_integrity", "verification result of an overlay file, as 'FILENAME=STATUS' or 'FILENAME=STATUS ACTUAL_INTEGRITY' (repeatable)")
+	fs.StringVar(&cfg.ArchiveVerificationStatus, "archive_verification_status", "", "verification status of the source archive, an ArchiveVerificationStatus name (optional)")
+	fs.StringVar(&cfg.ArchiveActualIntegrity, "archive_actual_integrity", "", "integrity computed from the source archive (optional)")
+	fs.StringVar(&cfg.ArchiveVerificationMessage, "archive_verification_message", "", "details of the source archive verification (optional)")
+	fs.Var(&cfg.AttestationVerification, "attestation_verification", "verification status of an attestation bundle, as 'FILENAME=STATUS' (repeatable)")
+	fs.Var(&cfg.AttestationMessages, "attestation_verification_message", "details of the verification of an attestation bundle, as 'FILENAME=MESSAGE' (repeatable)")
+	fs.StringVar(&cfg.ProvenancePredicateType, "provenance_predicate_type", "", "SLSA provenance predicate type of the verified attestations (optional)")
+	fs.StringVar(&cfg.ProvenanceBuilderId, "provenance_builder_id", "", "builder of the release, from the SLSA provenance (optional)")
+	fs.StringVar(&cfg.ProvenanceSourceRepository, "provenance_source_repository", "", "repository the release was built from, from the SLSA provenance (optional)")
+	fs.StringVar(&cfg.ProvenanceSourceRef, "provenance_source_ref", "", "git ref the release was built from, from the SLSA provenance (optional)")
+	fs.StringVar(&cfg.ProvenanceSourceCommit, "provenance_source_commit", "", "git commit the release was built from, from the SLSA provenance (optional)")
+	fs.StringVar(&cfg.ProvenanceWorkflowPath, "provenance_workflow_path", "", "path of the workflow that built the release, from the SLSA provenance (optional)")
+	fs.StringVar(&cfg.ProvenanceBuildInvocationId, "provenance_build_invocation_id", "", "identifier of the build run, from the SLSA provenance (optional)")
+	fs.Var(&cfg.ProvenanceAttestedFiles, "provenance_attested_file", "file whose verified attestation carries the SLSA provenance (repeatable)")
+	fs.Var(&cfg.MvsDevUpgrades, "mvs_dev_upgrade", "module upgraded only by dev dependencies, as 'rules_cc@0.1.0 -> rules_cc@0.2.0' (repeatable)")
+	fs.StringVar(&cfg.CompatibleBazelMinVersion, "compatible_bazel_min_version", "", "lowest known Bazel release compatible with the MVS closure (optional)")
+	fs.StringVar(&cfg.CompatibleBazelMaxVersion, "compatible_bazel_max_version", "", "highest known Bazel release compatible with the MVS closure (optional)")
+	fs.IntVar(&cfg.CompatibleBazelVersionCount, "compatible_bazel_version_count", -1, "number of known Bazel releases compatible with the MVS closure (-1 if not evaluated)")
+	fs.Var(&cfg.BazelCompatibilityNarrowedBy, "bazel_compatibility_narrowed_by", "dependency narrowing the compatible Bazel releases, as 'rules_cc@0.1.0 >=7.0.0 -7.1.0' (repeatable)")
 
 	fs.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s @PARAMS_FILE", toolName)
@@ -206,3 +307,167 @@ func parsePullRequestFromCommitMessage(message string) string {
 	}
 	return ""
 }
+
+var resolutionConflictRegex = regexp.MustCompile(`^(.+) \(compatibility_level=(-?\d+)\)$`)
+
+// parseResolutionConflicts builds a ResolutionError from a list of conflict
+// paths, as produced by gazelle for the module_version
+// "resolution_conflicts" attribute.
+// Example: "a@1.0 -> b@2.0 -> c@2.0 (compatibility_level=2)"
+func parseResolutionConflicts(conflicts []string) (*bzpb.ResolutionError, error) {
+	result := &bzpb.ResolutionError{}
+	byModuleName := make(map[string]*bzpb.CompatibilityLevelConflict)
+
+	for _, conflict := range conflicts {
+		matches := resolutionConflictRegex.FindStringSubmatch(conflict)
+		if matches == nil {
+			return nil, fmt.Errorf("malformed resolution conflict: %q", conflict)
+		}
+		level, err := strconv.ParseInt(matches[2], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("malformed compatibility level in %q: %v", conflict, err)
+		}
+		path := strings.Split(matches[1], " -> ")
+		name, version, ok := strings.Cut(path[len(path)-1], "@")
+		if !ok {
+			return nil, fmt.Errorf("malformed module id in %q", conflict)
+		}
+
+		c, exists := byModuleName[name]
+		if !exists {
+			c = &bzpb.CompatibilityLevelConflict{ModuleName: name}
+			byModuleName[name] = c
+			result.Conflicts = append(result.Conflicts, c)
+		}
+		c.Requirements = append(c.Requirements, &bzpb.CompatibilityLevelRequirement{
+			CompatibilityLevel: int32(level),
+			Version:            version,
+			Path:               path,
+		})
+	}
+
+	var messages []string
+	for _, c := range result.Conflicts {
+		var reqs []string
+		for _, req := range c.Requirements {
+			reqs = append(reqs, fmt.Sprintf("%s (compatibility level %d)", strings.Join(req.Path, " -> "), req.CompatibilityLevel))
+		}
+		messages = append(messages, fmt.Sprintf("%s is required at different compatibility levels: %s", c.ModuleName, strings.Join(reqs, ", ")))
+	}
+	result.Message = strings.Join(messages, "; ")
+
+	return result, nil
+}
+
+// parseBazelCompatibilityRange builds a BazelCompatibilityRange from the
+// values computed by gazelle for the module_version "compatible_bazel_*" and
+// "bazel_compatibility_narrowed_by" attributes.
+// Example narrowing: "rules_cc@0.1.0 >=7.0.0 -7.1.0"
+func parseBazelCompatibilityRange(minVersion, maxVersion string, count int, narrowedBy []string) (*bzpb.BazelCompatibilityRange, error) {
+	result := &bzpb.BazelCompatibilityRange{
+		MinVersion:   minVersion,
+		MaxVersion:   maxVersion,
+		VersionCount: int32(count),
+	}
+
+	for _, narrowing := range narrowedBy {
+		fields := strings.Fields(narrowing)
+		if len(fields) == 0 || !strings.Contains(fields[0], "@") {
+			return nil, fmt.Errorf("malformed bazel compatibility narrowing: %q", narrowing)
+		}
+		result.NarrowedBy = append(result.NarrowedBy, &bzpb.BazelCompatibilityNarrowing{
+			Module:             fields[0],
+			BazelCompatibility: fields[1:],
+		})
+	}
+
+	return result, nil
+}
+
+// parseDevDependencyUpgrades builds the list of DevDependencyUpgrade from the
+// values computed by gazelle for the module_version "mvs_dev_upgrades"
+// attribute.
+// Example: "rules_cc@0.1.0 -> rules_cc@0.2.0"
+func parseDevDependencyUpgrades(upgrades []string) ([]*bzpb.DevDependencyUpgrade, error) {
+	var result []*bzpb.DevDependencyUpgrade
+	for _, upgrade := range upgrades {
+		from, to, ok := strings.Cut(upgrade, " -> ")
+		if !ok {
+			return nil, fmt.Errorf("malformed dev dependency upgrade: %q", upgrade)
+		}
+		name, version, ok := strings.Cut(from, "@")
+		if !ok {
+			return nil, fmt.Errorf("malformed module id in %q", upgrade)
+		}
+		devName, devVersion, ok := strings.Cut(to, "@")
+		if !ok || devName != name {
+			return nil, fmt.Errorf("malformed module id in %q", upgrade)
+		}
+		result = append(result, &bzpb.DevDependencyUpgrade{
+			ModuleName: name,
+			Version:    version,
+			DevVersion: devVersion,
+		})
+	}
+	return result, nil
+}
+
+// parseFileIntegrity builds the verification results of the patch or overlay
+// files from the values computed by gazelle for the module_source
+// "patch_integrity" and "overlay_integrity" attributes.
+// Example: "fix.patch=FILE_INTEGRITY_MISMATCH sha256-..."
+func parseFileIntegrity(values []string) (map[string]*bzpb.FileIntegrity, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]*bzpb.FileIntegrity, len(values))
+	for _, value := range values {
+		filename, verification, ok := strings.Cut(value, "=")
+		fields := strings.Fields(verification)
+		if !ok || len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("malformed file integrity: %q", value)
+		}
+		status, ok := bzpb.FileIntegrityStatus_value[fields[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown file integrity status in %q", value)
+		}
+		integrity := &bzpb.FileIntegrity{Status: bzpb.FileIntegrityStatus(status)}
+		if len(fields) == 2 {
+			integrity.ActualIntegrity = fields[1]
+		}
+		result[filename] = integrity
+	}
+	return result, nil
+}
+
+// parseAttestationVerification records the verification results computed by
+// gazelle for the module_attestations "verification_status" and
+// "verification_message" attributes on the attestations.
+// Example: "source.json=ATTESTATION_VERIFIED"
+func parseAttestationVerification(attestations *bzpb.Attestations, statuses, messages []string) error {
+	for _, value := range statuses {
+		filename, name, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("malformed attestation verification: %q", value)
+		}
+		status, ok := bzpb.AttestationVerificationStatus_value[name]
+		if !ok {
+			return fmt.Errorf("unknown attestation verification status in %q", value)
+		}
+		att, ok := attestations.Attestations[filename]
+		if !ok {
+			return fmt.Errorf("attestation verification of unknown file in %q", value)
+		}
+		att.Verification = &bzpb.AttestationVerification{Status: bzpb.AttestationVerificationStatus(status)}
+	}
+	for _, value := range messages {
+		filename, message, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("malformed attestation verification message: %q", value)
+		}
+		if verification := attestations.Attestations[filename].GetVerification(); verification != nil {
+			verification.Message = message
+		}
+	}
+	return nil
+}
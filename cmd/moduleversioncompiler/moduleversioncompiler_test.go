@@ -0,0 +1,156 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestParseResolutionConflicts(t *testing.T) {
+	got, err := parseResolutionConflicts([]string{
+		"a@1.0 -> b@1.0 -> d@1.0 (compatibility_level=1)",
+		"a@1.0 -> c@1.0 -> d@2.0 (compatibility_level=2)",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %d", len(got.Conflicts))
+	}
+	conflict := got.Conflicts[0]
+	if conflict.ModuleName != "d" {
+		t.Errorf("module name = %q, want %q", conflict.ModuleName, "d")
+	}
+	if len(conflict.Requirements) != 2 {
+		t.Fatalf("expected 2 requirements, got %d", len(conflict.Requirements))
+	}
+	req := conflict.Requirements[1]
+	if req.CompatibilityLevel != 2 || req.Version != "2.0" {
+		t.Errorf("requirement = level %d version %q, want level 2 version %q", req.CompatibilityLevel, req.Version, "2.0")
+	}
+	if want := []string{"a@1.0", "c@1.0", "d@2.0"}; !slices.Equal(req.Path, want) {
+		t.Errorf("path = %v, want %v", req.Path, want)
+	}
+	if want := "d is required at different compatibility levels: a@1.0 -> b@1.0 -> d@1.0 (compatibility level 1), a@1.0 -> c@1.0 -> d@2.0 (compatibility level 2)"; got.Message != want {
+		t.Errorf("message = %q, want %q", got.Message, want)
+	}
+}
+
+func TestParseResolutionConflictsMalformed(t *testing.T) {
+	for _, conflict := range []string{
+		"a@1.0 -> d@1.0",
+		"a@1.0 -> d (compatibility_level=1)",
+	} {
+		if _, err := parseResolutionConflicts([]string{conflict}); err == nil {
+			t.Errorf("expected error for %q", conflict)
+		}
+	}
+}
+
+func TestParseBazelCompatibilityRange(t *testing.T) {
+	got, err := parseBazelCompatibilityRange("7.0.0", "7.4.1", 5, []string{
+		"rules_cc@0.1.0 >=7.0.0 -7.1.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MinVersion != "7.0.0" || got.MaxVersion != "7.4.1" || got.VersionCount != 5 {
+		t.Errorf("range = %s..%s (%d), want 7.0.0..7.4.1 (5)", got.MinVersion, got.MaxVersion, got.VersionCount)
+	}
+	if len(got.NarrowedBy) != 1 {
+		t.Fatalf("expected 1 narrowing, got %d", len(got.NarrowedBy))
+	}
+	if got.NarrowedBy[0].Module != "rules_cc@0.1.0" {
+		t.Errorf("module = %q, want %q", got.NarrowedBy[0].Module, "rules_cc@0.1.0")
+	}
+	if want := []string{">=7.0.0", "-7.1.0"}; !slices.Equal(got.NarrowedBy[0].BazelCompatibility, want) {
+		t.Errorf("bazel_compatibility = %v, want %v", got.NarrowedBy[0].BazelCompatibility, want)
+	}
+
+	if _, err := parseBazelCompatibilityRange("", "", 0, []string{">=7.0.0"}); err == nil {
+		t.Error("expected error for narrowing without module")
+	}
+}
+
+func TestParseDevDependencyUpgrades(t *testing.T) {
+	got, err := parseDevDependencyUpgrades([]string{"rules_cc@0.1.0 -> rules_cc@0.2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 upgrade, got %d", len(got))
+	}
+	if got[0].ModuleName != "rules_cc" || got[0].Version != "0.1.0" || got[0].DevVersion != "0.2.0" {
+		t.Errorf("upgrade = %v, want rules_cc 0.1.0 -> 0.2.0", got[0])
+	}
+
+	for _, upgrade := range []string{
+		"rules_cc@0.1.0",
+		"rules_cc -> rules_cc@0.2.0",
+		"rules_cc@0.1.0 -> platforms@0.2.0",
+	} {
+		if _, err := parseDevDependencyUpgrades([]string{upgrade}); err == nil {
+			t.Errorf("expected error for %q", upgrade)
+		}
+	}
+}
+
+func TestParseFileIntegrity(t *testing.T) {
+	got, err := parseFileIntegrity([]string{
+		"fix.patch=FILE_INTEGRITY_VERIFIED",
+		"other.patch=FILE_INTEGRITY_MISMATCH sha256-LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["fix.patch"].GetStatus() != bzpb.FileIntegrityStatus_FILE_INTEGRITY_VERIFIED {
+		t.Errorf("fix.patch = %v", got["fix.patch"])
+	}
+	other := got["other.patch"]
+	if other.GetStatus() != bzpb.FileIntegrityStatus_FILE_INTEGRITY_MISMATCH || other.GetActualIntegrity() != "sha256-LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=" {
+		t.Errorf("other.patch = %v", other)
+	}
+
+	for _, value := range []string{
+		"fix.patch",
+		"fix.patch=",
+		"fix.patch=VERIFIED",
+	} {
+		if _, err := parseFileIntegrity([]string{value}); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
+
+func TestParseAttestationVerification(t *testing.T) {
+	attestations := &bzpb.Attestations{
+		Attestations: map[string]*bzpb.Attestations_Attestation{
+			"source.json":  {},
+			"MODULE.bazel": {},
+		},
+	}
+	err := parseAttestationVerification(attestations,
+		[]string{"source.json=ATTESTATION_VERIFIED", "MODULE.bazel=ATTESTATION_SUBJECT_MISMATCH"},
+		[]string{"MODULE.bazel=the statement does not attest the digest of the file (subjects: a=b)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := attestations.Attestations["source.json"].GetVerification(); got.GetStatus() != bzpb.AttestationVerificationStatus_ATTESTATION_VERIFIED || got.GetMessage() != "" {
+		t.Errorf("source.json = %v", got)
+	}
+	if got := attestations.Attestations["MODULE.bazel"].GetVerification(); got.GetStatus() != bzpb.AttestationVerificationStatus_ATTESTATION_SUBJECT_MISMATCH ||
+		got.GetMessage() != "the statement does not attest the digest of the file (subjects: a=b)" {
+		t.Errorf("MODULE.bazel = %v", got)
+	}
+
+	for _, value := range []string{
+		"source.json",
+		"source.json=VERIFIED",
+		"other.json=ATTESTATION_VERIFIED",
+	} {
+		if err := parseAttestationVerification(attestations, []string{value}, nil); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+// Names and maximum scores of the module health components.  The components
+// add up to 100.  See "Module Health Score" in the README.
+const (
+	healthDocumentation = "documentation"
+	healthAttestations  = "attestations"
+	healthPresubmit     = "presubmit"
+	healthRecency       = "recency"
+	healthYanks         = "yanks"
+	healthRepository    = "repository"
+
+	maxDocumentationScore = 20
+	maxAttestationsScore  = 15
+	maxPresubmitScore     = 20
+	maxRecencyScore       = 20
+	maxYanksScore         = 15
+	maxRepositoryScore    = 10
+)
+
+// commitDateLayouts are the accepted formats of commit dates: git log
+// --format=%cI (module versions) and --format=%ci (--commit_date).
+var commitDateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05 -0700"}
+
+// parseCommitDate parses a commit date in one of the commitDateLayouts.
+func parseCommitDate(date string) (time.Time, error) {
+	for _, layout := range commitDateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid commit date: %q", date)
+}
+
+// healthReferenceDate returns the date recency is measured against, so that
+// the score only depends on the registry data: the registry commit date, or
+// if unknown, the most recent commit of any module version.
+func healthReferenceDate(commitDate string, modules []*bzpb.Module) (ref time.Time) {
+	if t, err := parseCommitDate(commitDate); err == nil {
+		return t
+	}
+	for _, module := range modules {
+		for _, mv := range module.Versions {
+			if mv.Commit == nil {
+				continue
+			}
+			if t, err := parseCommitDate(mv.Commit.Date); err == nil && t.After(ref) {
+				ref = t
+			}
+		}
+	}
+	return
+}
+
+// computeModuleHealth scores the module.  Except for yanks, the components
+// are computed from the latest version (module.Versions is sorted latest
+// first).
+func computeModuleHealth(module *bzpb.Module, ref time.Time) *bzpb.ModuleHealth {
+	health := &bzpb.ModuleHealth{}
+	if !ref.IsZero() {
+		health.ReferenceDate = ref.UTC().Format(time.RFC3339)
+	}
+
+	var latest *bzpb.ModuleVersion
+	if len(module.Versions) > 0 {
+		latest = module.Versions[0]
+	} else {
+		latest = &bzpb.ModuleVersion{}
+	}
+
+	health.Components = []*bzpb.ModuleHealthComponent{
+		documentationHealth(latest),
+		attestationsHealth(latest),
+		presubmitHealth(latest),
+		recencyHealth(latest, ref),
+		yanksHealth(module, latest),
+		repositoryHealth(module.RepositoryMetadata, ref),
+	}
+	for _, c := range health.Components {
+		health.Score += c.Score
+	}
+
+	return health
+}
+
+func newHealthComponent(name string, score, maxScore int32, format string, args ...any) *bzpb.ModuleHealthComponent {
+	return &bzpb.ModuleHealthComponent{
+		Name:     name,
+		Score:    score,
+		MaxScore: maxScore,
+		Reason:   fmt.Sprintf(format, args...),
+	}
+}
+
+// documentationHealth awards full points for generated API documentation or
+// a reachable docs_url, and half of them for a docs_url that was not checked.
+func documentationHealth(latest *bzpb.ModuleVersion) *bzpb.ModuleHealthComponent {
+	source := latest.Source
+	switch {
+	case source == nil:
+		return newHealthComponent(healthDocumentation, 0, maxDocumentationScore, "latest version has no source")
+	case source.Documentation != nil && len(source.Documentation.File) > 0:
+		return newHealthComponent(healthDocumentation, maxDocumentationScore, maxDocumentationScore, "latest version has API documentation")
+	case source.DocsUrl == "":
+		return newHealthComponent(healthDocumentation, 0, maxDocumentationScore, "latest version has no documentation")
+	case source.DocsUrlStatus == nil:
+		return newHealthComponent(healthDocumentation, maxDocumentationScore/2, maxDocumentationScore, "docs_url was not checked")
+	case source.DocsUrlStatus.Code == 200:
+		return newHealthComponent(healthDocumentation, maxDocumentationScore, maxDocumentationScore, "docs_url is reachable")
+	default:
+		return newHealthComponent(healthDocumentation, 0, maxDocumentationScore, "docs_url returned HTTP %d", source.DocsUrlStatus.Code)
+	}
+}
+
+// attestationsHealth awards full points if the latest version has
+// attestations.
+func attestationsHealth(latest *bzpb.ModuleVersion) *bzpb.ModuleHealthComponent {
+	if n := len(latest.Attestations.GetAttestations()); n > 0 {
+		return newHealthComponent(healthAttestations, maxAttestationsScore, maxAttestationsScore, "latest version has %d attestations", n)
+	}
+	return newHealthComponent(healthAttestations, 0, maxAttestationsScore, "latest version has no attestations")
+}
+
+// presubmitHealth awards half of the points for testing on up to three
+// platforms and the other half for testing with up to two Bazel versions.
+func presubmitHealth(latest *bzpb.ModuleVersion) *bzpb.ModuleHealthComponent {
+	if latest.Presubmit == nil {
+		return newHealthComponent(healthPresubmit, 0, maxPresubmitScore, "latest version has no presubmit.yml")
+	}
+	platforms, bazelVersions := presubmitCoverage(latest.Presubmit)
+	score := int32(maxPresubmitScore/2*min(len(platforms), 3)/3 + maxPresubmitScore/2*min(len(bazelVersions), 2)/2)
+	return newHealthComponent(healthPresubmit, score, maxPresubmitScore, "tested on %d platforms with %d Bazel versions", len(platforms), len(bazelVersions))
+}
+
+// presubmitCoverage returns the distinct platforms and Bazel versions of the
+// presubmit matrices and tasks.  Task values that refer to the matrix (e.g.
+// ${{ platform }}) are covered by the matrix.
+func presubmitCoverage(presubmit *bzpb.Presubmit) (platforms, bazelVersions map[string]bool) {
+	platforms = make(map[string]bool)
+	bazelVersions = make(map[string]bool)
+
+	addMatrix := func(matrix *bzpb.Presubmit_PresubmitMatrix) {
+		for _, p := range matrix.GetPlatform() {
+			platforms[p] = true
+		}
+		for _, b := range matrix.GetBazel() {
+			bazelVersions[b] = true
+		}
+	}
+	addTasks := func(tasks map[string]*bzpb.Presubmit_PresubmitTask) {
+		for _, task := range tasks {
+			if task.Platform != "" && !strings.Contains(task.Platform, "${{") {
+				platforms[task.Platform] = true
+			}
+			if task.Bazel != "" && !strings.Contains(task.Bazel, "${{") {
+				bazelVersions[task.Bazel] = true
+			}
+		}
+	}
+
+	addMatrix(presubmit.Matrix)
+	addTasks(presubmit.Tasks)
+	if m := presubmit.BcrTestModule; m != nil {
+		addMatrix(m.Matrix)
+		addTasks(m.Tasks)
+	}
+	return
+}
+
+// recencyHealth scores the age of the latest version at the reference date:
+// full points up to 6 months, then 5 points less for each of 1, 2 and 3
+// years.
+func recencyHealth(latest *bzpb.ModuleVersion, ref time.Time) *bzpb.ModuleHealthComponent {
+	if ref.IsZero() || latest.Commit == nil {
+		return newHealthComponent(healthRecency, 0, maxRecencyScore, "latest version has no commit date")
+	}
+	published, err := parseCommitDate(latest.Commit.Date)
+	if err != nil {
+		return newHealthComponent(healthRecency, 0, maxRecencyScore, "%v", err)
+	}
+	days := int(ref.Sub(published).Hours() / 24)
+	var score int32
+	switch {
+	case days <= 180:
+		score = maxRecencyScore
+	case days <= 365:
+		score = 15
+	case days <= 2*365:
+		score = 10
+	case days <= 3*365:
+		score = 5
+	}
+	return newHealthComponent(healthRecency, score, maxRecencyScore, "latest version was published %d days before %s", max(days, 0), ref.UTC().Format(time.DateOnly))
+}
+
+// yanksHealth awards no points to deprecated modules and modules whose latest
+// version is yanked, and otherwise deducts points for the fraction of
+// yanked versions.
+func yanksHealth(module *bzpb.Module, latest *bzpb.ModuleVersion) *bzpb.ModuleHealthComponent {
+	metadata := module.Metadata
+	if metadata.GetDeprecated() != "" {
+		return newHealthComponent(healthYanks, 0, maxYanksScore, "module is deprecated: %s", metadata.Deprecated)
+	}
+	yanked := metadata.GetYankedVersions()
+	if _, ok := yanked[latest.Version]; ok {
+		return newHealthComponent(healthYanks, 0, maxYanksScore, "latest version is yanked")
+	}
+	total := len(module.Versions)
+	if total == 0 {
+		return newHealthComponent(healthYanks, 0, maxYanksScore, "module has no versions")
+	}
+	n := 0
+	for _, mv := range module.Versions {
+		if _, ok := yanked[mv.Version]; ok {
+			n++
+		}
+	}
+	score := int32(maxYanksScore * (total - n) / total)
+	return newHealthComponent(healthYanks, score, maxYanksScore, "%d of %d versions are yanked", n, total)
+}
+
+// repositoryHealth scores the activity of the source repository: no points
+// if it is archived or disabled, full points for a push within a year of the
+// reference date and half of them within two years.
+func repositoryHealth(md *bzpb.RepositoryMetadata, ref time.Time) *bzpb.ModuleHealthComponent {
+	switch {
+	case md == nil:
+		return newHealthComponent(healthRepository, 0, maxRepositoryScore, "no repository metadata")
+	case md.Archived:
+		return newHealthComponent(healthRepository, 0, maxRepositoryScore, "repository is archived")
+	case md.Disabled:
+		return newHealthComponent(healthRepository, 0, maxRepositoryScore, "repository is disabled")
+	case md.PushedAt == "" || ref.IsZero():
+		return newHealthComponent(healthRepository, 0, maxRepositoryScore, "repository activity is unknown")
+	}
+	pushed, err := time.Parse(time.RFC3339, md.PushedAt)
+	if err != nil {
+		return newHealthComponent(healthRepository, 0, maxRepositoryScore, "invalid pushed_at: %q", md.PushedAt)
+	}
+	days := int(ref.Sub(pushed).Hours() / 24)
+	var score int32
+	switch {
+	case days <= 365:
+		score = maxRepositoryScore
+	case days <= 2*365:
+		score = maxRepositoryScore / 2
+	}
+	return newHealthComponent(healthRepository, score, maxRepositoryScore, "last push %d days before %s", max(days, 0), ref.UTC().Format(time.DateOnly))
+}
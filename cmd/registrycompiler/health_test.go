@@ -0,0 +1,165 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+var healthRef = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func componentScores(health *bzpb.ModuleHealth) map[string]int32 {
+	scores := make(map[string]int32)
+	for _, c := range health.Components {
+		scores[c.Name] = c.Score
+	}
+	return scores
+}
+
+func TestComputeModuleHealth(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		module    *bzpb.Module
+		wantScore int32
+		want      map[string]int32
+	}{
+		{
+			name:      "empty",
+			module:    &bzpb.Module{Name: "empty"},
+			wantScore: 0,
+			want: map[string]int32{
+				healthDocumentation: 0,
+				healthAttestations:  0,
+				healthPresubmit:     0,
+				healthRecency:       0,
+				healthYanks:         0,
+				healthRepository:    0,
+			},
+		},
+		{
+			name: "healthy",
+			module: &bzpb.Module{
+				Name:     "rules_foo",
+				Metadata: &bzpb.ModuleMetadata{},
+				Versions: []*bzpb.ModuleVersion{{
+					Version:      "1.0.0",
+					Commit:       &bzpb.ModuleCommit{Date: "2025-05-01T12:00:00+02:00"},
+					Source:       &bzpb.ModuleSource{DocsUrl: "https://example.com", DocsUrlStatus: &bzpb.ResourceStatus{Code: 200}},
+					Attestations: &bzpb.Attestations{Attestations: map[string]*bzpb.Attestations_Attestation{"source.json": {}}},
+					Presubmit: &bzpb.Presubmit{
+						Matrix: &bzpb.Presubmit_PresubmitMatrix{
+							Platform: []string{"debian11", "macos", "windows"},
+							Bazel:    []string{"7.x", "8.x"},
+						},
+					},
+				}},
+				RepositoryMetadata: &bzpb.RepositoryMetadata{PushedAt: "2025-05-30T00:00:00Z"},
+			},
+			wantScore: 100,
+		},
+		{
+			name: "stale",
+			module: &bzpb.Module{
+				Name: "rules_bar",
+				Metadata: &bzpb.ModuleMetadata{
+					YankedVersions: map[string]string{"0.1.0": "broken"},
+				},
+				Versions: []*bzpb.ModuleVersion{
+					{
+						Version: "0.2.0",
+						Commit:  &bzpb.ModuleCommit{Date: "2024-01-01T00:00:00Z"},
+						Source:  &bzpb.ModuleSource{DocsUrl: "https://example.com"},
+						Presubmit: &bzpb.Presubmit{
+							BcrTestModule: &bzpb.Presubmit_BcrTestModule{
+								Matrix: &bzpb.Presubmit_PresubmitMatrix{Platform: []string{"debian11"}},
+								Tasks: map[string]*bzpb.Presubmit_PresubmitTask{
+									"run_tests": {Platform: "${{ platform }}", Bazel: "7.4.0"},
+								},
+							},
+						},
+					},
+					{Version: "0.1.0"},
+				},
+				RepositoryMetadata: &bzpb.RepositoryMetadata{Archived: true},
+			},
+			wantScore: 35,
+			want: map[string]int32{
+				healthDocumentation: 10,
+				healthAttestations:  0,
+				healthPresubmit:     8,
+				healthRecency:       10,
+				healthYanks:         7,
+				healthRepository:    0,
+			},
+		},
+		{
+			name: "deprecated",
+			module: &bzpb.Module{
+				Name:     "rules_old",
+				Metadata: &bzpb.ModuleMetadata{Deprecated: "use rules_new"},
+				Versions: []*bzpb.ModuleVersion{{
+					Version: "1.0.0",
+					Source:  &bzpb.ModuleSource{DocsUrl: "https://example.com", DocsUrlStatus: &bzpb.ResourceStatus{Code: 404}},
+				}},
+				RepositoryMetadata: &bzpb.RepositoryMetadata{PushedAt: "2023-12-01T00:00:00Z"},
+			},
+			wantScore: 5,
+			want: map[string]int32{
+				healthDocumentation: 0,
+				healthYanks:         0,
+				healthRepository:    5,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := computeModuleHealth(tc.module, healthRef)
+			if got.Score != tc.wantScore {
+				t.Errorf("score = %d, want %d (%v)", got.Score, tc.wantScore, got.Components)
+			}
+			if got.ReferenceDate != "2025-06-01T00:00:00Z" {
+				t.Errorf("reference date = %q", got.ReferenceDate)
+			}
+			scores := componentScores(got)
+			for name, want := range tc.want {
+				if scores[name] != want {
+					t.Errorf("%s = %d, want %d", name, scores[name], want)
+				}
+			}
+			var total int32
+			for _, c := range got.Components {
+				total += c.MaxScore
+			}
+			if total != 100 {
+				t.Errorf("max scores add up to %d, want 100", total)
+			}
+		})
+	}
+}
+
+func TestHealthReferenceDate(t *testing.T) {
+	modules := []*bzpb.Module{
+		{Versions: []*bzpb.ModuleVersion{
+			{Commit: &bzpb.ModuleCommit{Date: "2025-03-01T00:00:00Z"}},
+			{Commit: &bzpb.ModuleCommit{Date: "invalid"}},
+			{},
+		}},
+		{Versions: []*bzpb.ModuleVersion{
+			{Commit: &bzpb.ModuleCommit{Date: "2025-04-01T00:00:00Z"}},
+		}},
+	}
+	for _, tc := range []struct {
+		commitDate string
+		want       time.Time
+	}{
+		{"2025-06-01 12:00:00 +0000", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{"2025-06-01T12:00:00Z", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{"", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(tc.commitDate, func(t *testing.T) {
+			if got := healthReferenceDate(tc.commitDate, modules); !got.Equal(tc.want) {
+				t.Errorf("healthReferenceDate(%q) = %v, want %v", tc.commitDate, got, tc.want)
+			}
+		})
+	}
+}
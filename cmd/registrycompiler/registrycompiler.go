@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
@@ -12,6 +13,7 @@ import (
 	"github.com/bazel-contrib/bcr-frontend/pkg/gh"
 	"github.com/bazel-contrib/bcr-frontend/pkg/paramsfile"
 	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+	"github.com/bazel-contrib/bcr-frontend/pkg/versionutil"
 )
 
 const toolName = "registrycompiler"
@@ -26,6 +28,7 @@ type Config struct {
 	Branch                    string
 	Commit                    string
 	CommitDate                string
+	RegistryPrecedence        paramsfile.StringSlice
 }
 
 func main() {
@@ -64,16 +67,24 @@ func run(args []string) error {
 
 	moduleVersionsById := make(map[string]*bzpb.ModuleVersion)
 
+	var modules []*bzpb.Module
 	for _, file := range cfg.ModuleFiles {
 		var module bzpb.Module
 		if err := protoutil.ReadFile(file, &module); err != nil {
 			return fmt.Errorf("reading %s: %v", file, err)
 		}
+		modules = append(modules, &module)
+	}
+	if len(cfg.RegistryPrecedence) > 0 {
+		modules = mergeModules(modules, cfg.RegistryPrecedence)
+	}
+
+	for _, module := range modules {
 		for _, mv := range module.Versions {
 			id := fmt.Sprintf("%s@%s", mv.Name, mv.Version)
 			moduleVersionsById[id] = mv
 		}
-		registry.Modules = append(registry.Modules, &module)
+		registry.Modules = append(registry.Modules, module)
 	}
 
 	if cfg.ModuleRegistrySymbolsFile != "" {
@@ -88,11 +99,17 @@ func run(args []string) error {
 					mv.Source.Documentation = d
 				}
 			} else {
-				log.Panicf("module version not found!", mv)
+				log.Panicf("module version not found: %s", id)
 			}
 		}
 	}
 
+	// Score the modules once the documentation is attached
+	ref := healthReferenceDate(cfg.CommitDate, modules)
+	for _, module := range modules {
+		module.Health = computeModuleHealth(module, ref)
+	}
+
 	// Write the compiled ModuleVersion to output file
 	if err := protoutil.WriteFile(cfg.OutputFile, &registry); err != nil {
 		return fmt.Errorf("failed to write output file: %v", err)
@@ -111,6 +128,7 @@ func parseFlags(args []string) (cfg Config, err error) {
 	fs.StringVar(&cfg.Branch, "branch", "", "branch name of the repository data (e.g. 'main')")
 	fs.StringVar(&cfg.Commit, "commit", "", "commit sha1 of the repository data")
 	fs.StringVar(&cfg.CommitDate, "commit_date", "", "timestamp of the commit date (ISO 8601 format)")
+	fs.Var(&cfg.RegistryPrecedence, "registry_precedence", "name of a combined registry, in order of precedence (repeatable)")
 	fs.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s @PARAMS_FILE", toolName)
 		fs.PrintDefaults()
@@ -125,6 +143,59 @@ func parseFlags(args []string) (cfg Config, err error) {
 	return
 }
 
+// mergeModules combines modules of the same name provided by several
+// registries.  Like bazel's --registry, the first registry (in the given
+// order of precedence) that provides a module version wins: the module is
+// attributed to the registry with the highest precedence, and versions only
+// found in other registries are added to it.  Modules of unknown registries
+// come last.
+func mergeModules(modules []*bzpb.Module, precedence []string) []*bzpb.Module {
+	rank := func(m *bzpb.Module) int {
+		if i := slices.Index(precedence, m.Registry); i >= 0 {
+			return i
+		}
+		return len(precedence)
+	}
+	modules = slices.Clone(modules)
+	slices.SortStableFunc(modules, func(a, b *bzpb.Module) int {
+		return rank(a) - rank(b)
+	})
+
+	var merged []*bzpb.Module
+	byName := make(map[string]*bzpb.Module)
+	for _, module := range modules {
+		existing, ok := byName[module.Name]
+		if !ok {
+			byName[module.Name] = module
+			merged = append(merged, module)
+			continue
+		}
+		for _, mv := range module.Versions {
+			if slices.ContainsFunc(existing.Versions, func(v *bzpb.ModuleVersion) bool {
+				return v.Version == mv.Version
+			}) {
+				continue
+			}
+			existing.Versions = append(existing.Versions, mv)
+			if existing.Metadata != nil && !slices.Contains(existing.Metadata.Versions, mv.Version) {
+				existing.Metadata.Versions = append(existing.Metadata.Versions, mv.Version)
+			}
+		}
+	}
+
+	// the latest version may now come from another registry
+	for _, module := range merged {
+		slices.SortStableFunc(module.Versions, func(a, b *bzpb.ModuleVersion) int {
+			return versionutil.Compare(b.Version, a.Version)
+		})
+		for i, mv := range module.Versions {
+			mv.IsLatestVersion = i == 0
+		}
+	}
+
+	return merged
+}
+
 // // enrichWithGitHubData fetches GitHub repository metadata and populates it into the registry
 // func enrichWithGitHubData(token string, registry *bzpb.Registry, maxCount int) error {
 // 	ctx := context.Background()
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func makeModule(registry, name string, versions ...string) *bzpb.Module {
+	module := &bzpb.Module{
+		Name:     name,
+		Registry: registry,
+		Metadata: &bzpb.ModuleMetadata{Versions: slices.Clone(versions)},
+	}
+	for _, version := range versions {
+		module.Versions = append(module.Versions, &bzpb.ModuleVersion{
+			Name:     name,
+			Version:  version,
+			Registry: registry,
+		})
+	}
+	return module
+}
+
+func TestMergeModules(t *testing.T) {
+	got := mergeModules([]*bzpb.Module{
+		makeModule("bcr", "rules_foo", "1.0.0", "1.1.0"),
+		makeModule("bcr", "rules_bar", "0.1.0"),
+		makeModule("internal", "rules_foo", "1.1.0", "1.2.0"),
+	}, []string{"internal", "bcr"})
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 modules, got %d", len(got))
+	}
+
+	foo := got[0]
+	if foo.Name != "rules_foo" || foo.Registry != "internal" {
+		t.Fatalf("first module = %s from %s, want rules_foo from internal", foo.Name, foo.Registry)
+	}
+	var versions []string
+	for _, mv := range foo.Versions {
+		versions = append(versions, mv.Version+"@"+mv.Registry)
+	}
+	if want := []string{"1.2.0@internal", "1.1.0@internal", "1.0.0@bcr"}; !slices.Equal(versions, want) {
+		t.Errorf("versions = %v, want %v", versions, want)
+	}
+	if !foo.Versions[0].IsLatestVersion || foo.Versions[1].IsLatestVersion {
+		t.Errorf("expected only %s to be the latest version", foo.Versions[0].Version)
+	}
+	if want := []string{"1.1.0", "1.2.0", "1.0.0"}; !slices.Equal(foo.Metadata.Versions, want) {
+		t.Errorf("metadata versions = %v, want %v", foo.Metadata.Versions, want)
+	}
+
+	if bar := got[1]; bar.Name != "rules_bar" || bar.Registry != "bcr" || len(bar.Versions) != 1 {
+		t.Errorf("unexpected second module: %v", bar)
+	}
+}
+
+func TestMergeModulesLatestFromLowerPrecedence(t *testing.T) {
+	got := mergeModules([]*bzpb.Module{
+		makeModule("internal", "rules_foo", "1.0.0"),
+		makeModule("bcr", "rules_foo", "2.0.0"),
+	}, []string{"internal", "bcr"})
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 module, got %d", len(got))
+	}
+	latest := got[0].Versions[0]
+	if latest.Version != "2.0.0" || latest.Registry != "bcr" || !latest.IsLatestVersion {
+		t.Errorf("latest = %s from %s (is_latest_version=%v), want 2.0.0 from bcr", latest.Version, latest.Registry, latest.IsLatestVersion)
+	}
+	if got[0].Versions[1].IsLatestVersion {
+		t.Errorf("1.0.0 should no longer be the latest version")
+	}
+}
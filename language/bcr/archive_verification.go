@@ -0,0 +1,132 @@
+package bcr
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"slices"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/archive"
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentArchiveDownloads limits the number of archives downloaded in
+// parallel.
+const maxConcurrentArchiveDownloads = 4
+
+// verifySourceArchives verifies the source archives of the module versions
+// against their integrity and strip_prefix (see --verify-archives).  The
+// results are recorded on the module_source rules; failures are reported as
+// diagnostics.
+func (ext *bcrExtension) verifySourceArchives(ctx context.Context) {
+	if !ext.verifyArchives {
+		return
+	}
+
+	verifier := archive.Verifier{Download: true}
+	if ext.archiveCacheDir != "" {
+		verifier.Cache = &archive.Cache{Dir: os.ExpandEnv(ext.archiveCacheDir)}
+	}
+
+	var ids []moduleID
+	for id, source := range ext.moduleSourceRules {
+		if _, unchanged := ext.unchangedModuleVersions[id]; unchanged {
+			continue
+		}
+		src := source.Proto()
+		if src.Url == "" || src.Type == "git_repository" || ext.blacklistedUrls[src.Url] {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	slices.SortFunc(ids, compareModuleIDs)
+
+	bar := progressbar.NewOptions(len(ids),
+		progressbar.OptionSetDescription("Verifying source archives"),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetWidth(40),
+		progressbar.OptionSetTheme(progressbar.Theme{
+			Saucer:        "=",
+			SaucerHead:    ">",
+			SaucerPadding: " ",
+			BarStart:      "[",
+			BarEnd:        "]",
+		}),
+	)
+
+	results := make([]*bzpb.ArchiveVerification, len(ids))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentArchiveDownloads)
+	for i, id := range ids {
+		v := verifier
+		// modules that skip network access are only verified from the cache
+		v.Download = !ext.skipsNetwork([]moduleID{id})
+		source := ext.moduleSourceRules[id].Proto()
+		g.Go(func() error {
+			results[i] = v.Verify(ctx, source)
+			bar.Add(1)
+			return nil
+		})
+	}
+	g.Wait()
+	bar.Finish()
+
+	counts := make(map[bzpb.ArchiveVerificationStatus]int)
+	for i, id := range ids {
+		result := results[i]
+		counts[result.Status]++
+		updateModuleSourceRuleArchiveVerification(ext.moduleSourceRules[id], result)
+		if result.Status != bzpb.ArchiveVerificationStatus_ARCHIVE_VERIFIED {
+			ext.reportArchiveVerification(id, result)
+		}
+		if ext.offline && result.Status == bzpb.ArchiveVerificationStatus_ARCHIVE_UNAVAILABLE {
+			ext.cacheMisses.add(bzpb.CacheMissKind_CACHE_MISS_SOURCE_ARCHIVE, ext.moduleSourceRules[id].Proto().Url, id)
+		}
+	}
+	log.Printf("Verified %d source archives (%d verified, %d integrity mismatches, %d missing strip_prefix, %d unavailable)", len(ids),
+		counts[bzpb.ArchiveVerificationStatus_ARCHIVE_VERIFIED],
+		counts[bzpb.ArchiveVerificationStatus_ARCHIVE_INTEGRITY_MISMATCH],
+		counts[bzpb.ArchiveVerificationStatus_ARCHIVE_STRIP_PREFIX_MISSING],
+		counts[bzpb.ArchiveVerificationStatus_ARCHIVE_UNAVAILABLE])
+}
+
+// reportArchiveVerification records a diagnostic for a source archive that
+// did not verify.  Archives that are merely unavailable are warnings.
+func (ext *bcrExtension) reportArchiveVerification(id moduleID, result *bzpb.ArchiveVerification) {
+	reg := ext.moduleVersionRegistry(id)
+	if reg == nil {
+		return
+	}
+	severity := bzpb.DiagnosticSeverity_ERROR
+	if result.Status == bzpb.ArchiveVerificationStatus_ARCHIVE_UNAVAILABLE {
+		severity = bzpb.DiagnosticSeverity_WARNING
+	}
+	err := fmt.Errorf("source archive: %v", result.Status)
+	switch {
+	case result.ActualIntegrity != "":
+		err = fmt.Errorf("source archive: integrity mismatch, the archive has integrity %s", result.ActualIntegrity)
+	case result.Message != "":
+		err = fmt.Errorf("source archive: %v: %s", result.Status, result.Message)
+	}
+	rel := path.Join(reg.modulesRoot, string(id.name()), string(id.version()))
+	ext.diagnostics.add(reg.modulesRoot, rel, "source.json", severity, err)
+}
+
+func updateModuleSourceRuleArchiveVerification(source *protoRule[*bzpb.ModuleSource], result *bzpb.ArchiveVerification) {
+	r := source.Rule()
+	r.SetAttr("archive_verification_status", result.Status.String())
+	if result.ActualIntegrity != "" {
+		r.SetAttr("archive_actual_integrity", result.ActualIntegrity)
+	}
+	if result.Message != "" {
+		r.SetAttr("archive_verification_message", result.Message)
+	}
+	source.Proto().ArchiveVerification = result
+}
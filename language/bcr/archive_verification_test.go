@@ -0,0 +1,70 @@
+package bcr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+)
+
+func TestVerifySourceArchives(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	repoRoot := t.TempDir()
+	for _, dir := range []string{"bcr/modules/foo/1.0.0", "bcr/modules/foo/2.0.0"} {
+		if err := os.MkdirAll(filepath.Join(repoRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repoRoot, dir, "MODULE.bazel"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	registries, err := newRegistryLayers([]string{"bcr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	makeSource := func(integrity string) *protoRule[*bzpb.ModuleSource] {
+		return newProtoRule(rule.NewRule(moduleSourceKind, "source"), &bzpb.ModuleSource{
+			Url:       server.URL + "/foo.tar.gz",
+			Integrity: integrity,
+		})
+	}
+	ext := &bcrExtension{
+		repoRoot:       repoRoot,
+		registries:     registries,
+		verifyArchives: true,
+		moduleSourceRules: map[moduleID]*protoRule[*bzpb.ModuleSource]{
+			"foo@1.0.0": makeSource("sha256-LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="),
+			"foo@2.0.0": makeSource("sha256-ShAT7rtQ9yj8YBvdgzsLKHAzPDs+WoFu66kh2VvsbxU="),
+		},
+	}
+	ext.verifySourceArchives(context.Background())
+
+	verified := ext.moduleSourceRules["foo@1.0.0"]
+	if got := verified.Rule().AttrString("archive_verification_status"); got != "ARCHIVE_VERIFIED" {
+		t.Errorf("foo@1.0.0 archive_verification_status = %q", got)
+	}
+	mismatch := ext.moduleSourceRules["foo@2.0.0"]
+	if got := mismatch.Proto().GetArchiveVerification().GetStatus(); got != bzpb.ArchiveVerificationStatus_ARCHIVE_INTEGRITY_MISMATCH {
+		t.Errorf("foo@2.0.0 status = %v", got)
+	}
+	if got := mismatch.Rule().AttrString("archive_actual_integrity"); got != "sha256-LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=" {
+		t.Errorf("foo@2.0.0 archive_actual_integrity = %q", got)
+	}
+
+	if len(ext.diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(ext.diagnostics))
+	}
+	if d := ext.diagnostics[0]; d.File != "bcr/modules/foo/2.0.0/source.json" || d.Severity != bzpb.DiagnosticSeverity_ERROR {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
@@ -0,0 +1,305 @@
+package bcr
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/archive"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sigstore"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+// attestationRef identifies one attestation of a module version.
+type attestationRef struct {
+	id   moduleID
+	name string // the attested file, e.g. "source.json"
+}
+
+// verifyModuleAttestations verifies the sigstore bundles of the
+// attestations.json files against the trusted root (see
+// --verify-attestations).  The signing certificates must be issued to the
+// expected identity (see attestationIdentityPolicy).  The signed statements
+// must attest the files of the module version: source.json and MODULE.bazel
+// are read from the registry, the source archive is identified by its
+// integrity.  The results are recorded on
+// the module_attestations rules, along with the SLSA provenance of the
+// verified statements; failures are reported as diagnostics.
+func (ext *bcrExtension) verifyModuleAttestations(ctx context.Context) {
+	if !ext.verifyAttestations {
+		return
+	}
+
+	verifier := sigstore.Verifier{TrustedRoot: ext.trustedRoot, Download: true}
+	if ext.attestationBundleDir != "" {
+		verifier.Bundles = &archive.Cache{Dir: os.ExpandEnv(ext.attestationBundleDir)}
+	}
+
+	var refs []attestationRef
+	for id, attestations := range ext.moduleAttestationsRules {
+		if _, unchanged := ext.unchangedModuleVersions[id]; unchanged {
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(attestations.Proto().Attestations)) {
+			refs = append(refs, attestationRef{id: id, name: name})
+		}
+	}
+	if len(refs) == 0 {
+		return
+	}
+	slices.SortFunc(refs, func(a, b attestationRef) int {
+		return cmp.Or(compareModuleIDs(a.id, b.id), strings.Compare(a.name, b.name))
+	})
+
+	bar := progressbar.NewOptions(len(refs),
+		progressbar.OptionSetDescription("Verifying attestations"),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetWidth(40),
+		progressbar.OptionSetTheme(progressbar.Theme{
+			Saucer:        "=",
+			SaucerHead:    ">",
+			SaucerPadding: " ",
+			BarStart:      "[",
+			BarEnd:        "]",
+		}),
+	)
+
+	results := make([]*bzpb.AttestationVerification, len(refs))
+	statements := make([]*sigstore.Statement, len(refs))
+	signers := make([]*sigstore.CertificateIdentity, len(refs))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentArchiveDownloads)
+	for i, ref := range refs {
+		v := verifier
+		// modules that skip network access are only verified from the bundle
+		// directory
+		v.Download = !ext.skipsNetwork([]moduleID{ref.id})
+		v.Identity = ext.attestationIdentityPolicy(ref.id.name())
+		att := ext.moduleAttestationsRules[ref.id].Proto().Attestations[ref.name]
+		digest := ext.attestationSubjectDigest(ref)
+		g.Go(func() error {
+			results[i], statements[i], signers[i] = v.Verify(ctx, att, digest)
+			bar.Add(1)
+			return nil
+		})
+	}
+	g.Wait()
+	bar.Finish()
+
+	counts := make(map[bzpb.AttestationVerificationStatus]int)
+	byID := make(map[moduleID]map[string]*bzpb.AttestationVerification)
+	for i, ref := range refs {
+		result := results[i]
+		counts[result.Status]++
+		if byID[ref.id] == nil {
+			byID[ref.id] = make(map[string]*bzpb.AttestationVerification)
+		}
+		byID[ref.id][ref.name] = result
+		if result.Status != bzpb.AttestationVerificationStatus_ATTESTATION_VERIFIED {
+			ext.reportAttestationVerification(ref, result)
+		}
+		if ext.offline && result.Status == bzpb.AttestationVerificationStatus_ATTESTATION_UNAVAILABLE {
+			att := ext.moduleAttestationsRules[ref.id].Proto().Attestations[ref.name]
+			ext.cacheMisses.add(bzpb.CacheMissKind_CACHE_MISS_ATTESTATION_BUNDLE, att.Url, ref.id)
+		}
+	}
+	for id, verifications := range byID {
+		updateModuleAttestationsRuleVerification(ext.moduleAttestationsRules[id], verifications)
+	}
+	ext.recordAttestationProvenance(refs, results, statements, signers)
+	log.Printf("Verified %d attestations (%d verified, %d subject mismatches, %d invalid signatures, %d identity mismatches, %d unchecked identities, %d unavailable)", len(refs),
+		counts[bzpb.AttestationVerificationStatus_ATTESTATION_VERIFIED],
+		counts[bzpb.AttestationVerificationStatus_ATTESTATION_SUBJECT_MISMATCH],
+		counts[bzpb.AttestationVerificationStatus_ATTESTATION_SIGNATURE_INVALID],
+		counts[bzpb.AttestationVerificationStatus_ATTESTATION_IDENTITY_MISMATCH],
+		counts[bzpb.AttestationVerificationStatus_ATTESTATION_IDENTITY_UNCHECKED],
+		counts[bzpb.AttestationVerificationStatus_ATTESTATION_UNAVAILABLE])
+}
+
+// trustedSignerRepositories are the GitHub repositories of the reusable
+// workflows that sign attestations on behalf of the module repositories.
+var trustedSignerRepositories = []string{
+	"bazel-contrib/publish-to-bcr", // .github/workflows/publish.yaml
+	"bazel-contrib/.github",        // .github/workflows/release_ruleset.yaml
+}
+
+// attestationIdentityPolicy returns the expected identity of the signing
+// certificates of the attestations of the module: certificates issued by
+// --attestation-certificate-oidc-issuer to a subject alternative name
+// matching --attestation-certificate-identity-regexp or, by default, to a
+// workflow of publish-to-bcr or of one of the GitHub repositories of the
+// module in metadata.json, run for one of those repositories.  The subject
+// alternative name of a GitHub Actions certificate is the workflow that
+// signed (e.g. https://github.com/bazel-contrib/publish-to-bcr/.github/workflows/publish.yaml@refs/tags/v0.2.2),
+// the repository the run belongs to is the source repository claim.
+// Returns nil if the module has no such repository.
+func (ext *bcrExtension) attestationIdentityPolicy(name moduleName) *sigstore.IdentityPolicy {
+	if ext.attestationIdentity != nil {
+		return &sigstore.IdentityPolicy{Issuer: ext.attestationIssuer, SubjectAlternativeName: ext.attestationIdentity}
+	}
+	metadata, ok := ext.moduleMetadataRules[name]
+	if !ok {
+		return nil
+	}
+	var repositories []string
+	for _, repo := range metadata.Proto().Repository {
+		md, ok := parseRepositoryMetadataFromRepositoryString(repo)
+		if !ok || md.Type != bzpb.RepositoryType_GITHUB {
+			continue
+		}
+		// GitHub owner and repository names are case-insensitive
+		repositories = append(repositories, "(?i:"+regexp.QuoteMeta(md.Organization+"/"+md.Name)+")")
+	}
+	if len(repositories) == 0 {
+		return nil
+	}
+	slices.Sort(repositories)
+	repositories = slices.Compact(repositories)
+	signers := slices.Clone(repositories)
+	for _, repo := range trustedSignerRepositories {
+		signers = append(signers, regexp.QuoteMeta(repo))
+	}
+	return &sigstore.IdentityPolicy{
+		Issuer:                 ext.attestationIssuer,
+		SubjectAlternativeName: regexp.MustCompile(`^https://github\.com/(?:` + strings.Join(signers, "|") + `)/\.github/workflows/`),
+		SourceRepository:       regexp.MustCompile(`^https://github\.com/(?:` + strings.Join(repositories, "|") + `)$`),
+	}
+}
+
+// attestationSubjectDigest returns the sha256 digest of the attested file, or
+// nil if it is not known.  The source archive is matched by the basename of
+// its url (or a mirror url); its digest is taken from the integrity in
+// source.json, or computed from the archive cache.
+func (ext *bcrExtension) attestationSubjectDigest(ref attestationRef) []byte {
+	switch ref.name {
+	case "source.json", "MODULE.bazel":
+		versionDir, _ := ext.moduleAttestationsRules[ref.id].Rule().PrivateAttr(versionDirPrivateAttr).(string)
+		integrity, err := sri.ComputeFile("sha256", filepath.Join(versionDir, ref.name))
+		if err != nil {
+			return nil
+		}
+		return integrity.Digest
+	}
+
+	source, ok := ext.moduleSourceRules[ref.id]
+	if !ok {
+		return nil
+	}
+	src := source.Proto()
+	if !slices.ContainsFunc(append([]string{src.Url}, src.MirrorUrls...), func(url string) bool {
+		return url != "" && path.Base(url) == ref.name
+	}) {
+		return nil
+	}
+	integrity, err := sri.Parse(src.Integrity)
+	if err != nil {
+		return nil
+	}
+	if integrity.Algorithm == "sha256" {
+		return integrity.Digest
+	}
+	if ext.archiveCacheDir == "" {
+		return nil
+	}
+	cache := &archive.Cache{Dir: os.ExpandEnv(ext.archiveCacheDir)}
+	filename, ok := cache.Get(integrity)
+	if !ok {
+		return nil
+	}
+	digest, err := sri.ComputeFile("sha256", filename)
+	if err != nil {
+		return nil
+	}
+	return digest.Digest
+}
+
+// reportAttestationVerification records a diagnostic for an attestation that
+// did not verify.  Bundles that are merely unavailable, and statements whose
+// subject or signer cannot be checked, are warnings.
+func (ext *bcrExtension) reportAttestationVerification(ref attestationRef, result *bzpb.AttestationVerification) {
+	severity := bzpb.DiagnosticSeverity_ERROR
+	switch result.Status {
+	case bzpb.AttestationVerificationStatus_ATTESTATION_UNAVAILABLE, bzpb.AttestationVerificationStatus_ATTESTATION_SUBJECT_UNAVAILABLE, bzpb.AttestationVerificationStatus_ATTESTATION_IDENTITY_UNCHECKED:
+		severity = bzpb.DiagnosticSeverity_WARNING
+	}
+	err := fmt.Errorf("%v", result.Status)
+	if result.Message != "" {
+		err = fmt.Errorf("%v: %s", result.Status, result.Message)
+	}
+	ext.reportAttestation(ref, severity, err)
+}
+
+// reportAttestation records a diagnostic on the attestations.json file.
+func (ext *bcrExtension) reportAttestation(ref attestationRef, severity bzpb.DiagnosticSeverity, err error) {
+	reg := ext.moduleVersionRegistry(ref.id)
+	if reg == nil {
+		return
+	}
+	rel := path.Join(reg.modulesRoot, string(ref.id.name()), string(ref.id.version()))
+	ext.diagnostics.add(reg.modulesRoot, rel, "attestations.json", severity, fmt.Errorf("attestation %s: %w", ref.name, err))
+}
+
+// recordAttestationProvenance extracts the SLSA provenance of the verified
+// statements, and records it on the module_attestations rules, which pass it
+// on to the module version (see rules/module_version.bzl).  The provenance is bound to the signing certificate (see
+// sigstore.VerifyProvenance): a statement whose predicate disagrees with its
+// certificate is reported as an error and not recorded.  The attestations of
+// a release normally share the provenance of the workflow run that published
+// it; a statement with a different provenance is reported (the one of the
+// first attested file is kept).
+func (ext *bcrExtension) recordAttestationProvenance(refs []attestationRef, results []*bzpb.AttestationVerification, statements []*sigstore.Statement, signers []*sigstore.CertificateIdentity) {
+	provenances := make(map[moduleID]*bzpb.SlsaProvenance)
+	for i, ref := range refs {
+		if results[i].Status != bzpb.AttestationVerificationStatus_ATTESTATION_VERIFIED {
+			continue
+		}
+		provenance, err := sigstore.VerifyProvenance(statements[i], signers[i])
+		if errors.Is(err, sigstore.ErrProvenanceMismatch) {
+			ext.reportAttestation(ref, bzpb.DiagnosticSeverity_ERROR, err)
+			continue
+		}
+		if err != nil {
+			ext.reportAttestation(ref, bzpb.DiagnosticSeverity_WARNING, err)
+			continue
+		}
+		if provenance == nil {
+			continue
+		}
+		first, ok := provenances[ref.id]
+		switch {
+		case !ok:
+			provenance.AttestedFiles = []string{ref.name}
+			provenances[ref.id] = provenance
+		case sameProvenance(first, provenance):
+			first.AttestedFiles = append(first.AttestedFiles, ref.name)
+		default:
+			ext.reportAttestation(ref, bzpb.DiagnosticSeverity_WARNING,
+				fmt.Errorf("the provenance (built by %s from %s@%s) differs from the one of %s", provenance.BuilderId, provenance.SourceRepository, provenance.SourceRef, first.AttestedFiles[0]))
+		}
+	}
+
+	for id, provenance := range provenances {
+		updateModuleAttestationsRuleProvenance(ext.moduleAttestationsRules[id], provenance)
+	}
+}
+
+// sameProvenance reports whether the provenances describe the same build,
+// regardless of the files they were read from.
+func sameProvenance(a, b *bzpb.SlsaProvenance) bool {
+	a, b = proto.Clone(a).(*bzpb.SlsaProvenance), proto.Clone(b).(*bzpb.SlsaProvenance)
+	a.AttestedFiles, b.AttestedFiles = nil, nil
+	return proto.Equal(a, b)
+}
@@ -0,0 +1,294 @@
+package bcr
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sigstore"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+)
+
+func TestVerifyModuleAttestations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/source.json.intoto.jsonl" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	repoRoot := t.TempDir()
+	versionDir := filepath.Join(repoRoot, "bcr/modules/foo/1.0.0")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(versionDir, "MODULE.bazel"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	registries, err := newRegistryLayers([]string{"bcr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &bzpb.Attestations{
+		Attestations: map[string]*bzpb.Attestations_Attestation{
+			// sha256 of "{}"
+			"source.json":  {Url: server.URL + "/source.json.intoto.jsonl", Integrity: "sha256-RBNvo1WzZ4oRRq0W9+hknpT7T8If536DEMBg9hyq/4o="},
+			"MODULE.bazel": {Url: server.URL + "/MODULE.bazel.intoto.jsonl", Integrity: "sha256-RBNvo1WzZ4oRRq0W9+hknpT7T8If536DEMBg9hyq/4o="},
+		},
+	}
+	r := makeModuleAttestationsRule(attestations, "attestations.json")
+	r.SetPrivateAttr(versionDirPrivateAttr, versionDir)
+
+	ext := &bcrExtension{
+		repoRoot:           repoRoot,
+		registries:         registries,
+		verifyAttestations: true,
+		moduleAttestationsRules: map[moduleID]*protoRule[*bzpb.Attestations]{
+			"foo@1.0.0": newProtoRule(r, attestations),
+		},
+	}
+	ext.verifyModuleAttestations(context.Background())
+
+	for name, want := range map[string]bzpb.AttestationVerificationStatus{
+		"source.json":  bzpb.AttestationVerificationStatus_ATTESTATION_MALFORMED_BUNDLE,
+		"MODULE.bazel": bzpb.AttestationVerificationStatus_ATTESTATION_UNAVAILABLE,
+	} {
+		if got := attestations.Attestations[name].GetVerification().GetStatus(); got != want {
+			t.Errorf("%s status = %v, want %v", name, got, want)
+		}
+	}
+	if r.Attr("verification_status") == nil || r.Attr("verification_message") == nil {
+		t.Errorf("expected the verification_status and verification_message attributes to be set")
+	}
+
+	if len(ext.diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %d", len(ext.diagnostics))
+	}
+	for _, d := range ext.diagnostics {
+		if d.File != "bcr/modules/foo/1.0.0/attestations.json" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
+
+func TestAttestationIdentityPolicy(t *testing.T) {
+	ext := &bcrExtension{
+		attestationIssuer: sigstore.GitHubActionsIssuer,
+		moduleMetadataRules: map[moduleName]*protoRule[*bzpb.ModuleMetadata]{
+			"foo": newProtoRule(rule.NewRule(moduleMetadataKind, "metadata"), &bzpb.ModuleMetadata{
+				Repository: []string{"github:Org/rules_foo", "https://github.com/org/rules_foo.legacy", "gitlab:org/rules_foo"},
+			}),
+			"bar": newProtoRule(rule.NewRule(moduleMetadataKind, "metadata"), &bzpb.ModuleMetadata{
+				Repository: []string{"gitlab:org/rules_bar"},
+			}),
+		},
+	}
+
+	const publishToBCR = "https://github.com/bazel-contrib/publish-to-bcr/.github/workflows/publish.yaml@refs/tags/v0.2.2"
+	for _, tc := range []struct {
+		san        string
+		repository string
+		want       bool
+	}{
+		// a release workflow of the module that calls publish-to-bcr
+		{san: publishToBCR, repository: "https://github.com/org/rules_foo", want: true},
+		{san: "https://github.com/bazel-contrib/.github/.github/workflows/release_ruleset.yaml@refs/tags/v7", repository: "https://github.com/org/rules_foo", want: true},
+		// a workflow of the module that signs itself
+		{san: "https://github.com/org/rules_foo/.github/workflows/release.yml@refs/tags/v1.0.0", repository: "https://github.com/org/rules_foo", want: true},
+		{san: "https://github.com/ORG/RULES_FOO/.github/workflows/release.yml@refs/tags/v1.0.0", repository: "https://github.com/ORG/RULES_FOO", want: true},
+		{san: publishToBCR, repository: "https://github.com/org/rules_foo.legacy", want: true},
+		// the run of another repository
+		{san: publishToBCR, repository: "https://github.com/org/rules_foo_x"},
+		{san: publishToBCR, repository: "https://github.com/org/rules_fooxlegacy"},
+		{san: publishToBCR, repository: "https://github.com/attacker/rules_foo"},
+		{san: publishToBCR},
+		// signed by a workflow of another repository
+		{san: "https://github.com/attacker/rules_foo/.github/workflows/release.yml@refs/tags/v1.0.0", repository: "https://github.com/org/rules_foo"},
+		{san: "https://github.com/bazel-contrib/publish-to-bcr-fork/.github/workflows/publish.yaml@refs/tags/v0.2.2", repository: "https://github.com/org/rules_foo"},
+	} {
+		policy := ext.attestationIdentityPolicy("foo")
+		err := policy.Check(&sigstore.CertificateIdentity{
+			Issuer:                 sigstore.GitHubActionsIssuer,
+			SubjectAlternativeName: tc.san,
+			BuildSignerURI:         tc.san,
+			SourceRepositoryURI:    tc.repository,
+		})
+		if got := err == nil; got != tc.want {
+			t.Errorf("Check(%s, %s) = %v, want match %v", tc.san, tc.repository, err, tc.want)
+		}
+	}
+
+	// without GitHub repositories the identity is not checked
+	if policy := ext.attestationIdentityPolicy("bar"); policy != nil {
+		t.Errorf("expected no policy for bar, got %v", policy)
+	}
+	if policy := ext.attestationIdentityPolicy("baz"); policy != nil {
+		t.Errorf("expected no policy for baz, got %v", policy)
+	}
+
+	// the flag takes precedence over metadata.json
+	ext.attestationIdentity = regexp.MustCompile(`^https://github\.com/bazel-contrib/`)
+	if policy := ext.attestationIdentityPolicy("bar"); policy == nil || policy.SubjectAlternativeName != ext.attestationIdentity {
+		t.Errorf("expected the policy of the flag, got %v", policy)
+	}
+}
+
+func TestAttestationSubjectDigest(t *testing.T) {
+	versionDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(versionDir, "source.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := rule.NewRule(moduleAttestationsKind, "attestations")
+	r.SetPrivateAttr(versionDirPrivateAttr, versionDir)
+
+	sourceJSON := sha256.Sum256([]byte("{}"))
+	// the integrity of the archive is the one of "hello"
+	archive := sha256.Sum256([]byte("hello"))
+
+	ext := &bcrExtension{
+		moduleAttestationsRules: map[moduleID]*protoRule[*bzpb.Attestations]{
+			"foo@1.0.0": newProtoRule(r, &bzpb.Attestations{}),
+		},
+		moduleSourceRules: map[moduleID]*protoRule[*bzpb.ModuleSource]{
+			"foo@1.0.0": newProtoRule(rule.NewRule(moduleSourceKind, "source"), &bzpb.ModuleSource{
+				Url:        "https://example.com/releases/foo-1.0.0.tar.gz",
+				MirrorUrls: []string{"https://mirror.example.com/foo-v1.0.0.tar.gz"},
+				Integrity:  "sha256-LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=",
+			}),
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		want []byte
+	}{
+		{name: "source.json", want: sourceJSON[:]},
+		{name: "MODULE.bazel"}, // missing
+		{name: "foo-1.0.0.tar.gz", want: archive[:]},
+		{name: "foo-v1.0.0.tar.gz", want: archive[:]},
+		{name: "bar-1.0.0.tar.gz"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ext.attestationSubjectDigest(attestationRef{id: "foo@1.0.0", name: tc.name})
+			if !bytes.Equal(got, tc.want) {
+				t.Errorf("attestationSubjectDigest() = %x, want %x", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRecordAttestationProvenance(t *testing.T) {
+	repoRoot := t.TempDir()
+	versionDir := filepath.Join(repoRoot, "bcr/modules/foo/1.0.0")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(versionDir, "MODULE.bazel"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	registries, err := newRegistryLayers([]string{"bcr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the statements of actions/attest-build-provenance
+	const githubHosted = "https://github.com/actions/runner/github-hosted"
+	provenance := func(repository, ref string) *sigstore.Statement {
+		return &sigstore.Statement{
+			PredicateType: sigstore.SLSAProvenanceV1,
+			Predicate: json.RawMessage(`{
+				"buildDefinition": {"externalParameters": {"workflow": {
+					"ref": "` + ref + `",
+					"repository": "` + repository + `",
+					"path": ".github/workflows/release.yml"
+				}}},
+				"runDetails": {"builder": {"id": "` + githubHosted + `"}}
+			}`),
+		}
+	}
+	verified := &bzpb.AttestationVerification{Status: bzpb.AttestationVerificationStatus_ATTESTATION_VERIFIED}
+	mismatch := &bzpb.AttestationVerification{Status: bzpb.AttestationVerificationStatus_ATTESTATION_SUBJECT_MISMATCH}
+	// the claims of the certificates Fulcio issues to a release workflow of
+	// the module that calls the reusable workflow of publish-to-bcr
+	const publishToBCR = "https://github.com/bazel-contrib/publish-to-bcr/.github/workflows/publish.yaml@refs/tags/v0.2.2"
+	signer := func(ref string) *sigstore.CertificateIdentity {
+		return &sigstore.CertificateIdentity{
+			Issuer:                 sigstore.GitHubActionsIssuer,
+			SubjectAlternativeName: publishToBCR,
+			BuildSignerURI:         publishToBCR,
+			SourceRepositoryURI:    "https://github.com/org/foo",
+			SourceRepositoryDigest: "0123456789abcdef0123456789abcdef01234567",
+			SourceRepositoryRef:    ref,
+			BuildConfigURI:         "https://github.com/org/foo/.github/workflows/release.yml@" + ref,
+		}
+	}
+	release := signer("refs/tags/v1.0.0")
+
+	r := rule.NewRule(moduleAttestationsKind, "attestations")
+	ext := &bcrExtension{
+		repoRoot:   repoRoot,
+		registries: registries,
+		moduleAttestationsRules: map[moduleID]*protoRule[*bzpb.Attestations]{
+			"foo@1.0.0": newProtoRule(r, &bzpb.Attestations{}),
+		},
+	}
+	ext.recordAttestationProvenance(
+		[]attestationRef{
+			{id: "foo@1.0.0", name: "MODULE.bazel"},
+			{id: "foo@1.0.0", name: "foo-1.0.0.tar.gz"},
+			{id: "foo@1.0.0", name: "other.json"},
+			{id: "foo@1.0.0", name: "signed.json"},
+			{id: "foo@1.0.0", name: "source.json"},
+		},
+		[]*bzpb.AttestationVerification{verified, mismatch, verified, verified, verified},
+		[]*sigstore.Statement{
+			provenance("https://github.com/org/foo", "refs/tags/v1.0.0"),
+			provenance("https://github.com/org/foo", "refs/tags/v1.0.0"),
+			provenance("https://github.com/org/foo", "refs/heads/main"),
+			provenance("https://github.com/attacker/foo", "refs/tags/v1.0.0"),
+			provenance("https://github.com/org/foo", "refs/tags/v1.0.0"),
+		},
+		[]*sigstore.CertificateIdentity{release, release, signer("refs/heads/main"), release, release},
+	)
+
+	// the provenance reaches the module version through the attributes of
+	// the module_attestations rule (see rules/module_version.bzl)
+	want := map[string]string{
+		"provenance_predicate_type":      sigstore.SLSAProvenanceV1,
+		"provenance_builder_id":          githubHosted,
+		"provenance_source_repository":   "https://github.com/org/foo",
+		"provenance_source_ref":          "refs/tags/v1.0.0",
+		"provenance_source_commit":       "0123456789abcdef0123456789abcdef01234567",
+		"provenance_workflow_path":       ".github/workflows/release.yml",
+		"provenance_build_invocation_id": "",
+	}
+	for name, value := range want {
+		if got := r.AttrString(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	if got := r.AttrStrings("provenance_attested_files"); !slices.Equal(got, []string{"MODULE.bazel", "source.json"}) {
+		t.Errorf("provenance_attested_files = %v", got)
+	}
+
+	// the statement of other.json comes from another run, the one of
+	// signed.json claims another source repository than the certificate
+	if len(ext.diagnostics) != 2 {
+		t.Fatalf("unexpected diagnostics: %v", ext.diagnostics)
+	}
+	if ext.diagnostics[0].Severity != bzpb.DiagnosticSeverity_WARNING || ext.diagnostics[1].Severity != bzpb.DiagnosticSeverity_ERROR {
+		t.Errorf("unexpected diagnostics: %v", ext.diagnostics)
+	}
+}
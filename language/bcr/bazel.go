@@ -1,12 +1,15 @@
 package bcr
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"time"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
@@ -36,12 +39,18 @@ func (ext *bcrExtension) fetchBazelRepositoryMetadata(todo []*bzpb.RepositoryMet
 		log.Printf("Created repository metadata for %s", bazelRepoID)
 	}
 
-	// Check if we already have cached releases
-	if false && len(ext.bazelReleasesByVersion) > 0 {
+	// Use the cached releases if they are recent enough (see --cache-ttl), and
+	// in offline mode.  The existing metadata.json is kept, as the
+	// maintainers are not cached.
+	if ext.offline || ext.hasFreshBazelReleases() {
+		if len(ext.bazelReleasesByVersion) == 0 {
+			ext.cacheMisses.add(bzpb.CacheMissKind_CACHE_MISS_BAZEL_RELEASES, cmp.Or(ext.cacheFile, "bazel releases"))
+			return
+		}
 		log.Printf("Using %d cached Bazel releases", len(ext.bazelReleasesByVersion))
 		releases := make([]*bzpb.BazelRelease, 0, len(ext.bazelReleasesByVersion))
-		for _, release := range ext.bazelReleasesByVersion {
-			releases = append(releases, release)
+		for _, version := range slices.Sorted(maps.Keys(ext.bazelReleasesByVersion)) {
+			releases = append(releases, ext.bazelReleasesByVersion[version])
 		}
 		ext.createBazelModulesFromReleases(releases)
 		return
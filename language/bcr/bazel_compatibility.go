@@ -0,0 +1,257 @@
+package bcr
+
+import (
+	"log"
+	"math/bits"
+	"slices"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/bazelcompat"
+)
+
+// bazelReleaseSet is a bitset over the (sorted) known Bazel releases.
+type bazelReleaseSet []uint64
+
+func newBazelReleaseSet(n int) bazelReleaseSet {
+	return make(bazelReleaseSet, (n+63)/64)
+}
+
+func (s bazelReleaseSet) set(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+func (s bazelReleaseSet) has(i int) bool {
+	return s[i/64]&(1<<(i%64)) != 0
+}
+
+// intersect removes the releases that are not in other.
+func (s bazelReleaseSet) intersect(other bazelReleaseSet) {
+	for i := range s {
+		s[i] &= other[i]
+	}
+}
+
+// excludesAnyOf reports whether some release of other is missing from s.
+func (s bazelReleaseSet) excludesAnyOf(other bazelReleaseSet) bool {
+	for i := range s {
+		if other[i]&^s[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s bazelReleaseSet) count() (n int) {
+	for _, word := range s {
+		n += bits.OnesCount64(word)
+	}
+	return
+}
+
+// bazelCompatibilityIndex evaluates the bazel_compatibility constraints of
+// module versions against the known Bazel releases.
+type bazelCompatibilityIndex struct {
+	// releases are the known Bazel releases, sorted ascending
+	releases []string
+	// sets holds the compatible releases of module versions that declare
+	// bazel_compatibility constraints
+	sets map[moduleID]bazelReleaseSet
+	// constraints holds the raw bazel_compatibility constraints
+	constraints map[moduleID][]string
+}
+
+// newBazelCompatibilityIndex evaluates the given constraints against the
+// given Bazel releases.  Only final releases (MAJOR.MINOR.PATCH) are
+// considered.  Invalid constraints are logged and ignored, like an absent
+// constraint.
+func newBazelCompatibilityIndex(releases []string, constraints map[moduleID][]string) *bazelCompatibilityIndex {
+	type release struct {
+		name    string
+		version bazelcompat.BazelVersion
+	}
+	var parsed []release
+	for _, name := range releases {
+		if !bazelcompat.IsFinalRelease(name) {
+			continue
+		}
+		version, err := bazelcompat.ParseRelease(name)
+		if err != nil {
+			continue
+		}
+		parsed = append(parsed, release{name, version})
+	}
+	slices.SortFunc(parsed, func(a, b release) int {
+		return a.version.Compare(b.version)
+	})
+	parsed = slices.CompactFunc(parsed, func(a, b release) bool {
+		return a.version == b.version
+	})
+
+	ix := &bazelCompatibilityIndex{
+		releases:    make([]string, len(parsed)),
+		sets:        make(map[moduleID]bazelReleaseSet),
+		constraints: make(map[moduleID][]string),
+	}
+	for i, r := range parsed {
+		ix.releases[i] = r.name
+	}
+
+	for id, list := range constraints {
+		if len(list) == 0 {
+			continue
+		}
+		cs, err := bazelcompat.ParseConstraints(list)
+		if err != nil {
+			log.Printf("WARN: %s: %v", id, err)
+		}
+		if len(cs) == 0 {
+			continue
+		}
+		set := newBazelReleaseSet(len(parsed))
+		for i, r := range parsed {
+			if bazelcompat.SatisfiesAll(cs, r.version) {
+				set.set(i)
+			}
+		}
+		ix.sets[id] = set
+		ix.constraints[id] = list
+	}
+
+	return ix
+}
+
+// bazelCompatibilityResult is the evaluated bazel compatibility of a module
+// version and its MVS closure.
+type bazelCompatibilityResult struct {
+	minVersion string
+	maxVersion string
+	count      int
+	// narrowedBy lists the dependencies that exclude releases the root
+	// itself allows, sorted
+	narrowedBy []moduleID
+}
+
+// evaluate intersects the compatible releases of the root and every module
+// version selected by MVS (the selected map includes the root itself).
+func (ix *bazelCompatibilityIndex) evaluate(root moduleID, selected moduleDeps) *bazelCompatibilityResult {
+	all := newBazelReleaseSet(len(ix.releases))
+	for i := range ix.releases {
+		all.set(i)
+	}
+
+	own := all
+	if set, ok := ix.sets[root]; ok {
+		own = set
+	}
+
+	combined := slices.Clone(own)
+	result := &bazelCompatibilityResult{}
+	for name, version := range selected {
+		id := toModuleID(name, version)
+		if id == root {
+			continue
+		}
+		set, ok := ix.sets[id]
+		if !ok {
+			continue
+		}
+		combined.intersect(set)
+		if set.excludesAnyOf(own) {
+			result.narrowedBy = append(result.narrowedBy, id)
+		}
+	}
+	slices.SortFunc(result.narrowedBy, compareModuleIDs)
+
+	result.count = combined.count()
+	for i := range ix.releases {
+		if combined.has(i) {
+			if result.minVersion == "" {
+				result.minVersion = ix.releases[i]
+			}
+			result.maxVersion = ix.releases[i]
+		}
+	}
+
+	return result
+}
+
+// knownBazelReleases returns the versions of the bazel pseudo-modules and of
+// the cached Bazel releases.
+func (ext *bcrExtension) knownBazelReleases() []string {
+	seen := make(map[string]bool)
+	var releases []string
+	add := func(version string) {
+		if !seen[version] {
+			seen[version] = true
+			releases = append(releases, version)
+		}
+	}
+	for id := range ext.moduleVersionRules {
+		if id.name() == bazelToolsName {
+			add(string(id.version()))
+		}
+	}
+	for version := range ext.bazelReleasesByVersion {
+		add(version)
+	}
+	return releases
+}
+
+// calculateBazelCompatibility evaluates the bazel_compatibility constraints
+// across the MVS closure of every module version and annotates the
+// module_version rules with the result.
+func (ext *bcrExtension) calculateBazelCompatibility(perModuleVersionMvs mvs) {
+	constraints := make(map[moduleID][]string)
+	for id, protoRule := range ext.moduleVersionRules {
+		constraints[id] = protoRule.Proto().BazelCompatibility
+	}
+
+	ix := newBazelCompatibilityIndex(ext.knownBazelReleases(), constraints)
+	if len(ix.releases) == 0 {
+		log.Println("No Bazel releases known, skipping bazel_compatibility evaluation")
+		return
+	}
+
+	var annotatedCount, incompatibleCount int
+	for id, selected := range perModuleVersionMvs {
+		protoRule, exists := ext.moduleVersionRules[id]
+		if !exists || id.name() == bazelToolsName {
+			continue
+		}
+		result := ix.evaluate(id, selected)
+		updateModuleVersionRuleBazelCompatibilityAttrs(protoRule, result, ix.constraints)
+		annotatedCount++
+		if result.count == 0 {
+			incompatibleCount++
+		}
+	}
+
+	log.Printf("Evaluated bazel_compatibility of %d module versions against %d Bazel releases (%d incompatible with every release)", annotatedCount, len(ix.releases), incompatibleCount)
+}
+
+// updateModuleVersionRuleBazelCompatibilityAttrs sets the
+// compatible_bazel_* and bazel_compatibility_narrowed_by attributes of a
+// module_version rule.
+func updateModuleVersionRuleBazelCompatibilityAttrs(protoRule *protoRule[*bzpb.ModuleVersion], result *bazelCompatibilityResult, constraints map[moduleID][]string) {
+	r := protoRule.Rule()
+	if result.minVersion != "" {
+		r.SetAttr("compatible_bazel_min_version", result.minVersion)
+		r.SetAttr("compatible_bazel_max_version", result.maxVersion)
+	}
+	r.SetAttr("compatible_bazel_version_count", result.count)
+	if len(result.narrowedBy) > 0 {
+		narrowedBy := make([]string, len(result.narrowedBy))
+		for i, id := range result.narrowedBy {
+			narrowedBy[i] = formatBazelCompatibilityNarrowing(id, constraints[id])
+		}
+		r.SetAttr("bazel_compatibility_narrowed_by", narrowedBy)
+	}
+}
+
+// formatBazelCompatibilityNarrowing encodes a narrowing dependency as
+// "MODULE@VERSION CONSTRAINT..." (e.g. "rules_cc@0.1.0 >=7.0.0 -7.1.0").  The
+// format is parsed by the moduleversioncompiler.
+func formatBazelCompatibilityNarrowing(id moduleID, constraints []string) string {
+	return strings.Join(append([]string{string(id)}, constraints...), " ")
+}
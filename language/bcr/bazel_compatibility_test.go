@@ -0,0 +1,100 @@
+package bcr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBazelCompatibilityIndexEvaluate(t *testing.T) {
+	releases := []string{"8.0.0", "6.5.0", "7.0.0", "7.1.0", "7.2.0rc1", "7.4.1", "7.4.1"}
+
+	tests := []struct {
+		name           string
+		constraints    map[moduleID][]string
+		root           moduleID
+		selected       moduleDeps
+		wantMin        string
+		wantMax        string
+		wantCount      int
+		wantNarrowedBy []moduleID
+	}{
+		{
+			name:      "no constraints",
+			root:      "a@1.0",
+			selected:  moduleDeps{"a": "1.0", "b": "1.0"},
+			wantMin:   "6.5.0",
+			wantMax:   "8.0.0",
+			wantCount: 5,
+		},
+		{
+			name: "narrowed by dependency",
+			constraints: map[moduleID][]string{
+				"a@1.0": {">=7.0.0"},
+				"b@1.0": {"<8.0.0", "-7.1.0"},
+				"c@1.0": {">=6.0.0"},
+			},
+			root:           "a@1.0",
+			selected:       moduleDeps{"a": "1.0", "b": "1.0", "c": "1.0"},
+			wantMin:        "7.0.0",
+			wantMax:        "7.4.1",
+			wantCount:      2,
+			wantNarrowedBy: []moduleID{"b@1.0"},
+		},
+		{
+			name: "only selected versions count",
+			constraints: map[moduleID][]string{
+				"b@1.0": {"<7.0.0"},
+			},
+			root:      "a@1.0",
+			selected:  moduleDeps{"a": "1.0", "b": "2.0"},
+			wantMin:   "6.5.0",
+			wantMax:   "8.0.0",
+			wantCount: 5,
+		},
+		{
+			name: "incompatible with every release",
+			constraints: map[moduleID][]string{
+				"a@1.0": {"<7.0.0"},
+				"b@1.0": {">=8.0.0"},
+			},
+			root:           "a@1.0",
+			selected:       moduleDeps{"a": "1.0", "b": "1.0"},
+			wantCount:      0,
+			wantNarrowedBy: []moduleID{"b@1.0"},
+		},
+		{
+			name: "invalid constraints are ignored",
+			constraints: map[moduleID][]string{
+				"a@1.0": {">=7", ">=7.1.0"},
+			},
+			root:      "a@1.0",
+			selected:  moduleDeps{"a": "1.0"},
+			wantMin:   "7.1.0",
+			wantMax:   "8.0.0",
+			wantCount: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ix := newBazelCompatibilityIndex(releases, tt.constraints)
+			if want := []string{"6.5.0", "7.0.0", "7.1.0", "7.4.1", "8.0.0"}; !slices.Equal(ix.releases, want) {
+				t.Fatalf("releases = %v, want %v", ix.releases, want)
+			}
+			got := ix.evaluate(tt.root, tt.selected)
+			if got.minVersion != tt.wantMin || got.maxVersion != tt.wantMax || got.count != tt.wantCount {
+				t.Errorf("got %s..%s (%d), want %s..%s (%d)", got.minVersion, got.maxVersion, got.count, tt.wantMin, tt.wantMax, tt.wantCount)
+			}
+			if !slices.Equal(got.narrowedBy, tt.wantNarrowedBy) {
+				t.Errorf("narrowedBy = %v, want %v", got.narrowedBy, tt.wantNarrowedBy)
+			}
+		})
+	}
+}
+
+func TestFormatBazelCompatibilityNarrowing(t *testing.T) {
+	got := formatBazelCompatibilityNarrowing("rules_cc@0.1.0", []string{">=7.0.0", "-7.1.0"})
+	if want := "rules_cc@0.1.0 >=7.0.0 -7.1.0"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package bcr
 
 import (
+	"cmp"
 	"flag"
 	"fmt"
 	"log"
@@ -8,13 +9,16 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
 	"github.com/bazel-contrib/bcr-frontend/pkg/attestationsjson"
+	"github.com/bazel-contrib/bcr-frontend/pkg/cachestore"
 	"github.com/bazel-contrib/bcr-frontend/pkg/metadatajson"
 	"github.com/bazel-contrib/bcr-frontend/pkg/modulebazel"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sigstore"
 	"github.com/bazel-contrib/bcr-frontend/pkg/sourcejson"
 	"github.com/bazelbuild/bazel-gazelle/config"
 	"github.com/bazelbuild/bazel-gazelle/label"
@@ -26,10 +30,7 @@ import (
 	"github.com/google/go-github/v66/github"
 )
 
-const (
-	bcrLangName                        = "bcr"
-	generateModuleDependencyCycleRules = false
-)
+const bcrLangName = "bcr"
 
 // NewLanguage is called by Gazelle to install this language extension in a
 // binary.
@@ -41,13 +42,23 @@ func NewLanguage() language.Language {
 		moduleToCycle:            make(map[moduleID]string),
 		unresolvedModules:        make(map[moduleID]bool),
 		repositoriesMetadataByID: make(map[repositoryID]*bzpb.RepositoryMetadata),
-		resourceStatusByUrl:      make(map[string]*bzpb.ResourceStatus),
 		moduleIDsByDocUrl:        make(map[string][]moduleID),
 		moduleIDsBySourceUrl:     make(map[string][]moduleID),
 		moduleMetadataRules:      make(map[moduleName]*protoRule[*bzpb.ModuleMetadata]),
+		moduleMetadataRulesByPkg: make(map[string]*protoRule[*bzpb.ModuleMetadata]),
 		moduleVersionRules:       make(map[moduleID]*protoRule[*bzpb.ModuleVersion]),
 		moduleSourceRules:        make(map[moduleID]*protoRule[*bzpb.ModuleSource]),
+		moduleAttestationsRules:  make(map[moduleID]*protoRule[*bzpb.Attestations]),
 		bazelReleasesByVersion:   make(map[string]*bzpb.BazelRelease),
+		excludedModules:          make(map[moduleName]bool),
+		docsMaxVersions:          make(map[moduleName]int),
+		skipNetworkModules:       make(map[moduleName]bool),
+		skipNetworkRepositories:  make(map[repositoryID]bool),
+		skipRefreshRepositories:  make(map[repositoryID]bool),
+		unchangedModuleVersions:  make(map[moduleID]*unchangedModuleVersion),
+		moduleConfigFingerprints: make(map[string]string),
+		cacheTTLs:                cachestore.DefaultTTLs(),
+		cache:                    cachestore.New(cachestore.DefaultTTLs()),
 	}
 }
 
@@ -56,33 +67,71 @@ type bcrExtension struct {
 	name                      string
 	repoRoot                  string // copy of config.RepoRoot
 	modulesRoot               string
-	resourceStatusSetFile     string
-	repositoryMetadataSetFile string
-	bazelReleaseSetFile       string
+	cacheFile                 string          // optional path to the cache store of the url statuses, repository metadata and Bazel releases
+	cacheTTLs                 cachestore.TTLs // times to live of the cache entries
+	registryCommitSetFile     string          // optional path to the registry commits of the previous run (enables incremental regeneration)
+	generatorFingerprint      string          // fingerprint of the generator binary, recorded with the registry commits
+	buildFileNames            []string        // copy of config.ValidBuildFileNames
+	cycleReportFile           string          // optional path to write the dependency cycle report to
+	diagnosticsReportFile     string          // optional path to write the registry diagnostics report to
+	verifyArchives            bool            // whether to download (or read from the archive cache) and verify the source archives
+	archiveCacheDir           string          // optional content-addressed archive cache (e.g., the bazel repository cache)
+	verifyAttestations        bool            // whether to verify the sigstore bundles of the attestations.json files
+	attestationTrustedRoot    string          // trusted_root.json file of the sigstore instance(s) that sign the attestations
+	attestationBundleDir      string          // optional content-addressed directory of sigstore bundles (verified downloads are added to it)
+	attestationIssuer         string          // expected OIDC issuer of the signing certificates of the attestations
+	attestationIdentityRegexp string          // expected subject alternative names of the signing certificates (derived from metadata.json if empty)
+	offline                   bool            // whether network lookups are served only from the cache files
+	cacheMissReportFile       string          // optional path to write the cache misses of an offline run to
+	generateCycleRules        bool            // whether to generate module_dependency_cycle rules
 	githubToken               string
 	gitlabToken               string
-	registryRoot              string
+	bitbucketToken            string           // optional, anonymous Bitbucket API requests are rate limited
+	sourcehutToken            string           // required for the sourcehut GraphQL API
+	registryRoot              string           // root dir of the base registry (the last --registry-root)
+	registryRoots             stringSlice      // --registry-root values, in order of precedence
+	registries                []*registryLayer // registries, in order of precedence
 	registryURL               string
 	registrySourceURL         string         // URL to fetch backup registry data from
 	backupRegistry            *bzpb.Registry // backup registry loaded from registrySourceURL
 	blacklistedUrls           stringBoolMap  // tracks urls that are known to have wrong integrity or would otherwise not download
 	githubClient              *github.Client
+	trustedRoot               *sigstore.TrustedRoot                           // sigstore trust roots, loaded from attestationTrustedRoot
+	attestationIdentity       *regexp.Regexp                                  // compiled attestationIdentityRegexp
 	depGraph                  graph.Graph[moduleID, moduleID]                 // graph of all dependencies (regular + dev) - for cycle detection
 	regularDepGraph           graph.Graph[moduleID, moduleID]                 // graph of only non-dev dependencies
 	devDepGraph               graph.Graph[moduleID, moduleID]                 // graph of only dev dependencies
 	moduleToCycle             map[moduleID]string                             // maps ID to cycle rule name
 	unresolvedModules         map[moduleID]bool                               // tracks module versions that failed to resolve
+	diagnostics               diagnostics                                     // problems found in the registry data
+	cacheMisses               cacheMisses                                     // network lookups skipped in offline mode
 	repositoriesMetadataByID  map[repositoryID]*bzpb.RepositoryMetadata       // tracks unique repository strings (e.g., "github:org/repo")
-	moduleMetadataRules       map[moduleName]*protoRule[*bzpb.ModuleMetadata] // tracks module metadata rules
+	moduleMetadataRules       map[moduleName]*protoRule[*bzpb.ModuleMetadata] // tracks module metadata rules (of the registry with the highest precedence)
+	moduleMetadataRulesByPkg  map[string]*protoRule[*bzpb.ModuleMetadata]     // tracks module metadata rules of all registries by package
+	moduleMetadataLabels      []label.Label                                   // module metadata rules of all registries, for the combined module_registry
+	cycleRuleLabels           []label.Label                                   // module_dependency_cycle rules, for the combined module_registry
+	combinedRegistryURL       string                                          // registry url of the base registry root package, for the combined module_registry
 	moduleVersionRules        map[moduleID]*protoRule[*bzpb.ModuleVersion]    // tracks module_version rules by ID
 	moduleSourceRules         map[moduleID]*protoRule[*bzpb.ModuleSource]     // tracks module_source rules by ID
+	moduleAttestationsRules   map[moduleID]*protoRule[*bzpb.Attestations]     // tracks module_attestations rules by ID
 	moduleIDsByDocUrl         map[string][]moduleID                           // tracks docs http_archives to fetch
 	moduleIDsBySourceUrl      map[string][]moduleID                           // tracks URLs for starlark_repository
-	resourceStatusByUrl       map[string]*bzpb.ResourceStatus                 // results of reading resourceStatusSetFile, keyed by URL
-	moduleCommits             map[moduleBazelRelPath]*bzpb.ModuleCommit       // cache of all module commits (preloaded)
+	cache                     *cachestore.Store                               // results of network lookups, read from cacheFile
+	moduleCommits             map[moduleBazelRelPath]*bzpb.ModuleCommit       // cache of all module commits of the base registry (preloaded)
 	bazelReleasesByVersion    map[string]*bzpb.BazelRelease                   // cache of Bazel releases (preloaded)
 	fetchedRepositoryMetadata bool                                            // tracks whether we fetched any new repository metadata this run
 	fetchedBazelReleases      bool                                            // tracks whether we fetched any new bazel releases this run
+	excludedModules           map[moduleName]bool                             // modules excluded via the bcr_exclude_module directive
+	docsMaxVersions           map[moduleName]int                              // per-module limit set via the bcr_docs_max_versions directive
+	skipNetworkModules        map[moduleName]bool                             // modules that skip network access via the bcr_skip_network directive
+	skipNetworkRepositories   map[repositoryID]bool                           // repositories only referenced by modules that skip network access
+	skipRefreshRepositories   map[repositoryID]bool                           // repositories only referenced by modules that skip network access via the bcr_skip_network directive
+	incremental               bool                                            // whether only changed modules are regenerated
+	changedModules            map[moduleName]bool                             // modules that changed since the previous run (incremental mode)
+	affectedModuleVersions    map[moduleID]bool                               // module versions whose dependency closure includes a changed module (incremental mode)
+	unchangedModuleVersions   map[moduleID]*unchangedModuleVersion            // module versions that keep their existing rules (incremental mode)
+	moduleConfigFingerprints  map[string]string                               // fingerprint of the bcr config of each module package, recorded with the registry commits
+	previousModuleConfigs     map[string]string                               // module config fingerprints of the previous run (incremental mode)
 }
 
 // Name returns the name of the language. This should be a prefix of the kinds
@@ -96,44 +145,96 @@ func (ext *bcrExtension) Name() string {
 // https://pkg.go.dev/github.com/bazelbuild/bazel-gazelle/resolve?tab=doc#Resolver
 // interface, but are otherwise unused.
 func (ext *bcrExtension) RegisterFlags(fs *flag.FlagSet, cmd string, c *config.Config) {
-	fs.StringVar(&ext.registryRoot,
-		"registry-root", "", "root dir for the bcr registry")
+	fs.Var(&ext.registryRoots,
+		"registry-root", "root dir for a bcr registry (repeatable, in order of precedence like bazel's --registry; the last one is the base registry)")
 	fs.StringVar(&ext.registryURL,
-		"registry-url", "", "base URL for the deployed registry")
+		"registry-url", "", "base URL for the deployed registry (may also be set with the bcr_registry_url directive)")
 	fs.StringVar(&ext.registrySourceURL,
 		"registry-source-url", "https://bcr.stack.build/registry.pb.gz", "URL to fetch backup registry data from (for repository metadata fallback)")
-	fs.StringVar(&ext.resourceStatusSetFile,
-		"resource-status-set-file", "", "path to resource-status.json file containing cached http statuses for the registry URLs (helpful for development)")
-	fs.StringVar(&ext.repositoryMetadataSetFile,
-		"repository-metadata-set-file", "", "path to repository-metadata.json file containing cached repository metadata (helpful for development)")
-	fs.StringVar(&ext.bazelReleaseSetFile,
-		"bazel-release-set-file", "", "path to bazel-releases.json file containing cached Bazel release data (helpful for development)")
+	fs.StringVar(&ext.cacheFile,
+		"cache-file", "", "path to the cache store (.json or .pb) of the url statuses, repository metadata, Bazel releases and source commits; entries older than their --cache-ttl are fetched again")
+	fs.Var(ext.cacheTTLs,
+		"cache-ttl", "time to live of a class of cache entries as CLASS=DURATION, e.g. repository_metadata=7d (repeatable; classes: resource_status, resource_failure, repository_metadata, bazel_release, source_commit; 0 never expires)")
+	fs.StringVar(&ext.registryCommitSetFile,
+		"registry-commit-set-file", "", "path to registry-commits.json file recording the registry commits of the last run; when present, only modules changed since then (and their dependents) are regenerated")
+	fs.StringVar(&ext.cycleReportFile,
+		"cycle-report-file", "", "path to write a report of the dependency cycles in the registry to (.json or .pb)")
+	fs.StringVar(&ext.diagnosticsReportFile,
+		"diagnostics-report-file", "", "path to write a report of the problems found in the registry data to (.json or .pb)")
+	fs.BoolVar(&ext.verifyArchives,
+		"verify-archives", false, "download the source archives (or read them from --archive-cache-dir) and verify their integrity and strip_prefix")
+	fs.StringVar(&ext.archiveCacheDir,
+		"archive-cache-dir", "", "content-addressed archive cache, in the layout of bazel's --repository_cache (verified downloads are added to it)")
+	fs.BoolVar(&ext.verifyAttestations,
+		"verify-attestations", false, "verify the sigstore bundles listed in the attestations.json files (read from --attestation-bundle-dir or downloaded) against --attestation-trusted-root")
+	fs.StringVar(&ext.attestationTrustedRoot,
+		"attestation-trusted-root", "", "trusted_root.json file of the certificate authorities and transparency logs that sign the attestations (required with --verify-attestations)")
+	fs.StringVar(&ext.attestationBundleDir,
+		"attestation-bundle-dir", "", "content-addressed directory of sigstore bundles, in the layout of bazel's --repository_cache (verified downloads are added to it)")
+	fs.StringVar(&ext.attestationIssuer,
+		"attestation-certificate-oidc-issuer", sigstore.GitHubActionsIssuer, "OIDC issuer the signing certificates of the attestations must be issued by, like cosign --certificate-oidc-issuer")
+	fs.StringVar(&ext.attestationIdentityRegexp,
+		"attestation-certificate-identity-regexp", "", "regexp the subject alternative name of the signing certificates of the attestations must match, like cosign --certificate-identity-regexp (by default, a workflow of publish-to-bcr or of the GitHub repository of the module in metadata.json, run for that repository)")
+	fs.BoolVar(&ext.offline,
+		"offline", false, "never access the network: serve all lookups from the cache files and the backup registry (which must be a local file) and report the cache misses")
+	fs.StringVar(&ext.cacheMissReportFile,
+		"cache-miss-report-file", "", "path to write a report of the cache misses of an --offline run to (.json or .pb)")
+	fs.BoolVar(&ext.generateCycleRules,
+		"generate-cycle-rules", false, "generate module_dependency_cycle rules and link module_dependency rules to their module_version or cycle")
 	fs.StringVar(&ext.githubToken,
 		"github-token", os.Getenv("GITHUB_TOKEN"), "GitHub API token (defaults to GITHUB_TOKEN env var)")
 	fs.StringVar(&ext.gitlabToken,
 		"gitlab-token", os.Getenv("GITLAB_TOKEN"), "GitLab API token (defaults to GITLAB_TOKEN env var)")
+	fs.StringVar(&ext.bitbucketToken,
+		"bitbucket-token", os.Getenv("BITBUCKET_TOKEN"), "Bitbucket API access token (defaults to BITBUCKET_TOKEN env var)")
+	fs.StringVar(&ext.sourcehutToken,
+		"sourcehut-token", os.Getenv("SRHT_TOKEN"), "sourcehut personal access token (defaults to SRHT_TOKEN env var)")
 	fs.Var(&ext.blacklistedUrls,
 		"blacklisted_url", "URL to blacklist (repeatable)")
 }
 
 func (ext *bcrExtension) CheckFlags(fs *flag.FlagSet, c *config.Config) error {
 	ext.repoRoot = c.RepoRoot
+	ext.buildFileNames = c.ValidBuildFileNames
 
-	if ext.registryURL == "" {
-		return fmt.Errorf("--registry-url is required")
-	}
-	if ext.registryRoot == "" {
+	if len(ext.registryRoots) == 0 {
 		return fmt.Errorf("--registry-root is required")
 	}
-	ext.modulesRoot = filepath.Join(ext.registryRoot, "modules")
+	registries, err := newRegistryLayers(ext.registryRoots)
+	if err != nil {
+		return err
+	}
+	ext.registries = registries
+	base := ext.baseRegistry()
+	ext.registryRoot = base.root
+	ext.modulesRoot = base.modulesRoot
 
 	ext.configureGithubClient()
-	ext.readResourceStatusCacheFile()
-	ext.readRepositoryMetadataCacheFile()
-	ext.readBazelReleaseCacheFile()
-	ext.readModuleCommits(c)
+	ext.readCacheFile()
+	for _, reg := range ext.registries {
+		reg.moduleCommits = readModuleCommits(c, reg.root)
+	}
+	ext.moduleCommits = base.moduleCommits
 	ext.loadBackupRegistry()
 
+	if ext.verifyAttestations {
+		if ext.attestationTrustedRoot == "" {
+			return fmt.Errorf("--attestation-trusted-root is required with --verify-attestations")
+		}
+		root, err := sigstore.ReadTrustedRoot(os.ExpandEnv(ext.attestationTrustedRoot))
+		if err != nil {
+			return err
+		}
+		ext.trustedRoot = root
+		if ext.attestationIdentityRegexp != "" {
+			re, err := regexp.Compile(ext.attestationIdentityRegexp)
+			if err != nil {
+				return fmt.Errorf("--attestation-certificate-identity-regexp: %w", err)
+			}
+			ext.attestationIdentity = re
+		}
+	}
+
 	return nil
 }
 
@@ -141,9 +242,18 @@ func (ext *bcrExtension) CheckFlags(fs *flag.FlagSet, c *config.Config) error {
 func (ext *bcrExtension) Configure(c *config.Config, rel string, f *rule.File) {
 	cfg := getOrCreateConfig(c)
 
-	// enable this extension once we hit the registry
-	if ext.registryRoot == rel {
-		cfg.enabled = true
+	if f != nil {
+		cfg.parseDirectives(rel, f.Directives)
+	}
+
+	// enable this extension once we hit a registry
+	for _, reg := range ext.registries {
+		if reg.root == rel {
+			cfg.enabled = true
+		}
+		if name, ok := moduleNameFromRel(reg.modulesRoot, rel); ok && path.Join(reg.modulesRoot, string(name)) == rel {
+			ext.trackModuleConfig(rel, name, cfg)
+		}
 	}
 }
 
@@ -196,7 +306,7 @@ func (ext *bcrExtension) Loads() []rule.LoadInfo {
 }
 
 func (*bcrExtension) KnownDirectives() []string {
-	return nil
+	return knownDirectives
 }
 
 // Fix repairs deprecated usage of language-specific rules in f. This is called
@@ -258,17 +368,21 @@ func (ext *bcrExtension) Resolve(
 	// Switch on rule kind to delegate to specific resolver functions
 	switch r.Kind() {
 	case "module_dependency":
-		resolveModuleDependencyRule(ext.modulesRoot, r, ix, from, ext.moduleToCycle, ext.unresolvedModules)
+		resolveModuleDependencyRule(ext.combinedPkg(), r, ix, from, ext.generateCycleRules, ext.moduleToCycle, ext.unresolvedModules, ext.preferredFindResult)
 	case "module_dependency_cycle":
 		resolveModuleDependencyCycleRule(r, ix)
 	case "module_metadata":
-		resolveModuleMetadataRule(r, ix)
+		resolveModuleMetadataRule(r, ix, from)
 	case "module_registry":
-		resolveModuleRegistryRule(r, ix)
+		if r.PrivateAttr(combinedModuleRegistryPrivateAttr) != nil {
+			ext.resolveCombinedModuleRegistryRule(r, ix)
+		} else {
+			resolveModuleRegistryRule(r, ix, from)
+		}
 	case "repository_metadata":
 		resolveRepositoryMetadataRule(r, ix, ext.repositoriesMetadataByID)
 	case "module_version":
-		resolveModuleVersionRule(r, ext.moduleMetadataRules)
+		resolveModuleVersionRule(r, ext.moduleMetadataRulesByPkg[path.Dir(from.Pkg)])
 	}
 }
 
@@ -286,31 +400,61 @@ func (ext *bcrExtension) Resolve(
 // log.Print.
 func (ext *bcrExtension) GenerateRules(args language.GenerateArgs) language.GenerateResult {
 	cfg := mustGetConfig(args.Config)
+
+	// Layered registries share the registry-wide rules, which need every
+	// registry to have been visited
+	if args.Rel == ext.combinedPkg() && len(ext.registries) > 1 {
+		return ext.generateCombinedRules(args)
+	}
+
 	if !cfg.enabled {
 		return language.GenerateResult{}
 	}
 
 	// log.Println("visiting:", args.Rel, args.RegularFiles)
 
+	reg := ext.registryForRel(args.Rel)
+	if reg == nil {
+		return language.GenerateResult{}
+	}
+
+	// Honor per-module directives
+	if name, ok := moduleNameFromRel(reg.modulesRoot, args.Rel); ok {
+		if cfg.isExcludedModule(string(name)) {
+			ext.excludedModules[name] = true
+			return language.GenerateResult{}
+		}
+		ext.applyModuleConfig(cfg, name)
+	}
+
 	var rules []*rule.Rule
 
-	// Generate repository metadata in the registry root package
+	isBaseRegistry := reg == ext.baseRegistry()
+
+	// Generate repository metadata in the base registry root package (in the
+	// combined package for layered registries)
 	if args.Rel == ext.registryRoot {
-		repoRules := makeRepositoryMetadataRules(ext.repositoriesMetadataByID)
-		rules = append(rules, repoRules...)
+		if len(ext.registries) > 1 {
+			ext.combinedRegistryURL = ext.mustGetRegistryURL(cfg)
+		} else {
+			rules = append(rules, makeRepositoryMetadataRules(ext.repositoriesMetadataByID)...)
+		}
 	}
 
 	// Generate cycles and the module registry in the modules root package
-	if args.Rel == ext.modulesRoot {
+	if args.Rel == reg.modulesRoot {
 		var cycleRules []*rule.Rule
-		if generateModuleDependencyCycleRules {
-			cycles := ext.getCycles()
-			if len(cycles) > 0 {
-				cycleRules = makeModuleDependencyCycleRules(cycles)
-				rules = append(rules, cycleRules...)
-			}
+		if args.Rel == ext.combinedPkg() {
+			cycleRules = ext.makeCycleRules(args.Rel)
+			rules = append(rules, cycleRules...)
 		}
-		rules = append(rules, makeModuleRegistryRule(path.Base(args.Rel), args.Subdirs, ext.registryURL, cycleRules, args.Config))
+		subdirs := ext.filterExcludedModules(cfg, args.Subdirs)
+		registryRule := makeModuleRegistryRule(path.Base(args.Rel), subdirs, ext.mustGetRegistryURL(cfg), reg.root, cycleRules, args.Config)
+		if isBaseRegistry {
+			// reverse dependencies are calculated over all registries
+			registryRule.SetAttr("reverse_dependencies", reverseDependencyIndexFilename)
+		}
+		rules = append(rules, registryRule)
 	}
 
 	// create module_metadata rule in the module root
@@ -318,27 +462,47 @@ func (ext *bcrExtension) GenerateRules(args language.GenerateArgs) language.Gene
 		filename := filepath.Join(args.Config.WorkDir, args.Rel, "metadata.json")
 		md, err := metadatajson.ReadFile(filename)
 		if err != nil {
-			log.Fatalf("reading %s/source.json: %v", args.Rel, err)
+			// the module is skipped, its versions are reported as unresolved
+			// dependencies
+			ext.diagnostics.add(reg.modulesRoot, args.Rel, "metadata.json", bzpb.DiagnosticSeverity_ERROR, err)
+		} else {
+			// Generate maintainer rules
+			maintainerRules := makeModuleMaintainerRules(md.Maintainers)
+			// Add maintainer rules to the list
+			rules = append(rules, maintainerRules...)
+			// Add metadata rule with references to maintainers (passing ext to track repositories)
+			r := makeModuleMetadataRule(path.Base(args.Rel), md, maintainerRules, "metadata.json", ext)
+			r.SetAttr("registry", reg.name)
+			ext.trackSkipNetworkRepositories(md.Repository, cfg.skipNetwork || !ext.isChangedModule(moduleName(path.Base(args.Rel))), cfg.skipNetwork)
+			ext.trackModuleMetadataLabel(args.Rel, r)
+			// track it so moduleVersion can determine if it is latest version
+			metadataRule := newProtoRule(r, md)
+			ext.moduleMetadataRulesByPkg[args.Rel] = metadataRule
+			if !ext.isShadowedModule(reg, r.Name()) {
+				ext.moduleMetadataRules[moduleName(r.Name())] = metadataRule
+			}
+			rules = append(rules, r)
 		}
-		// Generate maintainer rules
-		maintainerRules := makeModuleMaintainerRules(md.Maintainers)
-		// Add maintainer rules to the list
-		rules = append(rules, maintainerRules...)
-		// Add metadata rule with references to maintainers (passing ext to track repositories)
-		r := makeModuleMetadataRule(path.Base(args.Rel), md, maintainerRules, "metadata.json", ext)
-		// track it so moduleVersion can determine if it is latest version
-		ext.moduleMetadataRules[moduleName(r.Name())] = newProtoRule(r, md)
-		rules = append(rules, r)
 	}
 
 	// are we in a module version directory?
 	if !inOverlayDir(args.Rel) && slices.Contains(args.RegularFiles, "MODULE.bazel") {
+		// Extract version from path (e.g., modules/foo/1.2.3 -> 1.2.3)
+		version := filepath.Base(args.Rel)
+
+		// In incremental mode, module versions that are not affected by the
+		// registry changes keep their existing rules.  They are still read so
+		// that graph-wide computations (MVS, cycles, ...) see the whole
+		// registry.
+		existing := ext.findExistingModuleVersionRule(args.File)
+		regenerate := existing == nil || ext.isAffectedModuleVersion(moduleName(path.Base(path.Dir(args.Rel))), moduleVersion(version))
+
 		// The BCR should not contain BUILD.bazel files in the same directory as
 		// the MODULE.bazel file, these should exist as patches or overlays.
 		// However, at least one exists which references @rules_cc, and as a
 		// consequence we cannot build.  If this file exists, remove all
 		// pre-existing rules if a BUILD file exists.
-		if args.File != nil {
+		if args.File != nil && regenerate {
 			for _, r := range args.File.Rules {
 				r.Delete()
 			}
@@ -348,14 +512,37 @@ func (ext *bcrExtension) GenerateRules(args language.GenerateArgs) language.Gene
 			args.File.Sync()
 		}
 
+		// Read the registry files upfront: a version with a broken file is
+		// reported and skipped entirely rather than aborting the run.
 		moduleBazelFilename := filepath.Join(args.Config.WorkDir, args.Rel, "MODULE.bazel")
 		module, err := modulebazel.ExecFile(moduleBazelFilename)
 		if err != nil {
-			log.Fatalf("reading %s: %v", moduleBazelFilename, err)
+			ext.reportBrokenModuleVersion(reg.modulesRoot, args.Rel, "MODULE.bazel", err)
+			return language.GenerateResult{}
 		}
 
-		// Extract version from path (e.g., modules/foo/1.2.3 -> 1.2.3)
-		version := filepath.Base(args.Rel)
+		var source *bzpb.ModuleSource
+		if slices.Contains(args.RegularFiles, "source.json") {
+			source, err = sourcejson.ReadFile(filepath.Join(args.Config.WorkDir, args.Rel, "source.json"))
+			if err != nil {
+				ext.reportBrokenModuleVersion(reg.modulesRoot, args.Rel, "source.json", err)
+				return language.GenerateResult{}
+			}
+		}
+
+		var attestations *bzpb.Attestations
+		if slices.Contains(args.RegularFiles, "attestations.json") {
+			attestations, err = attestationsjson.ReadFile(filepath.Join(args.Config.WorkDir, args.Rel, "attestations.json"))
+			if err != nil {
+				ext.reportBrokenModuleVersion(reg.modulesRoot, args.Rel, "attestations.json", err)
+				return language.GenerateResult{}
+			}
+		}
+
+		// A module version that is also provided by a registry with higher
+		// precedence still gets its rules, but does not take part in the
+		// dependency graph, MVS or url checks (bazel never selects it).
+		shadowed := ext.isShadowedModuleVersion(reg, module.Name, version)
 
 		var sourceRule *rule.Rule
 		var attestationsRule *rule.Rule
@@ -363,42 +550,42 @@ func (ext *bcrExtension) GenerateRules(args language.GenerateArgs) language.Gene
 		var commitRule *rule.Rule
 
 		// Create module_commit rule with git metadata using preloaded cache
-		commit, err := makeModuleVersionCommitRule(args.Config, ext.registryRoot, args.Rel, ext.moduleCommits)
-		if err != nil {
-			log.Printf("warning: failed to create commit rule for %s: %v", args.Rel, err)
-		} else {
-			commitRule = commit
-			rules = append(rules, commitRule)
-		}
-
-		if slices.Contains(args.RegularFiles, "source.json") {
-			sourceFilename := filepath.Join(args.Config.WorkDir, args.Rel, "source.json")
-			source, err := sourcejson.ReadFile(sourceFilename)
+		if regenerate {
+			commit, err := makeModuleVersionCommitRule(args.Config, reg.root, args.Rel, reg.moduleCommits)
 			if err != nil {
-				log.Fatalf("reading %s/source.json: %v", args.Rel, err)
+				log.Printf("warning: failed to create commit rule for %s: %v", args.Rel, err)
+			} else {
+				commitRule = commit
+				rules = append(rules, commitRule)
 			}
+		}
+
+		if source != nil {
 			module.Source = source
+			ext.blacklistConfiguredUrls(cfg, source.Url, source.DocsUrl)
 
 			sourceRule = makeModuleSourceRule(module, source, "source.json")
+			ext.verifyModuleSourceFiles(sourceRule, source, reg.modulesRoot, args.Rel, filepath.Join(args.Config.WorkDir, args.Rel))
 			rules = append(rules, sourceRule)
 
 			// Track the rule and URLS
-			id := newModuleID(module.Name, module.Version)
-			ext.moduleSourceRules[id] = newProtoRule(sourceRule, source)
-			ext.trackDocsUrl(source.DocsUrl, id)
-			ext.trackSourceUrl(source.Url, id)
+			if !shadowed {
+				id := newModuleID(module.Name, module.Version)
+				ext.moduleSourceRules[id] = newProtoRule(sourceRule, source)
+				ext.trackDocsUrl(source.DocsUrl, id)
+				ext.trackSourceUrl(source.Url, id)
+			}
 		}
 
-		if slices.Contains(args.RegularFiles, "attestations.json") {
-			// Try to read attestations.json if it exists
-			attestationsFilename := filepath.Join(args.Config.WorkDir, args.Rel, "attestations.json")
-			attestations, err := attestationsjson.ReadFile(attestationsFilename)
-			if err != nil {
-				log.Fatalf("reading %s/attestations.json: %v", args.Rel, err)
-			}
+		if attestations != nil {
 			module.Attestations = attestations
 			attestationsRule = makeModuleAttestationsRule(attestations, "attestations.json")
+			attestationsRule.SetPrivateAttr(versionDirPrivateAttr, filepath.Join(args.Config.WorkDir, args.Rel))
 			rules = append(rules, attestationsRule)
+
+			if !shadowed {
+				ext.moduleAttestationsRules[newModuleID(module.Name, module.Version)] = newProtoRule(attestationsRule, attestations)
+			}
 		}
 
 		if slices.Contains(args.RegularFiles, "presubmit.yml") {
@@ -406,37 +593,39 @@ func (ext *bcrExtension) GenerateRules(args language.GenerateArgs) language.Gene
 			presubmitFilename := filepath.Join(args.Config.WorkDir, args.Rel, "presubmit.yml")
 			presubmit, err := readPresubmitYaml(presubmitFilename)
 			if err != nil {
-				log.Printf("reading %s/presubmit.yml: %v", args.Rel, err)
+				ext.diagnostics.add(reg.modulesRoot, args.Rel, "presubmit.yml", bzpb.DiagnosticSeverity_WARNING, err)
 			}
 			module.Presubmit = presubmit
 			presubmitRule = makeModulePresubmitRule(presubmit, "presubmit.yml")
 			rules = append(rules, presubmitRule)
 		}
 
-		// Add module to all dependency graphs
 		id := newModuleID(module.Name, version)
-		ext.addModuleToGraph(module.Name, version)
-		_ = ext.regularDepGraph.AddVertex(id)
-		_ = ext.devDepGraph.AddVertex(id)
-
-		// Add dependency edges to graphs
-		for _, dep := range module.Deps {
-			// Add to main graph (all deps - for cycle detection)
-			ext.addDependencyEdge(module.Name, version, dep.Name, dep.Version)
-
-			fromKey := newModuleID(module.Name, version)
-			toKey := newModuleID(dep.Name, dep.Version)
-
-			if dep.Dev {
-				// Add to dev graph only
-				_ = ext.devDepGraph.AddVertex(fromKey)
-				_ = ext.devDepGraph.AddVertex(toKey)
-				_ = ext.devDepGraph.AddEdge(fromKey, toKey)
-			} else {
-				// Add to regular graph only
-				_ = ext.regularDepGraph.AddVertex(fromKey)
-				_ = ext.regularDepGraph.AddVertex(toKey)
-				_ = ext.regularDepGraph.AddEdge(fromKey, toKey)
+		if !shadowed {
+			// Add module to all dependency graphs
+			ext.addModuleToGraph(module.Name, version)
+			_ = ext.regularDepGraph.AddVertex(id)
+			_ = ext.devDepGraph.AddVertex(id)
+
+			// Add dependency edges to graphs
+			for _, dep := range module.Deps {
+				// Add to main graph (all deps - for cycle detection)
+				ext.addDependencyEdge(module.Name, version, dep.Name, dep.Version)
+
+				fromKey := newModuleID(module.Name, version)
+				toKey := newModuleID(dep.Name, dep.Version)
+
+				if dep.Dev {
+					// Add to dev graph only
+					_ = ext.devDepGraph.AddVertex(fromKey)
+					_ = ext.devDepGraph.AddVertex(toKey)
+					_ = ext.devDepGraph.AddEdge(fromKey, toKey)
+				} else {
+					// Add to regular graph only
+					_ = ext.regularDepGraph.AddVertex(fromKey)
+					_ = ext.regularDepGraph.AddVertex(toKey)
+					_ = ext.regularDepGraph.AddEdge(fromKey, toKey)
+				}
 			}
 		}
 
@@ -453,7 +642,14 @@ func (ext *bcrExtension) GenerateRules(args language.GenerateArgs) language.Gene
 		rules = append(rules, moduleVersionRule)
 
 		// Track the module_version rule for later MVS annotation
-		ext.moduleVersionRules[id] = newProtoRule(moduleVersionRule, module)
+		if !shadowed {
+			ext.moduleVersionRules[id] = newProtoRule(moduleVersionRule, module)
+		}
+
+		if !regenerate {
+			ext.trackUnchangedModuleVersion(id, args.Rel, existing)
+			return language.GenerateResult{}
+		}
 
 		// Generate bazel_version rule for Bazel pseudo-modules
 		if module.Name == "bazel" {
@@ -473,6 +669,16 @@ func (ext *bcrExtension) GenerateRules(args language.GenerateArgs) language.Gene
 	}
 }
 
+// mustGetRegistryURL returns the registry url of the directory configuration
+// or the --registry-url flag.
+func (ext *bcrExtension) mustGetRegistryURL(cfg *Config) string {
+	registryURL := cmp.Or(cfg.registryURL, ext.registryURL)
+	if registryURL == "" {
+		log.Fatalf("--registry-url or the %s directive is required", registryURLDirective)
+	}
+	return registryURL
+}
+
 func inOverlayDir(rel string) bool {
 	return strings.Contains(rel, "/overlay")
 }
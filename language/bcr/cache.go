@@ -0,0 +1,135 @@
+package bcr
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/cachestore"
+)
+
+// readCacheFile reads the cache store given by --cache-file and preloads the
+// repository metadata and Bazel releases.  Expired repository metadata is
+// not preloaded, so it is fetched again.  In offline mode, the entries never
+// expire.
+func (ext *bcrExtension) readCacheFile() {
+	ttls := ext.cacheTTLs
+	if ext.offline {
+		ttls = cachestore.TTLs{}
+	}
+	ext.cache = cachestore.New(ttls)
+
+	if ext.cacheFile == "" {
+		return
+	}
+
+	filename := os.ExpandEnv(ext.cacheFile)
+	cache, err := cachestore.ReadFile(filename, ttls)
+	if err != nil {
+		log.Printf("warning: could not read cache: %v", err)
+		return
+	}
+	ext.cache = cache
+
+	expired := 0
+	for _, entry := range cache.Entries(bzpb.CacheEntryType_CACHE_ENTRY_TYPE_UNKNOWN) {
+		if cache.Expired(entry) {
+			expired++
+		}
+		switch entry.Type {
+		case bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA:
+			if !cache.Expired(entry) {
+				md := entry.GetRepositoryMetadata()
+				ext.repositoriesMetadataByID[formatRepositoryID(md)] = md
+			}
+		case bzpb.CacheEntryType_CACHE_ENTRY_BAZEL_RELEASE:
+			// releases do not change, the TTL only controls how often the
+			// list of releases is refetched
+			release := entry.GetBazelRelease()
+			ext.bazelReleasesByVersion[release.Version] = release
+		}
+	}
+
+	log.Printf("Loaded %d cache entries from %s (%d expired)", cache.Len(), filename, expired)
+}
+
+// cachedResourceStatus returns the cached status of the url, unless it has
+// expired.
+func (ext *bcrExtension) cachedResourceStatus(url string) (*bzpb.ResourceStatus, bool) {
+	entry := ext.cache.Get(bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, url)
+	if entry == nil {
+		return nil, false
+	}
+	return entry.GetResourceStatus(), true
+}
+
+// hasExpiredCacheEntry reports whether the cache has an entry of the given
+// type and key that has expired.  Unchanged modules refresh such entries in
+// incremental mode (see skipsNetworkLookup).
+func (ext *bcrExtension) hasExpiredCacheEntry(typ bzpb.CacheEntryType, key string) bool {
+	entry := ext.cache.Lookup(typ, key)
+	return entry != nil && ext.cache.Expired(entry)
+}
+
+// hasFreshBazelReleases reports whether the cached Bazel releases are recent
+// enough to skip refetching them.
+func (ext *bcrExtension) hasFreshBazelReleases() bool {
+	entries := ext.cache.Entries(bzpb.CacheEntryType_CACHE_ENTRY_BAZEL_RELEASE)
+	if len(entries) == 0 {
+		return false
+	}
+	for _, entry := range entries {
+		if ext.cache.Expired(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeCacheFile adds the repository metadata and Bazel releases fetched in
+// this run to the cache store, and writes it back to the file it was loaded
+// from.  Only writes if there are new entries.
+func (ext *bcrExtension) writeCacheFile() error {
+	if ext.cacheFile == "" {
+		// No file was specified, so nothing to write
+		return nil
+	}
+
+	if ext.fetchedRepositoryMetadata {
+		for _, id := range slices.Sorted(maps.Keys(ext.repositoriesMetadataByID)) {
+			md := ext.repositoriesMetadataByID[id]
+			// skip the repositories that were not fetched, or preloaded
+			// from the cache
+			if md.Languages == nil {
+				continue
+			}
+			if entry := ext.cache.Get(bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA, string(id)); entry != nil && entry.GetRepositoryMetadata() == md {
+				continue
+			}
+			ext.cache.Put(cachestore.RepositoryMetadataEntry(string(id), md))
+		}
+	}
+
+	if ext.fetchedBazelReleases {
+		for _, version := range slices.Sorted(maps.Keys(ext.bazelReleasesByVersion)) {
+			ext.cache.Put(cachestore.BazelReleaseEntry(ext.bazelReleasesByVersion[version]))
+		}
+	}
+
+	filename := os.ExpandEnv(ext.cacheFile)
+	if !ext.cache.Modified() {
+		// Nothing new was fetched, don't overwrite the cache
+		log.Printf("No new cache entries, skipping write to %s", filename)
+		return nil
+	}
+
+	if err := ext.cache.WriteFile(filename); err != nil {
+		return fmt.Errorf("failed to write cache file %s: %w", filename, err)
+	}
+
+	log.Printf("Wrote %d cache entries to %s", ext.cache.Len(), filename)
+	return nil
+}
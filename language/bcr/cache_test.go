@@ -0,0 +1,158 @@
+package bcr
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/cachestore"
+)
+
+func TestCacheFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.json")
+	old := time.Now().Add(-30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	store := cachestore.New(nil)
+	for _, entry := range []*bzpb.CacheEntry{
+		cachestore.RepositoryMetadataEntry("github:org/fresh", &bzpb.RepositoryMetadata{
+			Type: bzpb.RepositoryType_GITHUB, Organization: "org", Name: "fresh", Stargazers: 1, Languages: map[string]int32{},
+		}),
+		cachestore.RepositoryMetadataEntry("github:org/stale", &bzpb.RepositoryMetadata{
+			Type: bzpb.RepositoryType_GITHUB, Organization: "org", Name: "stale", Stargazers: 1, Languages: map[string]int32{},
+		}),
+		cachestore.ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com/404", Code: 404}),
+		cachestore.BazelReleaseEntry(&bzpb.BazelRelease{Version: "8.0.0"}),
+	} {
+		if entry.Key == "github:org/stale" || entry.Key == "https://example.com/404" {
+			entry.FetchedAt = old
+		}
+		store.Put(entry)
+	}
+	if err := store.WriteFile(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	ext := NewLanguage().(*bcrExtension)
+	ext.cacheFile = filename
+	ext.readCacheFile()
+
+	if _, ok := ext.repositoriesMetadataByID["github:org/fresh"]; !ok {
+		t.Error("expected the fresh repository metadata to be preloaded")
+	}
+	if _, ok := ext.repositoriesMetadataByID["github:org/stale"]; ok {
+		t.Error("expected the expired repository metadata to be fetched again")
+	}
+	if _, ok := ext.cachedResourceStatus("https://example.com/404"); ok {
+		t.Error("expected the failed url check to have expired")
+	}
+	if _, ok := ext.bazelReleasesByVersion["8.0.0"]; !ok || !ext.hasFreshBazelReleases() {
+		t.Error("expected the Bazel releases to be preloaded")
+	}
+
+	// nothing was fetched
+	if err := ext.writeCacheFile(); err != nil {
+		t.Fatal(err)
+	}
+	if ext.cache.Modified() {
+		t.Error("expected the cache not to be modified")
+	}
+
+	// refetch the stale repository
+	ext.repositoriesMetadataByID["github:org/stale"] = &bzpb.RepositoryMetadata{
+		Type: bzpb.RepositoryType_GITHUB, Organization: "org", Name: "stale", Stargazers: 2, Languages: map[string]int32{},
+	}
+	ext.repositoriesMetadataByID["github:org/unfetched"] = &bzpb.RepositoryMetadata{
+		Type: bzpb.RepositoryType_GITHUB, Organization: "org", Name: "unfetched",
+	}
+	ext.fetchedRepositoryMetadata = true
+	if err := ext.writeCacheFile(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cachestore.ReadFile(filename, cachestore.DefaultTTLs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Len() != 4 {
+		t.Errorf("expected 4 cache entries, got %d", got.Len())
+	}
+	if entry := got.Get(bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA, "github:org/stale"); entry == nil || entry.GetRepositoryMetadata().Stargazers != 2 {
+		t.Errorf("expected the refetched repository metadata, got %v", entry)
+	}
+	if entry := got.Get(bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA, "github:org/fresh"); entry == nil || entry.GetRepositoryMetadata().Stargazers != 1 {
+		t.Errorf("expected the preloaded repository metadata to be kept, got %v", entry)
+	}
+
+	// in offline mode, nothing expires
+	offline := NewLanguage().(*bcrExtension)
+	offline.cacheFile = filename
+	offline.offline = true
+	offline.readCacheFile()
+	if _, ok := offline.cachedResourceStatus("https://example.com/404"); !ok {
+		t.Error("expected expired entries to be used in offline mode")
+	}
+}
+
+func TestIncrementalCacheExpiry(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.json")
+	old := time.Now().Add(-30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	store := cachestore.New(nil)
+	for _, entry := range []*bzpb.CacheEntry{
+		cachestore.RepositoryMetadataEntry("github:org/stale", &bzpb.RepositoryMetadata{
+			Type: bzpb.RepositoryType_GITHUB, Organization: "org", Name: "stale", Stargazers: 1, Languages: map[string]int32{},
+		}),
+		cachestore.RepositoryMetadataEntry("github:org/optout", &bzpb.RepositoryMetadata{
+			Type: bzpb.RepositoryType_GITHUB, Organization: "org", Name: "optout", Stargazers: 1, Languages: map[string]int32{},
+		}),
+		cachestore.ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com/stale.tar.gz", Code: 200}),
+		cachestore.ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com/fresh.tar.gz", Code: 200}),
+	} {
+		if entry.Key != "https://example.com/fresh.tar.gz" {
+			entry.FetchedAt = old
+		}
+		store.Put(entry)
+	}
+	if err := store.WriteFile(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	ext := NewLanguage().(*bcrExtension)
+	ext.cacheFile = filename
+	ext.readCacheFile()
+	// rules_foo and rules_optout did not change, rules_optout opts out of
+	// network access
+	ext.incremental = true
+	ext.changedModules = map[moduleName]bool{"rules_bar": true}
+	ext.applyModuleConfig(&Config{}, "rules_foo")
+	ext.applyModuleConfig(&Config{skipNetwork: true}, "rules_optout")
+	ext.trackSkipNetworkRepositories([]string{"github:org/stale", "github:org/uncached"}, true, false)
+	ext.trackSkipNetworkRepositories([]string{"github:org/optout"}, true, true)
+
+	for _, tc := range []struct {
+		url  string
+		id   moduleID
+		want bool
+	}{
+		{url: "https://example.com/stale.tar.gz", id: "rules_foo@1.0.0", want: false},
+		{url: "https://example.com/fresh.tar.gz", id: "rules_foo@1.0.0", want: true},
+		{url: "https://example.com/uncached.tar.gz", id: "rules_foo@1.0.0", want: true},
+		{url: "https://example.com/stale.tar.gz", id: "rules_optout@1.0.0", want: true},
+		{url: "https://example.com/uncached.tar.gz", id: "rules_bar@1.0.0", want: false},
+	} {
+		if got := ext.skipsNetworkLookup(bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, tc.url, []moduleID{tc.id}); got != tc.want {
+			t.Errorf("skipsNetworkLookup(%s, %s) = %v, want %v", tc.url, tc.id, got, tc.want)
+		}
+	}
+
+	// the expired repository metadata of unchanged modules is fetched again
+	var todo []*bzpb.RepositoryMetadata
+	for _, name := range []string{"stale", "uncached", "optout"} {
+		todo = append(todo, &bzpb.RepositoryMetadata{Type: bzpb.RepositoryType_GITHUB, Organization: "org", Name: name})
+	}
+	todo = ext.filterSkipNetworkRepositories(todo)
+	if len(todo) != 1 || todo[0].Name != "stale" {
+		t.Errorf("expected only the expired repository metadata to be fetched, got %v", todo)
+	}
+}
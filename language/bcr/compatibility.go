@@ -0,0 +1,94 @@
+package bcr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// compatibilityIndex records the compatibility_level of every module version
+// and the max_compatibility_level of every dependency edge.  It is built once
+// before MVS and shared (read-only) by the MVS workers.
+type compatibilityIndex struct {
+	levels    map[moduleID]int32
+	maxLevels map[moduleID]map[moduleName]int32
+}
+
+// newCompatibilityIndex builds a compatibilityIndex from the tracked
+// module_version rules.
+func (ext *bcrExtension) newCompatibilityIndex() *compatibilityIndex {
+	ci := &compatibilityIndex{
+		levels:    make(map[moduleID]int32),
+		maxLevels: make(map[moduleID]map[moduleName]int32),
+	}
+	for id, protoRule := range ext.moduleVersionRules {
+		module := protoRule.Proto()
+		ci.levels[id] = module.CompatibilityLevel
+		for _, dep := range module.Deps {
+			if dep.MaxCompatibilityLevel == 0 {
+				continue
+			}
+			if ci.maxLevels[id] == nil {
+				ci.maxLevels[id] = make(map[moduleName]int32)
+			}
+			ci.maxLevels[id][moduleName(dep.Name)] = dep.MaxCompatibilityLevel
+		}
+	}
+	return ci
+}
+
+// level returns the compatibility level of the given module version (0 if
+// unknown).
+func (ci *compatibilityIndex) level(id moduleID) int32 {
+	if ci == nil {
+		return 0
+	}
+	return ci.levels[id]
+}
+
+// maxLevel returns the max_compatibility_level declared by from for its
+// dependency on the named module (0 if not set).
+func (ci *compatibilityIndex) maxLevel(from moduleID, name moduleName) int32 {
+	if ci == nil {
+		return 0
+	}
+	return ci.maxLevels[from][name]
+}
+
+// resolutionError describes why bazel would fail to resolve the dependency
+// graph rooted at a module version.
+type resolutionError struct {
+	conflicts []*compatibilityConflict
+}
+
+// compatibilityConflict is a module that is required at more than one
+// compatibility level.
+type compatibilityConflict struct {
+	moduleName   moduleName
+	requirements []*compatibilityRequirement
+}
+
+// compatibilityRequirement is the path that pulled in a module at a given
+// compatibility level.
+type compatibilityRequirement struct {
+	level   int32
+	version moduleVersion
+	path    []moduleID
+}
+
+// toStringList encodes the requirements of all conflicts as a list of
+// strings suitable for the "resolution_conflicts" attribute, one entry per
+// requirement.  Example: "a@1.0 -> b@2.0 -> c@2.0 (compatibility_level=2)".
+// The format is parsed by the moduleversioncompiler.
+func (e *resolutionError) toStringList() []string {
+	var list []string
+	for _, c := range e.conflicts {
+		for _, req := range c.requirements {
+			path := make([]string, len(req.path))
+			for i, id := range req.path {
+				path[i] = string(id)
+			}
+			list = append(list, fmt.Sprintf("%s (compatibility_level=%d)", strings.Join(path, " -> "), req.level))
+		}
+	}
+	return list
+}
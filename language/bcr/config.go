@@ -1,16 +1,58 @@
 package bcr
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"log"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/bazelbuild/bazel-gazelle/config"
+	"github.com/bazelbuild/bazel-gazelle/rule"
 )
 
+const (
+	// excludeModuleDirective skips rule generation for the named module
+	// (repeatable).  Example: "# gazelle:bcr_exclude_module rules_foo"
+	excludeModuleDirective = "bcr_exclude_module"
+	// docsMaxVersionsDirective limits the docs archives fetched for a module
+	// to its N most recent versions (0 = unlimited).
+	// Example: "# gazelle:bcr_docs_max_versions 3"
+	docsMaxVersionsDirective = "bcr_docs_max_versions"
+	// skipNetworkDirective disables URL status checks and repository
+	// metadata fetches for modules in this directory (cached data is still
+	// used).  Example: "# gazelle:bcr_skip_network true"
+	skipNetworkDirective = "bcr_skip_network"
+	// blacklistedUrlDirective blacklists a URL referenced by modules in this
+	// directory, like --blacklisted_url (repeatable).
+	// Example: "# gazelle:bcr_blacklisted_url https://example.com/foo.tar.gz"
+	blacklistedUrlDirective = "bcr_blacklisted_url"
+	// registryURLDirective overrides --registry-url.
+	// Example: "# gazelle:bcr_registry_url https://bcr.example.com"
+	registryURLDirective = "bcr_registry_url"
+)
+
+// knownDirectives lists the directives understood by the bcr extension
+var knownDirectives = []string{
+	excludeModuleDirective,
+	docsMaxVersionsDirective,
+	skipNetworkDirective,
+	blacklistedUrlDirective,
+	registryURLDirective,
+}
+
 // Config represents the config extension for the a bcr package.
 type Config struct {
-	config  *config.Config
-	enabled bool
+	config          *config.Config
+	enabled         bool
+	excludeModules  map[string]bool // module names to skip
+	docsMaxVersions int             // max number of versions per module to fetch docs for (0 = unlimited)
+	skipNetwork     bool            // skip network access for modules in this directory
+	blacklistedUrls stringBoolMap   // urls blacklisted via directive (in addition to --blacklisted_url)
+	registryURL     string          // overrides --registry-url, if set
 }
 
 // createConfig initializes a new Config.
@@ -47,9 +89,80 @@ func getOrCreateConfig(config *config.Config) *Config {
 func (c *Config) clone(config *config.Config) *Config {
 	clone := createConfig(config)
 	clone.enabled = c.enabled
+	clone.excludeModules = maps.Clone(c.excludeModules)
+	clone.docsMaxVersions = c.docsMaxVersions
+	clone.skipNetwork = c.skipNetwork
+	clone.blacklistedUrls = maps.Clone(c.blacklistedUrls)
+	clone.registryURL = c.registryURL
 	return clone
 }
 
+// parseDirectives applies the bcr directives of the given BUILD file.
+// Malformed directives are logged and ignored.
+func (c *Config) parseDirectives(rel string, directives []rule.Directive) {
+	for _, d := range directives {
+		value := strings.TrimSpace(d.Value)
+		switch d.Key {
+		case excludeModuleDirective:
+			if value == "" {
+				log.Printf("WARN: %s: %s directive requires a module name", rel, d.Key)
+				continue
+			}
+			if c.excludeModules == nil {
+				c.excludeModules = make(map[string]bool)
+			}
+			c.excludeModules[value] = true
+		case docsMaxVersionsDirective:
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				log.Printf("WARN: %s: invalid %s directive %q (want a non-negative integer)", rel, d.Key, value)
+				continue
+			}
+			c.docsMaxVersions = n
+		case skipNetworkDirective:
+			if value == "" {
+				c.skipNetwork = true
+				continue
+			}
+			skip, err := strconv.ParseBool(value)
+			if err != nil {
+				log.Printf("WARN: %s: invalid %s directive %q (want true or false)", rel, d.Key, value)
+				continue
+			}
+			c.skipNetwork = skip
+		case blacklistedUrlDirective:
+			if value == "" {
+				log.Printf("WARN: %s: %s directive requires a URL", rel, d.Key)
+				continue
+			}
+			c.blacklistedUrls.Set(value)
+		case registryURLDirective:
+			c.registryURL = value
+		}
+	}
+}
+
+// fingerprint returns a digest of the bcr settings of this config.
+func (c *Config) fingerprint() string {
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(c.excludeModules)) {
+		fmt.Fprintf(h, "%s=%s\n", excludeModuleDirective, name)
+	}
+	for _, url := range slices.Sorted(maps.Keys(c.blacklistedUrls)) {
+		fmt.Fprintf(h, "%s=%s\n", blacklistedUrlDirective, url)
+	}
+	fmt.Fprintf(h, "%s=%d\n", docsMaxVersionsDirective, c.docsMaxVersions)
+	fmt.Fprintf(h, "%s=%t\n", skipNetworkDirective, c.skipNetwork)
+	fmt.Fprintf(h, "%s=%s\n", registryURLDirective, c.registryURL)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isExcludedModule reports whether rule generation is disabled for the named
+// module.
+func (c *Config) isExcludedModule(name string) bool {
+	return c.excludeModules[name]
+}
+
 // Config returns the parent gazelle configuration
 func (c *Config) Config() *config.Config {
 	return c.config
@@ -76,3 +189,16 @@ func (m *stringBoolMap) Set(value string) error {
 	(*m)[value] = true
 	return nil
 }
+
+// stringSlice is a custom flag type for repeatable string flags that keeps the
+// order of the values
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSlice) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
@@ -0,0 +1,75 @@
+package bcr
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bazelbuild/bazel-gazelle/config"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+)
+
+func TestParseDirectives(t *testing.T) {
+	c := config.New()
+	parent := getOrCreateConfig(c)
+	parent.parseDirectives("modules", []rule.Directive{
+		{Key: excludeModuleDirective, Value: "rules_foo"},
+		{Key: docsMaxVersionsDirective, Value: "3"},
+		{Key: blacklistedUrlDirective, Value: "https://example.com/a.tar.gz"},
+		{Key: registryURLDirective, Value: "https://bcr.example.com"},
+	})
+
+	child := getOrCreateConfig(c)
+	child.parseDirectives("modules/rules_bar", []rule.Directive{
+		{Key: excludeModuleDirective, Value: "rules_baz"},
+		{Key: docsMaxVersionsDirective, Value: "invalid"},
+		{Key: skipNetworkDirective, Value: ""},
+		{Key: blacklistedUrlDirective, Value: "https://example.com/b.tar.gz"},
+	})
+
+	if !child.isExcludedModule("rules_foo") || !child.isExcludedModule("rules_baz") {
+		t.Errorf("expected child to exclude rules_foo and rules_baz, got %v", child.excludeModules)
+	}
+	if parent.isExcludedModule("rules_baz") {
+		t.Errorf("child directive leaked into parent config")
+	}
+	if child.docsMaxVersions != 3 {
+		t.Errorf("docsMaxVersions = %d, want 3 (invalid directive ignored)", child.docsMaxVersions)
+	}
+	if !child.skipNetwork || parent.skipNetwork {
+		t.Errorf("skipNetwork = %v (parent %v), want true (parent false)", child.skipNetwork, parent.skipNetwork)
+	}
+	if !child.blacklistedUrls["https://example.com/a.tar.gz"] || !child.blacklistedUrls["https://example.com/b.tar.gz"] {
+		t.Errorf("expected inherited and own blacklisted urls, got %v", child.blacklistedUrls)
+	}
+	if parent.blacklistedUrls["https://example.com/b.tar.gz"] {
+		t.Errorf("child blacklisted url leaked into parent config")
+	}
+	if child.registryURL != "https://bcr.example.com" {
+		t.Errorf("registryURL = %q, want inherited value", child.registryURL)
+	}
+}
+
+func TestModuleNameFromRel(t *testing.T) {
+	for rel, want := range map[string]string{
+		"registry/modules":                 "",
+		"registry/modules/rules_foo":       "rules_foo",
+		"registry/modules/rules_foo/1.0.0": "rules_foo",
+		"registry":                         "",
+		"registry/modulesx/rules_foo":      "",
+	} {
+		got, ok := moduleNameFromRel("registry/modules", rel)
+		if string(got) != want || ok != (want != "") {
+			t.Errorf("moduleNameFromRel(%q) = %q, %v, want %q", rel, got, ok, want)
+		}
+	}
+}
+
+func TestMostRecentVersions(t *testing.T) {
+	got := mostRecentVersions([]string{"1.0.0", "1.10.0", "1.2.0", "0.9.0"}, 2)
+	if want := []string{"1.10.0", "1.2.0"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := mostRecentVersions([]string{"1.0.0"}, 5); !slices.Equal(got, []string{"1.0.0"}) {
+		t.Errorf("got %v, want [1.0.0]", got)
+	}
+}
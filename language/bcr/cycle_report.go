@@ -0,0 +1,232 @@
+package bcr
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+	"github.com/dominikbraun/graph"
+)
+
+// minimalCyclePaths returns the shortest cycle through each member of the
+// strongly connected component.  Cycles are closed (the first and last
+// elements are the same) and rotated to start at their smallest member, so a
+// cycle passing through several members is only reported once.  The result
+// is sorted by length, then lexically.
+func minimalCyclePaths(scc []moduleID, adjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID]) [][]moduleID {
+	members := make(map[moduleID]bool, len(scc))
+	for _, id := range scc {
+		members[id] = true
+	}
+
+	seen := make(map[string]bool)
+	var paths [][]moduleID
+
+	for _, start := range slices.SortedFunc(slices.Values(scc), compareModuleIDs) {
+		cycle := shortestCycleThrough(start, members, adjacencyMap)
+		if cycle == nil {
+			continue
+		}
+		cycle = rotateCycle(cycle)
+		key := joinModuleIDs(cycle)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		paths = append(paths, cycle)
+	}
+
+	slices.SortFunc(paths, func(a, b []moduleID) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return strings.Compare(joinModuleIDs(a), joinModuleIDs(b))
+	})
+
+	return paths
+}
+
+// shortestCycleThrough finds the shortest closed path start -> ... -> start
+// using only edges between members (breadth-first, visiting neighbors in
+// sorted order for determinism).  Returns nil if there is none.
+func shortestCycleThrough(start moduleID, members map[moduleID]bool, adjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID]) []moduleID {
+	parents := make(map[moduleID]moduleID)
+	visited := map[moduleID]bool{start: true}
+	queue := []moduleID{start}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, target := range slices.SortedFunc(maps.Keys(adjacencyMap[id]), compareModuleIDs) {
+			if !members[target] {
+				continue
+			}
+			if target == start {
+				return append(pathToModule(id, parents), start)
+			}
+			if visited[target] {
+				continue
+			}
+			visited[target] = true
+			parents[target] = id
+			queue = append(queue, target)
+		}
+	}
+
+	return nil
+}
+
+// rotateCycle rotates a closed cycle so that it starts (and ends) at its
+// smallest member.
+func rotateCycle(cycle []moduleID) []moduleID {
+	open := cycle[:len(cycle)-1]
+	minIndex := 0
+	for i, id := range open {
+		if compareModuleIDs(id, open[minIndex]) < 0 {
+			minIndex = i
+		}
+	}
+	rotated := make([]moduleID, 0, len(cycle))
+	rotated = append(rotated, open[minIndex:]...)
+	rotated = append(rotated, open[:minIndex]...)
+	return append(rotated, open[minIndex])
+}
+
+// isDevOnlyComponent reports whether the strongly connected component is
+// acyclic when only regular (non-dev) dependency edges are considered.
+func isDevOnlyComponent(scc []moduleID, regularAdjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID]) bool {
+	members := make(map[moduleID]bool, len(scc))
+	for _, id := range scc {
+		members[id] = true
+	}
+
+	// Kahn's algorithm on the regular subgraph: if every member can be
+	// removed, there is no regular cycle.
+	inDegree := make(map[moduleID]int, len(scc))
+	for _, id := range scc {
+		for target := range regularAdjacencyMap[id] {
+			if members[target] {
+				inDegree[target]++
+			}
+		}
+	}
+	var queue []moduleID
+	for _, id := range scc {
+		if inDegree[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+	removed := 0
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		removed++
+		for target := range regularAdjacencyMap[id] {
+			if !members[target] {
+				continue
+			}
+			inDegree[target]--
+			if inDegree[target] == 0 {
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	return removed == len(scc)
+}
+
+// makeModuleDependencyCycle builds the report entry for a single strongly
+// connected component.
+func makeModuleDependencyCycle(scc []moduleID, adjacencyMap, regularAdjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID], isLatest func(moduleID) bool) *bzpb.ModuleDependencyCycle {
+	cycle := &bzpb.ModuleDependencyCycle{
+		Name:    cycleName(scc),
+		Modules: sortedCycleModules(scc),
+		DevOnly: isDevOnlyComponent(scc, regularAdjacencyMap),
+	}
+
+	for _, path := range minimalCyclePaths(scc, adjacencyMap) {
+		cyclePath := &bzpb.ModuleDependencyCyclePath{
+			Modules: make([]string, len(path)),
+		}
+		for i, id := range path {
+			cyclePath.Modules[i] = string(id)
+			if i > 0 {
+				if _, regular := regularAdjacencyMap[path[i-1]][id]; !regular {
+					cyclePath.Dev = true
+				}
+			}
+		}
+		cycle.Paths = append(cycle.Paths, cyclePath)
+	}
+
+	for _, id := range slices.SortedFunc(slices.Values(scc), compareModuleIDs) {
+		if isLatest(id) {
+			cycle.LatestVersions = append(cycle.LatestVersions, string(id))
+		}
+	}
+
+	return cycle
+}
+
+// makeModuleDependencyCycleReport builds the cycle report for the given
+// strongly connected components.
+func makeModuleDependencyCycleReport(cycles [][]moduleID, adjacencyMap, regularAdjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID], isLatest func(moduleID) bool) *bzpb.ModuleDependencyCycleReport {
+	report := &bzpb.ModuleDependencyCycleReport{}
+	for _, scc := range cycles {
+		if len(scc) == 0 {
+			continue
+		}
+		report.Cycles = append(report.Cycles, makeModuleDependencyCycle(scc, adjacencyMap, regularAdjacencyMap, isLatest))
+	}
+	slices.SortFunc(report.Cycles, func(a, b *bzpb.ModuleDependencyCycle) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return report
+}
+
+// writeCycleReportFile writes the dependency cycle report to the file given
+// by --cycle-report-file, if any.
+func (ext *bcrExtension) writeCycleReportFile() error {
+	if ext.cycleReportFile == "" {
+		// No file was specified, so nothing to write
+		return nil
+	}
+
+	adjacencyMap, err := ext.depGraph.AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("getting adjacency map: %w", err)
+	}
+	regularAdjacencyMap, err := ext.regularDepGraph.AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("getting regular adjacency map: %w", err)
+	}
+	isLatest := func(id moduleID) bool {
+		protoRule, ok := ext.moduleVersionRules[id]
+		return ok && isLatestVersion(protoRule)
+	}
+
+	report := makeModuleDependencyCycleReport(ext.getCycles(), adjacencyMap, regularAdjacencyMap, isLatest)
+
+	filename := os.ExpandEnv(ext.cycleReportFile)
+	if err := protoutil.WriteFile(filename, report); err != nil {
+		return fmt.Errorf("failed to write cycle report file %s: %w", filename, err)
+	}
+
+	log.Printf("Wrote %d dependency cycles to %s", len(report.Cycles), filename)
+	return nil
+}
+
+// joinModuleIDs formats a path of module versions as "a@1 -> b@2 -> a@1"
+func joinModuleIDs(path []moduleID) string {
+	parts := make([]string, len(path))
+	for i, id := range path {
+		parts[i] = string(id)
+	}
+	return strings.Join(parts, " -> ")
+}
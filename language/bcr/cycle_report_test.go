@@ -0,0 +1,117 @@
+package bcr
+
+import (
+	"slices"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/dominikbraun/graph"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMinimalCyclePaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		scc   []moduleID
+		edges map[moduleID][]moduleID
+		want  []string
+	}{
+		{
+			name: "two nodes",
+			scc:  []moduleID{"b@2.0", "a@1.0"},
+			edges: map[moduleID][]moduleID{
+				"a@1.0": {"b@2.0"},
+				"b@2.0": {"a@1.0"},
+			},
+			want: []string{"a@1.0 -> b@2.0 -> a@1.0"},
+		},
+		{
+			name: "shortest cycle per member",
+			scc:  []moduleID{"a@1.0", "b@1.0", "c@1.0", "d@1.0"},
+			edges: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0"},
+				"b@1.0": {"a@1.0", "c@1.0"},
+				"c@1.0": {"d@1.0"},
+				"d@1.0": {"b@1.0", "x@1.0"},
+			},
+			want: []string{
+				"a@1.0 -> b@1.0 -> a@1.0",
+				"b@1.0 -> c@1.0 -> d@1.0 -> b@1.0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, path := range minimalCyclePaths(tt.scc, makeAdjacencyMap(tt.edges)) {
+				got = append(got, joinModuleIDs(path))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeModuleDependencyCycleReport(t *testing.T) {
+	regular := map[moduleID][]moduleID{
+		"a@1.0": {"b@1.0"},
+		"b@1.0": {"a@1.0"},
+		"c@1.0": {"d@1.0"},
+	}
+	dev := map[moduleID][]moduleID{
+		"d@1.0": {"c@1.0"},
+	}
+	all := make(map[moduleID][]moduleID)
+	for _, edges := range []map[moduleID][]moduleID{regular, dev} {
+		for from, targets := range edges {
+			all[from] = append(all[from], targets...)
+		}
+	}
+	latest := map[moduleID]bool{"b@1.0": true, "d@1.0": true}
+
+	got := makeModuleDependencyCycleReport(
+		[][]moduleID{{"d@1.0", "c@1.0"}, {"b@1.0", "a@1.0"}},
+		makeAdjacencyMap(all),
+		makeAdjacencyMap(regular),
+		func(id moduleID) bool { return latest[id] },
+	)
+
+	want := &bzpb.ModuleDependencyCycleReport{
+		Cycles: []*bzpb.ModuleDependencyCycle{
+			{
+				Name:    "a-1.0+b-1.0",
+				Modules: []string{"a@1.0", "b@1.0"},
+				Paths: []*bzpb.ModuleDependencyCyclePath{
+					{Modules: []string{"a@1.0", "b@1.0", "a@1.0"}},
+				},
+				LatestVersions: []string{"b@1.0"},
+			},
+			{
+				Name:    "c-1.0+d-1.0",
+				Modules: []string{"c@1.0", "d@1.0"},
+				Paths: []*bzpb.ModuleDependencyCyclePath{
+					{Modules: []string{"c@1.0", "d@1.0", "c@1.0"}, Dev: true},
+				},
+				DevOnly:        true,
+				LatestVersions: []string{"d@1.0"},
+			},
+		},
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// makeAdjacencyMap builds an adjacency map from a from -> to edge list.
+func makeAdjacencyMap(edges map[moduleID][]moduleID) map[moduleID]map[moduleID]graph.Edge[moduleID] {
+	adjacencyMap := make(map[moduleID]map[moduleID]graph.Edge[moduleID])
+	for from, targets := range edges {
+		adjacencyMap[from] = make(map[moduleID]graph.Edge[moduleID])
+		for _, to := range targets {
+			adjacencyMap[from][to] = graph.Edge[moduleID]{Source: from, Target: to}
+		}
+	}
+	return adjacencyMap
+}
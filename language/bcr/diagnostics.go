@@ -0,0 +1,91 @@
+package bcr
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+)
+
+// diagnostics collects the problems found in the registry data.  Broken
+// files are reported here rather than aborting the run, so one bad upstream
+// commit does not block the whole registry.
+type diagnostics []*bzpb.RegistryDiagnostic
+
+// add records a diagnostic for the file rel/filename.  The module name and
+// version are derived from rel (modules/NAME or modules/NAME/VERSION).
+func (d *diagnostics) add(modulesRoot, rel, filename string, severity bzpb.DiagnosticSeverity, err error) *bzpb.RegistryDiagnostic {
+	diagnostic := &bzpb.RegistryDiagnostic{
+		File:     path.Join(rel, filename),
+		Severity: severity,
+		Message:  err.Error(),
+	}
+	if after, ok := strings.CutPrefix(rel, modulesRoot+"/"); ok {
+		name, version, _ := strings.Cut(after, "/")
+		diagnostic.ModuleName = name
+		diagnostic.Version = version
+	}
+	*d = append(*d, diagnostic)
+
+	log.Printf("%s: %s: %s", severity, diagnostic.File, diagnostic.Message)
+	return diagnostic
+}
+
+// count returns the number of diagnostics of the given severity.
+func (d diagnostics) count(severity bzpb.DiagnosticSeverity) (n int) {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == severity {
+			n++
+		}
+	}
+	return
+}
+
+// report returns the diagnostics sorted by file, then message.
+func (d diagnostics) report() *bzpb.RegistryDiagnosticReport {
+	sorted := slices.Clone(d)
+	slices.SortStableFunc(sorted, func(a, b *bzpb.RegistryDiagnostic) int {
+		return cmp.Or(strings.Compare(a.File, b.File), strings.Compare(a.Message, b.Message))
+	})
+	return &bzpb.RegistryDiagnosticReport{Diagnostics: sorted}
+}
+
+// reportBrokenModuleVersion records an error for a file of a module version
+// and marks the version as unresolved, so it is skipped like a missing
+// module version.
+func (ext *bcrExtension) reportBrokenModuleVersion(modulesRoot, rel, filename string, err error) {
+	diagnostic := ext.diagnostics.add(modulesRoot, rel, filename, bzpb.DiagnosticSeverity_ERROR, err)
+	if diagnostic.ModuleName != "" && diagnostic.Version != "" {
+		ext.unresolvedModules[newModuleID(diagnostic.ModuleName, diagnostic.Version)] = true
+	}
+}
+
+// writeDiagnosticsReportFile writes the diagnostics to the file given by
+// --diagnostics-report-file, if any.
+func (ext *bcrExtension) writeDiagnosticsReportFile() error {
+	if n := len(ext.diagnostics); n > 0 {
+		log.Printf("Found %d problems in the registry data (%d errors, %d warnings)", n,
+			ext.diagnostics.count(bzpb.DiagnosticSeverity_ERROR), ext.diagnostics.count(bzpb.DiagnosticSeverity_WARNING))
+	}
+
+	if ext.diagnosticsReportFile == "" {
+		// No file was specified, so nothing to write
+		return nil
+	}
+
+	report := ext.diagnostics.report()
+
+	filename := os.ExpandEnv(ext.diagnosticsReportFile)
+	if err := protoutil.WriteFile(filename, report); err != nil {
+		return fmt.Errorf("failed to write diagnostics report file %s: %w", filename, err)
+	}
+
+	log.Printf("Wrote %d diagnostics to %s", len(report.Diagnostics), filename)
+	return nil
+}
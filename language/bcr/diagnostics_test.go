@@ -0,0 +1,107 @@
+package bcr
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+)
+
+func TestDiagnosticsAdd(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rel         string
+		wantModule  string
+		wantVersion string
+	}{
+		"module version": {
+			rel:         "data/bcr/modules/rules_foo/1.0.0",
+			wantModule:  "rules_foo",
+			wantVersion: "1.0.0",
+		},
+		"module": {
+			rel:        "data/bcr/modules/rules_foo",
+			wantModule: "rules_foo",
+		},
+		"outside modules root": {
+			rel: "data/other/modules/rules_foo/1.0.0",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var d diagnostics
+			got := d.add("data/bcr/modules", tc.rel, "source.json", bzpb.DiagnosticSeverity_ERROR, errors.New("unexpected EOF"))
+			if got.File != tc.rel+"/source.json" {
+				t.Errorf("file = %q, want %q", got.File, tc.rel+"/source.json")
+			}
+			if got.ModuleName != tc.wantModule || got.Version != tc.wantVersion {
+				t.Errorf("module = %s@%s, want %s@%s", got.ModuleName, got.Version, tc.wantModule, tc.wantVersion)
+			}
+			if got.Message != "unexpected EOF" {
+				t.Errorf("message = %q", got.Message)
+			}
+			if len(d) != 1 {
+				t.Errorf("expected 1 diagnostic, got %d", len(d))
+			}
+		})
+	}
+}
+
+func TestDiagnosticsReport(t *testing.T) {
+	var d diagnostics
+	d.add("modules", "modules/b/1.0", "source.json", bzpb.DiagnosticSeverity_ERROR, errors.New("bad json"))
+	d.add("modules", "modules/a/1.0", "presubmit.yml", bzpb.DiagnosticSeverity_WARNING, errors.New("bad yaml"))
+	d.add("modules", "modules/a/1.0", "presubmit.yml", bzpb.DiagnosticSeverity_WARNING, errors.New("another"))
+
+	if n := d.count(bzpb.DiagnosticSeverity_WARNING); n != 2 {
+		t.Errorf("count(WARNING) = %d, want 2", n)
+	}
+	if n := d.count(bzpb.DiagnosticSeverity_ERROR); n != 1 {
+		t.Errorf("count(ERROR) = %d, want 1", n)
+	}
+
+	report := d.report()
+	var got []string
+	for _, diagnostic := range report.Diagnostics {
+		got = append(got, diagnostic.File+": "+diagnostic.Message)
+	}
+	want := []string{
+		"modules/a/1.0/presubmit.yml: another",
+		"modules/a/1.0/presubmit.yml: bad yaml",
+		"modules/b/1.0/source.json: bad json",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("report() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("report()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if d[0].File != "modules/b/1.0/source.json" {
+		t.Error("report() should not reorder the collected diagnostics")
+	}
+}
+
+func TestReportBrokenModuleVersion(t *testing.T) {
+	ext := &bcrExtension{
+		unresolvedModules:     make(map[moduleID]bool),
+		diagnosticsReportFile: filepath.Join(t.TempDir(), "diagnostics.json"),
+	}
+	ext.reportBrokenModuleVersion("modules", "modules/rules_foo/1.0.0", "MODULE.bazel", errors.New("syntax error"))
+
+	if !ext.unresolvedModules["rules_foo@1.0.0"] {
+		t.Error("expected rules_foo@1.0.0 to be marked unresolved")
+	}
+
+	if err := ext.writeDiagnosticsReportFile(); err != nil {
+		t.Fatal(err)
+	}
+	var report bzpb.RegistryDiagnosticReport
+	if err := protoutil.ReadFile(ext.diagnosticsReportFile, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Diagnostics) != 1 || report.Diagnostics[0].Severity != bzpb.DiagnosticSeverity_ERROR {
+		t.Errorf("unexpected report: %v", report.Diagnostics)
+	}
+}
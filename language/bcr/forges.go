@@ -0,0 +1,165 @@
+package bcr
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/bitbucket"
+	"github.com/bazel-contrib/bcr-frontend/pkg/gitea"
+	"github.com/bazel-contrib/bcr-frontend/pkg/srht"
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentForgeRequests limits the number of requests sent in parallel
+// to the forges that have no batch API (Gitea, Bitbucket, and GitLab for
+// commit SHAs).
+const maxConcurrentForgeRequests = 4
+
+// filterForgeRepositories returns the repositories of the given type that
+// have no metadata yet.
+func filterForgeRepositories(repositories map[repositoryID]*bzpb.RepositoryMetadata, repositoryType bzpb.RepositoryType) []*bzpb.RepositoryMetadata {
+	names := slices.Sorted(maps.Keys(repositories))
+
+	todo := make([]*bzpb.RepositoryMetadata, 0)
+	for _, name := range names {
+		md := repositories[name]
+		if md == nil || md.Type != repositoryType {
+			continue
+		}
+
+		// Skip repositories that already have metadata (from cache)
+		if md.Languages != nil {
+			continue
+		}
+
+		todo = append(todo, md)
+	}
+	return todo
+}
+
+// fetchForgeRepositoryMetadata fetches the metadata of the Gitea, Bitbucket
+// and sourcehut repositories.
+func (ext *bcrExtension) fetchForgeRepositoryMetadata(repositories map[repositoryID]*bzpb.RepositoryMetadata) {
+	var todo []*bzpb.RepositoryMetadata
+	todo = append(todo, filterForgeRepositories(repositories, bzpb.RepositoryType_GITEA)...)
+	todo = append(todo, filterForgeRepositories(repositories, bzpb.RepositoryType_BITBUCKET)...)
+	ext.fetchRepositoryMetadataEach(ext.filterSkipNetworkRepositories(todo))
+
+	ext.fetchSourcehutRepositoryMetadata(ext.filterSkipNetworkRepositories(filterForgeRepositories(repositories, bzpb.RepositoryType_SOURCEHUT)))
+}
+
+// fetchRepositoryMetadata fetches the metadata of a single Gitea or
+// Bitbucket repository.
+func (ext *bcrExtension) fetchRepositoryMetadata(ctx context.Context, md *bzpb.RepositoryMetadata) error {
+	switch md.Type {
+	case bzpb.RepositoryType_BITBUCKET:
+		return bitbucket.FetchRepositoryMetadata(ctx, bitbucket.APIURL, ext.bitbucketToken, md)
+	default:
+		return gitea.FetchRepositoryMetadata(ctx, gitea.BaseURL(md.Host), "", md)
+	}
+}
+
+// fetchRepositoryMetadataEach fetches the metadata of the repositories one
+// request at a time.  Repositories that cannot be fetched are looked up in
+// the backup registry.
+func (ext *bcrExtension) fetchRepositoryMetadataEach(todo []*bzpb.RepositoryMetadata) {
+	if len(todo) == 0 {
+		return
+	}
+
+	log.Printf("Fetching metadata for %d Gitea and Bitbucket repositories...", len(todo))
+
+	bar := progressbar.NewOptions(len(todo),
+		progressbar.OptionSetDescription("Fetching repository metadata"),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetWidth(40),
+		progressbar.OptionSetTheme(progressbar.Theme{
+			Saucer:        "=",
+			SaucerHead:    ">",
+			SaucerPadding: " ",
+			BarStart:      "[",
+			BarEnd:        "]",
+		}),
+	)
+
+	var failed []*bzpb.RepositoryMetadata
+	var mu sync.Mutex
+
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(maxConcurrentForgeRequests)
+
+	for _, md := range todo {
+		g.Go(func() error {
+			err := ext.fetchRepositoryMetadata(ctx, md)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			bar.Add(1)
+
+			if err != nil {
+				log.Printf("warning: failed to fetch repository metadata for %s: %v", formatRepositoryID(md), err)
+				failed = append(failed, md)
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	fetched := len(todo) - len(failed)
+	if len(failed) > 0 {
+		if n := ext.populateFromBackupRegistry(failed); n > 0 {
+			log.Printf("Successfully populated %d repositories from backup registry", n)
+			fetched += n
+		}
+	}
+
+	log.Printf("Successfully fetched metadata for %d of %d Gitea and Bitbucket repositories", fetched, len(todo))
+
+	if fetched > 0 {
+		ext.fetchedRepositoryMetadata = true
+	}
+}
+
+// fetchSourcehutRepositoryMetadata fetches the metadata of the sourcehut
+// repositories in batches.  The sourcehut GraphQL API requires a token.
+func (ext *bcrExtension) fetchSourcehutRepositoryMetadata(todo []*bzpb.RepositoryMetadata) {
+	if len(todo) == 0 {
+		return
+	}
+
+	if ext.sourcehutToken == "" {
+		log.Printf("No sourcehut-token available, skipping retrieval of %d sourcehut repositories...", len(todo))
+		if ext.populateFromBackupRegistry(todo) > 0 {
+			ext.fetchedRepositoryMetadata = true
+		}
+		return
+	}
+
+	ctx := context.Background()
+	totalFetched := 0
+
+	for batch := range slices.Chunk(todo, srht.MaxBatchSize) {
+		if err := srht.FetchRepositoryMetadataBatch(ctx, srht.BaseURL, ext.sourcehutToken, batch); err != nil {
+			log.Printf("warning: failed to fetch sourcehut repository metadata batch: %v", err)
+			totalFetched += ext.populateFromBackupRegistry(batch)
+			continue
+		}
+		for _, md := range batch {
+			if md.Languages != nil {
+				totalFetched++
+			}
+		}
+	}
+
+	log.Printf("Successfully fetched metadata for %d of %d sourcehut repositories", totalFetched, len(todo))
+
+	if totalFetched > 0 {
+		ext.fetchedRepositoryMetadata = true
+	}
+}
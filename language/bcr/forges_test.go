@@ -0,0 +1,37 @@
+package bcr
+
+import (
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestFilterForgeRepositories(t *testing.T) {
+	repositories := make(map[repositoryID]*bzpb.RepositoryMetadata)
+	for _, repo := range []string{
+		"github:bazelbuild/rules_go",
+		"https://codeberg.org/forgejo/forgejo",
+		"https://bitbucket.org/atlassian/python-bitbucket",
+		"https://git.sr.ht/~sircmpwn/scdoc",
+		"https://git.sr.ht/~sircmpwn/hare",
+	} {
+		md, ok := parseRepositoryMetadataFromRepositoryString(repo)
+		if !ok {
+			t.Fatalf("failed to parse %q", repo)
+		}
+		repositories[formatRepositoryID(md)] = md
+	}
+	// cached metadata is not fetched again
+	repositories["sourcehut:~sircmpwn/hare"].Languages = map[string]int32{}
+
+	for repositoryType, want := range map[bzpb.RepositoryType][]repositoryID{
+		bzpb.RepositoryType_GITEA:     {"gitea:codeberg.org/forgejo/forgejo"},
+		bzpb.RepositoryType_BITBUCKET: {"bitbucket:atlassian/python-bitbucket"},
+		bzpb.RepositoryType_SOURCEHUT: {"sourcehut:~sircmpwn/scdoc"},
+	} {
+		got := filterForgeRepositories(repositories, repositoryType)
+		if len(got) != len(want) || formatRepositoryID(got[0]) != want[0] {
+			t.Errorf("filterForgeRepositories(%v) = %v, want %v", repositoryType, got, want)
+		}
+	}
+}
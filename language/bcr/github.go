@@ -5,12 +5,10 @@ import (
 	"log"
 	"maps"
 	"slices"
-	"sync"
 	"time"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
 	"github.com/bazel-contrib/bcr-frontend/pkg/gh"
-	"github.com/schollz/progressbar/v3"
 )
 
 func (ext *bcrExtension) configureGithubClient() {
@@ -150,336 +148,3 @@ func filterGithubRepositories(repositories map[repositoryID]*bzpb.RepositoryMeta
 	}
 	return todo
 }
-
-// resolveSourceCommitSHAsForRankedModules resolves commit SHAs only for modules
-// that have rank > 0 in the rankedModuleVersionMap (i.e., modules we're generating docs for)
-func (ext *bcrExtension) resolveSourceCommitSHAsForRankedModules(rankedModules rankedModuleVersionMap) {
-	if ext.githubClient == nil {
-		log.Printf("No GitHub client available, skipping source commit SHA resolution")
-		return
-	}
-
-	ctx := context.Background()
-
-	// Collect source URLs from ranked modules only (rank > 0)
-	type urlInfo struct {
-		URL  string
-		Org  string
-		Repo string
-		Type string
-		Ref  string
-	}
-
-	urlToModuleID := make(map[string][]moduleID)
-	var urlInfos []urlInfo
-	totalRankedModules := 0
-	processedModules := 0
-
-	// Track how many commit SHAs we got from backup registry
-	backupCommitSHAs := 0
-
-	// Iterate through ranked modules
-	for moduleName, versions := range rankedModules {
-		for _, rv := range versions {
-			totalRankedModules++
-
-			// Only process modules with rank > 0 (those selected by MVS for doc generation)
-			if rv.rank <= 0 || rv.source == nil {
-				continue
-			}
-
-			processedModules++
-
-			// Get the module version proto
-			moduleVersion := rv.source.Proto()
-			if moduleVersion == nil || moduleVersion.Source == nil {
-				continue
-			}
-
-			// Skip if already has a commit SHA
-			if moduleVersion.Source.CommitSha != "" {
-				continue
-			}
-
-			// Try to get commit SHA from backup registry first
-			id := toModuleID(moduleName, rv.version)
-			if backupSource := ext.getBackupModuleSource(string(moduleName), string(rv.version)); backupSource != nil && backupSource.CommitSha != "" {
-				if source, ok := ext.moduleSourceRules[id]; ok {
-					updateModuleSourceRuleSourceCommitSha(source, backupSource.CommitSha)
-					backupCommitSHAs++
-					continue
-				}
-			}
-
-			// Parse the GitHub URL
-			parsed, err := gh.ParseGitHubSourceURL(moduleVersion.Source.Url)
-			if err != nil {
-				// Not a GitHub URL or doesn't match our patterns - skip silently
-				continue
-			}
-
-			// Track which module ID uses this URL
-			urlToModuleID[moduleVersion.Source.Url] = append(urlToModuleID[moduleVersion.Source.Url], id)
-
-			// Add to our batch (deduplicate by URL - only add first occurrence)
-			if len(urlToModuleID[moduleVersion.Source.Url]) == 1 {
-				urlInfos = append(urlInfos, urlInfo{
-					URL:  moduleVersion.Source.Url,
-					Org:  parsed.Organization,
-					Repo: parsed.Repository,
-					Type: parsed.Type.String(),
-					Ref:  parsed.Reference,
-				})
-			}
-		}
-	}
-
-	log.Printf("Processing %d ranked modules (out of %d total modules)", processedModules, totalRankedModules)
-
-	if backupCommitSHAs > 0 {
-		log.Printf("Retrieved %d commit SHAs from backup registry", backupCommitSHAs)
-	}
-
-	if len(urlInfos) == 0 {
-		log.Printf("No GitHub source URLs need commit SHA resolution for ranked modules")
-		return
-	}
-
-	totalURLs := len(urlInfos)
-	log.Printf("Resolving commit SHAs for %d unique GitHub source URLs from ranked modules...", totalURLs)
-
-	// Create progress bar
-	bar := progressbar.NewOptions(totalURLs,
-		progressbar.OptionSetDescription("Resolving commit SHAs (ranked)"),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(40),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "=",
-			SaucerHead:    ">",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
-
-	// Convert to the format expected by BatchResolveSourceCommits
-	batchInfos := make([]struct {
-		URL  string
-		Org  string
-		Repo string
-		Type string
-		Ref  string
-	}, len(urlInfos))
-	for i, info := range urlInfos {
-		batchInfos[i] = info
-	}
-
-	// Track progress
-	successCount := 0
-	errorCount := 0
-	var mu sync.Mutex
-
-	// Resolve all commit SHAs in batch with progress callback
-	results, err := gh.BatchResolveSourceCommits(ctx, ext.githubClient, batchInfos, func(result *gh.SourceCommitInfo) {
-		mu.Lock()
-		defer mu.Unlock()
-
-		bar.Add(1)
-
-		if result.Error != nil {
-			errorCount++
-		} else if result.CommitSHA != "" {
-			// Update all module IDs that use this URL
-			moduleIDs := urlToModuleID[result.URL]
-			for _, id := range moduleIDs {
-				if source, ok := ext.moduleSourceRules[id]; ok {
-					updateModuleSourceRuleSourceCommitSha(source, result.CommitSHA)
-					successCount++
-				}
-			}
-		}
-	})
-	if err != nil {
-		log.Printf("error: failed to resolve source commit SHAs: %v", err)
-		return
-	}
-
-	// Log any errors or warnings after completion
-	for _, result := range results {
-		if result.Error != nil {
-			log.Printf("warning: failed to resolve commit SHA for %s: %v", result.URL, result.Error)
-		} else if result.CommitSHA == "" {
-			log.Printf("warning: empty commit SHA for %s", result.URL)
-		}
-	}
-
-	totalResolved := backupCommitSHAs + successCount
-	log.Printf("Commit SHA resolution complete for ranked modules: %d from backup registry, %d from GitHub API (%d errors), %d total resolved", backupCommitSHAs, successCount, errorCount, totalResolved)
-}
-
-// resolveSourceCommitSHAsForLatestVersions resolves commit SHAs for ALL latest
-// versions, regardless of whether they have documentation or rank.
-func (ext *bcrExtension) resolveSourceCommitSHAsForLatestVersions() {
-	if ext.githubClient == nil {
-		log.Printf("No GitHub client available, skipping source commit SHA resolution")
-		return
-	}
-
-	ctx := context.Background()
-
-	// Collect source URLs from all latest versions
-	type urlInfo struct {
-		URL  string
-		Org  string
-		Repo string
-		Type string
-		Ref  string
-	}
-
-	urlToModuleID := make(map[string][]moduleID)
-	var urlInfos []urlInfo
-	totalLatestVersions := 0
-	processedVersions := 0
-
-	// Track how many commit SHAs we got from backup registry
-	backupCommitSHAs := 0
-
-	// Iterate through all module versions
-	for id, versionRule := range ext.moduleVersionRules {
-		// Check if this is marked as the latest version
-		isLatest := versionRule.Rule().PrivateAttr(isLatestVersionPrivateAttr)
-		if isLatest == nil || !isLatest.(bool) {
-			continue
-		}
-
-		totalLatestVersions++
-
-		// Get the module source for this version
-		sourceRule, exists := ext.moduleSourceRules[id]
-		if !exists {
-			continue
-		}
-
-		moduleSource := sourceRule.Proto()
-		if moduleSource == nil {
-			continue
-		}
-
-		processedVersions++
-
-		// Skip if already has a commit SHA
-		if moduleSource.CommitSha != "" {
-			continue
-		}
-
-		// Try to get commit SHA from backup registry first
-		if backupSource := ext.getBackupModuleSource(string(id.name()), string(id.version())); backupSource != nil && backupSource.CommitSha != "" {
-			updateModuleSourceRuleSourceCommitSha(sourceRule, backupSource.CommitSha)
-			backupCommitSHAs++
-			continue
-		}
-
-		// Parse the GitHub URL
-		parsed, err := gh.ParseGitHubSourceURL(moduleSource.Url)
-		if err != nil {
-			// Not a GitHub URL or doesn't match our patterns - skip silently
-			continue
-		}
-
-		// Track which module ID uses this URL
-		urlToModuleID[moduleSource.Url] = append(urlToModuleID[moduleSource.Url], id)
-
-		// Add to our batch (deduplicate by URL - only add first occurrence)
-		if len(urlToModuleID[moduleSource.Url]) == 1 {
-			urlInfos = append(urlInfos, urlInfo{
-				URL:  moduleSource.Url,
-				Org:  parsed.Organization,
-				Repo: parsed.Repository,
-				Type: parsed.Type.String(),
-				Ref:  parsed.Reference,
-			})
-		}
-	}
-
-	log.Printf("Processing %d latest versions with sources (out of %d total latest versions)", processedVersions, totalLatestVersions)
-
-	if backupCommitSHAs > 0 {
-		log.Printf("Retrieved %d commit SHAs from backup registry", backupCommitSHAs)
-	}
-
-	if len(urlInfos) == 0 {
-		log.Printf("No GitHub source URLs need commit SHA resolution for latest versions")
-		return
-	}
-
-	totalURLs := len(urlInfos)
-	log.Printf("Resolving commit SHAs for %d unique GitHub source URLs from latest versions...", totalURLs)
-
-	// Create progress bar
-	bar := progressbar.NewOptions(totalURLs,
-		progressbar.OptionSetDescription("Resolving commit SHAs"),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(40),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "=",
-			SaucerHead:    ">",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
-
-	// Convert to the format expected by BatchResolveSourceCommits
-	batchInfos := make([]struct {
-		URL  string
-		Org  string
-		Repo string
-		Type string
-		Ref  string
-	}, len(urlInfos))
-	for i, info := range urlInfos {
-		batchInfos[i] = info
-	}
-
-	// Track progress
-	successCount := 0
-	errorCount := 0
-	var mu sync.Mutex
-
-	// Resolve all commit SHAs in batch with progress callback
-	results, err := gh.BatchResolveSourceCommits(ctx, ext.githubClient, batchInfos, func(result *gh.SourceCommitInfo) {
-		mu.Lock()
-		defer mu.Unlock()
-
-		bar.Add(1)
-
-		if result.Error != nil {
-			errorCount++
-		} else if result.CommitSHA != "" {
-			// Update all module IDs that use this URL
-			moduleIDs := urlToModuleID[result.URL]
-			for _, id := range moduleIDs {
-				if source, ok := ext.moduleSourceRules[id]; ok {
-					updateModuleSourceRuleSourceCommitSha(source, result.CommitSHA)
-					successCount++
-				}
-			}
-		}
-	})
-	if err != nil {
-		log.Printf("error: failed to resolve source commit SHAs: %v", err)
-		return
-	}
-
-	// Log any errors or warnings after completion
-	for _, result := range results {
-		if result.Error != nil {
-			log.Printf("warning: failed to resolve commit SHA for %s: %v", result.URL, result.Error)
-		} else if result.CommitSHA == "" {
-			log.Printf("warning: empty commit SHA for %s", result.URL)
-		}
-	}
-
-	totalResolved := backupCommitSHAs + successCount
-	log.Printf("Commit SHA resolution complete for latest versions: %d from backup registry, %d from GitHub API (%d errors), %d total resolved", backupCommitSHAs, successCount, errorCount, totalResolved)
-}
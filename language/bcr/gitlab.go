@@ -0,0 +1,108 @@
+package bcr
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/gl"
+)
+
+// gitlabBatchSize is the number of projects fetched in a single GraphQL
+// query.
+const gitlabBatchSize = 100
+
+// gitlabTokenForHost returns the token to use for the GitLab instance at the
+// given host.  The token is only valid for gitlab.com, so it is never sent to
+// self-hosted instances, which are queried anonymously.
+func (ext *bcrExtension) gitlabTokenForHost(host string) string {
+	if host != "" {
+		return ""
+	}
+	return ext.gitlabToken
+}
+
+func (ext *bcrExtension) fetchGitlabRepositoryMetadata(todo []*bzpb.RepositoryMetadata) {
+	if len(todo) == 0 {
+		log.Printf("No GitLab repositories need metadata fetching")
+		return
+	}
+
+	log.Printf("Need to fetch metadata for %d GitLab repositories", len(todo))
+
+	ctx := context.Background()
+	totalFetched := 0
+
+	for _, batch := range batchGitlabRepositories(todo, gitlabBatchSize) {
+		host := batch[0].Host
+		baseURL := gl.BaseURL(host)
+		log.Printf("Fetching metadata for %d repositories from %s using GraphQL...", len(batch), baseURL)
+
+		// Retry with exponential backoff
+		maxRetries := 3
+		var err error
+		for attempt := 0; attempt < maxRetries; attempt++ {
+			if attempt > 0 {
+				backoff := time.Duration(attempt) * time.Second
+				log.Printf("Retrying batch for %s after %v (attempt %d/%d)...", baseURL, backoff, attempt+1, maxRetries)
+				time.Sleep(backoff)
+			}
+
+			err = gl.FetchRepositoryMetadataBatch(ctx, baseURL, ext.gitlabTokenForHost(host), batch)
+			if err == nil {
+				break
+			}
+
+			log.Printf("warning: failed to fetch GitLab repository metadata batch (attempt %d/%d): %v", attempt+1, maxRetries, err)
+		}
+
+		if err != nil {
+			log.Printf("error: failed to fetch GitLab repository metadata batch after %d attempts, trying backup registry", maxRetries)
+
+			batchFetched := ext.populateFromBackupRegistry(batch)
+			totalFetched += batchFetched
+
+			if batchFetched > 0 {
+				log.Printf("Successfully populated %d repositories from backup registry", batchFetched)
+			}
+			continue
+		}
+
+		batchFetched := 0
+		for _, md := range batch {
+			if md.Languages != nil {
+				batchFetched++
+			}
+		}
+		totalFetched += batchFetched
+
+		log.Printf("Successfully fetched metadata for %d repositories in this batch", batchFetched)
+	}
+
+	log.Printf("Successfully fetched metadata for %d of %d GitLab repositories total", totalFetched, len(todo))
+
+	if totalFetched > 0 {
+		ext.fetchedRepositoryMetadata = true
+	}
+}
+
+// batchGitlabRepositories groups the repositories by host (each instance has
+// its own GraphQL endpoint) and splits the groups into batches of at most
+// size repositories.
+func batchGitlabRepositories(todo []*bzpb.RepositoryMetadata, size int) (batches [][]*bzpb.RepositoryMetadata) {
+	byHost := make(map[string][]*bzpb.RepositoryMetadata)
+	for _, md := range todo {
+		byHost[md.Host] = append(byHost[md.Host], md)
+	}
+	for _, host := range slices.Sorted(maps.Keys(byHost)) {
+		batches = append(batches, slices.Collect(slices.Chunk(byHost[host], size))...)
+	}
+	return
+}
+
+func filterGitlabRepositories(repositories map[repositoryID]*bzpb.RepositoryMetadata) []*bzpb.RepositoryMetadata {
+	return filterForgeRepositories(repositories, bzpb.RepositoryType_GITLAB)
+}
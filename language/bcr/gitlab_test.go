@@ -0,0 +1,51 @@
+package bcr
+
+import (
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestBatchGitlabRepositories(t *testing.T) {
+	var todo []*bzpb.RepositoryMetadata
+	for range 3 {
+		todo = append(todo, &bzpb.RepositoryMetadata{Type: bzpb.RepositoryType_GITLAB})
+	}
+	todo = append(todo, &bzpb.RepositoryMetadata{Type: bzpb.RepositoryType_GITLAB, Host: "gitlab.arm.com"})
+
+	batches := batchGitlabRepositories(todo, 2)
+	if len(batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(batches))
+	}
+	for i, want := range []struct {
+		host string
+		size int
+	}{{"", 2}, {"", 1}, {"gitlab.arm.com", 1}} {
+		if len(batches[i]) != want.size || batches[i][0].Host != want.host {
+			t.Errorf("batch %d: got %d repositories of %q, want %d of %q", i, len(batches[i]), batches[i][0].Host, want.size, want.host)
+		}
+	}
+}
+
+func TestFilterGitlabRepositories(t *testing.T) {
+	repositories := map[repositoryID]*bzpb.RepositoryMetadata{
+		"github:bazelbuild/rules_go":       {Type: bzpb.RepositoryType_GITHUB, Organization: "bazelbuild", Name: "rules_go"},
+		"gitlab:libeigen/eigen":            {Type: bzpb.RepositoryType_GITLAB, Organization: "libeigen", Name: "eigen"},
+		"gitlab:gitlab.arm.com/bazel/ape":  {Type: bzpb.RepositoryType_GITLAB, Host: "gitlab.arm.com", Organization: "bazel", Name: "ape"},
+		"gitlab:gitlab.arm.com/bazel/rust": {Type: bzpb.RepositoryType_GITLAB, Host: "gitlab.arm.com", Organization: "bazel", Name: "rust", Languages: map[string]int32{}},
+	}
+	got := filterGitlabRepositories(repositories)
+	if len(got) != 2 || got[0].Name != "ape" || got[1].Name != "eigen" {
+		t.Errorf("filterGitlabRepositories() = %v, want the uncached GitLab repositories", got)
+	}
+}
+
+func TestGitlabTokenForHost(t *testing.T) {
+	ext := &bcrExtension{gitlabToken: "secret"}
+	if got := ext.gitlabTokenForHost(""); got != "secret" {
+		t.Errorf("expected the token for gitlab.com, got %q", got)
+	}
+	if got := ext.gitlabTokenForHost("gitlab.arm.com"); got != "" {
+		t.Errorf("expected no token for a self-hosted instance, got %q", got)
+	}
+}
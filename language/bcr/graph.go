@@ -89,8 +89,17 @@ func (ext *bcrExtension) logCycles() {
 		return
 	}
 
+	adjacencyMap, err := ext.depGraph.AdjacencyMap()
+	if err != nil {
+		log.Printf("Error getting adjacency map: %v", err)
+		return
+	}
+
 	log.Printf("WARNING: Found %d circular dependency group(s):", len(cycles))
 	for i, cycle := range cycles {
 		log.Printf("  Cycle %d: %v", i+1, cycle)
+		for _, path := range minimalCyclePaths(cycle, adjacencyMap) {
+			log.Printf("    %s", joinModuleIDs(path))
+		}
 	}
 }
@@ -0,0 +1,328 @@
+package bcr
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	gitpkg "github.com/bazel-contrib/bcr-frontend/pkg/git"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+)
+
+// moduleVersionAnnotationAttrs are the module_version attributes that are
+// computed over the whole registry once all rules have been generated.  They
+// are copied onto the existing rules of module versions that are not
+// regenerated.
+var moduleVersionAnnotationAttrs = []string{
+	"is_latest_version",
+	"mvs",
+	"mvs_dev",
+	"mvs_merged",
+	"mvs_dev_upgrades",
+	"mvs_overrides",
+	"resolution_conflicts",
+	"compatible_bazel_min_version",
+	"compatible_bazel_max_version",
+	"compatible_bazel_version_count",
+	"bazel_compatibility_narrowed_by",
+	"bzl_src",
+	"bzl_deps",
+}
+
+// unchangedModuleVersion is a module version whose rules are kept as-is in
+// incremental mode.
+type unchangedModuleVersion struct {
+	rel      string     // package of the module version
+	existing *rule.Rule // the module_version rule of the existing BUILD file
+}
+
+// configureIncremental enables incremental regeneration if the registry
+// commits of the previous run are known.  The registry git diff since then
+// determines the changed modules; the reverse dependency index of the
+// previous run determines the module versions whose dependency closure
+// includes a changed module.  The generator binary must be the same as in
+// the previous run (see computeGeneratorFingerprint); modules whose bcr
+// configuration changed are regenerated as well (see trackModuleConfig).
+// Anything unexpected falls back to a full regeneration.
+func (ext *bcrExtension) configureIncremental(ctx context.Context) {
+	if ext.registryCommitSetFile == "" {
+		return
+	}
+
+	fingerprint, err := computeGeneratorFingerprint()
+	if err != nil {
+		log.Printf("warning: %v (regenerating all modules)", err)
+		return
+	}
+	ext.generatorFingerprint = fingerprint
+
+	previous := ext.readRegistryCommitSetFile()
+	if len(previous.Commit) > 0 && previous.GeneratorFingerprint != fingerprint {
+		log.Printf("The generator changed since the previous run, regenerating all modules")
+		return
+	}
+	commits := make(map[string]*bzpb.RegistryCommit)
+	for _, commit := range previous.Commit {
+		commits[commit.Registry] = commit
+	}
+	changed := make(map[moduleName]bool)
+	for _, reg := range ext.registries {
+		commit, ok := commits[reg.name]
+		if !ok {
+			log.Printf("No previous commit recorded for registry %s, regenerating all modules", reg.name)
+			return
+		}
+		files, err := gitpkg.GetChangedFiles(ctx, filepath.Join(ext.repoRoot, reg.root), commit.Sha1, "modules")
+		if err != nil {
+			log.Printf("warning: %v (regenerating all modules)", err)
+			return
+		}
+		maps.Copy(changed, ext.changedModulesFromPaths(files))
+	}
+
+	index, err := ext.readReverseDependencyIndexFile()
+	if err != nil {
+		log.Printf("warning: %v (regenerating all modules)", err)
+		return
+	}
+
+	// new Bazel releases add versions to the bazel pseudo-module
+	if ext.fetchedBazelReleases {
+		changed[bazelToolsName] = true
+	}
+
+	ext.incremental = true
+	ext.changedModules = changed
+	ext.previousModuleConfigs = previous.ModuleConfigFingerprints
+	ext.affectedModuleVersions = affectedModuleVersions(index, changed)
+
+	names := make([]string, 0, len(changed))
+	for name := range changed {
+		names = append(names, string(name))
+	}
+	slices.Sort(names)
+	log.Printf("Incremental regeneration: %d changed modules (%s), %d affected module versions",
+		len(changed), strings.Join(names, ", "), len(ext.affectedModuleVersions))
+}
+
+// changedModulesFromPaths returns the names of the modules containing the
+// given paths (relative to the registry root, e.g. modules/foo/1.0/source.json).
+// The BUILD files are ignored: they are generated into the registry checkout
+// and show up as untracked files, not as changes of the registry data.  So
+// are files that do not belong to a module directory.
+func (ext *bcrExtension) changedModulesFromPaths(paths []string) map[moduleName]bool {
+	changed := make(map[moduleName]bool)
+	for _, p := range paths {
+		if slices.Contains(ext.buildFileNames, path.Base(p)) {
+			continue
+		}
+		if name, ok := moduleNameFromRel("modules", p); ok && path.Join("modules", string(name)) != p {
+			changed[name] = true
+		}
+	}
+	return changed
+}
+
+// affectedModuleVersions returns the module versions of the changed modules
+// and their dependents: the versions that depend on them transitively
+// through regular deps, and the versions with a dev dependency on any of
+// those (dev dependencies only matter for the root module, so they are not
+// followed any further).
+func affectedModuleVersions(index *bzpb.ReverseDependencyIndex, changed map[moduleName]bool) map[moduleID]bool {
+	type dependents struct {
+		direct    []string
+		directDev []string
+	}
+	byID := make(map[moduleID]*dependents, len(index.ModuleVersions))
+	var queue []moduleID
+	affected := make(map[moduleID]bool)
+	for _, mv := range index.ModuleVersions {
+		id := newModuleID(mv.Name, mv.Version)
+		byID[id] = &dependents{direct: mv.Direct, directDev: mv.DirectDev}
+		if changed[moduleName(mv.Name)] {
+			affected[id] = true
+			queue = append(queue, id)
+		}
+	}
+
+	var dev []moduleID
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		deps, ok := byID[current]
+		if !ok {
+			continue
+		}
+		for _, from := range deps.direct {
+			if id := moduleID(from); !affected[id] {
+				affected[id] = true
+				queue = append(queue, id)
+			}
+		}
+		for _, from := range deps.directDev {
+			dev = append(dev, moduleID(from))
+		}
+	}
+	for _, id := range dev {
+		affected[id] = true
+	}
+
+	return affected
+}
+
+// isChangedModule reports whether the module changed since the previous run
+// (always true unless in incremental mode).
+func (ext *bcrExtension) isChangedModule(name moduleName) bool {
+	return !ext.incremental || ext.changedModules[name]
+}
+
+// findExistingModuleVersionRule returns the module_version rule of the
+// existing BUILD file, if any.  Only module versions that have one can keep
+// their rules in incremental mode.
+func (ext *bcrExtension) findExistingModuleVersionRule(f *rule.File) *rule.Rule {
+	if !ext.incremental || f == nil {
+		return nil
+	}
+	for _, r := range f.Rules {
+		if r.Kind() == moduleVersionKind {
+			return r
+		}
+	}
+	return nil
+}
+
+// isAffectedModuleVersion reports whether the rules of the module version
+// need to be regenerated in incremental mode.
+func (ext *bcrExtension) isAffectedModuleVersion(name moduleName, version moduleVersion) bool {
+	return ext.changedModules[name] || ext.affectedModuleVersions[toModuleID(name, version)]
+}
+
+// trackUnchangedModuleVersion records a module version that keeps its
+// existing rules.
+func (ext *bcrExtension) trackUnchangedModuleVersion(id moduleID, rel string, existing *rule.Rule) {
+	ext.unchangedModuleVersions[id] = &unchangedModuleVersion{rel: rel, existing: existing}
+}
+
+// resolveUnchangedModuleVersionRules runs the module_version resolution for
+// module versions that keep their existing rules (gazelle only resolves
+// generated rules), so that the annotations computed after resolution see
+// the same state as in a full regeneration.
+func (ext *bcrExtension) resolveUnchangedModuleVersionRules() {
+	for id, u := range ext.unchangedModuleVersions {
+		if protoRule, ok := ext.moduleVersionRules[id]; ok {
+			resolveModuleVersionRule(protoRule.Rule(), ext.moduleMetadataRulesByPkg[path.Dir(u.rel)])
+		}
+	}
+}
+
+// syncUnchangedModuleVersionRules copies the registry-wide annotations of
+// module versions that were not regenerated onto their existing rules.
+func (ext *bcrExtension) syncUnchangedModuleVersionRules() {
+	if len(ext.unchangedModuleVersions) == 0 {
+		return
+	}
+	for id, u := range ext.unchangedModuleVersions {
+		if protoRule, ok := ext.moduleVersionRules[id]; ok {
+			syncRuleAttrs(protoRule.Rule(), u.existing, moduleVersionAnnotationAttrs)
+		}
+	}
+	log.Printf("Kept the rules of %d unchanged module versions", len(ext.unchangedModuleVersions))
+}
+
+// syncRuleAttrs sets the given attributes of dst to those of src, deleting
+// the ones src does not have.
+func syncRuleAttrs(src, dst *rule.Rule, attrs []string) {
+	for _, name := range attrs {
+		if value := src.Attr(name); value != nil {
+			dst.SetAttr(name, value)
+		} else {
+			dst.DelAttr(name)
+		}
+	}
+}
+
+// trackModuleConfig records the fingerprint of the bcr configuration of a
+// module directory, as parsed by gazelle.  In incremental mode, a module whose
+// configuration differs from the previous run is regenerated like a module
+// whose registry data changed.  Directives are inherited, so one in the
+// registry root reconfigures every module of the registry.
+func (ext *bcrExtension) trackModuleConfig(rel string, name moduleName, cfg *Config) {
+	fingerprint := cfg.fingerprint()
+	ext.moduleConfigFingerprints[rel] = fingerprint
+	if ext.incremental && !ext.changedModules[name] && ext.previousModuleConfigs[rel] != fingerprint {
+		log.Printf("The bcr configuration of %s changed since the previous run, regenerating it", rel)
+		ext.changedModules[name] = true
+	}
+}
+
+// computeGeneratorFingerprint returns a digest of the running binary.  The
+// rules of unchanged modules are only kept if the previous run used the same
+// generator.
+func computeGeneratorFingerprint() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("locating the generator binary: %w", err)
+	}
+	binary, err := sri.ComputeFile("sha256", executable)
+	if err != nil {
+		return "", fmt.Errorf("hashing the generator binary: %w", err)
+	}
+	return hex.EncodeToString(binary.Digest), nil
+}
+
+// readRegistryCommitSetFile reads the registry commits of the previous run,
+// along with the fingerprints of the run.
+func (ext *bcrExtension) readRegistryCommitSetFile() *bzpb.RegistryCommitSet {
+	var commitSet bzpb.RegistryCommitSet
+	filename := os.ExpandEnv(ext.registryCommitSetFile)
+	if _, err := os.Stat(filename); err != nil {
+		return &commitSet
+	}
+	if err := protoutil.ReadFile(filename, &commitSet); err != nil {
+		log.Printf("warning: could not read registry commits: %v", err)
+		return &bzpb.RegistryCommitSet{}
+	}
+	return &commitSet
+}
+
+// writeRegistryCommitSetFile records the current commit of each registry for
+// the next (incremental) run.
+func (ext *bcrExtension) writeRegistryCommitSetFile(ctx context.Context) error {
+	if ext.registryCommitSetFile == "" {
+		return nil
+	}
+
+	commitSet := &bzpb.RegistryCommitSet{
+		GeneratorFingerprint:     ext.generatorFingerprint,
+		ModuleConfigFingerprints: ext.moduleConfigFingerprints,
+	}
+	for _, reg := range ext.registries {
+		sha, date, err := gitpkg.GetRegistryCommit(ctx, filepath.Join(ext.repoRoot, reg.root))
+		if err != nil {
+			return fmt.Errorf("registry %s: %w", reg.name, err)
+		}
+		commitSet.Commit = append(commitSet.Commit, &bzpb.RegistryCommit{
+			Registry: reg.name,
+			Sha1:     sha,
+			Date:     date,
+		})
+	}
+
+	filename := os.ExpandEnv(ext.registryCommitSetFile)
+	if err := protoutil.WriteFile(filename, commitSet); err != nil {
+		return fmt.Errorf("failed to write registry commit file %s: %w", filename, err)
+	}
+
+	log.Printf("Wrote %d registry commits to %s", len(commitSet.Commit), filename)
+	return nil
+}
@@ -0,0 +1,182 @@
+package bcr
+
+import (
+	"context"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	gitpkg "github.com/bazel-contrib/bcr-frontend/pkg/git"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+)
+
+func TestChangedModulesFromPaths(t *testing.T) {
+	ext := &bcrExtension{buildFileNames: []string{"BUILD.bazel", "BUILD"}}
+	got := ext.changedModulesFromPaths([]string{
+		"modules/rules_foo/1.0.0/source.json",
+		"modules/rules_foo/metadata.json",
+		"modules/rules_bar/2.0.0/patches/fix.patch",
+		"modules/rules_baz/BUILD.bazel",
+		"modules/rules_baz/1.0.0/BUILD",
+		"modules/reverse_dependencies.pb",
+		"README.md",
+		"modules",
+	})
+	want := []moduleName{"rules_bar", "rules_foo"}
+	if names := slices.Sorted(maps.Keys(got)); !slices.Equal(names, want) {
+		t.Errorf("changedModulesFromPaths() = %v, want %v", names, want)
+	}
+}
+
+func TestChangedModulesIgnoreGeneratedBuildFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	root := t.TempDir()
+	write := func(rel, content string) {
+		filename := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git := func(args ...string) {
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", root, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	write("modules/rules_foo/metadata.json", "{}")
+	write("modules/rules_foo/1.0.0/MODULE.bazel", "module(name = \"rules_foo\")")
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+	sha, _, err := gitpkg.GetRegistryCommit(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the BUILD files written by gazelle are untracked files of the checkout
+	write("modules/rules_foo/BUILD.bazel", "module_metadata(name = \"metadata\")")
+	write("modules/rules_foo/1.0.0/BUILD.bazel", "module_version(name = \"rules_foo\")")
+	write("modules/rules_bar/metadata.json", "{}")
+
+	files, err := gitpkg.GetChangedFiles(ctx, root, sha, "modules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext := &bcrExtension{buildFileNames: []string{"BUILD.bazel", "BUILD"}}
+	got := slices.Sorted(maps.Keys(ext.changedModulesFromPaths(files)))
+	if want := []moduleName{"rules_bar"}; !slices.Equal(got, want) {
+		t.Errorf("changed modules = %v, want %v", got, want)
+	}
+}
+
+func TestAffectedModuleVersions(t *testing.T) {
+	// c@1.0 <- b@1.0 <- a@1.0 (regular deps)
+	// c@1.0 <- d@1.0 (dev dep)
+	// b@1.0 <- e@1.0 (dev dep), e@1.0 <- f@1.0 (regular dep)
+	index := &bzpb.ReverseDependencyIndex{
+		ModuleVersions: []*bzpb.ModuleVersionDependents{
+			{Name: "a", Version: "1.0"},
+			{Name: "b", Version: "1.0", Direct: []string{"a@1.0"}, DirectDev: []string{"e@1.0"}},
+			{Name: "c", Version: "1.0", Direct: []string{"b@1.0"}, DirectDev: []string{"d@1.0"}},
+			{Name: "c", Version: "2.0"},
+			{Name: "d", Version: "1.0"},
+			{Name: "e", Version: "1.0", Direct: []string{"f@1.0"}},
+			{Name: "f", Version: "1.0"},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		changed []moduleName
+		want    []moduleID
+	}{
+		"leaf": {
+			changed: []moduleName{"c"},
+			want:    []moduleID{"a@1.0", "b@1.0", "c@1.0", "c@2.0", "d@1.0", "e@1.0"},
+		},
+		"root": {
+			changed: []moduleName{"a"},
+			want:    []moduleID{"a@1.0"},
+		},
+		"none": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			changed := make(map[moduleName]bool)
+			for _, n := range tc.changed {
+				changed[n] = true
+			}
+			got := slices.SortedFunc(maps.Keys(affectedModuleVersions(index, changed)), compareModuleIDs)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("affectedModuleVersions() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSyncRuleAttrs(t *testing.T) {
+	src := rule.NewRule(moduleVersionKind, "module_version")
+	src.SetAttr("mvs", map[string]string{"b": "1.0"})
+	src.SetAttr("is_latest_version", true)
+
+	dst := rule.NewRule(moduleVersionKind, "module_version")
+	dst.SetAttr("module_name", "a")
+	dst.SetAttr("mvs", map[string]string{"b": "0.9"})
+	dst.SetAttr("bzl_deps", []string{"@bzl.b---0.9//:modules"})
+
+	syncRuleAttrs(src, dst, []string{"mvs", "is_latest_version", "bzl_deps"})
+
+	if got := dst.AttrString("module_name"); got != "a" {
+		t.Errorf("module_name = %q, want it to be left alone", got)
+	}
+	if dst.Attr("bzl_deps") != nil {
+		t.Error("expected bzl_deps to be deleted")
+	}
+	if dst.Attr("is_latest_version") == nil {
+		t.Error("expected is_latest_version to be set")
+	}
+	if dst.Attr("mvs") != src.Attr("mvs") {
+		t.Error("expected mvs to be copied")
+	}
+}
+
+func TestTrackModuleConfig(t *testing.T) {
+	configure := func(directives ...rule.Directive) *Config {
+		cfg := createConfig(nil)
+		cfg.parseDirectives("modules/rules_foo", directives)
+		return cfg
+	}
+	skipNetwork := rule.Directive{Key: skipNetworkDirective, Value: "true"}
+
+	previous := &bcrExtension{moduleConfigFingerprints: make(map[string]string)}
+	previous.trackModuleConfig("modules/rules_foo", "rules_foo", configure(skipNetwork))
+	previous.trackModuleConfig("modules/rules_bar", "rules_bar", configure())
+
+	ext := &bcrExtension{
+		moduleConfigFingerprints: make(map[string]string),
+		incremental:              true,
+		changedModules:           make(map[moduleName]bool),
+		previousModuleConfigs:    previous.moduleConfigFingerprints,
+	}
+	// the same settings through an inherited config
+	ext.trackModuleConfig("modules/rules_foo", "rules_foo", configure(skipNetwork).clone(nil))
+	ext.trackModuleConfig("modules/rules_bar", "rules_bar", configure(rule.Directive{Key: excludeModuleDirective, Value: "rules_baz"}))
+	ext.trackModuleConfig("modules/rules_baz", "rules_baz", configure())
+
+	got := slices.Sorted(maps.Keys(ext.changedModules))
+	if want := []moduleName{"rules_bar", "rules_baz"}; !slices.Equal(got, want) {
+		t.Errorf("changed modules = %v, want %v", got, want)
+	}
+	if ext.moduleConfigFingerprints["modules/rules_foo"] != previous.moduleConfigFingerprints["modules/rules_foo"] {
+		t.Error("expected the fingerprint of rules_foo to be recorded")
+	}
+}
@@ -13,6 +13,9 @@ func (ext *bcrExtension) Before(ctx context.Context) {
 
 	// Fetch Bazel release data and create pseudo BCR modules
 	ext.fetchBazelRepositoryMetadata(nil)
+
+	// Limit regeneration to the modules changed since the previous run
+	ext.configureIncremental(ctx)
 }
 
 // DoneGeneratingRules is called after all rules have been generated. This is
@@ -30,10 +33,14 @@ func (ext *bcrExtension) DoneGeneratingRules() {
 	// Log any circular dependencies
 	ext.logCycles()
 
+	// Resolve the module versions that keep their existing rules
+	ext.resolveUnchangedModuleVersionRules()
+
 	// fetch repository metadata now that we know the full list of repos to
 	// gather info for
-	ext.fetchGithubRepositoryMetadata(filterGithubRepositories(ext.repositoriesMetadataByID))
-	// ext.fetchGitlabRepositoryMetadata(filterGitlabRepositories(ext.repositories))
+	ext.fetchGithubRepositoryMetadata(ext.filterSkipNetworkRepositories(filterGithubRepositories(ext.repositoriesMetadataByID)))
+	ext.fetchGitlabRepositoryMetadata(ext.filterSkipNetworkRepositories(filterGitlabRepositories(ext.repositoriesMetadataByID)))
+	ext.fetchForgeRepositoryMetadata(ext.repositoriesMetadataByID)
 
 	log.Println("===[BeforeResolvingDeps]======================================")
 }
@@ -50,25 +57,50 @@ func (ext *bcrExtension) AfterResolvingDeps(ctx context.Context) {
 	// Calculate MVS sets - this updates the rankings of
 	ext.calculateMvs(availableBzlRepositories)
 
+	// Build the reverse dependency index and fold the counts into the
+	// module_metadata rules
+	ext.calculateReverseDependencies()
+
 	if err := mergeGeneratedModuleBazelFile(ext.repoRoot, binaryProtoHttpArchives, availableBzlRepositories); err != nil {
 		log.Fatal(err)
 	}
 
-	// Resolve source commit SHAs for ranked modules (those we're generating
-	// docs for) Only do this after MVS calculation and MODULE.bazel merge to
-	// narrow down the set
-	ext.resolveSourceCommitSHAsForRankedModules(availableBzlRepositories)
+	// Resolve the source commit SHAs of all module versions (resolved SHAs
+	// are kept in the cache, so only new versions hit the network)
+	ext.resolveSourceCommitSHAs()
+
+	// Download and verify the source archives (optional)
+	ext.verifySourceArchives(ctx)
+
+	// Verify the sigstore bundles of the attestations (optional)
+	ext.verifyModuleAttestations(ctx)
+
+	// Carry the registry-wide annotations over to the rules that were not
+	// regenerated
+	ext.syncUnchangedModuleVersionRules()
+
+	// Write the updated cache back to file - best effort, ignoring errors.
+	// In offline mode the cache file is only read, so the run does not change
+	// its own inputs.
+	if !ext.offline {
+		if err := ext.writeCacheFile(); err != nil {
+			log.Printf("writing cache file: %v", err)
+		}
+	}
+
+	if err := ext.writeCycleReportFile(); err != nil {
+		log.Printf("writing cycle report file: %v", err)
+	}
 
-	// Write the updated caches back to files - best effort, ignoring errors
-	if err := ext.writeResourceStatusCacheFile(); err != nil {
-		log.Println("writing resource status cache file: ")
+	if err := ext.writeRegistryCommitSetFile(ctx); err != nil {
+		log.Printf("writing registry commit file: %v", err)
 	}
 
-	if err := ext.writeRepositoryMetadataCacheFile(); err != nil {
-		log.Println("writing repository metadata cache file: ")
+	if err := ext.writeDiagnosticsReportFile(); err != nil {
+		log.Printf("writing diagnostics report file: %v", err)
 	}
 
-	if err := ext.writeBazelReleaseCacheFile(); err != nil {
-		log.Println("writing bazel release cache file: ")
+	if err := ext.writeCacheMissReportFile(); err != nil {
+		log.Printf("writing cache miss report file: %v", err)
 	}
 }
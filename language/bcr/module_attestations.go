@@ -5,7 +5,12 @@ import (
 	"github.com/bazelbuild/bazel-gazelle/rule"
 )
 
-const moduleAttestationsKind = "module_attestations"
+const (
+	moduleAttestationsKind = "module_attestations"
+	// versionDirPrivateAttr holds the directory of the module version, to
+	// read the attested files from.
+	versionDirPrivateAttr = "_version_dir"
+)
 
 func makeModuleAttestationsRule(attestations *bzpb.Attestations, attestationsJsonFile string) *rule.Rule {
 	r := rule.NewRule(moduleAttestationsKind, "attestations")
@@ -52,3 +57,45 @@ func moduleAttestationsKinds() map[string]rule.KindInfo {
 		},
 	}
 }
+
+// updateModuleAttestationsRuleVerification records the verification results
+// (by attestation name) on the rule and the proto.
+func updateModuleAttestationsRuleVerification(attestations *protoRule[*bzpb.Attestations], results map[string]*bzpb.AttestationVerification) {
+	statuses := make(map[string]string, len(results))
+	messages := make(map[string]string)
+	for name, result := range results {
+		statuses[name] = result.Status.String()
+		if result.Message != "" {
+			messages[name] = result.Message
+		}
+		attestations.Proto().Attestations[name].Verification = result
+	}
+	r := attestations.Rule()
+	r.SetAttr("verification_status", statuses)
+	if len(messages) > 0 {
+		r.SetAttr("verification_message", messages)
+	}
+}
+
+// updateModuleAttestationsRuleProvenance records the SLSA provenance of the
+// verified statements on the rule.
+func updateModuleAttestationsRuleProvenance(attestations *protoRule[*bzpb.Attestations], provenance *bzpb.SlsaProvenance) {
+	r := attestations.Rule()
+	for _, attr := range []struct {
+		name  string
+		value string
+	}{
+		{"provenance_predicate_type", provenance.PredicateType},
+		{"provenance_builder_id", provenance.BuilderId},
+		{"provenance_source_repository", provenance.SourceRepository},
+		{"provenance_source_ref", provenance.SourceRef},
+		{"provenance_source_commit", provenance.SourceCommit},
+		{"provenance_workflow_path", provenance.WorkflowPath},
+		{"provenance_build_invocation_id", provenance.BuildInvocationId},
+	} {
+		if attr.value != "" {
+			r.SetAttr(attr.name, attr.value)
+		}
+	}
+	r.SetAttr("provenance_attested_files", provenance.AttestedFiles)
+}
@@ -80,20 +80,22 @@ func makeModuleVersionCommitRule(cfg *config.Config, registryRoot, rel string, c
 	return r, nil
 }
 
-func (ext *bcrExtension) readModuleCommits(c *config.Config) {
+// readModuleCommits preloads the creation commits of all MODULE.bazel files
+// of the given registry.
+func readModuleCommits(c *config.Config, registryRoot string) map[moduleBazelRelPath]*bzpb.ModuleCommit {
 	// Preload all module commits in one git call for performance
 	ctx := context.Background()
-	submodulePath := filepath.Join(c.RepoRoot, ext.registryRoot)
+	submodulePath := filepath.Join(c.RepoRoot, registryRoot)
 	log.Printf("Preloading module commits from %s...", submodulePath)
 	commits, err := gitpkg.GetAllModuleCommits(ctx, submodulePath, "modules/*/*/MODULE.bazel")
 	if err != nil {
 		log.Printf("warning: failed to preload module commits: %v", err)
-		ext.moduleCommits = make(map[moduleBazelRelPath]*bzpb.ModuleCommit)
-	} else {
-		ext.moduleCommits = make(map[moduleBazelRelPath]*bzpb.ModuleCommit, len(commits))
-		for key, commit := range commits {
-			ext.moduleCommits[moduleBazelRelPath(key)] = commit
-		}
-		log.Printf("Preloaded %d module commits", len(commits))
+		return make(map[moduleBazelRelPath]*bzpb.ModuleCommit)
+	}
+	moduleCommits := make(map[moduleBazelRelPath]*bzpb.ModuleCommit, len(commits))
+	for key, commit := range commits {
+		moduleCommits[moduleBazelRelPath(key)] = commit
 	}
+	log.Printf("Preloaded %d module commits", len(commits))
+	return moduleCommits
 }
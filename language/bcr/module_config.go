@@ -0,0 +1,164 @@
+package bcr
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+// moduleNameFromRel returns the name of the module whose directory contains
+// rel (e.g. modules/foo/1.2.3 -> foo).  Returns false if rel is not inside a
+// module directory.
+func moduleNameFromRel(modulesRoot, rel string) (moduleName, bool) {
+	after, ok := strings.CutPrefix(rel, modulesRoot+"/")
+	if !ok || after == "" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(after, "/")
+	return moduleName(name), true
+}
+
+// applyModuleConfig records the per-module settings of the directory
+// configuration so they can be honored after rule generation.
+func (ext *bcrExtension) applyModuleConfig(cfg *Config, name moduleName) {
+	if cfg.docsMaxVersions > 0 {
+		ext.docsMaxVersions[name] = cfg.docsMaxVersions
+	} else {
+		delete(ext.docsMaxVersions, name)
+	}
+	// unchanged modules of an incremental run are skipped by skipsNetwork
+	if cfg.skipNetwork {
+		ext.skipNetworkModules[name] = true
+	} else {
+		delete(ext.skipNetworkModules, name)
+	}
+}
+
+// blacklistConfiguredUrls adds the given urls to the global blacklist if they
+// were blacklisted via directive.
+func (ext *bcrExtension) blacklistConfiguredUrls(cfg *Config, urls ...string) {
+	for _, url := range urls {
+		if url != "" && cfg.blacklistedUrls[url] {
+			ext.blacklistedUrls.Set(url)
+		}
+	}
+}
+
+// trackSkipNetworkRepositories records whether the given repositories are
+// referenced by a module that skips network access (skip), and whether it
+// does so even to refresh expired cache entries (skipRefresh).  A repository
+// is only skipped if every module referencing it skips network access.
+func (ext *bcrExtension) trackSkipNetworkRepositories(repos []string, skip, skipRefresh bool) {
+	track := func(repositories map[repositoryID]bool, id repositoryID, skip bool) {
+		if !skip {
+			repositories[id] = false
+		} else if _, seen := repositories[id]; !seen {
+			repositories[id] = true
+		}
+	}
+	for _, repo := range repos {
+		md, ok := parseRepositoryMetadataFromRepositoryString(repo)
+		if !ok {
+			continue
+		}
+		id := formatRepositoryID(md)
+		track(ext.skipNetworkRepositories, id, skip)
+		track(ext.skipRefreshRepositories, id, skipRefresh)
+	}
+}
+
+// filterSkipNetworkRepositories removes the repositories that are only
+// referenced by modules that skip network access.  In offline mode, all
+// repositories are removed and served from the backup registry instead.
+func (ext *bcrExtension) filterSkipNetworkRepositories(todo []*bzpb.RepositoryMetadata) []*bzpb.RepositoryMetadata {
+	if ext.offline {
+		ext.populateRepositoriesOffline(todo)
+		return nil
+	}
+	return slices.DeleteFunc(todo, func(md *bzpb.RepositoryMetadata) bool {
+		id := formatRepositoryID(md)
+		if ext.hasExpiredCacheEntry(bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA, string(id)) {
+			return ext.skipRefreshRepositories[id]
+		}
+		return ext.skipNetworkRepositories[id]
+	})
+}
+
+// skipsNetwork reports whether every one of the given module versions belongs
+// to a module that skips network access: via the bcr_skip_network directive,
+// or in incremental mode, because the module did not change (its cached
+// results still apply).  In offline mode, all modules skip network access.
+func (ext *bcrExtension) skipsNetwork(ids []moduleID) bool {
+	return ext.skipsNetworkIf(ids, func(name moduleName) bool {
+		return ext.skipNetworkModules[name] || !ext.isChangedModule(name)
+	})
+}
+
+// skipsNetworkLookup is like skipsNetwork for a lookup that is cached under
+// the given type and key.  If the cache entry has expired, only the modules
+// that skip network access via the bcr_skip_network directive skip it:
+// unchanged modules of an incremental run would otherwise never refresh
+// their cached results.
+func (ext *bcrExtension) skipsNetworkLookup(typ bzpb.CacheEntryType, key string, ids []moduleID) bool {
+	if ext.hasExpiredCacheEntry(typ, key) {
+		return ext.skipsNetworkIf(ids, func(name moduleName) bool {
+			return ext.skipNetworkModules[name]
+		})
+	}
+	return ext.skipsNetwork(ids)
+}
+
+// skipsNetworkIf reports whether the module of every one of the given module
+// versions skips network access according to skip (always in offline mode).
+func (ext *bcrExtension) skipsNetworkIf(ids []moduleID, skip func(moduleName) bool) bool {
+	if ext.offline {
+		return true
+	}
+	if len(ids) == 0 {
+		return false
+	}
+	for _, id := range ids {
+		if !skip(id.name()) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterDocsVersions removes the module versions that are not among the
+// docsMaxVersions most recent versions of their module.
+func (ext *bcrExtension) filterDocsVersions(ids []moduleID) []moduleID {
+	if len(ext.docsMaxVersions) == 0 {
+		return ids
+	}
+	return slices.DeleteFunc(slices.Clone(ids), func(id moduleID) bool {
+		limit, ok := ext.docsMaxVersions[id.name()]
+		if !ok {
+			return false
+		}
+		metadata, ok := ext.moduleMetadataRules[id.name()]
+		if !ok {
+			return false
+		}
+		return !slices.Contains(mostRecentVersions(metadata.Proto().Versions, limit), string(id.version()))
+	})
+}
+
+// mostRecentVersions returns the n highest of the given versions.
+func mostRecentVersions(versions []string, n int) []string {
+	sorted := slices.SortedFunc(slices.Values(versions), func(a, b string) int {
+		return compareVersions(moduleVersion(b), moduleVersion(a))
+	})
+	return sorted[:min(n, len(sorted))]
+}
+
+// filterExcludedModules removes excluded modules from the list of module
+// directories.
+func (ext *bcrExtension) filterExcludedModules(cfg *Config, subdirs []string) []string {
+	return slices.DeleteFunc(slices.Clone(subdirs), func(subdir string) bool {
+		name := path.Base(subdir)
+		return cfg.isExcludedModule(name) || ext.excludedModules[moduleName(name)]
+	})
+}
@@ -92,8 +92,30 @@ func makeOverrideRule(moduleName string, override *bzpb.ModuleDependencyOverride
 	}
 }
 
-// resolveModuleDependencyRule resolves the module and cycle attributes for a module_dependency rule
-func resolveModuleDependencyRule(modulesRoot string, r *rule.Rule, ix *resolve.RuleIndex, from label.Label, moduleToCycle map[moduleID]string, unresolvedModules map[moduleID]bool) {
+// overrideType returns the short name of the override type, as used by
+// DependencyTreeNode.override_type ("single_version", "git", "archive",
+// "local_path"), or "" if unknown.
+func overrideType(override *bzpb.ModuleDependencyOverride) string {
+	switch override.GetOverride().(type) {
+	case *bzpb.ModuleDependencyOverride_GitOverride:
+		return "git"
+	case *bzpb.ModuleDependencyOverride_ArchiveOverride:
+		return "archive"
+	case *bzpb.ModuleDependencyOverride_SingleVersionOverride:
+		return "single_version"
+	case *bzpb.ModuleDependencyOverride_LocalPathOverride:
+		return "local_path"
+	default:
+		return ""
+	}
+}
+
+// resolveModuleDependencyRule resolves the module and cycle attributes for a
+// module_dependency rule.  The module and cycle attributes are only set if
+// generateCycleRules is true, since otherwise the module_version targets
+// would form bazel dependency cycles.  When several registries provide the
+// module version, preferredResult picks the one bazel would use.
+func resolveModuleDependencyRule(modulesRoot string, r *rule.Rule, ix *resolve.RuleIndex, from label.Label, generateCycleRules bool, moduleToCycle map[moduleID]string, unresolvedModules map[moduleID]bool, preferredResult func([]resolve.FindResult) resolve.FindResult) {
 	// Get the dependency name and version to construct the import spec
 	depName := r.AttrString("dep_name")
 	version := r.AttrString("version")
@@ -128,11 +150,11 @@ func resolveModuleDependencyRule(modulesRoot string, r *rule.Rule, ix *resolve.R
 		return
 	}
 
-	// Use the first result (should only be one)
-	result := results[0]
+	// Use the result of the registry with the highest precedence
+	result := preferredResult(results)
 
 	// Check if this module is part of a cycle
-	if generateModuleDependencyCycleRules {
+	if generateCycleRules {
 		id := moduleID(id)
 		if cycleName, inCycle := moduleToCycle[id]; inCycle {
 			// Set the cycle attr to point to the cycle rule
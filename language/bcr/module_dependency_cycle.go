@@ -27,6 +27,29 @@ func moduleDependencyCycleKinds() map[string]rule.KindInfo {
 	}
 }
 
+// sortedCycleModules returns the module@version strings of the cycle members
+// in sorted order.
+func sortedCycleModules(cycle []moduleID) []string {
+	sorted := make([]string, len(cycle))
+	for i, id := range cycle {
+		sorted[i] = id.String()
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// cycleName returns the deterministic name of a cycle (also used as the
+// module_dependency_cycle rule name): the sorted members with @ replaced by
+// - and joined with +
+func cycleName(cycle []moduleID) string {
+	sorted := sortedCycleModules(cycle)
+	nameSegments := make([]string, len(sorted))
+	for i, moduleVersion := range sorted {
+		nameSegments[i] = strings.ReplaceAll(moduleVersion, "@", "-")
+	}
+	return strings.Join(nameSegments, "+")
+}
+
 // buildModuleToCycleMap creates a mapping from moduleID to cycle rule name
 func buildModuleToCycleMap(cycles [][]moduleID) map[moduleID]string {
 	moduleToCycle := make(map[moduleID]string)
@@ -36,23 +59,10 @@ func buildModuleToCycleMap(cycles [][]moduleID) map[moduleID]string {
 			continue
 		}
 
-		// Sort cycle members for deterministic naming
-		sorted := make([]string, len(cycle))
-		for i, id := range cycle {
-			sorted[i] = id.String()
-		}
-		sort.Strings(sorted)
-
-		// Generate cycle rule name: replace @ with - and join with +
-		nameSegments := make([]string, len(sorted))
-		for i, moduleVersion := range sorted {
-			nameSegments[i] = strings.ReplaceAll(moduleVersion, "@", "-")
-		}
-		cycleName := strings.Join(nameSegments, "+")
-
 		// Map each module version in the cycle to the cycle name
+		name := cycleName(cycle)
 		for _, id := range cycle {
-			moduleToCycle[id] = cycleName
+			moduleToCycle[id] = name
 		}
 	}
 
@@ -61,24 +71,10 @@ func buildModuleToCycleMap(cycles [][]moduleID) map[moduleID]string {
 
 // makeModuleDependencyCycleRule generates a module_dependency_cycle rule for a detected cycles
 func makeModuleDependencyCycleRule(cycle []moduleID) *rule.Rule {
-	// Sort cycle members for deterministic naming
-	sorted := make([]string, len(cycle))
-	for i, id := range cycle {
-		sorted[i] = id.String()
-	}
-	sort.Strings(sorted)
-
-	// Generate rule name: replace @ with - and join with +
-	nameSegments := make([]string, len(sorted))
-	for i, moduleVersion := range sorted {
-		nameSegments[i] = strings.ReplaceAll(moduleVersion, "@", "-")
-	}
-	ruleName := strings.Join(nameSegments, "+")
-
-	r := rule.NewRule("module_dependency_cycle", ruleName)
+	r := rule.NewRule("module_dependency_cycle", cycleName(cycle))
 
 	// Set cycle_modules attr with original module@version strings
-	r.SetAttr("cycle_modules", sorted)
+	r.SetAttr("cycle_modules", sortedCycleModules(cycle))
 
 	r.SetAttr("visibility", []string{"//visibility:public"})
 
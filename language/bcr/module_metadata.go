@@ -5,6 +5,7 @@ import (
 	"log"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazelbuild/bazel-gazelle/label"
 	"github.com/bazelbuild/bazel-gazelle/resolve"
 	"github.com/bazelbuild/bazel-gazelle/rule"
 )
@@ -84,7 +85,7 @@ func moduleMetadataImports(r *rule.Rule) []resolve.ImportSpec {
 // resolveModuleMetadataRule resolves the deps and overrides attributes for a module_metadata rule
 // by looking up module_version rules for each version in the versions list
 // and override rules for the module
-func resolveModuleMetadataRule(r *rule.Rule, ix *resolve.RuleIndex) {
+func resolveModuleMetadataRule(r *rule.Rule, ix *resolve.RuleIndex, from label.Label) {
 	// Get the versions attribute
 	versions := r.AttrStrings("versions")
 	moduleName := r.Name()
@@ -99,8 +100,9 @@ func resolveModuleMetadataRule(r *rule.Rule, ix *resolve.RuleIndex) {
 				Imp:  newModuleID(moduleName, version).String(),
 			}
 
-			// Find the module_version rule that provides this import
-			results := ix.FindRulesByImport(importSpec, bcrLangName)
+			// Find the module_version rule that provides this import (in
+			// the same registry)
+			results := findResultsInPackage(ix.FindRulesByImport(importSpec, bcrLangName), from.Pkg)
 
 			if len(results) == 0 {
 				log.Printf("resolveModuleMetadataRule: No module_version found for %s@%s in module_metadata", moduleName, version)
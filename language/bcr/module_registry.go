@@ -9,6 +9,7 @@ import (
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
 	gitpkg "github.com/bazel-contrib/bcr-frontend/pkg/git"
 	"github.com/bazelbuild/bazel-gazelle/config"
+	"github.com/bazelbuild/bazel-gazelle/label"
 	"github.com/bazelbuild/bazel-gazelle/resolve"
 	"github.com/bazelbuild/bazel-gazelle/rule"
 )
@@ -35,7 +36,7 @@ func moduleRegistryKinds() map[string]rule.KindInfo {
 	}
 }
 
-func makeModuleRegistryRule(name string, subdirs []string, registryURL string, cycleRules []*rule.Rule, cfg *config.Config) *rule.Rule {
+func makeModuleRegistryRule(name string, subdirs []string, registryURL, registryRoot string, cycleRules []*rule.Rule, cfg *config.Config) *rule.Rule {
 	r := rule.NewRule(moduleRegistryKind, name)
 	if len(cycleRules) > 0 {
 		cycles := make([]string, len(cycleRules))
@@ -50,7 +51,7 @@ func makeModuleRegistryRule(name string, subdirs []string, registryURL string, c
 
 	// Fetch registry metadata from git submodule
 	ctx := context.Background()
-	submodulePath := filepath.Join(cfg.RepoRoot, "data", "bazel-central-registry")
+	submodulePath := filepath.Join(cfg.RepoRoot, registryRoot)
 	registry, err := getBazelCentralRegistryMetadata(ctx, submodulePath)
 	if err != nil {
 		log.Printf("warning: failed to fetch registry metadata: %v", err)
@@ -65,8 +66,10 @@ func makeModuleRegistryRule(name string, subdirs []string, registryURL string, c
 }
 
 // resolveModuleRegistryRule resolves the deps and bazel_versions attributes for
-// a module_registry rule by looking up module_metadata and bazel_version rules
-func resolveModuleRegistryRule(r *rule.Rule, ix *resolve.RuleIndex) {
+// a module_registry rule by looking up module_metadata and bazel_version rules.
+// Only module_metadata rules below the registry package are considered, since
+// other registries may provide modules of the same name.
+func resolveModuleRegistryRule(r *rule.Rule, ix *resolve.RuleIndex, from label.Label) {
 	// Get the subdirs private attribute
 	subdirsRaw := r.PrivateAttr("subdirs")
 	if subdirsRaw == nil {
@@ -89,7 +92,7 @@ func resolveModuleRegistryRule(r *rule.Rule, ix *resolve.RuleIndex) {
 		}
 
 		// Find all module_metadata rules with this import
-		results := ix.FindRulesByImport(importSpec, bcrLangName)
+		results := findResultsInPackage(ix.FindRulesByImport(importSpec, bcrLangName), from.Pkg)
 		if len(results) == 0 {
 			log.Printf("No module_metadata found for module %s", moduleName)
 			continue
@@ -103,7 +106,12 @@ func resolveModuleRegistryRule(r *rule.Rule, ix *resolve.RuleIndex) {
 		r.SetAttr("deps", deps)
 	}
 
-	// Resolve bazel_version rules
+	resolveBazelVersions(r, ix)
+}
+
+// resolveBazelVersions sets the bazel_versions attribute of a module_registry
+// rule to all bazel_version rules.
+func resolveBazelVersions(r *rule.Rule, ix *resolve.RuleIndex) {
 	bazelVersionImportSpec := resolve.ImportSpec{
 		Lang: bcrLangName,
 		Imp:  bazelVersionKind,
@@ -1,10 +1,16 @@
 package bcr
 
 import (
+	"fmt"
+	"maps"
 	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
 	"github.com/bazel-contrib/bcr-frontend/pkg/netutil"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sourcejson"
 	"github.com/bazelbuild/bazel-gazelle/rule"
 )
 
@@ -62,6 +68,65 @@ func makeModuleSourceRule(module *bzpb.ModuleVersion, source *bzpb.ModuleSource,
 	return r
 }
 
+// verifyModuleSourceFiles checks the local patch and overlay files of the
+// module version against their integrity.  The results are recorded in the
+// source proto and on the rule (as 'STATUS' or 'STATUS ACTUAL_INTEGRITY' per
+// filename); files that do not verify are reported as diagnostics.
+func (ext *bcrExtension) verifyModuleSourceFiles(r *rule.Rule, source *bzpb.ModuleSource, modulesRoot, rel, versionDir string) {
+	sourcejson.VerifyFiles(versionDir, source)
+
+	for _, kind := range []struct {
+		dir     string
+		attr    string
+		results map[string]*bzpb.FileIntegrity
+	}{
+		{dir: "patches", attr: "patch_integrity", results: source.PatchIntegrity},
+		{dir: "overlay", attr: "overlay_integrity", results: source.OverlayIntegrity},
+	} {
+		if len(kind.results) == 0 {
+			continue
+		}
+		values := make(map[string]string, len(kind.results))
+		for _, filename := range slices.Sorted(maps.Keys(kind.results)) {
+			result := kind.results[filename]
+			values[filename] = formatFileIntegrity(result)
+			if result.Status != bzpb.FileIntegrityStatus_FILE_INTEGRITY_VERIFIED {
+				// names that are not local paths are reported on source.json
+				file := path.Join(kind.dir, filename)
+				if !filepath.IsLocal(filename) {
+					file = "source.json"
+				}
+				ext.diagnostics.add(modulesRoot, rel, file, bzpb.DiagnosticSeverity_ERROR, fileIntegrityError(filename, result))
+			}
+		}
+		r.SetAttr(kind.attr, values)
+	}
+}
+
+// formatFileIntegrity formats the verification result for the
+// patch_integrity and overlay_integrity attributes.
+func formatFileIntegrity(result *bzpb.FileIntegrity) string {
+	if result.ActualIntegrity != "" {
+		return result.Status.String() + " " + result.ActualIntegrity
+	}
+	return result.Status.String()
+}
+
+func fileIntegrityError(filename string, result *bzpb.FileIntegrity) error {
+	switch result.Status {
+	case bzpb.FileIntegrityStatus_FILE_INTEGRITY_MISMATCH:
+		return fmt.Errorf("integrity mismatch: the file has integrity %s", result.ActualIntegrity)
+	case bzpb.FileIntegrityStatus_FILE_INTEGRITY_FILE_MISSING:
+		return fmt.Errorf("file listed in source.json does not exist")
+	case bzpb.FileIntegrityStatus_FILE_INTEGRITY_MALFORMED:
+		if !filepath.IsLocal(filename) {
+			return fmt.Errorf("file %s listed in source.json is not a local path", filename)
+		}
+		return fmt.Errorf("integrity listed in source.json is not a valid SRI string")
+	}
+	return fmt.Errorf("integrity could not be verified (%v)", result.Status)
+}
+
 func updateModuleSourceRuleDocsUrlStatus(r *rule.Rule, status netutil.URLStatus) {
 	if status.Code != 0 {
 		r.SetAttr("docs_url_status_code", status.Code)
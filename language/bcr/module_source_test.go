@@ -0,0 +1,47 @@
+package bcr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+)
+
+func TestVerifyModuleSourceFiles(t *testing.T) {
+	versionDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(versionDir, "patches"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(versionDir, "patches", "fix.patch"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	const emptyIntegrity = "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+	source := &bzpb.ModuleSource{
+		Patches: map[string]string{
+			"fix.patch":     emptyIntegrity,
+			"missing.patch": emptyIntegrity,
+		},
+	}
+	ext := &bcrExtension{}
+	r := rule.NewRule(moduleSourceKind, "source")
+	ext.verifyModuleSourceFiles(r, source, "modules", "modules/foo/1.0.0", versionDir)
+
+	if got := source.PatchIntegrity["missing.patch"].GetStatus(); got != bzpb.FileIntegrityStatus_FILE_INTEGRITY_FILE_MISSING {
+		t.Errorf("missing.patch status = %v", got)
+	}
+	if r.Attr("patch_integrity") == nil {
+		t.Error("expected patch_integrity to be set")
+	}
+	if r.Attr("overlay_integrity") != nil {
+		t.Error("expected overlay_integrity not to be set")
+	}
+	if len(ext.diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(ext.diagnostics))
+	}
+	if d := ext.diagnostics[0]; d.File != "modules/foo/1.0.0/patches/missing.patch" || d.ModuleName != "foo" || d.Version != "1.0.0" {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
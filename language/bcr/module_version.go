@@ -6,6 +6,7 @@ import (
 	"slices"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/versionutil"
 	"github.com/bazelbuild/bazel-gazelle/label"
 	"github.com/bazelbuild/bazel-gazelle/resolve"
 	"github.com/bazelbuild/bazel-gazelle/rule"
@@ -107,18 +108,19 @@ func moduleVersionImports(r *rule.Rule) []resolve.ImportSpec {
 	return []resolve.ImportSpec{importSpec}
 }
 
-func resolveModuleVersionRule(r *rule.Rule, moduleRules map[moduleName]*protoRule[*bzpb.ModuleMetadata]) {
+func resolveModuleVersionRule(r *rule.Rule, protoRule *protoRule[*bzpb.ModuleMetadata]) {
 	moduleName := moduleName(r.AttrString("module_name"))
 	moduleVersion := moduleVersion(r.AttrString("version"))
 
-	if protoRule, ok := moduleRules[moduleName]; !ok {
+	if protoRule == nil {
 		// https://github.com/bazelbuild/bazel-central-registry/tree/8c5761038905a45f1cf2d1098ba9917a456d20bb/modules/postgres/14.18
 		log.Printf("WARN: while resolving latest versions, discovered unknown module: %v", moduleName)
 	} else {
 		versions := protoRule.Proto().Versions
 
-		// latest version is expected to be the last element in the list
-		if len(versions) > 0 && versions[len(versions)-1] == string(moduleVersion) {
+		// metadata.json is not guaranteed to list versions in order, so pick
+		// the highest by version precedence rather than the last element.
+		if len(versions) > 0 && versionutil.Max(versions) == string(moduleVersion) {
 			r.SetAttr("is_latest_version", true)
 			r.SetPrivateAttr(isLatestVersionPrivateAttr, true)
 		}
@@ -172,6 +174,49 @@ func updateModuleVersionRuleMvsAttr(moduleVersions map[moduleID]*protoRule[*bzpb
 	return
 }
 
+// updateModuleVersionRuleMvsOverridesAttr sets the mvs_overrides attribute
+// (module name -> override type) on module_version rules whose own overrides
+// affected the MVS result.
+func updateModuleVersionRuleMvsOverridesAttr(moduleVersions map[moduleID]*protoRule[*bzpb.ModuleVersion], results mvsResults) (annotatedCount int) {
+	for id, result := range results {
+		if len(result.overrideTypes) == 0 {
+			continue
+		}
+		protoRule, exists := moduleVersions[id]
+		if !exists {
+			continue
+		}
+		overrideTypes := make(map[string]string, len(result.overrideTypes))
+		for name, overrideType := range result.overrideTypes {
+			overrideTypes[string(name)] = overrideType
+		}
+		protoRule.Rule().SetAttr("mvs_overrides", overrideTypes)
+		annotatedCount++
+	}
+
+	return
+}
+
+// updateModuleVersionRuleResolutionErrorAttr sets the resolution_conflicts
+// attribute on module_version rules whose dependency graph would fail to
+// resolve.
+func updateModuleVersionRuleResolutionErrorAttr(moduleVersions map[moduleID]*protoRule[*bzpb.ModuleVersion], resolutionErrors map[moduleID]*resolutionError) (annotatedCount int) {
+	for id, resolutionErr := range resolutionErrors {
+		protoRule, exists := moduleVersions[id]
+		if !exists {
+			continue
+		}
+		protoRule.Rule().SetAttr("resolution_conflicts", resolutionErr.toStringList())
+		annotatedCount++
+	}
+
+	if annotatedCount > 0 {
+		log.Printf("WARN: %d module versions have dependency resolution errors", annotatedCount)
+	}
+
+	return
+}
+
 func isLatestVersion(moduleVersionRule *protoRule[*bzpb.ModuleVersion]) bool {
 	isLatest, ok := moduleVersionRule.Rule().PrivateAttr(isLatestVersionPrivateAttr).(bool)
 	return ok && isLatest
@@ -241,3 +286,23 @@ func makeBzlDepsSelectExpr(labels []string) *build.CallExpr {
 		},
 	}
 }
+
+// updateModuleVersionRuleMvsDevUpgradesAttr sets the mvs_dev_upgrades
+// attribute on module_version rules for which dev dependencies upgrade some
+// of the modules selected for regular dependencies.
+func updateModuleVersionRuleMvsDevUpgradesAttr(moduleVersions map[moduleID]*protoRule[*bzpb.ModuleVersion], upgrades map[moduleID][]*devDependencyUpgrade) (annotatedCount int) {
+	for id, list := range upgrades {
+		protoRule, exists := moduleVersions[id]
+		if !exists || len(list) == 0 {
+			continue
+		}
+		values := make([]string, len(list))
+		for i, upgrade := range list {
+			values[i] = upgrade.String()
+		}
+		protoRule.Rule().SetAttr("mvs_dev_upgrades", values)
+		annotatedCount++
+	}
+
+	return
+}
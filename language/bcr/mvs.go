@@ -2,8 +2,12 @@ package bcr
 
 import (
 	"log"
-	"sync"
+	"maps"
+	"slices"
+	"strings"
 
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/versionutil"
 	"github.com/dominikbraun/graph"
 )
 
@@ -19,6 +23,46 @@ func (md *moduleDeps) ToStringDict() map[string]string {
 
 type mvs map[moduleID]moduleDeps
 
+// moduleOverrides maps a module name to the override declared for it in the
+// root module's MODULE.bazel
+type moduleOverrides map[moduleName]*bzpb.ModuleDependencyOverride
+
+// mvsResult is the outcome of running MVS with a single module@version as the
+// root
+type mvsResult struct {
+	// selected is the version selected for each module (including the root)
+	selected moduleDeps
+	// overrideTypes records the override type (e.g. "single_version") of
+	// selected modules that were affected by a root override
+	overrideTypes map[moduleName]string
+	// err is non-nil if bazel would fail to resolve the dependency graph
+	err *resolutionError
+}
+
+// mvsResults maps "module@version" -> MVS result with that module as the root
+type mvsResults map[moduleID]*mvsResult
+
+// selected returns the selected versions for each root
+func (r mvsResults) selected() mvs {
+	result := make(mvs, len(r))
+	for id, res := range r {
+		result[id] = res.selected
+	}
+	return result
+}
+
+// resolutionErrors returns the resolution errors for roots that failed to
+// resolve
+func (r mvsResults) resolutionErrors() map[moduleID]*resolutionError {
+	result := make(map[moduleID]*resolutionError)
+	for id, res := range r {
+		if res.err != nil {
+			result[id] = res.err
+		}
+	}
+	return result
+}
+
 // calculateMvs implements Minimum Version Selection algorithm
 // This calculates MVS for each individual module@version in the registry
 func (ext *bcrExtension) calculateMvs(bzlRepositories rankedModuleVersionMap) {
@@ -27,35 +71,44 @@ func (ext *bcrExtension) calculateMvs(bzlRepositories rankedModuleVersionMap) {
 	// perModuleVersionMvs maps "module@version" -> (module name -> selected
 	// version) This shows what MVS would select for regular deps if that
 	// specific module@version were the root
-	perModuleVersionMvs := ext.calculatePerModuleVersionMvs(ext.regularDepGraph, "regular")
+	perModuleVersionResults := ext.calculatePerModuleVersionMvs(ext.regularDepGraph, "regular")
+	perModuleVersionMvs := perModuleVersionResults.selected()
 	// perModuleVersionMvsDev maps "module@version" -> (module name -> selected
 	// version) This shows what MVS would select for dev deps if that specific
 	// module@version were the root
-	perModuleVersionMvsDev := ext.calculatePerModuleVersionMvs(ext.devDepGraph, "dev")
+	perModuleVersionMvsDev := ext.calculatePerModuleVersionMvs(ext.devDepGraph, "dev").selected()
 	// perModuleVersionMvsMerged records selected versions in the merged set of
-	// regular + dev
-	// perModuleVersionMvsMerged := ext.calculatePerModuleVersionMvs(allVersions, ext.depGraph, "merged")
+	// regular + dev (dev deps of the root only, like bazel)
+	perModuleVersionMvsMerged := ext.calculatePerModuleVersionMergedMvs(perModuleVersionResults).selected()
 
 	// Annotate module_version rules with their MVS results
 	updateModuleVersionRuleMvsAttr(ext.moduleVersionRules, "mvs", perModuleVersionMvs)
 	updateModuleVersionRuleMvsAttr(ext.moduleVersionRules, "mvs_dev", perModuleVersionMvsDev)
+	updateModuleVersionRuleMvsAttr(ext.moduleVersionRules, "mvs_merged", perModuleVersionMvsMerged)
+	updateModuleVersionRuleMvsDevUpgradesAttr(ext.moduleVersionRules, devOnlyUpgrades(perModuleVersionMvs, perModuleVersionMvsMerged))
+	// Only resolution errors for regular deps are reported: dev deps of a
+	// non-root module are ignored by bazel.
+	updateModuleVersionRuleResolutionErrorAttr(ext.moduleVersionRules, perModuleVersionResults.resolutionErrors())
+	updateModuleVersionRuleMvsOverridesAttr(ext.moduleVersionRules, perModuleVersionResults)
+	ext.calculateBazelCompatibility(perModuleVersionMvs)
 
 	ext.rankBzlRepositoryVersions(perModuleVersionMvs, bzlRepositories)
 	ext.finalizeBzlSrcsAndDeps(bzlRepositories)
 }
 
 // calculatePerModuleVersionMvs computes MVS for each module@version in the given graph
-// Returns map of "module@version" -> (module name -> selected version)
+// Returns map of "module@version" -> MVS result (selected versions, applied
+// overrides and resolution error, if any)
 // depGraph is the dependency graph to use (either regular deps or dev deps)
 // depType is a description for the progress bar ("regular" or "dev")
-func (ext *bcrExtension) calculatePerModuleVersionMvs(depGraph graph.Graph[moduleID, moduleID], depType string) mvs {
-	perModuleVersionMvs := make(mvs)
+func (ext *bcrExtension) calculatePerModuleVersionMvs(depGraph graph.Graph[moduleID, moduleID], depType string) mvsResults {
+	results := make(mvsResults)
 
 	// Get all module@version nodes from the graph
 	adjacencyMap, err := depGraph.AdjacencyMap()
 	if err != nil {
 		log.Printf("Error getting adjacency map for per-version MVS (%s): %v", depType, err)
-		return perModuleVersionMvs
+		return results
 	}
 
 	// Collect module keys to process (excluding unresolved)
@@ -68,119 +121,275 @@ func (ext *bcrExtension) calculatePerModuleVersionMvs(depGraph graph.Graph[modul
 
 	if len(moduleIDs) == 0 {
 		log.Println("No module versions to calculate MVS for")
-		return perModuleVersionMvs
-	}
-
-	// Parallelize MVS calculations using worker pool
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	// Create channels for jobs and results
-	jobChan := make(chan moduleID, len(moduleIDs))
-	resultChan := make(chan struct {
-		id     moduleID
-		result map[moduleName]moduleVersion
-	}, len(moduleIDs))
-
-	// Start worker goroutines
-	numWorkers := min(10, len(moduleIDs)) // Limit concurrent workers
-
-	for range numWorkers {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for id := range jobChan {
-				// Run MVS with this single module@version as the root
-				selected := runMvs([]moduleID{id}, adjacencyMap)
-				resultChan <- struct {
-					id     moduleID
-					result map[moduleName]moduleVersion
-				}{id: id, result: selected}
-			}
-		}()
+		return results
 	}
 
-	// Send jobs
-	go func() {
-		for _, id := range moduleIDs {
-			jobChan <- id
-		}
-		close(jobChan)
-	}()
+	// Condense the graph into strongly connected components so that the
+	// reachable closures can be computed bottom-up and shared between roots
+	sccs, err := graph.StronglyConnectedComponents(depGraph)
+	if err != nil {
+		log.Printf("Error getting strongly connected components for per-version MVS (%s): %v", depType, err)
+		return results
+	}
 
-	// Close result channel when all workers are done
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
+	compat := ext.newCompatibilityIndex()
 
-	// Collect results with progress reporting
-	for result := range resultChan {
-		mu.Lock()
-		perModuleVersionMvs[result.id] = result.result
-		mu.Unlock()
-	}
+	results = runPerModuleVersionMvs(moduleIDs, adjacencyMap, sccs, compat, ext.rootModuleOverrides, 10)
+
+	log.Printf("Calculated MVS for %d module versions (%d resolution errors)", len(moduleIDs), len(results.resolutionErrors()))
+	return results
+}
 
-	log.Printf("Calculated MVS for %d module versions", len(moduleIDs))
-	return perModuleVersionMvs
+// rootModuleOverrides returns the overrides declared in the MODULE.bazel of
+// the given module version.  Bazel only honors overrides of the root module,
+// so these apply only when this module version is the MVS root.
+func (ext *bcrExtension) rootModuleOverrides(id moduleID) moduleOverrides {
+	protoRule, ok := ext.moduleVersionRules[id]
+	if !ok || len(protoRule.Proto().Override) == 0 {
+		return nil
+	}
+	overrides := make(moduleOverrides)
+	for _, override := range protoRule.Proto().Override {
+		if override.ModuleName != "" {
+			overrides[moduleName(override.ModuleName)] = override
+		}
+	}
+	return overrides
 }
 
 // runMvs runs the MVS algorithm starting from root module@version keys
 // adjacencyMap is passed in to avoid repeated fetches
-// Returns the selected version for each module (excluding the roots themselves)
-func runMvs(roots []moduleID, adjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID]) moduleDeps {
-	selected := make(moduleDeps)
-
-	// Initialize selected versions from roots
+// Returns the selected version for each module (including the roots
+// themselves), the override types applied, and a non-nil resolution error if
+// more than one compatibility level of the same module is required.
+//
+// Like bazel, selection happens in two phases.  First, every module version
+// reachable from the roots is placed into a selection group keyed by (module
+// name, compatibility level) and the highest version in each group is
+// selected.  Then the graph is walked again from the roots, resolving each
+// dependency edge to the selected version of the highest compatibility level
+// allowed by the edge's max_compatibility_level.  Modules that remain
+// reachable at more than one compatibility level are reported as conflicts.
+//
+// Root overrides are applied to every dependency edge before selection:
+// single_version_override pins the module to the given version, while
+// non-registry overrides (git, archive, local_path) replace the module with
+// one having an empty version.  The dependencies of a non-registry module
+// are unknown, so its subgraph is dropped.
+//
+// If groups is non-nil, it is used as the (precomputed) result of phase 1.
+//
+// If devAdjacencyMap is non-nil, the dev dependencies of the roots are
+// followed as well.  Like bazel, dev dependencies of non-root modules are
+// ignored.
+func runMvs(roots []moduleID, adjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID], compat *compatibilityIndex, overrides moduleOverrides, groups selectionGroups, devAdjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID]) *mvsResult {
+	// The roots always win over any other version of the same module
+	rootVersions := make(moduleDeps)
 	for _, id := range roots {
-		selected[id.name()] = id.version()
+		rootVersions[id.name()] = id.version()
 	}
 
-	// Build the transitive closure of dependencies
-	// Start from roots and traverse the graph, selecting maximum versions
-	visited := make(map[moduleID]bool)
-	var visit func(id moduleID)
+	// override rewrites a dependency edge target according to the root
+	// overrides.
+	override := func(to moduleID) moduleID {
+		name := to.name()
+		if rootVersion, isRoot := rootVersions[name]; isRoot {
+			return toModuleID(name, rootVersion)
+		}
+		o, ok := overrides[name]
+		if !ok {
+			return to
+		}
+		switch o := o.Override.(type) {
+		case *bzpb.ModuleDependencyOverride_SingleVersionOverride:
+			if o.SingleVersionOverride.Version != "" {
+				return toModuleID(name, moduleVersion(o.SingleVersionOverride.Version))
+			}
+		case *bzpb.ModuleDependencyOverride_GitOverride,
+			*bzpb.ModuleDependencyOverride_ArchiveOverride,
+			*bzpb.ModuleDependencyOverride_LocalPathOverride:
+			return toModuleID(name, "")
+		}
+		return to
+	}
+
+	// Phase 1: collect the unpruned graph and compute selection groups.
+	if groups == nil {
+		groups = make(selectionGroups)
+		visited := make(map[moduleID]bool)
+		var visit func(id moduleID)
+
+		visit = func(id moduleID) {
+			if visited[id] {
+				return
+			}
+			visited[id] = true
+
+			// Update selected version if this is higher
+			groups.add(id.name(), compat.level(id), id.version())
 
-	visit = func(id moduleID) {
-		if visited[id] {
-			return
+			// Visit dependencies using adjacency map
+			for targetKey := range adjacencyMap[id] {
+				visit(override(targetKey))
+			}
 		}
-		visited[id] = true
 
-		moduleName := id.name()
-		version := id.version()
+		// Visit all root module@version keys (and their transitive dependencies)
+		for _, id := range roots {
+			visit(id)
+			for targetKey := range devAdjacencyMap[id] {
+				visit(override(targetKey))
+			}
+		}
+	}
 
-		// Update selected version if this is higher
-		if currentVersion, exists := selected[moduleName]; !exists || compareVersions(version, currentVersion) > 0 {
-			selected[moduleName] = version
+	// resolve maps a dependency edge to the module version that bazel would
+	// actually use.
+	resolve := func(from, to moduleID) moduleID {
+		to = override(to)
+		name := to.name()
+		if _, isRoot := rootVersions[name]; isRoot {
+			return to
 		}
+		minLevel := compat.level(to)
+		maxLevel := max(minLevel, compat.maxLevel(from, name))
 
-		// Visit dependencies using adjacency map
-		if deps, exists := adjacencyMap[id]; exists {
-			for targetKey := range deps {
-				visit(targetKey)
+		bestLevel := minLevel
+		for level := range groups[name] {
+			if level > bestLevel && level <= maxLevel {
+				bestLevel = level
 			}
 		}
+		if version, ok := groups[name][bestLevel]; ok {
+			return toModuleID(name, version)
+		}
+		return to
 	}
 
-	// Visit all root module@version keys (and their transitive dependencies)
+	// Phase 2: walk the resolved graph breadth-first (so that reported paths
+	// are the shortest ones) and record which compatibility levels of each
+	// module are reachable.
+	parents := make(map[moduleID]moduleID)
+	reached := make(map[moduleID]bool)
+	var queue []moduleID
+	isRoot := make(map[moduleID]bool, len(roots))
 	for _, id := range roots {
-		visit(id)
+		isRoot[id] = true
+		if !reached[id] {
+			reached[id] = true
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		targets := slices.Collect(maps.Keys(adjacencyMap[id]))
+		if isRoot[id] {
+			targets = slices.AppendSeq(targets, maps.Keys(devAdjacencyMap[id]))
+		}
+		slices.SortFunc(targets, compareModuleIDs)
+		for _, target := range targets {
+			resolved := resolve(id, target)
+			if reached[resolved] {
+				continue
+			}
+			reached[resolved] = true
+			parents[resolved] = id
+			queue = append(queue, resolved)
+		}
+	}
+
+	selected := make(moduleDeps)
+	reachedLevels := make(map[moduleName]map[int32]moduleID)
+	for id := range reached {
+		name := id.name()
+		level := compat.level(id)
+		if reachedLevels[name] == nil {
+			reachedLevels[name] = make(map[int32]moduleID)
+		}
+		reachedLevels[name][level] = id
+
+		// In case of conflict, report the highest compatibility level as
+		// selected (bazel would fail instead).
+		if currentVersion, exists := selected[name]; !exists || compat.level(toModuleID(name, currentVersion)) < level {
+			selected[name] = id.version()
+		}
+	}
+
+	var resolutionErr *resolutionError
+	for _, name := range slices.Sorted(maps.Keys(reachedLevels)) {
+		byLevel := reachedLevels[name]
+		if len(byLevel) < 2 {
+			continue
+		}
+		conflict := &compatibilityConflict{moduleName: name}
+		for _, level := range slices.Sorted(maps.Keys(byLevel)) {
+			id := byLevel[level]
+			conflict.requirements = append(conflict.requirements, &compatibilityRequirement{
+				level:   level,
+				version: id.version(),
+				path:    pathToModule(id, parents),
+			})
+		}
+		if resolutionErr == nil {
+			resolutionErr = &resolutionError{}
+		}
+		resolutionErr.conflicts = append(resolutionErr.conflicts, conflict)
 	}
 
-	return selected
+	var overrideTypes map[moduleName]string
+	for name, o := range overrides {
+		if _, ok := selected[name]; !ok {
+			continue
+		}
+		if _, isRoot := rootVersions[name]; isRoot {
+			continue
+		}
+		if overrideTypes == nil {
+			overrideTypes = make(map[moduleName]string)
+		}
+		overrideTypes[name] = overrideType(o)
+	}
+
+	return &mvsResult{
+		selected:      selected,
+		overrideTypes: overrideTypes,
+		err:           resolutionErr,
+	}
 }
 
-// compareVersions compares two version strings lexicographically
-// Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
+// pathToModule follows the parent links recorded during the resolved graph
+// walk and returns the path from the root to the given module version.
+func pathToModule(id moduleID, parents map[moduleID]moduleID) []moduleID {
+	path := []moduleID{id}
+	for {
+		parent, ok := parents[id]
+		if !ok {
+			break
+		}
+		path = append(path, parent)
+		id = parent
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// compareModuleIDs orders module IDs by name then version
+func compareModuleIDs(a, b moduleID) int {
+	if c := strings.Compare(string(a.name()), string(b.name())); c != 0 {
+		return c
+	}
+	return compareVersions(a.version(), b.version())
+}
+
+// compareVersions compares two version strings using Bazel module version
+// semantics. Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
 // This is used during MVS graph traversal to select the maximum version
 // when multiple versions of the same module are encountered.
 func compareVersions(v1, v2 moduleVersion) int {
 	if v1 == v2 {
 		return 0
 	}
-	if v1 < v2 {
-		return -1
-	}
-	return 1
+	return versionutil.Compare(string(v1), string(v2))
 }
@@ -0,0 +1,245 @@
+package bcr
+
+import (
+	"sync"
+
+	"github.com/dominikbraun/graph"
+)
+
+// selectionGroups maps module name -> compatibility level -> highest version
+// seen in that (name, level) selection group.
+type selectionGroups map[moduleName]map[int32]moduleVersion
+
+// add records the given module version in its selection group.
+func (g selectionGroups) add(name moduleName, level int32, version moduleVersion) {
+	byLevel, ok := g[name]
+	if !ok {
+		byLevel = make(map[int32]moduleVersion)
+		g[name] = byLevel
+	}
+	if currentVersion, exists := byLevel[level]; !exists || compareVersions(version, currentVersion) > 0 {
+		byLevel[level] = version
+	}
+}
+
+// componentSummary is the memoized result of MVS phase 1 for a strongly
+// connected component of the dependency graph: the selection groups of every
+// module version reachable from it.  All members of a component reach each
+// other, so they share a single summary.
+type componentSummary struct {
+	groups selectionGroups
+	// lowest and highest reachable version of each module; if they differ,
+	// more than one version of the module is reachable.
+	lowest  moduleDeps
+	highest moduleDeps
+}
+
+func newComponentSummary() *componentSummary {
+	return &componentSummary{
+		groups:  make(selectionGroups),
+		lowest:  make(moduleDeps),
+		highest: make(moduleDeps),
+	}
+}
+
+// add records a single module version.
+func (s *componentSummary) add(name moduleName, level int32, version moduleVersion) {
+	s.groups.add(name, level, version)
+	if lowest, ok := s.lowest[name]; !ok || compareVersions(version, lowest) < 0 {
+		s.lowest[name] = version
+	}
+	if highest, ok := s.highest[name]; !ok || compareVersions(version, highest) > 0 {
+		s.highest[name] = version
+	}
+}
+
+// merge folds the summary of a child component into this one.
+func (s *componentSummary) merge(other *componentSummary) {
+	for name, byLevel := range other.groups {
+		for level, version := range byLevel {
+			s.groups.add(name, level, version)
+		}
+	}
+	for name, version := range other.lowest {
+		if lowest, ok := s.lowest[name]; !ok || compareVersions(version, lowest) < 0 {
+			s.lowest[name] = version
+		}
+	}
+	for name, version := range other.highest {
+		if highest, ok := s.highest[name]; !ok || compareVersions(version, highest) > 0 {
+			s.highest[name] = version
+		}
+	}
+}
+
+// appliesTo reports whether the summary equals MVS phase 1 for the given
+// root.  Phase 1 rewrites dependency edges that point to another version of
+// the root module or to a module overridden by the root; if no such module
+// is reachable, the unrewritten closure is the same as the rewritten one.
+func (s *componentSummary) appliesTo(root moduleID, overrides moduleOverrides) bool {
+	if s == nil {
+		return false
+	}
+	rootName := root.name()
+	if s.lowest[rootName] != root.version() || s.highest[rootName] != root.version() {
+		return false
+	}
+	for name := range overrides {
+		if name == rootName {
+			continue
+		}
+		if _, reachable := s.groups[name]; reachable {
+			return false
+		}
+	}
+	return true
+}
+
+// runPerModuleVersionMvs runs MVS with each of the given module versions as
+// the root.
+//
+// Rather than walking the full closure of every root, the graph is condensed
+// into its strongly connected components, which are processed in reverse
+// topological order: the phase 1 selection groups of a component are the
+// union of those of its members and of its (already summarized) child
+// components.  Each root then only needs the phase 2 walk over the resolved
+// graph, which is bounded by the number of selected modules.  Roots whose
+// own overrides (or other reachable versions of themselves) would alter the
+// closure fall back to a full runMvs.
+//
+// Summaries are released once every parent component has consumed them, and
+// the job queue is bounded by the number of workers (the traversal waits for
+// a free worker), so peak memory is proportional to the frontier of the
+// traversal plus the summaries of the roots in flight, rather than the whole
+// graph.
+func runPerModuleVersionMvs(
+	roots []moduleID,
+	adjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID],
+	sccs [][]moduleID,
+	compat *compatibilityIndex,
+	overridesFor func(moduleID) moduleOverrides,
+	numWorkers int,
+) mvsResults {
+	componentOf := make(map[moduleID]int, len(adjacencyMap))
+	for i, scc := range sccs {
+		for _, id := range scc {
+			componentOf[id] = i
+		}
+	}
+
+	// Build the condensation: distinct child components and the number of
+	// parents of each component.
+	children := make([][]int, len(sccs))
+	remainingParents := make([]int, len(sccs))
+	for i, scc := range sccs {
+		seen := make(map[int]bool)
+		for _, id := range scc {
+			for target := range adjacencyMap[id] {
+				j, ok := componentOf[target]
+				if !ok || j == i || seen[j] {
+					continue
+				}
+				seen[j] = true
+				children[i] = append(children[i], j)
+				remainingParents[j]++
+			}
+		}
+	}
+
+	rootsByComponent := make(map[int][]moduleID)
+	var uncondensedRoots []moduleID
+	for _, id := range roots {
+		if i, ok := componentOf[id]; ok {
+			rootsByComponent[i] = append(rootsByComponent[i], id)
+		} else {
+			uncondensedRoots = append(uncondensedRoots, id)
+		}
+	}
+
+	type job struct {
+		root    moduleID
+		summary *componentSummary
+	}
+
+	results := make(mvsResults, len(roots))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	numWorkers = max(1, numWorkers)
+	// a bounded queue: each queued job holds on to the summary of its
+	// component
+	jobs := make(chan job, numWorkers)
+
+	for range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				overrides := overridesFor(j.root)
+				var groups selectionGroups
+				if j.summary.appliesTo(j.root, overrides) {
+					groups = j.summary.groups
+				}
+				result := runMvs([]moduleID{j.root}, adjacencyMap, compat, overrides, groups, nil)
+				mu.Lock()
+				results[j.root] = result
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Process components in reverse topological order (Kahn's algorithm over
+	// the reversed condensation): a component is ready once all of its
+	// children have been summarized.
+	pendingChildren := make([]int, len(sccs))
+	parents := make([][]int, len(sccs))
+	var ready []int
+	for i := range sccs {
+		pendingChildren[i] = len(children[i])
+		for _, j := range children[i] {
+			parents[j] = append(parents[j], i)
+		}
+		if pendingChildren[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	summaries := make([]*componentSummary, len(sccs))
+	for len(ready) > 0 {
+		i := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+
+		summary := newComponentSummary()
+		for _, id := range sccs[i] {
+			summary.add(id.name(), compat.level(id), id.version())
+		}
+		for _, j := range children[i] {
+			summary.merge(summaries[j])
+			remainingParents[j]--
+			if remainingParents[j] == 0 {
+				summaries[j] = nil
+			}
+		}
+		if remainingParents[i] > 0 {
+			summaries[i] = summary
+		}
+
+		for _, root := range rootsByComponent[i] {
+			jobs <- job{root: root, summary: summary}
+		}
+
+		for _, p := range parents[i] {
+			pendingChildren[p]--
+			if pendingChildren[p] == 0 {
+				ready = append(ready, p)
+			}
+		}
+	}
+	// Roots missing from the condensation (should not happen) get a full run
+	for _, root := range uncondensedRoots {
+		jobs <- job{root: root}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
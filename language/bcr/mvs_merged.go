@@ -0,0 +1,124 @@
+package bcr
+
+import (
+	"log"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/dominikbraun/graph"
+)
+
+// devDependencyUpgrade is a module that MVS selects at a higher version when
+// the dev dependencies of the root are taken into account.
+type devDependencyUpgrade struct {
+	name moduleName
+	// version is the version selected for regular dependencies only
+	version moduleVersion
+	// devVersion is the version selected for regular + dev dependencies
+	devVersion moduleVersion
+}
+
+// String formats the upgrade as "name@version -> name@devVersion"
+func (u *devDependencyUpgrade) String() string {
+	return joinModuleIDs([]moduleID{toModuleID(u.name, u.version), toModuleID(u.name, u.devVersion)})
+}
+
+// runMergedMvs computes the MVS selection of each root over its regular and
+// dev dependencies (the view of the root module's own build and tests).
+// Roots without dev dependencies select the same versions as for regular
+// dependencies only, so their regular result is reused.
+func runMergedMvs(
+	roots []moduleID,
+	adjacencyMap, devAdjacencyMap map[moduleID]map[moduleID]graph.Edge[moduleID],
+	regularResults mvsResults,
+	compat *compatibilityIndex,
+	overridesFor func(moduleID) moduleOverrides,
+	numWorkers int,
+) mvsResults {
+	results := make(mvsResults, len(roots))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan moduleID, len(roots))
+
+	for range max(1, numWorkers) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for root := range jobs {
+				result := runMvs([]moduleID{root}, adjacencyMap, compat, overridesFor(root), nil, devAdjacencyMap)
+				mu.Lock()
+				results[root] = result
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, root := range roots {
+		if regular, ok := regularResults[root]; ok && len(devAdjacencyMap[root]) == 0 {
+			mu.Lock()
+			results[root] = regular
+			mu.Unlock()
+			continue
+		}
+		jobs <- root
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// devOnlyUpgrades compares the regular and merged (regular + dev) selections
+// of each root and returns the modules that are selected at a higher version
+// only because of dev dependencies, sorted by module name.  Modules that are
+// only reachable through dev dependencies are not upgrades and are omitted.
+func devOnlyUpgrades(regular, merged mvs) map[moduleID][]*devDependencyUpgrade {
+	result := make(map[moduleID][]*devDependencyUpgrade)
+	for id, mergedDeps := range merged {
+		regularDeps, ok := regular[id]
+		if !ok {
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(mergedDeps)) {
+			if name == id.name() {
+				continue
+			}
+			version, ok := regularDeps[name]
+			if !ok || version == "" {
+				continue
+			}
+			devVersion := mergedDeps[name]
+			if devVersion == "" || compareVersions(devVersion, version) <= 0 {
+				continue
+			}
+			result[id] = append(result[id], &devDependencyUpgrade{
+				name:       name,
+				version:    version,
+				devVersion: devVersion,
+			})
+		}
+	}
+	return result
+}
+
+// calculatePerModuleVersionMergedMvs computes the merged (regular + dev) MVS
+// selection for every root of the given regular results.
+func (ext *bcrExtension) calculatePerModuleVersionMergedMvs(regularResults mvsResults) mvsResults {
+	adjacencyMap, err := ext.regularDepGraph.AdjacencyMap()
+	if err != nil {
+		log.Printf("Error getting adjacency map for merged MVS: %v", err)
+		return nil
+	}
+	devAdjacencyMap, err := ext.devDepGraph.AdjacencyMap()
+	if err != nil {
+		log.Printf("Error getting dev adjacency map for merged MVS: %v", err)
+		return nil
+	}
+
+	roots := slices.Collect(maps.Keys(regularResults))
+	results := runMergedMvs(roots, adjacencyMap, devAdjacencyMap, regularResults, ext.newCompatibilityIndex(), ext.rootModuleOverrides, 10)
+
+	log.Printf("Calculated merged (regular + dev) MVS for %d module versions", len(results))
+	return results
+}
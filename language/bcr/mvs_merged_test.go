@@ -0,0 +1,62 @@
+package bcr
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestRunMergedMvs(t *testing.T) {
+	regular := makeAdjacencyMap(map[moduleID][]moduleID{
+		"a@1.0": {"b@1.0"},
+		"b@1.0": {"c@1.0"},
+		"c@1.0": nil,
+		"c@2.0": nil,
+		"c@3.0": nil,
+		"d@1.0": {"c@2.0"},
+		"e@1.0": {"c@3.0"},
+	})
+	dev := makeAdjacencyMap(map[moduleID][]moduleID{
+		"a@1.0": {"d@1.0"},
+		// dev dependencies of non-root modules are ignored
+		"b@1.0": {"e@1.0"},
+	})
+	roots := []moduleID{"a@1.0", "b@1.0", "d@1.0"}
+	noOverrides := func(moduleID) moduleOverrides { return nil }
+
+	regularResults := make(mvsResults)
+	for _, root := range roots {
+		regularResults[root] = runMvs([]moduleID{root}, regular, nil, nil, nil, nil)
+	}
+
+	got := runMergedMvs(roots, regular, dev, regularResults, nil, noOverrides, 2)
+
+	want := mvs{
+		"a@1.0": {"a": "1.0", "b": "1.0", "c": "2.0", "d": "1.0"},
+		"b@1.0": {"b": "1.0", "c": "3.0", "e": "1.0"},
+		"d@1.0": {"d": "1.0", "c": "2.0"},
+	}
+	for _, root := range roots {
+		if !maps.Equal(got[root].selected, want[root]) {
+			t.Errorf("%s: selected = %v, want %v", root, got[root].selected, want[root])
+		}
+	}
+	if got["d@1.0"] != regularResults["d@1.0"] {
+		t.Errorf("expected the regular result to be reused for a root without dev dependencies")
+	}
+
+	upgrades := devOnlyUpgrades(regularResults.selected(), got.selected())
+	gotUpgrades := make(map[moduleID][]string)
+	for id, list := range upgrades {
+		for _, upgrade := range list {
+			gotUpgrades[id] = append(gotUpgrades[id], upgrade.String())
+		}
+	}
+	wantUpgrades := map[moduleID][]string{
+		"a@1.0": {"c@1.0 -> c@2.0"},
+		"b@1.0": {"c@1.0 -> c@3.0"},
+	}
+	if !maps.EqualFunc(gotUpgrades, wantUpgrades, slices.Equal) {
+		t.Errorf("upgrades = %v, want %v", gotUpgrades, wantUpgrades)
+	}
+}
@@ -0,0 +1,283 @@
+package bcr
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/dominikbraun/graph"
+)
+
+func TestRunMvs(t *testing.T) {
+	tests := []struct {
+		name          string
+		root          moduleID
+		edges         map[moduleID][]moduleID
+		levels        map[moduleID]int32
+		maxLevels     map[moduleID]map[moduleName]int32
+		overrides     moduleOverrides
+		want          moduleDeps
+		wantConflicts []string
+		wantOverrides map[moduleName]string
+	}{
+		{
+			name: "selects highest version",
+			root: "a@1.0",
+			edges: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0", "c@1.0"},
+				"b@1.0": {"d@1.9.0"},
+				"c@1.0": {"d@1.10.0"},
+			},
+			want: moduleDeps{"a": "1.0", "b": "1.0", "c": "1.0", "d": "1.10.0"},
+		},
+		{
+			name: "root wins over other versions of itself",
+			root: "a@1.0",
+			edges: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0"},
+				"b@1.0": {"a@2.0"},
+				"a@2.0": {"c@1.0"},
+			},
+			want: moduleDeps{"a": "1.0", "b": "1.0"},
+		},
+		{
+			name: "different compatibility levels conflict",
+			root: "a@1.0",
+			edges: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0", "c@1.0"},
+				"b@1.0": {"d@1.0"},
+				"c@1.0": {"d@2.0"},
+			},
+			levels: map[moduleID]int32{
+				"d@1.0": 1,
+				"d@2.0": 2,
+			},
+			want: moduleDeps{"a": "1.0", "b": "1.0", "c": "1.0", "d": "2.0"},
+			wantConflicts: []string{
+				"a@1.0 -> b@1.0 -> d@1.0 (compatibility_level=1)",
+				"a@1.0 -> c@1.0 -> d@2.0 (compatibility_level=2)",
+			},
+		},
+		{
+			name: "max_compatibility_level allows upgrade",
+			root: "a@1.0",
+			edges: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0", "c@1.0"},
+				"b@1.0": {"d@1.0"},
+				"c@1.0": {"d@2.0"},
+			},
+			levels: map[moduleID]int32{
+				"d@1.0": 1,
+				"d@2.0": 2,
+			},
+			maxLevels: map[moduleID]map[moduleName]int32{
+				"b@1.0": {"d": 2},
+			},
+			want: moduleDeps{"a": "1.0", "b": "1.0", "c": "1.0", "d": "2.0"},
+		},
+		{
+			name: "unreachable after upgrade does not count",
+			root: "a@1.0",
+			edges: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0", "b@1.1"},
+				"b@1.0": {"d@1.0"},
+				"b@1.1": {"d@2.0"},
+			},
+			levels: map[moduleID]int32{
+				"d@1.0": 1,
+				"d@2.0": 2,
+			},
+			want: moduleDeps{"a": "1.0", "b": "1.1", "d": "2.0"},
+		},
+		{
+			name: "single_version_override pins version",
+			root: "a@1.0",
+			edges: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0", "c@1.0"},
+				"b@1.0": {"d@1.2"},
+				"c@1.0": {"d@1.5"},
+				"d@1.5": {"e@1.0"},
+			},
+			overrides: moduleOverrides{
+				"d": {
+					ModuleName: "d",
+					Override: &bzpb.ModuleDependencyOverride_SingleVersionOverride{
+						SingleVersionOverride: &bzpb.SingleVersionOverride{Version: "1.2"},
+					},
+				},
+			},
+			want:          moduleDeps{"a": "1.0", "b": "1.0", "c": "1.0", "d": "1.2"},
+			wantOverrides: map[moduleName]string{"d": "single_version"},
+		},
+		{
+			name: "non-registry override drops subgraph",
+			root: "a@1.0",
+			edges: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0"},
+				"b@1.0": {"c@1.0"},
+			},
+			overrides: moduleOverrides{
+				"b": {
+					ModuleName: "b",
+					Override: &bzpb.ModuleDependencyOverride_GitOverride{
+						GitOverride: &bzpb.GitOverride{Remote: "https://github.com/example/b.git"},
+					},
+				},
+				"z": {
+					ModuleName: "z",
+					Override: &bzpb.ModuleDependencyOverride_LocalPathOverride{
+						LocalPathOverride: &bzpb.LocalPathOverride{Path: "../z"},
+					},
+				},
+			},
+			want:          moduleDeps{"a": "1.0", "b": ""},
+			wantOverrides: map[moduleName]string{"b": "git"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjacencyMap := makeAdjacencyMap(tt.edges)
+			compat := &compatibilityIndex{levels: tt.levels, maxLevels: tt.maxLevels}
+
+			got := runMvs([]moduleID{tt.root}, adjacencyMap, compat, tt.overrides, nil, nil)
+			if !maps.Equal(got.selected, tt.want) {
+				t.Errorf("selected = %v, want %v", got.selected, tt.want)
+			}
+			if !maps.Equal(got.overrideTypes, tt.wantOverrides) {
+				t.Errorf("override types = %v, want %v", got.overrideTypes, tt.wantOverrides)
+			}
+
+			var gotConflicts []string
+			if got.err != nil {
+				gotConflicts = got.err.toStringList()
+			}
+			if !slices.Equal(gotConflicts, tt.wantConflicts) {
+				t.Errorf("conflicts = %q, want %q", gotConflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestRunPerModuleVersionMvs(t *testing.T) {
+	depGraph, compat, overridesFor := syntheticRegistry(60, 8)
+	adjacencyMap, sccs, roots := condenseForTest(t, depGraph)
+
+	got := runPerModuleVersionMvs(roots, adjacencyMap, sccs, compat, overridesFor, 4)
+	if len(got) != len(roots) {
+		t.Fatalf("got %d results, want %d", len(got), len(roots))
+	}
+	for _, root := range roots {
+		want := runMvs([]moduleID{root}, adjacencyMap, compat, overridesFor(root), nil, nil)
+		if !reflect.DeepEqual(got[root], want) {
+			t.Errorf("%s: got %+v, want %+v", root, got[root], want)
+		}
+	}
+}
+
+func BenchmarkPerModuleVersionMvs(b *testing.B) {
+	depGraph, compat, overridesFor := syntheticRegistry(200, 15)
+	adjacencyMap, sccs, roots := condenseForTest(b, depGraph)
+
+	b.Run("per-root", func(b *testing.B) {
+		for b.Loop() {
+			for _, root := range roots {
+				runMvs([]moduleID{root}, adjacencyMap, compat, overridesFor(root), nil, nil)
+			}
+		}
+	})
+	b.Run("condensation", func(b *testing.B) {
+		for b.Loop() {
+			runPerModuleVersionMvs(roots, adjacencyMap, sccs, compat, overridesFor, 1)
+		}
+	})
+}
+
+// syntheticRegistry builds a deterministic pseudo-random registry of
+// numModules modules with numVersions versions each.  Versions mostly depend
+// on lower-numbered modules, with occasional back edges to nearby modules
+// (creating small cycles, like rules_go <-> gazelle) and dependencies on
+// other versions of the same module.  Every fifth module
+// bumps its compatibility level halfway through its versions, and every
+// seventh module version pins one of its dependencies with a
+// single_version_override.
+func syntheticRegistry(numModules, numVersions int) (graph.Graph[moduleID, moduleID], *compatibilityIndex, func(moduleID) moduleOverrides) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	depGraph := initDepGraph()
+	compat := &compatibilityIndex{
+		levels:    make(map[moduleID]int32),
+		maxLevels: make(map[moduleID]map[moduleName]int32),
+	}
+	overrides := make(map[moduleID]moduleOverrides)
+
+	id := func(m, v int) moduleID {
+		return toModuleID(moduleName(fmt.Sprintf("m%03d", m)), moduleVersion(fmt.Sprintf("1.%d.0", v)))
+	}
+
+	for m := range numModules {
+		for v := range numVersions {
+			_ = depGraph.AddVertex(id(m, v))
+			if m%5 == 0 && v >= numVersions/2 {
+				compat.levels[id(m, v)] = 1
+			}
+		}
+	}
+
+	for m := range numModules {
+		for v := range numVersions {
+			from := id(m, v)
+			addEdge := func(to moduleID) {
+				_ = depGraph.AddEdge(from, to)
+			}
+			if m > 0 {
+				for range 3 {
+					dep := rng.IntN(m)
+					to := id(dep, rng.IntN(numVersions))
+					addEdge(to)
+					if dep%5 == 0 && rng.IntN(2) == 0 {
+						compat.maxLevels[from] = map[moduleName]int32{to.name(): 1}
+					}
+				}
+			}
+			switch r := rng.IntN(100); {
+			case r < 3 && m+3 < numModules:
+				to := id(m+1+rng.IntN(3), rng.IntN(numVersions))
+				addEdge(to)
+				_ = depGraph.AddEdge(to, from)
+			case r < 5 && v > 0:
+				addEdge(id(m, rng.IntN(v)))
+			}
+			if m > 0 && (m*numVersions+v)%7 == 0 {
+				name := id(rng.IntN(m), 0).name()
+				overrides[from] = moduleOverrides{
+					name: {
+						ModuleName: string(name),
+						Override: &bzpb.ModuleDependencyOverride_SingleVersionOverride{
+							SingleVersionOverride: &bzpb.SingleVersionOverride{Version: "1.0.0"},
+						},
+					},
+				}
+			}
+		}
+	}
+
+	return depGraph, compat, func(id moduleID) moduleOverrides { return overrides[id] }
+}
+
+// condenseForTest returns the adjacency map, strongly connected components
+// and (sorted) vertices of the given graph.
+func condenseForTest(tb testing.TB, depGraph graph.Graph[moduleID, moduleID]) (map[moduleID]map[moduleID]graph.Edge[moduleID], [][]moduleID, []moduleID) {
+	adjacencyMap, err := depGraph.AdjacencyMap()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sccs, err := graph.StronglyConnectedComponents(depGraph)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return adjacencyMap, sccs, slices.SortedFunc(maps.Keys(adjacencyMap), compareModuleIDs)
+}
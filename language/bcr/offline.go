@@ -0,0 +1,87 @@
+package bcr
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+)
+
+// cacheMisses collects the network lookups that were skipped in offline mode
+// (see --offline) because their result is not in any of the cache files.
+type cacheMisses []*bzpb.CacheMiss
+
+// add records a cache miss for the given key, affecting the given module
+// versions (if any).
+func (m *cacheMisses) add(kind bzpb.CacheMissKind, key string, ids ...moduleID) {
+	miss := &bzpb.CacheMiss{Kind: kind, Key: key}
+	for _, id := range ids {
+		miss.ModuleVersions = append(miss.ModuleVersions, id.String())
+	}
+	slices.Sort(miss.ModuleVersions)
+	*m = append(*m, miss)
+}
+
+// report returns the cache misses sorted by kind, then key.  Misses of the
+// same key (e.g., a source url shared by several module versions) are merged.
+func (m cacheMisses) report() *bzpb.CacheMissReport {
+	sorted := slices.Clone(m)
+	slices.SortStableFunc(sorted, compareCacheMisses)
+
+	report := &bzpb.CacheMissReport{}
+	for _, miss := range sorted {
+		if n := len(report.Misses); n > 0 && compareCacheMisses(report.Misses[n-1], miss) == 0 {
+			last := report.Misses[n-1]
+			merged := slices.Concat(last.ModuleVersions, miss.ModuleVersions)
+			slices.Sort(merged)
+			report.Misses[n-1] = &bzpb.CacheMiss{Kind: last.Kind, Key: last.Key, ModuleVersions: slices.Compact(merged)}
+			continue
+		}
+		report.Misses = append(report.Misses, miss)
+	}
+	return report
+}
+
+func compareCacheMisses(a, b *bzpb.CacheMiss) int {
+	return cmp.Or(cmp.Compare(a.Kind, b.Kind), strings.Compare(a.Key, b.Key))
+}
+
+// populateRepositoriesOffline serves the repositories that would otherwise be
+// fetched from the backup registry, and records a cache miss for the others.
+func (ext *bcrExtension) populateRepositoriesOffline(todo []*bzpb.RepositoryMetadata) {
+	for _, md := range todo {
+		if ext.populateFromBackupRegistry([]*bzpb.RepositoryMetadata{md}) == 0 {
+			ext.cacheMisses.add(bzpb.CacheMissKind_CACHE_MISS_REPOSITORY_METADATA, string(formatRepositoryID(md)))
+		}
+	}
+}
+
+// writeCacheMissReportFile writes the cache misses to the file given by
+// --cache-miss-report-file, if any.
+func (ext *bcrExtension) writeCacheMissReportFile() error {
+	if !ext.offline {
+		return nil
+	}
+
+	log.Printf("Offline mode: %d cache misses", len(ext.cacheMisses))
+
+	if ext.cacheMissReportFile == "" {
+		// No file was specified, so nothing to write
+		return nil
+	}
+
+	report := ext.cacheMisses.report()
+
+	filename := os.ExpandEnv(ext.cacheMissReportFile)
+	if err := protoutil.WriteFile(filename, report); err != nil {
+		return fmt.Errorf("failed to write cache miss report file %s: %w", filename, err)
+	}
+
+	log.Printf("Wrote %d cache misses to %s", len(report.Misses), filename)
+	return nil
+}
@@ -0,0 +1,109 @@
+package bcr
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCacheMissesReport(t *testing.T) {
+	var misses cacheMisses
+	misses.add(bzpb.CacheMissKind_CACHE_MISS_SOURCE_URL_STATUS, "https://example.com/b.tar.gz", "b@1.0.0")
+	misses.add(bzpb.CacheMissKind_CACHE_MISS_REPOSITORY_METADATA, "github:org/repo")
+	misses.add(bzpb.CacheMissKind_CACHE_MISS_SOURCE_URL_STATUS, "https://example.com/a.tar.gz", "a@2.0.0", "a@1.0.0")
+	misses.add(bzpb.CacheMissKind_CACHE_MISS_SOURCE_COMMIT_SHA, "https://example.com/a.tar.gz", "a@2.0.0")
+	misses.add(bzpb.CacheMissKind_CACHE_MISS_SOURCE_COMMIT_SHA, "https://example.com/a.tar.gz", "a@1.0.0")
+
+	want := &bzpb.CacheMissReport{
+		Misses: []*bzpb.CacheMiss{
+			{Kind: bzpb.CacheMissKind_CACHE_MISS_REPOSITORY_METADATA, Key: "github:org/repo"},
+			{Kind: bzpb.CacheMissKind_CACHE_MISS_SOURCE_URL_STATUS, Key: "https://example.com/a.tar.gz", ModuleVersions: []string{"a@1.0.0", "a@2.0.0"}},
+			{Kind: bzpb.CacheMissKind_CACHE_MISS_SOURCE_URL_STATUS, Key: "https://example.com/b.tar.gz", ModuleVersions: []string{"b@1.0.0"}},
+			{Kind: bzpb.CacheMissKind_CACHE_MISS_SOURCE_COMMIT_SHA, Key: "https://example.com/a.tar.gz", ModuleVersions: []string{"a@1.0.0", "a@2.0.0"}},
+		},
+	}
+	if got := misses.report(); !proto.Equal(got, want) {
+		t.Errorf("report() = %v, want %v", got, want)
+	}
+	// merging does not modify the recorded misses
+	if got := misses[3].ModuleVersions; len(got) != 1 {
+		t.Errorf("recorded miss was modified: %v", got)
+	}
+}
+
+func TestOfflineRepositories(t *testing.T) {
+	ext := NewLanguage().(*bcrExtension)
+	ext.offline = true
+	ext.backupRegistry = &bzpb.Registry{
+		Modules: []*bzpb.Module{{
+			Name: "rules_foo",
+			RepositoryMetadata: &bzpb.RepositoryMetadata{
+				Type:         bzpb.RepositoryType_GITHUB,
+				Organization: "org",
+				Name:         "rules_foo",
+				Description:  "Foo rules",
+			},
+		}},
+	}
+
+	foo := &bzpb.RepositoryMetadata{Type: bzpb.RepositoryType_GITHUB, Organization: "org", Name: "rules_foo"}
+	bar := &bzpb.RepositoryMetadata{Type: bzpb.RepositoryType_GITHUB, Organization: "org", Name: "rules_bar"}
+
+	if todo := ext.filterSkipNetworkRepositories([]*bzpb.RepositoryMetadata{foo, bar}); len(todo) != 0 {
+		t.Errorf("expected no repositories to fetch in offline mode, got %v", todo)
+	}
+	if foo.Description != "Foo rules" {
+		t.Errorf("expected metadata from the backup registry, got %v", foo)
+	}
+	if len(ext.cacheMisses) != 1 || ext.cacheMisses[0].Key != "github:org/rules_bar" {
+		t.Errorf("unexpected cache misses: %v", ext.cacheMisses)
+	}
+	if !ext.skipsNetwork([]moduleID{"rules_foo@1.0.0"}) {
+		t.Error("expected all modules to skip network access in offline mode")
+	}
+}
+
+func TestLoadBackupRegistryOffline(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "registry.pb.gz")
+	data, err := proto.Marshal(&bzpb.Registry{Modules: []*bzpb.Module{{Name: "rules_foo"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := gzip.NewWriter(f)
+	w.Write(data)
+	w.Close()
+	f.Close()
+
+	for _, tc := range []struct {
+		name        string
+		url         string
+		wantModules int
+		wantMisses  int
+	}{
+		{name: "local path", url: filename, wantModules: 1},
+		{name: "file url", url: "file://" + filename, wantModules: 1},
+		{name: "remote url", url: "https://bcr.stack.build/registry.pb.gz", wantMisses: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ext := NewLanguage().(*bcrExtension)
+			ext.offline = true
+			ext.registrySourceURL = tc.url
+			ext.loadBackupRegistry()
+
+			if got := len(ext.backupRegistry.GetModules()); got != tc.wantModules {
+				t.Errorf("loaded %d modules, want %d", got, tc.wantModules)
+			}
+			if got := len(ext.cacheMisses); got != tc.wantMisses {
+				t.Errorf("recorded %d cache misses, want %d", got, tc.wantMisses)
+			}
+		})
+	}
+}
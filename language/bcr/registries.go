@@ -0,0 +1,234 @@
+package bcr
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazelbuild/bazel-gazelle/config"
+	"github.com/bazelbuild/bazel-gazelle/label"
+	"github.com/bazelbuild/bazel-gazelle/language"
+	"github.com/bazelbuild/bazel-gazelle/resolve"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+)
+
+// combinedModuleRegistryName is the name of the module_registry rule that
+// combines all registries when more than one is configured
+const combinedModuleRegistryName = "combined"
+
+// registryLayer is one of the registries given with --registry-root.  Like
+// bazel's --registry, registries are ordered by precedence: a module version
+// is taken from the first registry that provides it.
+type registryLayer struct {
+	name          string                                    // base name of the registry root (e.g. "bazel-central-registry")
+	root          string                                    // registry root, relative to the repo root
+	modulesRoot   string                                    // modules dir, relative to the repo root
+	precedence    int                                       // index in the --registry-root list (0 = highest precedence)
+	moduleCommits map[moduleBazelRelPath]*bzpb.ModuleCommit // cache of module commits (preloaded)
+}
+
+// newRegistryLayers creates the registry layers for the given roots, in order
+// of precedence.
+func newRegistryLayers(roots []string) ([]*registryLayer, error) {
+	var registries []*registryLayer
+	seen := make(map[string]bool)
+	for i, root := range roots {
+		root = path.Clean(root)
+		layer := &registryLayer{
+			name:        path.Base(root),
+			root:        root,
+			modulesRoot: path.Join(root, "modules"),
+			precedence:  i,
+		}
+		if seen[layer.name] {
+			return nil, fmt.Errorf("duplicate registry name %q (from --registry-root=%s)", layer.name, root)
+		}
+		seen[layer.name] = true
+		registries = append(registries, layer)
+	}
+	return registries, nil
+}
+
+// contains reports whether the package rel is inside this registry.
+func (r *registryLayer) contains(rel string) bool {
+	return rel == r.root || strings.HasPrefix(rel, r.root+"/")
+}
+
+// baseRegistry returns the registry with the lowest precedence.  The base
+// registry hosts the Bazel pseudo-modules and the reverse dependencies.
+func (ext *bcrExtension) baseRegistry() *registryLayer {
+	return ext.registries[len(ext.registries)-1]
+}
+
+// registryForRel returns the registry containing the package rel, or nil.
+func (ext *bcrExtension) registryForRel(rel string) *registryLayer {
+	var found *registryLayer
+	for _, r := range ext.registries {
+		// prefer the most specific root, should registries be nested
+		if r.contains(rel) && (found == nil || len(r.root) > len(found.root)) {
+			found = r
+		}
+	}
+	return found
+}
+
+// isShadowedModuleVersion reports whether a registry with higher precedence
+// than reg also provides the given module version.
+func (ext *bcrExtension) isShadowedModuleVersion(reg *registryLayer, name, version string) bool {
+	return ext.existsInHigherRegistry(reg, name, version, "MODULE.bazel")
+}
+
+// isShadowedModule reports whether a registry with higher precedence than reg
+// also has metadata for the given module.
+func (ext *bcrExtension) isShadowedModule(reg *registryLayer, name string) bool {
+	return ext.existsInHigherRegistry(reg, name, "metadata.json")
+}
+
+// moduleVersionRegistry returns the registry with the highest precedence
+// that provides the module version (nil if none does).
+func (ext *bcrExtension) moduleVersionRegistry(id moduleID) *registryLayer {
+	for _, reg := range ext.registries {
+		filename := filepath.Join(ext.repoRoot, reg.modulesRoot, string(id.name()), string(id.version()), "MODULE.bazel")
+		if _, err := os.Stat(filename); err == nil {
+			return reg
+		}
+	}
+	return nil
+}
+
+func (ext *bcrExtension) existsInHigherRegistry(reg *registryLayer, elem ...string) bool {
+	for _, r := range ext.registries[:reg.precedence] {
+		filename := filepath.Join(append([]string{ext.repoRoot, r.modulesRoot}, elem...)...)
+		if _, err := os.Stat(filename); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// preferredFindResult returns the result from the registry with the highest
+// precedence (results outside of any registry come last).
+func (ext *bcrExtension) preferredFindResult(results []resolve.FindResult) resolve.FindResult {
+	best := results[0]
+	bestPrecedence := ext.precedenceOf(best.Label)
+	for _, result := range results[1:] {
+		if p := ext.precedenceOf(result.Label); p < bestPrecedence {
+			best, bestPrecedence = result, p
+		}
+	}
+	return best
+}
+
+func (ext *bcrExtension) precedenceOf(l label.Label) int {
+	if r := ext.registryForRel(l.Pkg); r != nil {
+		return r.precedence
+	}
+	return len(ext.registries)
+}
+
+// findResultsInPackage filters results to the rules in pkg or its
+// subpackages.  Used to keep per-registry views from referencing rules of
+// other registries providing the same import.
+func findResultsInPackage(results []resolve.FindResult, pkg string) []resolve.FindResult {
+	var filtered []resolve.FindResult
+	for _, result := range results {
+		if result.Label.Pkg == pkg || strings.HasPrefix(result.Label.Pkg, pkg+"/") {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// combinedPkg returns the package of the rules computed over all registries
+// (the dependency cycles, and the repository metadata and combined
+// module_registry of layered registries): the modules root of a single
+// registry, or the repo root for layered registries.  Gazelle generates
+// rules in post-order, so either is visited after all the module versions
+// they cover have been read; the modules root of the base registry may be
+// visited before the other registries.
+func (ext *bcrExtension) combinedPkg() string {
+	if len(ext.registries) > 1 {
+		return ""
+	}
+	return ext.modulesRoot
+}
+
+// generateCombinedRules generates the rules of the combined package of
+// layered registries.
+func (ext *bcrExtension) generateCombinedRules(args language.GenerateArgs) language.GenerateResult {
+	rules := makeRepositoryMetadataRules(ext.repositoriesMetadataByID)
+	rules = append(rules, ext.makeCycleRules(args.Rel)...)
+	rules = append(rules, ext.makeCombinedModuleRegistryRule(ext.combinedRegistryURL))
+
+	imports := make([]interface{}, len(rules))
+	for i, r := range rules {
+		imports[i] = r.PrivateAttr(config.GazelleImportsKey)
+	}
+	return language.GenerateResult{
+		Gen:     rules,
+		Imports: imports,
+	}
+}
+
+// makeCycleRules creates the module_dependency_cycle rules of the
+// dependency graph of all registries, in the package rel.
+func (ext *bcrExtension) makeCycleRules(rel string) []*rule.Rule {
+	if !ext.generateCycleRules {
+		return nil
+	}
+	rules := makeModuleDependencyCycleRules(ext.getCycles())
+	for _, r := range rules {
+		ext.cycleRuleLabels = append(ext.cycleRuleLabels, label.New("", rel, r.Name()))
+	}
+	return rules
+}
+
+// trackModuleMetadataLabel records the module_metadata rule of a registry
+// for the combined module_registry.
+func (ext *bcrExtension) trackModuleMetadataLabel(rel string, r *rule.Rule) {
+	ext.moduleMetadataLabels = append(ext.moduleMetadataLabels, label.New("", rel, r.Name()))
+}
+
+// makeCombinedModuleRegistryRule creates the module_registry rule that
+// combines all registries.  It lives in the combined package (see
+// combinedPkg).  The registries attr lists the registry names in order of
+// precedence, the deps are resolved after all registries have been visited.
+func (ext *bcrExtension) makeCombinedModuleRegistryRule(registryURL string) *rule.Rule {
+	r := rule.NewRule(moduleRegistryKind, combinedModuleRegistryName)
+	names := make([]string, len(ext.registries))
+	for i, reg := range ext.registries {
+		names[i] = reg.name
+	}
+	r.SetAttr("registries", names)
+	r.SetAttr("registry_url", registryURL)
+	if len(ext.cycleRuleLabels) > 0 {
+		cycles := make([]string, len(ext.cycleRuleLabels))
+		for i, l := range ext.cycleRuleLabels {
+			cycles[i] = l.String()
+		}
+		r.SetAttr("cycles", cycles)
+	}
+	r.SetAttr("reverse_dependencies", label.New("", ext.modulesRoot, reverseDependencyIndexFilename).String())
+	r.SetPrivateAttr(combinedModuleRegistryPrivateAttr, true)
+	r.SetAttr("visibility", []string{"//visibility:public"})
+	return r
+}
+
+// combinedModuleRegistryPrivateAttr marks the combined module_registry rule
+const combinedModuleRegistryPrivateAttr = "_combined"
+
+// resolveCombinedModuleRegistryRule sets the deps of the combined
+// module_registry rule to the module_metadata rules of every registry.
+func (ext *bcrExtension) resolveCombinedModuleRegistryRule(r *rule.Rule, ix *resolve.RuleIndex) {
+	deps := make([]string, 0, len(ext.moduleMetadataLabels))
+	for _, l := range ext.moduleMetadataLabels {
+		deps = append(deps, l.String())
+	}
+	if len(deps) > 0 {
+		r.SetAttr("deps", deps)
+	}
+	resolveBazelVersions(r, ix)
+}
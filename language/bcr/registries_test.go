@@ -0,0 +1,164 @@
+package bcr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bazelbuild/bazel-gazelle/label"
+	"github.com/bazelbuild/bazel-gazelle/language"
+	"github.com/bazelbuild/bazel-gazelle/resolve"
+)
+
+func TestNewRegistryLayers(t *testing.T) {
+	registries, err := newRegistryLayers([]string{"data/internal-registry/", "data/bazel-central-registry"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(registries) != 2 {
+		t.Fatalf("expected 2 registries, got %d", len(registries))
+	}
+	first := registries[0]
+	if first.name != "internal-registry" || first.root != "data/internal-registry" || first.modulesRoot != "data/internal-registry/modules" || first.precedence != 0 {
+		t.Errorf("unexpected first registry: %+v", first)
+	}
+	if registries[1].precedence != 1 {
+		t.Errorf("expected precedence 1, got %d", registries[1].precedence)
+	}
+
+	if _, err := newRegistryLayers([]string{"a/registry", "b/registry"}); err == nil {
+		t.Error("expected error for duplicate registry names")
+	}
+}
+
+func TestRegistryForRel(t *testing.T) {
+	registries, err := newRegistryLayers([]string{"data/internal", "data/bcr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext := &bcrExtension{registries: registries}
+
+	for rel, want := range map[string]string{
+		"data/bcr":                      "bcr",
+		"data/bcr/modules/foo/1.0.0":    "bcr",
+		"data/internal/modules":         "internal",
+		"data/bcrx/modules/foo":         "",
+		"data":                          "",
+		"data/internal/modules/foo/1.0": "internal",
+	} {
+		var got string
+		if reg := ext.registryForRel(rel); reg != nil {
+			got = reg.name
+		}
+		if got != want {
+			t.Errorf("registryForRel(%q) = %q, want %q", rel, got, want)
+		}
+	}
+
+	if base := ext.baseRegistry(); base.name != "bcr" {
+		t.Errorf("baseRegistry() = %q, want bcr", base.name)
+	}
+}
+
+func TestGenerateCombinedRules(t *testing.T) {
+	registries, err := newRegistryLayers([]string{"data/internal", "data/bcr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext := &bcrExtension{
+		registries:         registries,
+		modulesRoot:        "data/bcr/modules",
+		generateCycleRules: true,
+		depGraph:           initDepGraph(),
+	}
+	if got := ext.combinedPkg(); got != "" {
+		t.Errorf("combinedPkg() = %q, want the repo root", got)
+	}
+
+	// a cycle across the registries: the modules root of the base registry
+	// is visited before the internal registry ("bcr" < "internal")
+	ext.addDependencyEdge("a", "1.0", "b", "1.0") // data/bcr/modules/a
+	ext.addDependencyEdge("b", "1.0", "a", "1.0") // data/internal/modules/b
+
+	result := ext.generateCombinedRules(language.GenerateArgs{Rel: ""})
+	if len(result.Gen) != 2 || len(result.Imports) != len(result.Gen) {
+		t.Fatalf("expected a cycle rule and the combined module_registry, got %d rules", len(result.Gen))
+	}
+	cycle, combined := result.Gen[0], result.Gen[1]
+	if cycle.Kind() != moduleDependencyCycleKind || combined.Name() != combinedModuleRegistryName {
+		t.Fatalf("unexpected rules: %s %s, %s %s", cycle.Kind(), cycle.Name(), combined.Kind(), combined.Name())
+	}
+	if got := combined.AttrStrings("cycles"); len(got) != 1 || got[0] != "//:"+cycle.Name() {
+		t.Errorf("combined cycles = %v", got)
+	}
+
+	// a single registry keeps them in its modules root
+	ext.registries = registries[1:]
+	if got := ext.combinedPkg(); got != "data/bcr/modules" {
+		t.Errorf("combinedPkg() = %q, want the modules root", got)
+	}
+}
+
+func TestPreferredFindResult(t *testing.T) {
+	registries, err := newRegistryLayers([]string{"data/internal", "data/bcr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext := &bcrExtension{registries: registries}
+
+	results := []resolve.FindResult{
+		{Label: label.New("", "other/modules/foo/1.0.0", "module_version")},
+		{Label: label.New("", "data/bcr/modules/foo/1.0.0", "module_version")},
+		{Label: label.New("", "data/internal/modules/foo/1.0.0", "module_version")},
+	}
+	if got := ext.preferredFindResult(results); got.Label.Pkg != "data/internal/modules/foo/1.0.0" {
+		t.Errorf("preferredFindResult() = %v, want the internal registry result", got.Label)
+	}
+	if got := ext.preferredFindResult(results[:2]); got.Label.Pkg != "data/bcr/modules/foo/1.0.0" {
+		t.Errorf("preferredFindResult() = %v, want the bcr registry result", got.Label)
+	}
+}
+
+func TestFindResultsInPackage(t *testing.T) {
+	results := []resolve.FindResult{
+		{Label: label.New("", "data/bcr/modules/foo", "foo")},
+		{Label: label.New("", "data/bcr-mirror/modules/foo", "foo")},
+		{Label: label.New("", "data/internal/modules/foo", "foo")},
+	}
+	got := findResultsInPackage(results, "data/bcr/modules")
+	if len(got) != 1 || got[0].Label.Pkg != "data/bcr/modules/foo" {
+		t.Errorf("findResultsInPackage() = %v, want only data/bcr/modules/foo", got)
+	}
+}
+
+func TestIsShadowedModuleVersion(t *testing.T) {
+	repoRoot := t.TempDir()
+	for _, dir := range []string{
+		"internal/modules/foo/1.0.0",
+		"bcr/modules/foo/1.0.0",
+		"bcr/modules/foo/2.0.0",
+	} {
+		if err := os.MkdirAll(filepath.Join(repoRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repoRoot, dir, "MODULE.bazel"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	registries, err := newRegistryLayers([]string{"internal", "bcr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext := &bcrExtension{repoRoot: repoRoot, registries: registries}
+	internal, bcr := registries[0], registries[1]
+
+	if !ext.isShadowedModuleVersion(bcr, "foo", "1.0.0") {
+		t.Error("expected bcr foo@1.0.0 to be shadowed by the internal registry")
+	}
+	if ext.isShadowedModuleVersion(bcr, "foo", "2.0.0") {
+		t.Error("expected bcr foo@2.0.0 not to be shadowed")
+	}
+	if ext.isShadowedModuleVersion(internal, "foo", "1.0.0") {
+		t.Error("the registry with the highest precedence is never shadowed")
+	}
+}
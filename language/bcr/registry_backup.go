@@ -2,40 +2,37 @@ package bcr
 
 import (
 	"compress/gzip"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
 	"google.golang.org/protobuf/proto"
 )
 
-// loadBackupRegistry fetches and loads the backup registry from the configured URL
+// loadBackupRegistry fetches and loads the backup registry from the configured
+// URL.  A URL without scheme (or a file:// URL) is read from the local file
+// system, which is the only option in offline mode.
 func (ext *bcrExtension) loadBackupRegistry() {
 	if ext.registrySourceURL == "" {
 		return
 	}
 
-	log.Printf("Fetching backup registry from %s", ext.registrySourceURL)
-
-	resp, err := http.Get(ext.registrySourceURL)
+	body, err := ext.openBackupRegistry()
 	if err != nil {
 		log.Printf("warning: failed to fetch backup registry: %v", err)
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("warning: failed to fetch backup registry: HTTP %d", resp.StatusCode)
-		return
-	}
+	defer body.Close()
 
-	var reader io.Reader = resp.Body
+	var reader io.Reader = body
 
 	// If the URL ends with .gz, decompress
 	if strings.HasSuffix(ext.registrySourceURL, ".gz") {
-		gzReader, err := gzip.NewReader(resp.Body)
+		gzReader, err := gzip.NewReader(body)
 		if err != nil {
 			log.Printf("warning: failed to decompress backup registry: %v", err)
 			return
@@ -62,6 +59,35 @@ func (ext *bcrExtension) loadBackupRegistry() {
 	log.Printf("Loaded backup registry with %d modules", len(registry.Modules))
 }
 
+// openBackupRegistry opens the backup registry file or starts the download.
+func (ext *bcrExtension) openBackupRegistry() (io.ReadCloser, error) {
+	filename, isFile := strings.CutPrefix(ext.registrySourceURL, "file://")
+	if !isFile && !strings.Contains(ext.registrySourceURL, "://") {
+		filename, isFile = os.ExpandEnv(ext.registrySourceURL), true
+	}
+	if isFile {
+		log.Printf("Reading backup registry from %s", filename)
+		return os.Open(filename)
+	}
+
+	if ext.offline {
+		ext.cacheMisses.add(bzpb.CacheMissKind_CACHE_MISS_BACKUP_REGISTRY, ext.registrySourceURL)
+		return nil, fmt.Errorf("%s is not a local file (offline mode)", ext.registrySourceURL)
+	}
+
+	log.Printf("Fetching backup registry from %s", ext.registrySourceURL)
+
+	resp, err := http.Get(ext.registrySourceURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
 // getBackupRepositoryMetadata retrieves repository metadata from the backup registry
 func (ext *bcrExtension) getBackupRepositoryMetadata(repoID repositoryID) *bzpb.RepositoryMetadata {
 	if ext.backupRegistry == nil {
@@ -195,6 +221,26 @@ func (ext *bcrExtension) populateFromBackupRegistry(repositories []*bzpb.Reposit
 		if backupMd.CanonicalName != "" {
 			md.CanonicalName = backupMd.CanonicalName
 		}
+		if backupMd.License != "" {
+			md.License = backupMd.License
+		}
+		if len(backupMd.Topics) > 0 {
+			md.Topics = backupMd.Topics
+		}
+		md.Archived = backupMd.Archived
+		md.Disabled = backupMd.Disabled
+		if backupMd.DefaultBranch != "" {
+			md.DefaultBranch = backupMd.DefaultBranch
+		}
+		if backupMd.PushedAt != "" {
+			md.PushedAt = backupMd.PushedAt
+		}
+		if backupMd.OpenIssues > 0 {
+			md.OpenIssues = backupMd.OpenIssues
+		}
+		if backupMd.LatestRelease != "" {
+			md.LatestRelease = backupMd.LatestRelease
+		}
 
 		populated++
 	}
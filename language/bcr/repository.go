@@ -1,12 +1,15 @@
 package bcr
 
 import (
+	"cmp"
 	"fmt"
 	"maps"
 	"slices"
 	"strings"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/gitea"
+	"github.com/bazel-contrib/bcr-frontend/pkg/gl"
 	"github.com/bazelbuild/bazel-gazelle/rule"
 )
 
@@ -31,8 +34,20 @@ func (ext *bcrExtension) trackRepositories(repos []string) {
 // Supports formats like:
 //   - "github:owner/repo"
 //   - "gitlab:owner/repo"
+//   - "gitlab:gitlab.example.com/owner/repo"
+//   - "codeberg:owner/repo"
+//   - "gitea:gitea.example.com/owner/repo"
+//   - "bitbucket:owner/repo"
+//   - "sourcehut:~owner/repo"
 //   - "https://github.com/owner/repo"
 //   - "https://gitlab.com/owner/repo"
+//   - "https://gitlab.example.com/group/subgroup/repo"
+//   - "https://codeberg.org/owner/repo"
+//   - "https://bitbucket.org/owner/repo"
+//   - "https://git.sr.ht/~owner/repo"
+//
+// GitLab projects can be nested in subgroups, in which case the name holds
+// the remaining path (e.g. "lib/libxtrans").
 func parseRepositoryMetadataFromRepositoryString(repoStr string) (*bzpb.RepositoryMetadata, bool) {
 	md := &bzpb.RepositoryMetadata{}
 
@@ -47,24 +62,51 @@ func parseRepositoryMetadataFromRepositoryString(repoStr string) (*bzpb.Reposito
 		md.Type = bzpb.RepositoryType_GITHUB
 		repoStr = after
 	} else if after, found := strings.CutPrefix(repoStr, "gitlab:"); found {
-		// GitLab support (currently mapped to REPOSITORY_TYPE_UNKNOWN)
-		md.Type = bzpb.RepositoryType_REPOSITORY_TYPE_UNKNOWN
+		md.Type = bzpb.RepositoryType_GITLAB
 		repoStr = after
-	} else if strings.HasPrefix(repoStr, "https://gitlab.") || strings.HasPrefix(repoStr, "http://gitlab.") {
-		// Handle any gitlab.* domain (gitlab.com, gitlab.arm.com, gitlab.freedesktop.org, etc.)
-		md.Type = bzpb.RepositoryType_REPOSITORY_TYPE_UNKNOWN
-		// Remove protocol prefix
-		if after, found := strings.CutPrefix(repoStr, "https://"); found {
-			repoStr = after
-		} else if after, found := strings.CutPrefix(repoStr, "http://"); found {
-			repoStr = after
+		// Self-hosted instances are written as gitlab:HOST/owner/repo
+		if host, rest, ok := strings.Cut(repoStr, "/"); ok && isGitlabHost(host) {
+			md.Host = normalizeGitlabHost(host)
+			repoStr = rest
 		}
-		// Remove domain (everything up to and including the first /)
-		if idx := strings.Index(repoStr, "/"); idx >= 0 {
-			repoStr = repoStr[idx+1:]
-		} else {
+	} else if after, found := strings.CutPrefix(repoStr, "codeberg:"); found {
+		md.Type = bzpb.RepositoryType_GITEA
+		md.Host = gitea.DefaultHost
+		repoStr = after
+	} else if after, found := strings.CutPrefix(repoStr, "gitea:"); found {
+		// Gitea instances are always written with their host
+		host, rest, ok := strings.Cut(after, "/")
+		if !ok {
+			return nil, false
+		}
+		md.Type = bzpb.RepositoryType_GITEA
+		md.Host = strings.ToLower(host)
+		repoStr = rest
+	} else if after, found := strings.CutPrefix(repoStr, "bitbucket:"); found {
+		md.Type = bzpb.RepositoryType_BITBUCKET
+		repoStr = after
+	} else if after, found := strings.CutPrefix(repoStr, "sourcehut:"); found {
+		md.Type = bzpb.RepositoryType_SOURCEHUT
+		repoStr = after
+	} else if host, rest, ok := cutHTTPURL(repoStr); ok {
+		host = strings.ToLower(host)
+		switch {
+		case isGitlabHost(host):
+			// Handle any gitlab.* domain (gitlab.com, gitlab.arm.com, gitlab.freedesktop.org, etc.)
+			md.Type = bzpb.RepositoryType_GITLAB
+			md.Host = normalizeGitlabHost(host)
+		case isGiteaHost(host):
+			md.Type = bzpb.RepositoryType_GITEA
+			md.Host = host
+		case host == "bitbucket.org":
+			md.Type = bzpb.RepositoryType_BITBUCKET
+		case host == "git.sr.ht":
+			md.Type = bzpb.RepositoryType_SOURCEHUT
+		default:
+			// Unknown host
 			return nil, false
 		}
+		repoStr = rest
 	} else {
 		// Unknown format
 		return nil, false
@@ -79,6 +121,11 @@ func parseRepositoryMetadataFromRepositoryString(repoStr string) (*bzpb.Reposito
 	md.Organization = parts[0]
 	md.Name = parts[1]
 
+	// sourcehut user names are prefixed with a tilde in urls
+	if md.Type == bzpb.RepositoryType_SOURCEHUT {
+		md.Organization = strings.TrimPrefix(md.Organization, "~")
+	}
+
 	// Clean up the name (remove trailing slashes, .git suffix, query params, etc.)
 	md.Name = strings.TrimSuffix(md.Name, "/")
 	md.Name = strings.TrimSuffix(md.Name, ".git")
@@ -86,9 +133,46 @@ func parseRepositoryMetadataFromRepositoryString(repoStr string) (*bzpb.Reposito
 		md.Name = md.Name[:idx]
 	}
 
+	if md.Organization == "" || md.Name == "" {
+		return nil, false
+	}
+
 	return md, true
 }
 
+// cutHTTPURL splits a http(s) url into the host and the path.
+func cutHTTPURL(url string) (host, path string, ok bool) {
+	for _, scheme := range []string{"https://", "http://"} {
+		if after, found := strings.CutPrefix(url, scheme); found {
+			return strings.Cut(after, "/")
+		}
+	}
+	return "", "", false
+}
+
+// isGitlabHost reports whether the host is a GitLab instance.  Self-hosted
+// instances are recognized by their gitlab.* domain.
+func isGitlabHost(host string) bool {
+	return strings.HasPrefix(strings.ToLower(host), "gitlab.")
+}
+
+// normalizeGitlabHost returns the host as stored in RepositoryMetadata: empty
+// for gitlab.com.
+func normalizeGitlabHost(host string) string {
+	host = strings.ToLower(host)
+	if host == gl.DefaultHost {
+		return ""
+	}
+	return host
+}
+
+// isGiteaHost reports whether the host is a Gitea or Forgejo instance:
+// codeberg.org, gitea.com, or a gitea.* or forgejo.* domain.
+func isGiteaHost(host string) bool {
+	return host == gitea.DefaultHost || host == "gitea.com" ||
+		strings.HasPrefix(host, "gitea.") || strings.HasPrefix(host, "forgejo.")
+}
+
 // normalizeRepositoryID returns a canonical form of a repository string e.g.,
 // "github:org/repo"
 func normalizeRepositoryID(repoStr string) repositoryID {
@@ -100,13 +184,23 @@ func normalizeRepositoryID(repoStr string) repositoryID {
 }
 
 // formatRepositoryID prints a canonical form of a repository string
-// e.g., "github:org/repo"
+// e.g., "github:org/repo", or "gitlab:host/org/repo" for self-hosted GitLab
+// instances
 func formatRepositoryID(md *bzpb.RepositoryMetadata) repositoryID {
 	switch md.Type {
 	case bzpb.RepositoryType_GITHUB:
 		return repositoryID(fmt.Sprintf("github:%s/%s", md.Organization, md.Name))
 	case bzpb.RepositoryType_GITLAB:
+		if md.Host != "" {
+			return repositoryID(fmt.Sprintf("gitlab:%s/%s/%s", md.Host, md.Organization, md.Name))
+		}
 		return repositoryID(fmt.Sprintf("gitlab:%s/%s", md.Organization, md.Name))
+	case bzpb.RepositoryType_GITEA:
+		return repositoryID(fmt.Sprintf("gitea:%s/%s/%s", md.Host, md.Organization, md.Name))
+	case bzpb.RepositoryType_BITBUCKET:
+		return repositoryID(fmt.Sprintf("bitbucket:%s/%s", md.Organization, md.Name))
+	case bzpb.RepositoryType_SOURCEHUT:
+		return repositoryID(fmt.Sprintf("sourcehut:~%s/%s", md.Organization, md.Name))
 	default:
 		return repositoryID(fmt.Sprintf("%s/%s", md.Organization, md.Name))
 	}
@@ -114,14 +208,26 @@ func formatRepositoryID(md *bzpb.RepositoryMetadata) repositoryID {
 
 // makeRepositoryMetadataRuleName creates a Bazel rule name from repository metadata
 func makeRepositoryMetadataRuleName(md *bzpb.RepositoryMetadata) string {
+	var host string
 	switch md.Type {
 	case bzpb.RepositoryType_GITHUB:
 		return fmt.Sprintf("com_github_%s_%s", md.Organization, md.Name)
 	case bzpb.RepositoryType_GITLAB:
-		return fmt.Sprintf("com_gitlab_%s_%s", md.Organization, md.Name)
+		host = cmp.Or(md.Host, gl.DefaultHost)
+	case bzpb.RepositoryType_GITEA:
+		host = md.Host
+	case bzpb.RepositoryType_BITBUCKET:
+		host = "bitbucket.org"
+	case bzpb.RepositoryType_SOURCEHUT:
+		host = "git.sr.ht"
 	default:
 		return fmt.Sprintf("%s_%s", md.Organization, md.Name)
 	}
+	// gitlab.arm.com => com_arm_gitlab
+	labels := strings.Split(host, ".")
+	slices.Reverse(labels)
+	// subgroups and dotted names are flattened
+	return strings.NewReplacer("/", "_", ".", "_").Replace(fmt.Sprintf("%s_%s_%s", strings.Join(labels, "_"), md.Organization, md.Name))
 }
 
 // makeRepositoryMetadataRules creates repository_metadata rules from the
@@ -28,6 +28,14 @@ func repositoryMetadataKinds() map[string]rule.KindInfo {
 				"description":      true,
 				"stargazers":       true,
 				"primary_language": true,
+				"license":          true,
+				"topics":           true,
+				"archived":         true,
+				"disabled":         true,
+				"default_branch":   true,
+				"pushed_at":        true,
+				"open_issues":      true,
+				"latest_release":   true,
 			},
 		},
 	}
@@ -59,6 +67,9 @@ func updateRepositoryMetadataRule(md *bzpb.RepositoryMetadata, r *rule.Rule) {
 	if md.Type != bzpb.RepositoryType_REPOSITORY_TYPE_UNKNOWN {
 		r.SetAttr("type", strings.ToLower(md.Type.String()))
 	}
+	if md.Host != "" {
+		r.SetAttr("host", md.Host)
+	}
 	if md.Organization != "" {
 		r.SetAttr("organization", md.Organization)
 	}
@@ -75,6 +86,33 @@ func updateRepositoryMetadataRule(md *bzpb.RepositoryMetadata, r *rule.Rule) {
 		r.SetAttr("languages", makeStringDict(md.Languages))
 		primaryLanguage := computePrimaryLanguage(md.Languages)
 		r.SetAttr("primary_language", primaryLanguage)
+	} else if md.PrimaryLanguage != "" {
+		// some hosts only report the primary language (e.g. Bitbucket)
+		r.SetAttr("primary_language", md.PrimaryLanguage)
+	}
+	if md.License != "" {
+		r.SetAttr("license", md.License)
+	}
+	if len(md.Topics) > 0 {
+		r.SetAttr("topics", md.Topics)
+	}
+	if md.Archived {
+		r.SetAttr("archived", true)
+	}
+	if md.Disabled {
+		r.SetAttr("disabled", true)
+	}
+	if md.DefaultBranch != "" {
+		r.SetAttr("default_branch", md.DefaultBranch)
+	}
+	if md.PushedAt != "" {
+		r.SetAttr("pushed_at", md.PushedAt)
+	}
+	if md.OpenIssues != 0 {
+		r.SetAttr("open_issues", int(md.OpenIssues))
+	}
+	if md.LatestRelease != "" {
+		r.SetAttr("latest_release", md.LatestRelease)
 	}
 }
 
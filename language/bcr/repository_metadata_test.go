@@ -0,0 +1,50 @@
+package bcr
+
+import (
+	"slices"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestMakeRepositoryMetadataRule(t *testing.T) {
+	r := makeRepositoryMetadataRule(&bzpb.RepositoryMetadata{
+		Type:          bzpb.RepositoryType_GITHUB,
+		Organization:  "bazel-contrib",
+		Name:          "rules_go",
+		Languages:     map[string]int32{"Go": 2000, "Starlark": 1000},
+		License:       "Apache-2.0",
+		Topics:        []string{"bazel", "golang"},
+		Archived:      true,
+		DefaultBranch: "master",
+		PushedAt:      "2026-10-01T12:00:00Z",
+		OpenIssues:    321,
+		LatestRelease: "v0.50.0",
+	})
+
+	if got := r.Name(); got != "com_github_bazel-contrib_rules_go" {
+		t.Errorf("name = %q", got)
+	}
+	for attr, want := range map[string]string{
+		"canonical_name":   "github:bazel-contrib/rules_go",
+		"type":             "github",
+		"primary_language": "Go",
+		"license":          "Apache-2.0",
+		"default_branch":   "master",
+		"pushed_at":        "2026-10-01T12:00:00Z",
+		"latest_release":   "v0.50.0",
+	} {
+		if got := r.AttrString(attr); got != want {
+			t.Errorf("%s = %q, want %q", attr, got, want)
+		}
+	}
+	if got := r.AttrStrings("topics"); !slices.Equal(got, []string{"bazel", "golang"}) {
+		t.Errorf("topics = %v", got)
+	}
+	if r.Attr("archived") == nil || r.Attr("disabled") != nil {
+		t.Error("expected only archived to be set")
+	}
+	if r.Attr("open_issues") == nil {
+		t.Error("expected open_issues to be set")
+	}
+}
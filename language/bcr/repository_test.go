@@ -13,6 +13,7 @@ func TestParseRepository(t *testing.T) {
 		wantOrg  string
 		wantName string
 		wantType bzpb.RepositoryType
+		wantHost string
 		wantOk   bool
 	}{
 		// GitHub formats
@@ -72,13 +73,30 @@ func TestParseRepository(t *testing.T) {
 			wantType: bzpb.RepositoryType_GITHUB,
 			wantOk:   true,
 		},
-		// GitLab formats (currently REPOSITORY_TYPE_UNKNOWN)
+		// GitLab formats
 		{
 			name:     "gitlab prefix",
 			input:    "gitlab:arm-bazel/ape",
 			wantOrg:  "arm-bazel",
 			wantName: "ape",
-			wantType: bzpb.RepositoryType_REPOSITORY_TYPE_UNKNOWN,
+			wantType: bzpb.RepositoryType_GITLAB,
+			wantOk:   true,
+		},
+		{
+			name:     "gitlab prefix with host",
+			input:    "gitlab:gitlab.arm.com/bazel/ape",
+			wantOrg:  "bazel",
+			wantName: "ape",
+			wantType: bzpb.RepositoryType_GITLAB,
+			wantHost: "gitlab.arm.com",
+			wantOk:   true,
+		},
+		{
+			name:     "https gitlab.com",
+			input:    "https://gitlab.com/libeigen/eigen",
+			wantOrg:  "libeigen",
+			wantName: "eigen",
+			wantType: bzpb.RepositoryType_GITLAB,
 			wantOk:   true,
 		},
 		{
@@ -86,7 +104,8 @@ func TestParseRepository(t *testing.T) {
 			input:    "https://gitlab.arm.com/bazel/ape",
 			wantOrg:  "bazel",
 			wantName: "ape",
-			wantType: bzpb.RepositoryType_REPOSITORY_TYPE_UNKNOWN,
+			wantType: bzpb.RepositoryType_GITLAB,
+			wantHost: "gitlab.arm.com",
 			wantOk:   true,
 		},
 		{
@@ -94,7 +113,68 @@ func TestParseRepository(t *testing.T) {
 			input:    "https://gitlab.freedesktop.org/xorg/lib/libxtrans",
 			wantOrg:  "xorg",
 			wantName: "lib/libxtrans",
-			wantType: bzpb.RepositoryType_REPOSITORY_TYPE_UNKNOWN,
+			wantType: bzpb.RepositoryType_GITLAB,
+			wantHost: "gitlab.freedesktop.org",
+			wantOk:   true,
+		},
+		// Gitea, Bitbucket and sourcehut formats
+		{
+			name:     "codeberg prefix",
+			input:    "codeberg:forgejo/forgejo",
+			wantOrg:  "forgejo",
+			wantName: "forgejo",
+			wantType: bzpb.RepositoryType_GITEA,
+			wantHost: "codeberg.org",
+			wantOk:   true,
+		},
+		{
+			name:     "https codeberg",
+			input:    "https://codeberg.org/forgejo/forgejo.git",
+			wantOrg:  "forgejo",
+			wantName: "forgejo",
+			wantType: bzpb.RepositoryType_GITEA,
+			wantHost: "codeberg.org",
+			wantOk:   true,
+		},
+		{
+			name:     "gitea prefix with host",
+			input:    "gitea:gitea.example.com/org/repo",
+			wantOrg:  "org",
+			wantName: "repo",
+			wantType: bzpb.RepositoryType_GITEA,
+			wantHost: "gitea.example.com",
+			wantOk:   true,
+		},
+		{
+			name:     "https bitbucket",
+			input:    "https://bitbucket.org/atlassian/python-bitbucket/",
+			wantOrg:  "atlassian",
+			wantName: "python-bitbucket",
+			wantType: bzpb.RepositoryType_BITBUCKET,
+			wantOk:   true,
+		},
+		{
+			name:     "bitbucket prefix",
+			input:    "bitbucket:atlassian/python-bitbucket",
+			wantOrg:  "atlassian",
+			wantName: "python-bitbucket",
+			wantType: bzpb.RepositoryType_BITBUCKET,
+			wantOk:   true,
+		},
+		{
+			name:     "https sourcehut",
+			input:    "https://git.sr.ht/~sircmpwn/scdoc",
+			wantOrg:  "sircmpwn",
+			wantName: "scdoc",
+			wantType: bzpb.RepositoryType_SOURCEHUT,
+			wantOk:   true,
+		},
+		{
+			name:     "sourcehut prefix",
+			input:    "sourcehut:~sircmpwn/scdoc",
+			wantOrg:  "sircmpwn",
+			wantName: "scdoc",
+			wantType: bzpb.RepositoryType_SOURCEHUT,
 			wantOk:   true,
 		},
 		// Invalid formats
@@ -189,6 +269,40 @@ func TestParseRepository(t *testing.T) {
 			if md.Type != tt.wantType {
 				t.Errorf("parseRepository(%q) type = %v, want %v", tt.input, md.Type, tt.wantType)
 			}
+
+			if md.Host != tt.wantHost {
+				t.Errorf("parseRepository(%q) host = %q, want %q", tt.input, md.Host, tt.wantHost)
+			}
 		})
 	}
 }
+
+func TestFormatRepositoryID(t *testing.T) {
+	for input, want := range map[string]struct {
+		id       repositoryID
+		ruleName string
+	}{
+		"github:bazelbuild/rules_go":                        {"github:bazelbuild/rules_go", "com_github_bazelbuild_rules_go"},
+		"https://gitlab.com/libeigen/eigen":                 {"gitlab:libeigen/eigen", "com_gitlab_libeigen_eigen"},
+		"https://gitlab.arm.com/bazel/ape":                  {"gitlab:gitlab.arm.com/bazel/ape", "com_arm_gitlab_bazel_ape"},
+		"https://gitlab.freedesktop.org/xorg/lib/libxtrans": {"gitlab:gitlab.freedesktop.org/xorg/lib/libxtrans", "org_freedesktop_gitlab_xorg_lib_libxtrans"},
+		"https://codeberg.org/forgejo/forgejo":              {"gitea:codeberg.org/forgejo/forgejo", "org_codeberg_forgejo_forgejo"},
+		"https://bitbucket.org/atlassian/python-bitbucket":  {"bitbucket:atlassian/python-bitbucket", "org_bitbucket_atlassian_python-bitbucket"},
+		"https://git.sr.ht/~sircmpwn/scdoc":                 {"sourcehut:~sircmpwn/scdoc", "ht_sr_git_sircmpwn_scdoc"},
+	} {
+		md, ok := parseRepositoryMetadataFromRepositoryString(input)
+		if !ok {
+			t.Fatalf("failed to parse %q", input)
+		}
+		if got := formatRepositoryID(md); got != want.id {
+			t.Errorf("formatRepositoryID(%q) = %q, want %q", input, got, want.id)
+		}
+		if got := makeRepositoryMetadataRuleName(md); got != want.ruleName {
+			t.Errorf("makeRepositoryMetadataRuleName(%q) = %q, want %q", input, got, want.ruleName)
+		}
+		// the canonical form parses back to the same repository
+		if roundTrip := normalizeRepositoryID(string(want.id)); roundTrip != want.id {
+			t.Errorf("normalizeRepositoryID(%q) = %q", want.id, roundTrip)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package bcr
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+	"github.com/dominikbraun/graph"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// reverseDependencyIndexFilename is the name of the file written into the
+// modules root package (next to the module_registry rule).
+const reverseDependencyIndexFilename = "reverse_dependencies.pb"
+
+// reverseDependencies holds the dependents of a single module version.  The
+// dev sets only contain module versions that are not already present in the
+// corresponding regular set.
+type reverseDependencies struct {
+	direct        map[moduleID]bool
+	directDev     map[moduleID]bool
+	transitive    map[moduleID]bool
+	transitiveDev map[moduleID]bool
+	counts        *bzpb.ReverseDependencyCounts
+}
+
+// reverseDependencyIndex maps each module version to its dependents.
+type reverseDependencyIndex map[moduleID]*reverseDependencies
+
+// dependentsSummary holds the transitive dependents shared by the members of
+// a strongly connected component of the regular graph: the (known) module
+// versions of its ancestor components, and the dev dependents of the
+// component and of its ancestors.
+type dependentsSummary struct {
+	transitive map[moduleID]bool
+	dev        map[moduleID]bool
+}
+
+// buildReverseDependencyIndex computes the reverse dependencies of every
+// known module version from the predecessor maps of the regular and dev
+// dependency graphs, and the strongly connected components of the regular
+// graph.  Vertices that are not known (e.g. unresolved deps) are ignored both
+// as entries and as dependents.
+//
+// Dev dependencies are only honored by bazel for the root module, so a
+// module version D is a transitive dev dependent of X if D has a dev dep on X
+// or on one of the regular transitive dependents of X.
+//
+// Rather than walking up the graph from every module version, the components
+// are processed in topological order (dependents first): the transitive
+// dependents of a component are the union of its parent components and of
+// their transitive dependents.  Summaries are released once every child
+// component has consumed them, so only the frontier of the traversal is held
+// in memory while the dependents of every module version are collected.
+func buildReverseDependencyIndex(regular, dev map[moduleID]map[moduleID]graph.Edge[moduleID], sccs [][]moduleID, known map[moduleID]bool, isLatest func(moduleID) bool) reverseDependencyIndex {
+	index := make(reverseDependencyIndex, len(known))
+
+	// vertices that are missing from the regular graph are components on
+	// their own
+	sccs = slices.Clone(sccs)
+	componentOf := make(map[moduleID]int, len(known))
+	for i, scc := range sccs {
+		for _, id := range scc {
+			componentOf[id] = i
+		}
+	}
+	addSingleton := func(id moduleID) {
+		if _, ok := componentOf[id]; !ok {
+			componentOf[id] = len(sccs)
+			sccs = append(sccs, []moduleID{id})
+		}
+	}
+	for id := range known {
+		addSingleton(id)
+	}
+	for id, predecessors := range regular {
+		addSingleton(id)
+		for from := range predecessors {
+			addSingleton(from)
+		}
+	}
+
+	// Build the condensation: distinct parent (dependent) components and
+	// child components of each component.
+	parents := make([][]int, len(sccs))
+	children := make([][]int, len(sccs))
+	for i, scc := range sccs {
+		seen := make(map[int]bool)
+		for _, id := range scc {
+			for from := range regular[id] {
+				j := componentOf[from]
+				if j == i || seen[j] {
+					continue
+				}
+				seen[j] = true
+				parents[i] = append(parents[i], j)
+				children[j] = append(children[j], i)
+			}
+		}
+	}
+
+	// Kahn's algorithm: a component is ready once all of its parents have
+	// been summarized.
+	pendingParents := make([]int, len(sccs))
+	remainingChildren := make([]int, len(sccs))
+	var ready []int
+	for i := range sccs {
+		pendingParents[i] = len(parents[i])
+		remainingChildren[i] = len(children[i])
+		if pendingParents[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	summaries := make([]*dependentsSummary, len(sccs))
+	for len(ready) > 0 {
+		i := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+
+		summary := &dependentsSummary{
+			transitive: make(map[moduleID]bool),
+			dev:        make(map[moduleID]bool),
+		}
+		for _, j := range parents[i] {
+			for _, id := range sccs[j] {
+				if known[id] {
+					summary.transitive[id] = true
+				}
+			}
+			maps.Copy(summary.transitive, summaries[j].transitive)
+			maps.Copy(summary.dev, summaries[j].dev)
+			if remainingChildren[j]--; remainingChildren[j] == 0 {
+				summaries[j] = nil
+			}
+		}
+		for _, id := range sccs[i] {
+			for from := range dev[id] {
+				if known[from] {
+					summary.dev[from] = true
+				}
+			}
+		}
+		if len(children[i]) > 0 {
+			summaries[i] = summary
+		}
+
+		for _, id := range sccs[i] {
+			if known[id] {
+				index[id] = summary.reverseDependencies(id, sccs[i], regular, dev, known, isLatest)
+			}
+		}
+
+		for _, j := range children[i] {
+			if pendingParents[j]--; pendingParents[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+
+	return index
+}
+
+// reverseDependencies returns the dependents of a member of the summarized
+// component.  The other members of a cycle are transitive dependents too.
+func (s *dependentsSummary) reverseDependencies(id moduleID, component []moduleID, regular, dev map[moduleID]map[moduleID]graph.Edge[moduleID], known map[moduleID]bool, isLatest func(moduleID) bool) *reverseDependencies {
+	rd := &reverseDependencies{
+		direct:        make(map[moduleID]bool),
+		directDev:     make(map[moduleID]bool),
+		transitive:    maps.Clone(s.transitive),
+		transitiveDev: make(map[moduleID]bool),
+	}
+
+	for from := range regular[id] {
+		if from != id && known[from] {
+			rd.direct[from] = true
+		}
+	}
+	for from := range dev[id] {
+		if from != id && known[from] && !rd.direct[from] {
+			rd.directDev[from] = true
+		}
+	}
+
+	inComponent := make(map[moduleID]bool, len(component))
+	for _, member := range component {
+		inComponent[member] = true
+		if member != id && known[member] {
+			rd.transitive[member] = true
+		}
+	}
+	for from := range s.dev {
+		if !inComponent[from] && !s.transitive[from] {
+			rd.transitiveDev[from] = true
+		}
+	}
+
+	rd.counts = countReverseDependencies(rd.direct, rd.directDev, rd.transitive, rd.transitiveDev, isLatest)
+	return rd
+}
+
+// countReverseDependencies summarizes the given dependent sets.
+func countReverseDependencies(direct, directDev, transitive, transitiveDev map[moduleID]bool, isLatest func(moduleID) bool) *bzpb.ReverseDependencyCounts {
+	countLatest := func(ids map[moduleID]bool) (n int32) {
+		for id := range ids {
+			if isLatest(id) {
+				n++
+			}
+		}
+		return
+	}
+	return &bzpb.ReverseDependencyCounts{
+		Direct:              int32(len(direct)),
+		DirectDev:           int32(len(directDev)),
+		Transitive:          int32(len(transitive)),
+		TransitiveDev:       int32(len(transitiveDev)),
+		DirectLatest:        countLatest(direct),
+		DirectDevLatest:     countLatest(directDev),
+		TransitiveLatest:    countLatest(transitive),
+		TransitiveDevLatest: countLatest(transitiveDev),
+	}
+}
+
+// toProto converts the index to its proto form.
+func (index reverseDependencyIndex) toProto(isLatest func(moduleID) bool) *bzpb.ReverseDependencyIndex {
+	ids := slices.SortedFunc(maps.Keys(index), compareModuleIDs)
+
+	result := &bzpb.ReverseDependencyIndex{
+		ModuleVersions: make([]*bzpb.ModuleVersionDependents, 0, len(ids)),
+	}
+	for _, id := range ids {
+		rd := index[id]
+		result.ModuleVersions = append(result.ModuleVersions, &bzpb.ModuleVersionDependents{
+			Name:                string(id.name()),
+			Version:             string(id.version()),
+			Direct:              sortedModuleIDStrings(rd.direct, nil),
+			DirectDev:           sortedModuleIDStrings(rd.directDev, nil),
+			Transitive:          sortedModuleIDStrings(rd.transitive, nil),
+			TransitiveDev:       sortedModuleIDStrings(rd.transitiveDev, nil),
+			TransitiveLatest:    sortedModuleIDStrings(rd.transitive, isLatest),
+			TransitiveDevLatest: sortedModuleIDStrings(rd.transitiveDev, isLatest),
+			Counts:              rd.counts,
+		})
+	}
+	return result
+}
+
+// moduleCounts computes per-module counts: the dependents of any version of
+// the module, except the versions of the module itself.
+func (index reverseDependencyIndex) moduleCounts(isLatest func(moduleID) bool) map[moduleName]*bzpb.ReverseDependencyCounts {
+	versions := make(map[moduleName][]moduleID)
+	for id := range index {
+		versions[id.name()] = append(versions[id.name()], id)
+	}
+
+	counts := make(map[moduleName]*bzpb.ReverseDependencyCounts, len(versions))
+	for name, ids := range versions {
+		direct := make(map[moduleID]bool)
+		directDev := make(map[moduleID]bool)
+		transitive := make(map[moduleID]bool)
+		transitiveDev := make(map[moduleID]bool)
+		union := func(dst, src map[moduleID]bool) {
+			for from := range src {
+				if from.name() != name {
+					dst[from] = true
+				}
+			}
+		}
+		for _, id := range ids {
+			union(direct, index[id].direct)
+			union(directDev, index[id].directDev)
+			union(transitive, index[id].transitive)
+			union(transitiveDev, index[id].transitiveDev)
+		}
+
+		// a dependent may reach one version regularly and another only via
+		// dev deps; keep the dev sets disjoint from the regular ones
+		for from := range direct {
+			delete(directDev, from)
+		}
+		for from := range transitive {
+			delete(transitiveDev, from)
+		}
+
+		counts[name] = countReverseDependencies(direct, directDev, transitive, transitiveDev, isLatest)
+	}
+	return counts
+}
+
+// sortedModuleIDStrings returns the IDs in the set (optionally filtered) as a
+// sorted list of strings.
+func sortedModuleIDStrings(ids map[moduleID]bool, filter func(moduleID) bool) []string {
+	var list []moduleID
+	for id := range ids {
+		if filter == nil || filter(id) {
+			list = append(list, id)
+		}
+	}
+	slices.SortFunc(list, compareModuleIDs)
+
+	result := make([]string, len(list))
+	for i, id := range list {
+		result[i] = string(id)
+	}
+	return result
+}
+
+// reverseDependencyCountsToStringDict encodes the non-zero counts as a
+// string_dict keyed by proto field name, as expected by the
+// "reverse_dependency_counts" attribute of module_metadata.
+func reverseDependencyCountsToStringDict(counts *bzpb.ReverseDependencyCounts) map[string]string {
+	dict := make(map[string]string)
+	counts.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		dict[string(fd.Name())] = strconv.FormatInt(v.Int(), 10)
+		return true
+	})
+	return dict
+}
+
+// calculateReverseDependencies builds the reverse dependency index, annotates
+// the module_metadata rules with per-module counts and writes the index next
+// to the module_registry rule.
+func (ext *bcrExtension) calculateReverseDependencies() {
+	regular, err := ext.regularDepGraph.PredecessorMap()
+	if err != nil {
+		log.Printf("WARN: failed to get regular predecessor map: %v", err)
+		return
+	}
+	dev, err := ext.devDepGraph.PredecessorMap()
+	if err != nil {
+		log.Printf("WARN: failed to get dev predecessor map: %v", err)
+		return
+	}
+
+	known := make(map[moduleID]bool, len(ext.moduleVersionRules))
+	for id := range ext.moduleVersionRules {
+		known[id] = true
+	}
+	isLatest := func(id moduleID) bool {
+		protoRule, ok := ext.moduleVersionRules[id]
+		return ok && isLatestVersion(protoRule)
+	}
+
+	sccs, err := graph.StronglyConnectedComponents(ext.regularDepGraph)
+	if err != nil {
+		log.Printf("WARN: failed to get strongly connected components of the regular graph: %v", err)
+		return
+	}
+
+	index := buildReverseDependencyIndex(regular, dev, sccs, known, isLatest)
+
+	var annotatedCount int
+	for name, counts := range index.moduleCounts(isLatest) {
+		protoRule, ok := ext.moduleMetadataRules[name]
+		if !ok {
+			continue
+		}
+		if dict := reverseDependencyCountsToStringDict(counts); len(dict) > 0 {
+			protoRule.Rule().SetAttr("reverse_dependency_counts", dict)
+			annotatedCount++
+		}
+	}
+	log.Printf("Annotated %d modules with reverse dependency counts", annotatedCount)
+
+	if err := ext.writeReverseDependencyIndexFile(index.toProto(isLatest)); err != nil {
+		log.Printf("WARN: writing reverse dependency index: %v", err)
+	}
+}
+
+// writeReverseDependencyIndexFile writes the index into the modules root
+// package, where it is picked up by the module_registry rule.
+func (ext *bcrExtension) writeReverseDependencyIndexFile(index *bzpb.ReverseDependencyIndex) error {
+	filename := filepath.Join(ext.repoRoot, ext.modulesRoot, reverseDependencyIndexFilename)
+	if _, err := os.Stat(filepath.Dir(filename)); err != nil {
+		return fmt.Errorf("modules root not found: %w", err)
+	}
+	if err := protoutil.WriteFile(filename, index); err != nil {
+		return fmt.Errorf("failed to write reverse dependency index file %s: %w", filename, err)
+	}
+
+	log.Printf("Wrote reverse dependencies of %d module versions to %s", len(index.ModuleVersions), filename)
+	return nil
+}
+
+// readReverseDependencyIndexFile reads the index written by a previous run.
+func (ext *bcrExtension) readReverseDependencyIndexFile() (*bzpb.ReverseDependencyIndex, error) {
+	filename := filepath.Join(ext.repoRoot, ext.modulesRoot, reverseDependencyIndexFilename)
+	var index bzpb.ReverseDependencyIndex
+	if err := protoutil.ReadFile(filename, &index); err != nil {
+		return nil, fmt.Errorf("failed to read reverse dependency index file %s: %w", filename, err)
+	}
+	return &index, nil
+}
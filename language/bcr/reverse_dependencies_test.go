@@ -0,0 +1,208 @@
+package bcr
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/dominikbraun/graph"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBuildReverseDependencyIndex(t *testing.T) {
+	tests := []struct {
+		name              string
+		regular           map[moduleID][]moduleID
+		dev               map[moduleID][]moduleID
+		id                moduleID
+		wantDirect        []moduleID
+		wantDirectDev     []moduleID
+		wantTransitive    []moduleID
+		wantTransitiveDev []moduleID
+	}{
+		{
+			name: "direct and transitive",
+			regular: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0"},
+				"b@1.0": {"c@1.0"},
+				"d@1.0": {"c@1.0"},
+			},
+			id:             "c@1.0",
+			wantDirect:     []moduleID{"b@1.0", "d@1.0"},
+			wantTransitive: []moduleID{"a@1.0", "b@1.0", "d@1.0"},
+		},
+		{
+			name: "dev deps are only followed from the root",
+			regular: map[moduleID][]moduleID{
+				"b@1.0": {"c@1.0"},
+			},
+			dev: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0"},
+				"e@1.0": {"c@1.0"},
+				"f@1.0": {"a@1.0"},
+			},
+			id:                "c@1.0",
+			wantDirect:        []moduleID{"b@1.0"},
+			wantDirectDev:     []moduleID{"e@1.0"},
+			wantTransitive:    []moduleID{"b@1.0"},
+			wantTransitiveDev: []moduleID{"a@1.0", "e@1.0"},
+		},
+		{
+			name: "transitive dev excludes regular transitive dependents",
+			regular: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0"},
+				"b@1.0": {"c@1.0"},
+			},
+			dev: map[moduleID][]moduleID{
+				"a@1.0": {"c@1.0"},
+			},
+			id:             "c@1.0",
+			wantDirect:     []moduleID{"b@1.0"},
+			wantDirectDev:  []moduleID{"a@1.0"},
+			wantTransitive: []moduleID{"a@1.0", "b@1.0"},
+		},
+		{
+			name: "cycles and unknown vertices",
+			regular: map[moduleID][]moduleID{
+				"a@1.0": {"b@1.0"},
+				"b@1.0": {"a@1.0"},
+				"x@1.0": {"a@1.0"},
+			},
+			id:             "a@1.0",
+			wantDirect:     []moduleID{"b@1.0"},
+			wantTransitive: []moduleID{"b@1.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			known := make(map[moduleID]bool)
+			regular := makePredecessorMap(tt.regular, known)
+			dev := makePredecessorMap(tt.dev, known)
+			delete(known, "x@1.0")
+
+			index := buildReverseDependencyIndex(regular, dev, makeSCCs(t, tt.regular), known, func(moduleID) bool { return true })
+			if _, ok := index["x@1.0"]; ok {
+				t.Errorf("unknown vertex should not be indexed")
+			}
+
+			rd := index[tt.id]
+			for _, check := range []struct {
+				what string
+				got  map[moduleID]bool
+				want []moduleID
+			}{
+				{"direct", rd.direct, tt.wantDirect},
+				{"direct dev", rd.directDev, tt.wantDirectDev},
+				{"transitive", rd.transitive, tt.wantTransitive},
+				{"transitive dev", rd.transitiveDev, tt.wantTransitiveDev},
+			} {
+				got := slices.SortedFunc(maps.Keys(check.got), compareModuleIDs)
+				if !slices.Equal(got, check.want) {
+					t.Errorf("%s = %v, want %v", check.what, got, check.want)
+				}
+			}
+			if int(rd.counts.Transitive) != len(tt.wantTransitive) || int(rd.counts.TransitiveDev) != len(tt.wantTransitiveDev) {
+				t.Errorf("transitive counts = %d/%d, want %d/%d", rd.counts.Transitive, rd.counts.TransitiveDev, len(tt.wantTransitive), len(tt.wantTransitiveDev))
+			}
+		})
+	}
+}
+
+func TestReverseDependencyIndexModuleCounts(t *testing.T) {
+	edges := map[moduleID][]moduleID{
+		"a@1.0": {"c@1.0"},
+		"a@2.0": {"c@2.0"},
+		"b@1.0": {"c@2.0"},
+		"c@2.0": {"c@1.0"},
+	}
+	known := make(map[moduleID]bool)
+	regular := makePredecessorMap(edges, known)
+	dev := makePredecessorMap(map[moduleID][]moduleID{
+		"d@1.0": {"c@1.0"},
+		"b@1.0": {"c@1.0"},
+	}, known)
+	latest := map[moduleID]bool{"a@2.0": true, "b@1.0": true, "c@2.0": true, "d@1.0": true}
+
+	isLatest := func(id moduleID) bool { return latest[id] }
+
+	index := buildReverseDependencyIndex(regular, dev, makeSCCs(t, edges), known, isLatest)
+
+	// all the transitive dependents are listed, and the latest ones apart
+	rd := index["c@1.0"]
+	if rd.counts.Transitive != 4 || rd.counts.TransitiveLatest != 3 {
+		t.Errorf("transitive counts of c@1.0 = %d/%d, want 4/3", rd.counts.Transitive, rd.counts.TransitiveLatest)
+	}
+	var dependents *bzpb.ModuleVersionDependents
+	for _, mv := range index.toProto(isLatest).ModuleVersions {
+		if mv.Name == "c" && mv.Version == "1.0" {
+			dependents = mv
+		}
+	}
+	if want := []string{"a@1.0", "a@2.0", "b@1.0", "c@2.0"}; !slices.Equal(dependents.GetTransitive(), want) {
+		t.Errorf("transitive of c@1.0 = %v, want %v", dependents.GetTransitive(), want)
+	}
+	if want := []string{"a@2.0", "b@1.0", "c@2.0"}; !slices.Equal(dependents.GetTransitiveLatest(), want) {
+		t.Errorf("transitive latest of c@1.0 = %v, want %v", dependents.GetTransitiveLatest(), want)
+	}
+	if want := []string{"d@1.0"}; !slices.Equal(dependents.GetTransitiveDev(), want) {
+		t.Errorf("transitive dev of c@1.0 = %v, want %v", dependents.GetTransitiveDev(), want)
+	}
+
+	got := index.moduleCounts(isLatest)
+
+	want := &bzpb.ReverseDependencyCounts{
+		Direct:              3,
+		DirectDev:           1,
+		Transitive:          3,
+		TransitiveDev:       1,
+		DirectLatest:        2,
+		DirectDevLatest:     1,
+		TransitiveLatest:    2,
+		TransitiveDevLatest: 1,
+	}
+	if !proto.Equal(got["c"], want) {
+		t.Errorf("counts for c = %v, want %v", got["c"], want)
+	}
+
+	dict := reverseDependencyCountsToStringDict(got["c"])
+	if dict["direct"] != "3" || dict["transitive_dev_latest"] != "1" || len(dict) != 8 {
+		t.Errorf("unexpected string dict: %v", dict)
+	}
+}
+
+// makeSCCs returns the strongly connected components of the graph of the
+// given from -> to edge list.
+func makeSCCs(t *testing.T, edges map[moduleID][]moduleID) [][]moduleID {
+	g := initDepGraph()
+	for from, targets := range edges {
+		_ = g.AddVertex(from)
+		for _, to := range targets {
+			_ = g.AddVertex(to)
+			_ = g.AddEdge(from, to)
+		}
+	}
+	sccs, err := graph.StronglyConnectedComponents(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sccs
+}
+
+// makePredecessorMap builds a predecessor map from a from -> to edge list,
+// recording every vertex in known.
+func makePredecessorMap(edges map[moduleID][]moduleID, known map[moduleID]bool) map[moduleID]map[moduleID]graph.Edge[moduleID] {
+	predecessors := make(map[moduleID]map[moduleID]graph.Edge[moduleID])
+	for from, targets := range edges {
+		known[from] = true
+		for _, to := range targets {
+			known[to] = true
+			if predecessors[to] == nil {
+				predecessors[to] = make(map[moduleID]graph.Edge[moduleID])
+			}
+			predecessors[to][from] = graph.Edge[moduleID]{Source: from, Target: to}
+		}
+	}
+	return predecessors
+}
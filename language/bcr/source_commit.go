@@ -0,0 +1,293 @@
+package bcr
+
+import (
+	"context"
+	"log"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/cachestore"
+	"github.com/bazel-contrib/bcr-frontend/pkg/gh"
+	"github.com/bazel-contrib/bcr-frontend/pkg/gitea"
+	"github.com/bazel-contrib/bcr-frontend/pkg/gl"
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/sync/errgroup"
+)
+
+// sourceMirrorPrefixes are the url prefixes of the mirrors that keep the host
+// and path of the original url, e.g.
+// https://mirror.bazel.build/github.com/org/repo/archive/v1.0.tar.gz.
+var sourceMirrorPrefixes = []string{
+	"https://mirror.bazel.build/",
+}
+
+// commitSHAPattern matches a full commit SHA
+var commitSHAPattern = regexp.MustCompile(`^[a-f0-9]{40}$`)
+
+// sourceCommitRef is a reference to a commit of a forge repository, parsed
+// from a source archive url.  Exactly one of the fields is set.
+type sourceCommitRef struct {
+	github *gh.SourceURLInfo
+	gitlab *gl.SourceURLInfo
+	gitea  *gitea.SourceURLInfo
+}
+
+// commitSHA returns the commit SHA if the url already names it, or the empty
+// string if the reference needs to be resolved.
+func (r *sourceCommitRef) commitSHA() string {
+	var ref string
+	switch {
+	case r.github != nil:
+		if r.github.Type != gh.URLTypeCommitSHA {
+			return ""
+		}
+		ref = r.github.Reference
+	case r.gitlab != nil:
+		ref = r.gitlab.Reference
+	case r.gitea != nil:
+		ref = r.gitea.Reference
+	}
+	if commitSHAPattern.MatchString(ref) {
+		return ref
+	}
+	return ""
+}
+
+// unmirrorSourceURL returns the original url of a mirror url, or the url
+// itself.
+func unmirrorSourceURL(url string) string {
+	for _, prefix := range sourceMirrorPrefixes {
+		if rest, ok := strings.CutPrefix(url, prefix); ok {
+			return "https://" + rest
+		}
+	}
+	return url
+}
+
+// parseSourceCommitRef parses a source archive url hosted by GitHub (including
+// codeload.github.com), GitLab or a Gitea instance.
+func parseSourceCommitRef(url string) (*sourceCommitRef, bool) {
+	url = unmirrorSourceURL(url)
+	if parsed, err := gh.ParseGitHubSourceURL(url); err == nil {
+		return &sourceCommitRef{github: parsed}, true
+	}
+	if parsed, err := gl.ParseGitLabSourceURL(url); err == nil {
+		return &sourceCommitRef{gitlab: parsed}, true
+	}
+	if parsed, err := gitea.ParseGiteaSourceURL(url); err == nil && isGiteaHost(parsed.Host) {
+		return &sourceCommitRef{gitea: parsed}, true
+	}
+	return nil, false
+}
+
+// sourceCommitRefs returns the references parsed from the source url and its
+// mirror urls, in that order.
+func sourceCommitRefs(source *bzpb.ModuleSource) (refs []*sourceCommitRef) {
+	for _, url := range append([]string{source.Url}, source.MirrorUrls...) {
+		if ref, ok := parseSourceCommitRef(url); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return
+}
+
+// resolveSourceCommitSHAs populates the commit_sha of every module source.
+// In order, the commit SHA is taken from the commit of a git_repository
+// source, the cache, the backup registry, or a commit SHA in the source (or
+// mirror) url.  Otherwise the tag or release of the url is resolved with the
+// API of its forge, and the result is added to the cache.
+func (ext *bcrExtension) resolveSourceCommitSHAs() {
+	ctx := context.Background()
+
+	urlToModuleID := make(map[string][]moduleID)
+	refs := make(map[string]*sourceCommitRef)
+	fromSource := 0
+	fromCache := 0
+	fromBackup := 0
+
+	for _, id := range slices.Sorted(maps.Keys(ext.moduleSourceRules)) {
+		sourceRule := ext.moduleSourceRules[id]
+		source := sourceRule.Proto()
+		if source == nil || source.CommitSha != "" {
+			continue
+		}
+
+		// git_repository sources name the commit, and have no archive url
+		if source.Type == "git_repository" {
+			if source.Commit != "" {
+				updateModuleSourceRuleSourceCommitSha(sourceRule, source.Commit)
+				fromSource++
+			}
+			continue
+		}
+		if source.Url == "" {
+			continue
+		}
+
+		if entry := ext.cache.Get(bzpb.CacheEntryType_CACHE_ENTRY_SOURCE_COMMIT, source.Url); entry != nil {
+			updateModuleSourceRuleSourceCommitSha(sourceRule, entry.GetCommitSha())
+			fromCache++
+			continue
+		}
+
+		if backupSource := ext.getBackupModuleSource(string(id.name()), string(id.version())); backupSource != nil && backupSource.CommitSha != "" {
+			updateModuleSourceRuleSourceCommitSha(sourceRule, backupSource.CommitSha)
+			fromBackup++
+			continue
+		}
+
+		candidates := sourceCommitRefs(source)
+		if len(candidates) == 0 {
+			// Not hosted by a known forge - skip silently
+			continue
+		}
+		if sha := firstSourceCommitSHA(candidates); sha != "" {
+			updateModuleSourceRuleSourceCommitSha(sourceRule, sha)
+			fromSource++
+			continue
+		}
+
+		// Modules may opt out of network access
+		if ext.skipsNetworkLookup(bzpb.CacheEntryType_CACHE_ENTRY_SOURCE_COMMIT, source.Url, []moduleID{id}) {
+			if ext.offline {
+				ext.cacheMisses.add(bzpb.CacheMissKind_CACHE_MISS_SOURCE_COMMIT_SHA, source.Url, id)
+			}
+			continue
+		}
+
+		urlToModuleID[source.Url] = append(urlToModuleID[source.Url], id)
+		refs[source.Url] = candidates[0]
+	}
+
+	log.Printf("Commit SHAs: %d from the sources, %d from cache, %d from backup registry", fromSource, fromCache, fromBackup)
+
+	if len(refs) == 0 {
+		log.Printf("No source URLs need commit SHA resolution")
+		return
+	}
+
+	log.Printf("Resolving commit SHAs for %d unique source URLs...", len(refs))
+
+	bar := progressbar.NewOptions(len(refs),
+		progressbar.OptionSetDescription("Resolving commit SHAs"),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetWidth(40),
+		progressbar.OptionSetTheme(progressbar.Theme{
+			Saucer:        "=",
+			SaucerHead:    ">",
+			SaucerPadding: " ",
+			BarStart:      "[",
+			BarEnd:        "]",
+		}),
+	)
+
+	successCount := 0
+	errorCount := 0
+	var mu sync.Mutex
+
+	// setCommitSHA records the result of resolving the url, the caller
+	// holds mu
+	setCommitSHA := func(url, sha string, err error) {
+		bar.Add(1)
+		if err != nil {
+			log.Printf("warning: failed to resolve commit SHA for %s: %v", url, err)
+			errorCount++
+			return
+		}
+		if sha == "" {
+			log.Printf("warning: empty commit SHA for %s", url)
+			errorCount++
+			return
+		}
+		for _, id := range urlToModuleID[url] {
+			updateModuleSourceRuleSourceCommitSha(ext.moduleSourceRules[id], sha)
+			successCount++
+		}
+		ext.cache.Put(cachestore.SourceCommitEntry(url, sha))
+	}
+
+	// GitHub urls are resolved in one rate limited batch, the other forges
+	// one url at a time
+	var githubURLs []struct {
+		URL  string
+		Org  string
+		Repo string
+		Type string
+		Ref  string
+	}
+	var forgeURLs []string
+	for _, url := range slices.Sorted(maps.Keys(refs)) {
+		ref := refs[url]
+		if ref.github == nil {
+			forgeURLs = append(forgeURLs, url)
+			continue
+		}
+		githubURLs = append(githubURLs, struct {
+			URL  string
+			Org  string
+			Repo string
+			Type string
+			Ref  string
+		}{
+			URL:  url,
+			Org:  ref.github.Organization,
+			Repo: ref.github.Repository,
+			Type: ref.github.Type.String(),
+			Ref:  ref.github.Reference,
+		})
+	}
+
+	if len(githubURLs) > 0 && ext.githubClient == nil {
+		log.Printf("No GitHub client available, skipping commit SHA resolution of %d GitHub source URLs", len(githubURLs))
+	} else if len(githubURLs) > 0 {
+		_, err := gh.BatchResolveSourceCommits(ctx, ext.githubClient, githubURLs, func(result *gh.SourceCommitInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			setCommitSHA(result.URL, result.CommitSHA, result.Error)
+		})
+		if err != nil {
+			log.Printf("error: failed to resolve GitHub source commit SHAs: %v", err)
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentForgeRequests)
+	for _, url := range forgeURLs {
+		ref := refs[url]
+		g.Go(func() error {
+			sha, err := ext.resolveForgeCommitSHA(gctx, ref)
+
+			mu.Lock()
+			defer mu.Unlock()
+			setCommitSHA(url, sha, err)
+			return nil
+		})
+	}
+	g.Wait()
+
+	log.Printf("Commit SHA resolution complete: %d from the sources, %d from cache, %d from backup registry, %d resolved (%d errors)",
+		fromSource, fromCache, fromBackup, successCount, errorCount)
+}
+
+// firstSourceCommitSHA returns the first commit SHA named by the references.
+func firstSourceCommitSHA(refs []*sourceCommitRef) string {
+	for _, ref := range refs {
+		if sha := ref.commitSHA(); sha != "" {
+			return sha
+		}
+	}
+	return ""
+}
+
+// resolveForgeCommitSHA resolves a GitLab or Gitea reference to a commit SHA.
+func (ext *bcrExtension) resolveForgeCommitSHA(ctx context.Context, ref *sourceCommitRef) (string, error) {
+	if ref.gitlab != nil {
+		host := normalizeGitlabHost(ref.gitlab.Host)
+		return gl.GetCommitSHA(ctx, gl.BaseURL(host), ext.gitlabTokenForHost(host), ref.gitlab.Project, ref.gitlab.Reference)
+	}
+	return gitea.GetCommitSHA(ctx, gitea.BaseURL(ref.gitea.Host), "", ref.gitea.Owner, ref.gitea.Repository, ref.gitea.Reference)
+}
@@ -0,0 +1,112 @@
+package bcr
+
+import (
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/cachestore"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+)
+
+const (
+	testCommitSHA  = "0123456789abcdef0123456789abcdef01234567"
+	otherCommitSHA = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+func TestParseSourceCommitRef(t *testing.T) {
+	for _, tc := range []struct {
+		url    string
+		forge  string
+		ref    string
+		commit string
+	}{
+		{url: "https://github.com/org/repo/archive/refs/tags/v1.0.0.tar.gz", forge: "github", ref: "v1.0.0"},
+		{url: "https://codeload.github.com/org/repo/tar.gz/" + testCommitSHA, forge: "github", ref: testCommitSHA, commit: testCommitSHA},
+		{url: "https://mirror.bazel.build/github.com/org/repo/archive/" + testCommitSHA + ".tar.gz", forge: "github", ref: testCommitSHA, commit: testCommitSHA},
+		{url: "https://gitlab.com/group/sub/repo/-/archive/v1.0.0/repo-v1.0.0.tar.gz", forge: "gitlab", ref: "v1.0.0"},
+		{url: "https://gitlab.com/group/repo/-/archive/" + testCommitSHA + "/repo.tar.gz", forge: "gitlab", ref: testCommitSHA, commit: testCommitSHA},
+		{url: "https://codeberg.org/owner/repo/archive/v1.0.0.tar.gz", forge: "gitea", ref: "v1.0.0"},
+		{url: "https://example.com/owner/repo/archive/v1.0.0.tar.gz"},
+		{url: "https://example.com/repo-1.0.0.tar.gz"},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			ref, ok := parseSourceCommitRef(tc.url)
+			if !ok {
+				if tc.forge != "" {
+					t.Fatalf("expected a %s reference", tc.forge)
+				}
+				return
+			}
+			var forge, reference string
+			switch {
+			case ref.github != nil:
+				forge, reference = "github", ref.github.Reference
+			case ref.gitlab != nil:
+				forge, reference = "gitlab", ref.gitlab.Reference
+			case ref.gitea != nil:
+				forge, reference = "gitea", ref.gitea.Reference
+			}
+			if forge != tc.forge || reference != tc.ref {
+				t.Errorf("got %s reference %q, want %s reference %q", forge, reference, tc.forge, tc.ref)
+			}
+			if got := ref.commitSHA(); got != tc.commit {
+				t.Errorf("commitSHA() = %q, want %q", got, tc.commit)
+			}
+		})
+	}
+}
+
+func TestResolveSourceCommitSHAs(t *testing.T) {
+	ext := NewLanguage().(*bcrExtension)
+	ext.offline = true
+	ext.cache = cachestore.New(nil)
+	ext.cache.Put(cachestore.SourceCommitEntry("https://github.com/org/cached/archive/refs/tags/v1.0.0.tar.gz", otherCommitSHA))
+
+	sources := map[moduleID]*bzpb.ModuleSource{
+		"git@1.0.0": {Type: "git_repository", Remote: "https://github.com/org/git.git", Commit: testCommitSHA},
+		"cached@1.0.0": {
+			Url: "https://github.com/org/cached/archive/refs/tags/v1.0.0.tar.gz",
+		},
+		"mirrored@1.0.0": {
+			Url:        "https://example.com/mirrored-1.0.0.tar.gz",
+			MirrorUrls: []string{"https://mirror.bazel.build/github.com/org/mirrored/archive/" + testCommitSHA + ".tar.gz"},
+		},
+		"tagged@1.0.0": {
+			Url: "https://gitlab.com/org/tagged/-/archive/v1.0.0/tagged-v1.0.0.tar.gz",
+		},
+		"resolved@1.0.0": {
+			Url:       "https://github.com/org/resolved/archive/refs/tags/v1.0.0.tar.gz",
+			CommitSha: otherCommitSHA,
+		},
+		"unknown@1.0.0": {
+			Url: "https://example.com/unknown-1.0.0.tar.gz",
+		},
+	}
+	for id, source := range sources {
+		ext.moduleSourceRules[id] = newProtoRule(rule.NewRule(moduleSourceKind, "source"), source)
+	}
+
+	ext.resolveSourceCommitSHAs()
+
+	for id, want := range map[moduleID]string{
+		"git@1.0.0":      testCommitSHA,
+		"cached@1.0.0":   otherCommitSHA,
+		"mirrored@1.0.0": testCommitSHA,
+		"tagged@1.0.0":   "",
+		"resolved@1.0.0": otherCommitSHA,
+		"unknown@1.0.0":  "",
+	} {
+		source := ext.moduleSourceRules[id]
+		if got := source.Proto().CommitSha; got != want {
+			t.Errorf("%s: commit_sha = %q, want %q", id, got, want)
+		}
+		if want != "" && id != "resolved@1.0.0" && source.Rule().AttrString("commit_sha") != want {
+			t.Errorf("%s: expected the commit_sha attribute to be set", id)
+		}
+	}
+
+	// the tag cannot be resolved offline
+	if len(ext.cacheMisses) != 1 || ext.cacheMisses[0].Key != sources["tagged@1.0.0"].Url {
+		t.Errorf("unexpected cache misses: %v", ext.cacheMisses)
+	}
+}
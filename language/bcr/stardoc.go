@@ -12,8 +12,10 @@ import (
 	"strings"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/cachestore"
 	"github.com/bazel-contrib/bcr-frontend/pkg/modulebazel"
 	"github.com/bazel-contrib/bcr-frontend/pkg/netutil"
+	"github.com/bazel-contrib/bcr-frontend/pkg/versionutil"
 	"github.com/bazelbuild/bazel-gazelle/label"
 	"github.com/bazelbuild/bazel-gazelle/rule"
 	"github.com/bazelbuild/buildtools/build"
@@ -72,11 +74,13 @@ func (ext *bcrExtension) trackSourceUrl(url string, id moduleID) {
 
 // handleDocsUrlStatus processes a docs URL status and updates the repos map and rules
 func (ext *bcrExtension) handleDocsUrlStatus(url string, moduleIDs []moduleID, status netutil.URLStatus, repos map[label.Label]*rule.Rule, cached bool) {
-	// Store status in the map for future caching
-	ext.resourceStatusByUrl[url] = &bzpb.ResourceStatus{
-		Url:     url,
-		Code:    int32(status.Code),
-		Message: status.Message,
+	// Cache the status of urls that were checked
+	if !cached {
+		ext.cache.Put(cachestore.ResourceStatusEntry(&bzpb.ResourceStatus{
+			Url:     url,
+			Code:    int32(status.Code),
+			Message: status.Message,
+		}))
 	}
 
 	if status.Exists() {
@@ -116,6 +120,8 @@ func (ext *bcrExtension) prepareBinaryprotoRepositories() []*rule.Rule {
 	var uncachedItems []checkItem
 	var cachedCount int
 	var blacklistedCount int
+	var maxVersionsCount int
+	var skipNetworkCount int
 
 	for url, moduleIDs := range ext.moduleIDsByDocUrl {
 		if ext.blacklistedUrls[url] {
@@ -125,7 +131,14 @@ func (ext *bcrExtension) prepareBinaryprotoRepositories() []*rule.Rule {
 			continue
 		}
 
-		if cachedStatus, found := ext.resourceStatusByUrl[url]; found {
+		// Skip versions beyond the bcr_docs_max_versions limit of their module
+		moduleIDs = ext.filterDocsVersions(moduleIDs)
+		if len(moduleIDs) == 0 {
+			maxVersionsCount++
+			continue
+		}
+
+		if cachedStatus, found := ext.cachedResourceStatus(url); found {
 			// Use cached status
 			cachedCount++
 			status := netutil.URLStatus{
@@ -133,6 +146,11 @@ func (ext *bcrExtension) prepareBinaryprotoRepositories() []*rule.Rule {
 				Message: cachedStatus.Message,
 			}
 			ext.handleDocsUrlStatus(url, moduleIDs, status, repos, true)
+		} else if ext.skipsNetworkLookup(bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, url, moduleIDs) {
+			skipNetworkCount++
+			if ext.offline {
+				ext.cacheMisses.add(bzpb.CacheMissKind_CACHE_MISS_DOCS_URL_STATUS, url, moduleIDs...)
+			}
 		} else {
 			// Need to check this URL
 			uncachedItems = append(uncachedItems, checkItem{url, moduleIDs})
@@ -145,6 +163,12 @@ func (ext *bcrExtension) prepareBinaryprotoRepositories() []*rule.Rule {
 	if blacklistedCount > 0 {
 		log.Printf("Skipped %d blacklisted docs URLs", blacklistedCount)
 	}
+	if maxVersionsCount > 0 {
+		log.Printf("Skipped %d docs URLs (beyond %s)", maxVersionsCount, docsMaxVersionsDirective)
+	}
+	if skipNetworkCount > 0 {
+		log.Printf("Skipped %d uncached docs URL checks (%s)", skipNetworkCount, skipNetworkDirective)
+	}
 
 	// Check uncached URLs in parallel and update rules with status
 	if len(uncachedItems) > 0 {
@@ -160,11 +184,13 @@ func (ext *bcrExtension) prepareBinaryprotoRepositories() []*rule.Rule {
 // handleSourceUrlStatus processes a source URL status and updates the repos map
 // and rules
 func (ext *bcrExtension) handleSourceUrlStatus(url string, moduleIDs []moduleID, status netutil.URLStatus, versions rankedModuleVersionMap, cached bool) {
-	// Store status in the map for future caching
-	ext.resourceStatusByUrl[url] = &bzpb.ResourceStatus{
-		Url:     url,
-		Code:    int32(status.Code),
-		Message: status.Message,
+	// Cache the status of urls that were checked
+	if !cached {
+		ext.cache.Put(cachestore.ResourceStatusEntry(&bzpb.ResourceStatus{
+			Url:     url,
+			Code:    int32(status.Code),
+			Message: status.Message,
+		}))
 	}
 
 	var moduleSourceProtoRule *protoRule[*bzpb.ModuleSource]
@@ -205,6 +231,7 @@ func (ext *bcrExtension) prepareBzlRepositories() rankedModuleVersionMap {
 	var unrequestedCount int
 	var blacklistedCount int
 	var bzlSrcsFilteredCount int
+	var skipNetworkCount int
 
 	for url, moduleIDs := range ext.moduleIDsBySourceUrl {
 		if ext.blacklistedUrls[url] {
@@ -215,7 +242,7 @@ func (ext *bcrExtension) prepareBzlRepositories() rankedModuleVersionMap {
 		}
 
 		// Priority 1: Check local cache
-		if cachedStatus, found := ext.resourceStatusByUrl[url]; found {
+		if cachedStatus, found := ext.cachedResourceStatus(url); found {
 			cachedCount++
 			status := netutil.URLStatus{
 				Code:    int(cachedStatus.Code),
@@ -236,6 +263,15 @@ func (ext *bcrExtension) prepareBzlRepositories() rankedModuleVersionMap {
 			continue
 		}
 
+		// Modules may opt out of network access
+		if ext.skipsNetworkLookup(bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, url, moduleIDs) {
+			skipNetworkCount++
+			if ext.offline {
+				ext.cacheMisses.add(bzpb.CacheMissKind_CACHE_MISS_SOURCE_URL_STATUS, url, moduleIDs...)
+			}
+			continue
+		}
+
 		// Priority 3: Need to check this URL via HTTP
 		uncachedItems = append(uncachedItems, checkItem{url, moduleIDs})
 	}
@@ -255,6 +291,9 @@ func (ext *bcrExtension) prepareBzlRepositories() rankedModuleVersionMap {
 	if bzlSrcsFilteredCount > 0 {
 		log.Printf("Skipped %d source URLs (not referenced in any bzl_src)", bzlSrcsFilteredCount)
 	}
+	if skipNetworkCount > 0 {
+		log.Printf("Skipped %d uncached source URL checks (%s)", skipNetworkCount, skipNetworkDirective)
+	}
 
 	// Check uncached URLs in parallel and update rules with status
 	if len(uncachedItems) > 0 {
@@ -620,7 +659,9 @@ func getSha256Integrity(integrity string) (string, bool) {
 //   - 1.8.2 (rank=18) ← merged 1.8.1 and 1.8.0
 //   - 1.7.1 (rank=2)
 //
-// The sortedVersions list should be the sorted versions from moduleMetadata.Versions
+// The sortedVersions list should be the versions from moduleMetadata.Versions;
+// it is re-sorted by version precedence so the order in metadata.json does not
+// matter.
 func narrowSelectedVersionsByPatchLevel(sortedVersions []moduleVersion, versions []*rankedVersion) []*rankedVersion {
 	if len(versions) == 0 {
 		return versions
@@ -632,6 +673,9 @@ func narrowSelectedVersionsByPatchLevel(sortedVersions []moduleVersion, versions
 		versionMap[v.version] = v
 	}
 
+	sortedVersions = slices.Clone(sortedVersions)
+	slices.SortStableFunc(sortedVersions, compareVersions)
+
 	// Group versions by major.minor prefix
 	// Key is major.minor (e.g., "1.8"), value is list of full versions
 	groups := make(map[string][]moduleVersion)
@@ -641,8 +685,7 @@ func narrowSelectedVersionsByPatchLevel(sortedVersions []moduleVersion, versions
 			continue
 		}
 
-		// Extract major.minor by taking everything before the last dot
-		// This handles versions like "1.8.2", "1.8.2-rc1", etc.
+		// This handles versions like "1.8.2", "1.8.2-rc1", "1.8.2.bcr.1" etc.
 		majorMinor := extractMajorMinor(string(version))
 		groups[majorMinor] = append(groups[majorMinor], version)
 	}
@@ -654,7 +697,7 @@ func narrowSelectedVersionsByPatchLevel(sortedVersions []moduleVersion, versions
 			continue
 		}
 
-		// The versions are already sorted (from sortedVersions), so the last one is highest
+		// The versions are sorted by precedence, so the last one is highest
 		// within this group (since we iterated in order)
 		highestVersion := groupVersions[len(groupVersions)-1]
 		highest := versionMap[highestVersion]
@@ -698,6 +741,11 @@ func narrowSelectedVersionsByPatchLevel(sortedVersions []moduleVersion, versions
 		narrowed = append(narrowed, merged)
 	}
 
+	// keep output deterministic (and ascending, as selectVersion expects)
+	slices.SortFunc(narrowed, func(a, b *rankedVersion) int {
+		return compareVersions(a.version, b.version)
+	})
+
 	return narrowed
 }
 
@@ -705,24 +753,25 @@ func narrowSelectedVersionsByPatchLevel(sortedVersions []moduleVersion, versions
 // Examples:
 //   - "1.8.2" -> "1.8"
 //   - "1.8.2-rc1" -> "1.8"
+//   - "1.8.2.bcr.1" -> "1.8"
 //   - "2.0.0" -> "2.0"
+//   - "20230802.0" -> "20230802.0"
 func extractMajorMinor(version string) string {
-	// Find the last dot to separate patch version
-	lastDot := strings.LastIndex(version, ".")
-	if lastDot == -1 {
-		// No dots, use the whole version
+	v, err := versionutil.Parse(version)
+	if err != nil || v.IsEmpty() {
+		// Not a valid version, use the whole string
 		return version
 	}
 
-	// Take everything before the last dot, but stop at any non-numeric character after that
-	majorMinor := version[:lastDot]
-
-	// Handle pre-release suffixes like "1.8.2-rc1" - find the first dash/hyphen
-	if dashIdx := strings.Index(majorMinor, "-"); dashIdx != -1 {
-		majorMinor = majorMinor[:dashIdx]
+	release := v.Release
+	if len(release) > 2 {
+		release = release[:2]
 	}
-
-	return majorMinor
+	parts := make([]string, len(release))
+	for i, id := range release {
+		parts[i] = id.Value
+	}
+	return strings.Join(parts, ".")
 }
 
 // selectVersion votes for a version and returns the actual version selected.
@@ -753,7 +802,9 @@ func selectVersion(rule *protoRule[*bzpb.ModuleVersion], version moduleVersion,
 	}
 
 	// Fallback to highest available version
-	fallback := available[len(available)-1]
+	fallback := slices.MaxFunc(available, func(a, b *rankedVersion) int {
+		return compareVersions(a.version, b.version)
+	})
 	if debugBzlRepositoryResolution {
 		log.Printf("WARNING: %s not available, falling back to %s", newModuleID(rule.Proto().Name, string(version)), newModuleID(rule.Proto().Name, string(fallback.version)))
 	}
@@ -1 +1,46 @@
 package bcr
+
+import "testing"
+
+func TestExtractMajorMinor(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.8.2", "1.8"},
+		{"1.8.2-rc1", "1.8"},
+		{"1.8.2.bcr.1", "1.8"},
+		{"2.0.0", "2.0"},
+		{"20230802.0", "20230802.0"},
+		{"17", "17"},
+		{"0.0.0-20220923-a547704", "0.0"},
+	}
+
+	for _, tt := range tests {
+		if got := extractMajorMinor(tt.version); got != tt.want {
+			t.Errorf("extractMajorMinor(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestNarrowSelectedVersionsByPatchLevel(t *testing.T) {
+	// metadata.json order is not guaranteed to be sorted
+	sortedVersions := []moduleVersion{"1.8.10", "1.7.1", "1.8.2", "1.8.9"}
+	versions := []*rankedVersion{
+		{version: "1.8.2", rank: 3},
+		{version: "1.8.10", rank: 10},
+		{version: "1.8.9", rank: 5},
+		{version: "1.7.1", rank: 2},
+	}
+
+	got := narrowSelectedVersionsByPatchLevel(sortedVersions, versions)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(got))
+	}
+	if got[0].version != "1.7.1" || got[0].rank != 2 {
+		t.Errorf("got[0] = %s (rank=%d), want 1.7.1 (rank=2)", got[0].version, got[0].rank)
+	}
+	if got[1].version != "1.8.10" || got[1].rank != 18 {
+		t.Errorf("got[1] = %s (rank=%d), want 1.8.10 (rank=18)", got[1].version, got[1].rank)
+	}
+}
@@ -0,0 +1,175 @@
+// Package archive verifies source archives against the integrity and
+// strip_prefix of a source.json file.  Archives are read from a local
+// content-addressed cache, or downloaded (and added to the cache).
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
+)
+
+const downloadTimeout = 5 * time.Minute
+
+// Verifier verifies source archives.
+type Verifier struct {
+	// Cache is the optional archive cache.
+	Cache *Cache
+	// Download enables downloading archives that are not in the cache.
+	Download bool
+	// Client is the http client used for downloads (http.DefaultClient if
+	// nil).
+	Client *http.Client
+}
+
+// Verify checks the archive of the source: its digest must match the
+// integrity and it must contain the strip_prefix directory.  The url is
+// tried first, then the mirror urls.
+func (v *Verifier) Verify(ctx context.Context, source *bzpb.ModuleSource) *bzpb.ArchiveVerification {
+	want, err := sri.Parse(source.Integrity)
+	if err != nil {
+		return &bzpb.ArchiveVerification{
+			Status:  bzpb.ArchiveVerificationStatus_ARCHIVE_MALFORMED_INTEGRITY,
+			Message: err.Error(),
+		}
+	}
+
+	filename, downloaded, err := v.fetch(ctx, want, append([]string{source.Url}, source.MirrorUrls...))
+	if err != nil {
+		return &bzpb.ArchiveVerification{
+			Status:  bzpb.ArchiveVerificationStatus_ARCHIVE_UNAVAILABLE,
+			Message: err.Error(),
+		}
+	}
+	if downloaded {
+		// no-op if the file was moved into the cache
+		defer os.Remove(filename)
+	}
+
+	got, err := sri.ComputeFile(want.Algorithm, filename)
+	if err != nil {
+		return &bzpb.ArchiveVerification{
+			Status:  bzpb.ArchiveVerificationStatus_ARCHIVE_UNAVAILABLE,
+			Message: err.Error(),
+		}
+	}
+	if !got.Equal(want) {
+		return &bzpb.ArchiveVerification{
+			Status:          bzpb.ArchiveVerificationStatus_ARCHIVE_INTEGRITY_MISMATCH,
+			ActualIntegrity: got.String(),
+		}
+	}
+
+	// only verified archives may enter the cache
+	if downloaded && v.Cache != nil {
+		if cached, err := v.Cache.Put(want, filename); err == nil {
+			filename = cached
+		}
+	}
+
+	result := &bzpb.ArchiveVerification{Status: bzpb.ArchiveVerificationStatus_ARCHIVE_VERIFIED}
+	if source.StripPrefix == "" {
+		return result
+	}
+	entries, err := ListEntries(filename, TypeOf(source.ArchiveType, source.Url))
+	if errors.Is(err, ErrUnsupportedType) {
+		result.Message = fmt.Sprintf("strip_prefix not checked: %v", err)
+		return result
+	}
+	if err != nil {
+		return &bzpb.ArchiveVerification{
+			Status:  bzpb.ArchiveVerificationStatus_ARCHIVE_UNAVAILABLE,
+			Message: fmt.Sprintf("reading archive: %v", err),
+		}
+	}
+	if !HasPrefix(entries, source.StripPrefix) {
+		return &bzpb.ArchiveVerification{
+			Status:  bzpb.ArchiveVerificationStatus_ARCHIVE_STRIP_PREFIX_MISSING,
+			Message: fmt.Sprintf("the archive does not contain %s/", source.StripPrefix),
+		}
+	}
+	return result
+}
+
+// fetch returns the filename of the archive, from the cache or downloaded
+// from the first url that works.
+func (v *Verifier) fetch(ctx context.Context, integrity *sri.Integrity, urls []string) (filename string, downloaded bool, err error) {
+	if v.Cache != nil {
+		if filename, ok := v.Cache.Get(integrity); ok {
+			return filename, false, nil
+		}
+	}
+	if !v.Download {
+		return "", false, fmt.Errorf("%s is not in the archive cache (downloads are disabled)", integrity)
+	}
+
+	var errs []error
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		filename, err := v.download(ctx, url)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return filename, true, nil
+	}
+	return "", false, errors.Join(errs...)
+}
+
+// download writes the response body of the url to a temporary file.
+func (v *Verifier) download(ctx context.Context, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	// Add a User-Agent to avoid being blocked by some servers
+	req.Header.Set("User-Agent", "Bazel-Central-Registry-Gazelle/1.0")
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	dir := ""
+	if v.Cache != nil {
+		// same filesystem as the cache, so the file can be moved into it
+		dir = v.Cache.Dir
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", err
+		}
+	}
+	f, err := os.CreateTemp(dir, "archive-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("GET %s: %w", url, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
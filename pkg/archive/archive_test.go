@@ -0,0 +1,188 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
+)
+
+func makeTarGz(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func integrityOf(t *testing.T, data []byte) string {
+	t.Helper()
+	integrity, err := sri.Compute("sha256", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return integrity.String()
+}
+
+func TestVerify(t *testing.T) {
+	archive := makeTarGz(t, "./rules_foo-1.0.0/", "./rules_foo-1.0.0/MODULE.bazel")
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/rules_foo-1.0.0.tar.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	url := server.URL + "/rules_foo-1.0.0.tar.gz"
+	integrity := integrityOf(t, archive)
+
+	for name, tc := range map[string]struct {
+		source     *bzpb.ModuleSource
+		download   bool
+		wantStatus bzpb.ArchiveVerificationStatus
+	}{
+		"verified": {
+			source:     &bzpb.ModuleSource{Url: url, Integrity: integrity, StripPrefix: "rules_foo-1.0.0"},
+			download:   true,
+			wantStatus: bzpb.ArchiveVerificationStatus_ARCHIVE_VERIFIED,
+		},
+		"mirror": {
+			source:     &bzpb.ModuleSource{Url: server.URL + "/gone.tar.gz", MirrorUrls: []string{url}, Integrity: integrity},
+			download:   true,
+			wantStatus: bzpb.ArchiveVerificationStatus_ARCHIVE_VERIFIED,
+		},
+		"mismatch": {
+			source:     &bzpb.ModuleSource{Url: url, Integrity: "sha256-ShAT7rtQ9yj8YBvdgzsLKHAzPDs+WoFu66kh2VvsbxU="},
+			download:   true,
+			wantStatus: bzpb.ArchiveVerificationStatus_ARCHIVE_INTEGRITY_MISMATCH,
+		},
+		"strip prefix missing": {
+			source:     &bzpb.ModuleSource{Url: url, Integrity: integrity, StripPrefix: "rules_foo-2.0.0"},
+			download:   true,
+			wantStatus: bzpb.ArchiveVerificationStatus_ARCHIVE_STRIP_PREFIX_MISSING,
+		},
+		"malformed integrity": {
+			source:     &bzpb.ModuleSource{Url: url, Integrity: "sha256"},
+			download:   true,
+			wantStatus: bzpb.ArchiveVerificationStatus_ARCHIVE_MALFORMED_INTEGRITY,
+		},
+		"downloads disabled": {
+			source:     &bzpb.ModuleSource{Url: url, Integrity: integrity},
+			wantStatus: bzpb.ArchiveVerificationStatus_ARCHIVE_UNAVAILABLE,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v := &Verifier{Download: tc.download}
+			got := v.Verify(context.Background(), tc.source)
+			if got.Status != tc.wantStatus {
+				t.Errorf("Verify() = %v (%s), want %v", got.Status, got.Message, tc.wantStatus)
+			}
+		})
+	}
+
+	t.Run("cache", func(t *testing.T) {
+		cache := &Cache{Dir: t.TempDir()}
+		source := &bzpb.ModuleSource{Url: url, Integrity: integrity, StripPrefix: "rules_foo-1.0.0"}
+
+		v := &Verifier{Cache: cache, Download: true}
+		if got := v.Verify(context.Background(), source); got.Status != bzpb.ArchiveVerificationStatus_ARCHIVE_VERIFIED {
+			t.Fatalf("Verify() = %v (%s)", got.Status, got.Message)
+		}
+		want, _ := sri.Parse(integrity)
+		if _, ok := cache.Get(want); !ok {
+			t.Fatal("expected the verified archive to be cached")
+		}
+
+		// the cached archive is used without downloading
+		requests.Store(0)
+		v = &Verifier{Cache: cache}
+		if got := v.Verify(context.Background(), source); got.Status != bzpb.ArchiveVerificationStatus_ARCHIVE_VERIFIED {
+			t.Errorf("Verify() = %v (%s)", got.Status, got.Message)
+		}
+		if n := requests.Load(); n != 0 {
+			t.Errorf("expected no requests, got %d", n)
+		}
+
+		entries, err := os.ReadDir(cache.Dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Name() != "content_addressable" {
+			t.Errorf("expected only content_addressable in the cache dir, got %v", entries)
+		}
+	})
+}
+
+func TestListEntriesZip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "a.zip")
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"foo-1.0/", "foo-1.0/BUILD"} {
+		if _, err := zw.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	entries, err := ListEntries(filename, "zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"foo-1.0", "foo-1.0/BUILD"}; !slices.Equal(entries, want) {
+		t.Errorf("ListEntries() = %v, want %v", entries, want)
+	}
+	if !HasPrefix(entries, "foo-1.0/") || HasPrefix(entries, "foo") {
+		t.Error("unexpected HasPrefix() result")
+	}
+
+	if _, err := ListEntries(filename, "tar.zst"); err == nil {
+		t.Error("expected error for unsupported archive type")
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	for _, tc := range []struct {
+		archiveType, url, want string
+	}{
+		{"", "https://example.com/foo-1.0.tar.gz", "tar.gz"},
+		{"", "https://example.com/foo-1.0.zip?raw=true", "zip"},
+		{"zip", "https://example.com/download", "zip"},
+		{"", "https://example.com/download", ""},
+	} {
+		if got := TypeOf(tc.archiveType, tc.url); got != tc.want {
+			t.Errorf("TypeOf(%q, %q) = %q, want %q", tc.archiveType, tc.url, got, tc.want)
+		}
+	}
+}
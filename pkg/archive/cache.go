@@ -0,0 +1,43 @@
+package archive
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
+)
+
+// Cache is a content-addressed directory of archives.  It uses the layout of
+// the bazel repository cache (content_addressable/ALGORITHM/HEXDIGEST/file),
+// so bazel's --repository_cache directory can be used directly.
+type Cache struct {
+	Dir string
+}
+
+// Path returns the filename of the archive with the given integrity.
+func (c *Cache) Path(integrity *sri.Integrity) string {
+	return filepath.Join(c.Dir, "content_addressable", integrity.Algorithm, hex.EncodeToString(integrity.Digest), "file")
+}
+
+// Get returns the filename of the archive with the given integrity, if it is
+// in the cache.
+func (c *Cache) Get(integrity *sri.Integrity) (string, bool) {
+	filename := c.Path(integrity)
+	if _, err := os.Stat(filename); err != nil {
+		return "", false
+	}
+	return filename, true
+}
+
+// Put moves the (verified) file into the cache and returns its new filename.
+func (c *Cache) Put(integrity *sri.Integrity, filename string) (string, error) {
+	dst := c.Path(integrity)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(filename, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
@@ -0,0 +1,113 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// ErrUnsupportedType is returned by ListEntries for archive types that cannot
+// be read (e.g., tar.xz and tar.zst).
+var ErrUnsupportedType = errors.New("unsupported archive type")
+
+// TypeOf returns the archive type given in source.json, or else derives it
+// from the extension of the url (the same way bazel's http_archive does).
+func TypeOf(archiveType, url string) string {
+	if archiveType != "" {
+		return archiveType
+	}
+	url, _, _ = strings.Cut(url, "?")
+	for _, ext := range []string{"tar.gz", "tar.bz2", "tar.xz", "tar.zst", "tgz", "tbz", "txz", "tzst", "tar", "zip", "jar", "war", "aar", "srcjar"} {
+		if strings.HasSuffix(url, "."+ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// ListEntries returns the names of the entries of the archive, without any
+// leading "./" or trailing "/".
+func ListEntries(filename, archiveType string) ([]string, error) {
+	switch archiveType {
+	case "zip", "jar", "war", "aar", "srcjar":
+		return listZipEntries(filename)
+	case "tar", "tar.gz", "tgz", "tar.bz2", "tbz":
+		return listTarEntries(filename, archiveType)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedType, archiveType)
+}
+
+func listZipEntries(filename string) ([]string, error) {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	entries := make([]string, 0, len(r.File))
+	for _, f := range r.File {
+		entries = append(entries, cleanEntryName(f.Name))
+	}
+	return entries, nil
+}
+
+func listTarEntries(filename, archiveType string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	switch archiveType {
+	case "tar.gz", "tgz":
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	case "tar.bz2", "tbz":
+		r = bzip2.NewReader(f)
+	}
+
+	var entries []string
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, cleanEntryName(header.Name))
+	}
+	return entries, nil
+}
+
+func cleanEntryName(name string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(path.Clean("/"+name), "/"), "/")
+}
+
+// HasPrefix reports whether the entries contain the directory prefix (as
+// used by strip_prefix).
+func HasPrefix(entries []string, prefix string) bool {
+	prefix = cleanEntryName(prefix)
+	if prefix == "" {
+		return true
+	}
+	for _, entry := range entries {
+		if entry == prefix || strings.HasPrefix(entry, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
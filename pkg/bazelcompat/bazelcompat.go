@@ -0,0 +1,142 @@
+package bazelcompat
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// constraintPattern mirrors the grammar of the bazel_compatibility attribute
+// of the module() directive: an operator followed by a MAJOR.MINOR.PATCH
+// version. See
+// https://bazel.build/rules/lib/globals/module#module
+var constraintPattern = regexp.MustCompile(`^(>=|<=|>|<|-)(\d+)\.(\d+)\.(\d+)$`)
+
+// releasePattern matches a Bazel release version.  Anything after
+// MAJOR.MINOR.PATCH (e.g. "rc1", "-pre.20240101.1") is ignored when
+// evaluating constraints, like Bazel itself does.
+var releasePattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)(.*)$`)
+
+// BazelVersion is the numeric part of a Bazel release version.
+type BazelVersion [3]int
+
+// Compare returns -1, 0 or 1 if v is lower, equal or higher than other.
+func (v BazelVersion) Compare(other BazelVersion) int {
+	for i := range v {
+		if v[i] < other[i] {
+			return -1
+		}
+		if v[i] > other[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// String formats the version as MAJOR.MINOR.PATCH.
+func (v BazelVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// Constraint is a single parsed bazel_compatibility entry such as ">=7.0.0"
+// or "-7.1.0".
+type Constraint struct {
+	// Op is one of ">=", "<=", ">", "<" or "-" (exclude)
+	Op string
+	// Version is the version the operator applies to
+	Version BazelVersion
+}
+
+// String formats the constraint as it appears in MODULE.bazel.
+func (c Constraint) String() string {
+	return c.Op + c.Version.String()
+}
+
+// ParseConstraint parses a bazel_compatibility entry.
+func ParseConstraint(s string) (Constraint, error) {
+	m := constraintPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Constraint{}, fmt.Errorf("invalid bazel_compatibility constraint %q", s)
+	}
+	version, err := parseNumbers(m[2:5])
+	if err != nil {
+		return Constraint{}, fmt.Errorf("invalid bazel_compatibility constraint %q: %w", s, err)
+	}
+	return Constraint{Op: m[1], Version: version}, nil
+}
+
+// ParseConstraints parses a list of bazel_compatibility entries.  Invalid
+// entries are skipped and reported in the returned error (the valid ones are
+// still returned).
+func ParseConstraints(list []string) ([]Constraint, error) {
+	var constraints []Constraint
+	var firstErr error
+	for _, s := range list {
+		c, err := ParseConstraint(s)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints, firstErr
+}
+
+// ParseRelease parses the numeric part of a Bazel release version.
+func ParseRelease(s string) (BazelVersion, error) {
+	m := releasePattern.FindStringSubmatch(s)
+	if m == nil {
+		return BazelVersion{}, fmt.Errorf("invalid bazel version %q", s)
+	}
+	return parseNumbers(m[1:4])
+}
+
+// IsFinalRelease reports whether s is a plain MAJOR.MINOR.PATCH version (not
+// a release candidate or prerelease).
+func IsFinalRelease(s string) bool {
+	m := releasePattern.FindStringSubmatch(s)
+	return m != nil && m[4] == ""
+}
+
+// Satisfies reports whether the given Bazel version satisfies the constraint.
+func (c Constraint) Satisfies(v BazelVersion) bool {
+	cmp := v.Compare(c.Version)
+	switch c.Op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "-":
+		return cmp != 0
+	}
+	return false
+}
+
+// SatisfiesAll reports whether the given Bazel version satisfies every
+// constraint.
+func SatisfiesAll(constraints []Constraint, v BazelVersion) bool {
+	for _, c := range constraints {
+		if !c.Satisfies(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseNumbers(parts []string) (BazelVersion, error) {
+	var v BazelVersion
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return BazelVersion{}, err
+		}
+		v[i] = n
+	}
+	return v, nil
+}
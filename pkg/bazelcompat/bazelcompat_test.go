@@ -0,0 +1,88 @@
+package bazelcompat
+
+import (
+	"testing"
+)
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Constraint
+		wantErr bool
+	}{
+		{in: ">=7.0.0", want: Constraint{Op: ">=", Version: BazelVersion{7, 0, 0}}},
+		{in: "<=8.1.2", want: Constraint{Op: "<=", Version: BazelVersion{8, 1, 2}}},
+		{in: ">6.4.0", want: Constraint{Op: ">", Version: BazelVersion{6, 4, 0}}},
+		{in: "<9.0.0", want: Constraint{Op: "<", Version: BazelVersion{9, 0, 0}}},
+		{in: "-7.1.0", want: Constraint{Op: "-", Version: BazelVersion{7, 1, 0}}},
+		{in: ">=7.0", wantErr: true},
+		{in: "7.0.0", wantErr: true},
+		{in: "=7.0.0", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseConstraint(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConstraint(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseConstraint(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.in {
+				t.Errorf("String() = %q, want %q", got.String(), tt.in)
+			}
+		})
+	}
+}
+
+func TestSatisfiesAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints []string
+		version     string
+		want        bool
+	}{
+		{name: "no constraints", version: "6.0.0", want: true},
+		{name: "lower bound", constraints: []string{">=7.0.0"}, version: "7.0.0", want: true},
+		{name: "below lower bound", constraints: []string{">=7.0.0"}, version: "6.5.0", want: false},
+		{name: "range", constraints: []string{">=7.0.0", "<8.0.0"}, version: "7.4.1", want: true},
+		{name: "upper bound exclusive", constraints: []string{">=7.0.0", "<8.0.0"}, version: "8.0.0", want: false},
+		{name: "excluded version", constraints: []string{">=7.0.0", "-7.1.0"}, version: "7.1.0", want: false},
+		{name: "suffix ignored", constraints: []string{">=7.0.0"}, version: "7.0.0rc1", want: true},
+		{name: "numeric comparison", constraints: []string{">6.9.0"}, version: "6.10.0", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			constraints, err := ParseConstraints(tt.constraints)
+			if err != nil {
+				t.Fatal(err)
+			}
+			version, err := ParseRelease(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := SatisfiesAll(constraints, version); got != tt.want {
+				t.Errorf("SatisfiesAll(%v, %s) = %v, want %v", tt.constraints, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsFinalRelease(t *testing.T) {
+	for in, want := range map[string]bool{
+		"7.0.0":                    true,
+		"7.0.0rc1":                 false,
+		"8.0.0-pre.20240101.1":     false,
+		"7.0":                      false,
+		"last_green":               false,
+		"10.12.130":                true,
+		"7.0.0-pre.20230917.3+foo": false,
+	} {
+		if got := IsFinalRelease(in); got != want {
+			t.Errorf("IsFinalRelease(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Package bitbucket fetches repository metadata from the Bitbucket Cloud API.
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+// APIURL is the base url of the Bitbucket Cloud REST API.
+const APIURL = "https://api.bitbucket.org/2.0"
+
+// httpClient is used for all requests to the Bitbucket API.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// FetchRepositoryMetadata fetches the description and language of the
+// repository from the API at apiURL.  Bitbucket has no stars, so the number
+// of watchers is reported instead.  Only the main language of a repository is
+// known, so the language breakdown is left empty and the primary language is
+// set directly.
+func FetchRepositoryMetadata(ctx context.Context, apiURL, token string, md *bzpb.RepositoryMetadata) error {
+	repoURL := fmt.Sprintf("%s/repositories/%s/%s", apiURL, url.PathEscape(md.Organization), url.PathEscape(md.Name))
+
+	var repo struct {
+		Description string `json:"description"`
+		Language    string `json:"language"`
+	}
+	if err := getJSON(ctx, repoURL, token, &repo); err != nil {
+		return err
+	}
+
+	var watchers struct {
+		Size int32 `json:"size"`
+	}
+	if err := getJSON(ctx, repoURL+"/watchers?pagelen=1", token, &watchers); err != nil {
+		return err
+	}
+
+	md.Description = repo.Description
+	md.Stargazers = watchers.Size
+	md.PrimaryLanguage = repo.Language
+	// Initialize Languages map to indicate metadata was fetched
+	md.Languages = make(map[string]int32)
+
+	return nil
+}
+
+// getJSON decodes the JSON response of a GET request into v.
+func getJSON(ctx context.Context, endpoint, token string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GET %s failed with status %d: %s", endpoint, resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestFetchRepositoryMetadata(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repositories/atlassian/python-bitbucket", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"full_name": "atlassian/python-bitbucket", "description": "Bitbucket client", "language": "python"}`))
+	})
+	mux.HandleFunc("/repositories/atlassian/python-bitbucket/watchers", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("pagelen"); got != "1" {
+			t.Errorf("pagelen = %q, want 1", got)
+		}
+		w.Write([]byte(`{"size": 7, "pagelen": 1, "values": []}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	md := &bzpb.RepositoryMetadata{Type: bzpb.RepositoryType_BITBUCKET, Organization: "atlassian", Name: "python-bitbucket"}
+	if err := FetchRepositoryMetadata(context.Background(), server.URL, "", md); err != nil {
+		t.Fatal(err)
+	}
+	if md.Description != "Bitbucket client" || md.Stargazers != 7 || md.PrimaryLanguage != "python" {
+		t.Errorf("unexpected metadata: %v", md)
+	}
+	if md.Languages == nil {
+		t.Error("expected the languages to be initialized")
+	}
+
+	missing := &bzpb.RepositoryMetadata{Type: bzpb.RepositoryType_BITBUCKET, Organization: "atlassian", Name: "missing"}
+	if err := FetchRepositoryMetadata(context.Background(), server.URL, "", missing); err == nil {
+		t.Error("expected an error for a missing repository")
+	}
+}
@@ -0,0 +1,223 @@
+// Package cachestore implements a versioned store for the results of network
+// lookups (url statuses, repository metadata, Bazel releases, source
+// commits).  Every entry
+// records when it was fetched and expires after the TTL of its class.
+package cachestore
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+)
+
+// Version is the format version of the stores written by this package.
+const Version = 1
+
+type entryKey struct {
+	typ bzpb.CacheEntryType
+	key string
+}
+
+// Store is a cache store loaded into memory.
+type Store struct {
+	// TTLs are the times to live of the entries.  Get does not return expired
+	// entries.
+	TTLs TTLs
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+
+	entries  map[entryKey]*bzpb.CacheEntry
+	modified bool
+}
+
+// New returns an empty store.
+func New(ttls TTLs) *Store {
+	return &Store{TTLs: ttls, entries: make(map[entryKey]*bzpb.CacheEntry)}
+}
+
+// ReadFile reads the store from the given file (.json or .pb).  A missing
+// file yields an empty store, as does a file of another format version (the
+// entries are refetched).
+func ReadFile(filename string, ttls TTLs) (*Store, error) {
+	s := New(ttls)
+
+	var store bzpb.CacheStore
+	if err := protoutil.ReadFile(filename, &store); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if store.Version != Version {
+		s.modified = len(store.Entries) > 0
+		return s, nil
+	}
+	for _, entry := range store.Entries {
+		s.entries[entryKey{entry.Type, entry.Key}] = entry
+	}
+	return s, nil
+}
+
+// WriteFile writes the store to the given file (.json or .pb), sorted by
+// type, then key.
+func (s *Store) WriteFile(filename string) error {
+	store := &bzpb.CacheStore{Version: Version}
+	for _, k := range slices.SortedFunc(maps.Keys(s.entries), compareEntryKeys) {
+		store.Entries = append(store.Entries, s.entries[k])
+	}
+	if err := protoutil.WriteFile(filename, store); err != nil {
+		return err
+	}
+	s.modified = false
+	return nil
+}
+
+// Modified reports whether the store was changed since it was read or
+// written.
+func (s *Store) Modified() bool {
+	return s.modified
+}
+
+// Len returns the number of entries, including expired ones.
+func (s *Store) Len() int {
+	return len(s.entries)
+}
+
+// Get returns the entry of the given type and key, or nil if there is none
+// or it has expired.
+func (s *Store) Get(typ bzpb.CacheEntryType, key string) *bzpb.CacheEntry {
+	entry, ok := s.entries[entryKey{typ, key}]
+	if !ok || s.Expired(entry) {
+		return nil
+	}
+	return entry
+}
+
+// Lookup returns the entry of the given type and key, even if it has expired,
+// or nil if there is none.
+func (s *Store) Lookup(typ bzpb.CacheEntryType, key string) *bzpb.CacheEntry {
+	return s.entries[entryKey{typ, key}]
+}
+
+// Put adds or replaces the entry of its type and key.  FetchedAt is set to
+// the current time if empty.
+func (s *Store) Put(entry *bzpb.CacheEntry) {
+	if entry.FetchedAt == "" {
+		entry.FetchedAt = s.now().UTC().Format(time.RFC3339)
+	}
+	s.entries[entryKey{entry.Type, entry.Key}] = entry
+	s.modified = true
+}
+
+// Entries returns the entries of the given type (all entries if the type is
+// CACHE_ENTRY_TYPE_UNKNOWN), including expired ones, sorted by type, then key.
+func (s *Store) Entries(typ bzpb.CacheEntryType) []*bzpb.CacheEntry {
+	var entries []*bzpb.CacheEntry
+	for _, k := range slices.SortedFunc(maps.Keys(s.entries), compareEntryKeys) {
+		if typ == bzpb.CacheEntryType_CACHE_ENTRY_TYPE_UNKNOWN || k.typ == typ {
+			entries = append(entries, s.entries[k])
+		}
+	}
+	return entries
+}
+
+// Expired reports whether the entry is older than the TTL of its class.
+// Entries without (valid) fetch time are expired, unless their TTL is zero.
+func (s *Store) Expired(entry *bzpb.CacheEntry) bool {
+	ttl := s.TTLs[ClassOf(entry)]
+	if ttl == 0 {
+		return false
+	}
+	fetchedAt, err := time.Parse(time.RFC3339, entry.FetchedAt)
+	if err != nil {
+		return true
+	}
+	return s.now().Sub(fetchedAt) > ttl
+}
+
+// Prune removes the expired entries and returns their number.
+func (s *Store) Prune() int {
+	n := 0
+	for k, entry := range s.entries {
+		if s.Expired(entry) {
+			delete(s.entries, k)
+			n++
+		}
+	}
+	if n > 0 {
+		s.modified = true
+	}
+	return n
+}
+
+func (s *Store) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func compareEntryKeys(a, b entryKey) int {
+	return cmp.Or(cmp.Compare(a.typ, b.typ), strings.Compare(a.key, b.key))
+}
+
+// ResourceStatusEntry returns a new entry for the status of a url.
+func ResourceStatusEntry(status *bzpb.ResourceStatus) *bzpb.CacheEntry {
+	return &bzpb.CacheEntry{
+		Type:  bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS,
+		Key:   status.Url,
+		Value: &bzpb.CacheEntry_ResourceStatus{ResourceStatus: status},
+	}
+}
+
+// RepositoryMetadataEntry returns a new entry for the metadata of the
+// repository with the given ID.
+func RepositoryMetadataEntry(id string, md *bzpb.RepositoryMetadata) *bzpb.CacheEntry {
+	return &bzpb.CacheEntry{
+		Type:  bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA,
+		Key:   id,
+		Value: &bzpb.CacheEntry_RepositoryMetadata{RepositoryMetadata: md},
+	}
+}
+
+// BazelReleaseEntry returns a new entry for a Bazel release.
+func BazelReleaseEntry(release *bzpb.BazelRelease) *bzpb.CacheEntry {
+	return &bzpb.CacheEntry{
+		Type:  bzpb.CacheEntryType_CACHE_ENTRY_BAZEL_RELEASE,
+		Key:   release.Version,
+		Value: &bzpb.CacheEntry_BazelRelease{BazelRelease: release},
+	}
+}
+
+// SourceCommitEntry returns a new entry for the commit SHA resolved from a
+// source url.
+func SourceCommitEntry(url, commitSHA string) *bzpb.CacheEntry {
+	return &bzpb.CacheEntry{
+		Type:  bzpb.CacheEntryType_CACHE_ENTRY_SOURCE_COMMIT,
+		Key:   url,
+		Value: &bzpb.CacheEntry_CommitSha{CommitSha: commitSHA},
+	}
+}
+
+// FormatEntryType returns the short name of the entry type (e.g.,
+// 'resource_status').
+func FormatEntryType(typ bzpb.CacheEntryType) string {
+	return strings.ToLower(strings.TrimPrefix(typ.String(), "CACHE_ENTRY_"))
+}
+
+// ParseEntryType parses the short name of an entry type.
+func ParseEntryType(name string) (bzpb.CacheEntryType, error) {
+	value, ok := bzpb.CacheEntryType_value["CACHE_ENTRY_"+strings.ToUpper(name)]
+	if !ok || value == 0 {
+		return 0, fmt.Errorf("unknown cache entry type %q", name)
+	}
+	return bzpb.CacheEntryType(value), nil
+}
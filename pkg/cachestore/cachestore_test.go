@@ -0,0 +1,174 @@
+package cachestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+)
+
+var now = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func fetchedAt(d time.Duration) string {
+	return now.Add(-d).Format(time.RFC3339)
+}
+
+func newTestStore() *Store {
+	s := New(DefaultTTLs())
+	s.Now = func() time.Time { return now }
+	return s
+}
+
+func TestGetExpired(t *testing.T) {
+	s := newTestStore()
+	for _, entry := range []*bzpb.CacheEntry{
+		withFetchedAt(ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com/ok", Code: 200}), fetchedAt(10*24*time.Hour)),
+		withFetchedAt(ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com/404", Code: 404}), fetchedAt(2*24*time.Hour)),
+		withFetchedAt(ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com/500", Code: 500}), fetchedAt(time.Hour)),
+		withFetchedAt(RepositoryMetadataEntry("github:org/old", &bzpb.RepositoryMetadata{}), fetchedAt(8*24*time.Hour)),
+		withFetchedAt(RepositoryMetadataEntry("github:org/new", &bzpb.RepositoryMetadata{}), fetchedAt(6*24*time.Hour)),
+		withFetchedAt(RepositoryMetadataEntry("github:org/unknown", &bzpb.RepositoryMetadata{}), ""),
+		withFetchedAt(SourceCommitEntry("https://example.com/a.tar.gz", "0123456789abcdef0123456789abcdef01234567"), fetchedAt(365*24*time.Hour)),
+	} {
+		s.entries[entryKey{entry.Type, entry.Key}] = entry
+	}
+
+	for _, tc := range []struct {
+		typ  bzpb.CacheEntryType
+		key  string
+		want bool
+	}{
+		{bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, "https://example.com/ok", true},
+		{bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, "https://example.com/404", false},
+		{bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, "https://example.com/500", true},
+		{bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, "https://example.com/missing", false},
+		{bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA, "github:org/old", false},
+		{bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA, "github:org/new", true},
+		{bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA, "github:org/unknown", false},
+		{bzpb.CacheEntryType_CACHE_ENTRY_BAZEL_RELEASE, "https://example.com/ok", false},
+		{bzpb.CacheEntryType_CACHE_ENTRY_SOURCE_COMMIT, "https://example.com/a.tar.gz", true},
+	} {
+		if got := s.Get(tc.typ, tc.key) != nil; got != tc.want {
+			t.Errorf("Get(%v, %q) found = %v, want %v", tc.typ, tc.key, got, tc.want)
+		}
+	}
+	if s.Lookup(bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS, "https://example.com/404") == nil {
+		t.Error("expected Lookup to return expired entries")
+	}
+
+	if n := s.Prune(); n != 3 {
+		t.Errorf("Prune() = %d, want 3", n)
+	}
+	if s.Len() != 4 || !s.Modified() {
+		t.Errorf("unexpected store after pruning: %d entries, modified = %v", s.Len(), s.Modified())
+	}
+
+	// a zero TTL never expires
+	s.TTLs = TTLs{}
+	s.entries[entryKey{bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA, "github:org/unknown"}] = RepositoryMetadataEntry("github:org/unknown", &bzpb.RepositoryMetadata{})
+	if s.Get(bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA, "github:org/unknown") == nil {
+		t.Error("expected entries to never expire without TTLs")
+	}
+}
+
+func withFetchedAt(entry *bzpb.CacheEntry, fetchedAt string) *bzpb.CacheEntry {
+	entry.FetchedAt = fetchedAt
+	return entry
+}
+
+func TestReadWriteFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.json")
+
+	s, err := ReadFile(filename, DefaultTTLs())
+	if err != nil {
+		t.Fatalf("reading a missing file: %v", err)
+	}
+	s.Now = func() time.Time { return now }
+	s.Put(BazelReleaseEntry(&bzpb.BazelRelease{Version: "8.0.0"}))
+	s.Put(ResourceStatusEntry(&bzpb.ResourceStatus{Url: "https://example.com", Code: 200}))
+	if err := s.WriteFile(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	var store bzpb.CacheStore
+	if err := protoutil.ReadFile(filename, &store); err != nil {
+		t.Fatal(err)
+	}
+	if store.Version != Version || len(store.Entries) != 2 {
+		t.Fatalf("unexpected store: %v", &store)
+	}
+	if first := store.Entries[0]; first.Type != bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS || first.FetchedAt != "2025-06-01T00:00:00Z" {
+		t.Errorf("unexpected first entry: %v", first)
+	}
+
+	// stores of another version are discarded
+	store.Version = Version + 1
+	if err := protoutil.WriteFile(filename, &store); err != nil {
+		t.Fatal(err)
+	}
+	s, err = ReadFile(filename, DefaultTTLs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != 0 {
+		t.Errorf("expected the entries of another version to be discarded, got %d", s.Len())
+	}
+
+	if err := os.WriteFile(filename, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(filename, DefaultTTLs()); err == nil {
+		t.Error("expected an error for a corrupt file")
+	}
+}
+
+func TestTTLsSet(t *testing.T) {
+	ttls := DefaultTTLs()
+	for _, tc := range []struct {
+		value   string
+		class   Class
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "repository_metadata=3d", class: ClassRepositoryMetadata, want: 3 * 24 * time.Hour},
+		{value: "resource_failure=6h", class: ClassResourceFailure, want: 6 * time.Hour},
+		{value: "bazel_release=0", class: ClassBazelRelease, want: 0},
+		{value: "stars=7d", wantErr: true},
+		{value: "resource_status", wantErr: true},
+		{value: "resource_status=-1h", wantErr: true},
+		{value: "resource_status=xd", wantErr: true},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			err := ttls.Set(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tc.value, err, tc.wantErr)
+			}
+			if err == nil && ttls[tc.class] != tc.want {
+				t.Errorf("Set(%q): %s = %v, want %v", tc.value, tc.class, ttls[tc.class], tc.want)
+			}
+		})
+	}
+	if got, want := ttls.String(), "bazel_release=0s,repository_metadata=3d,resource_failure=6h0m0s,resource_status=30d"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseEntryType(t *testing.T) {
+	for _, typ := range []bzpb.CacheEntryType{
+		bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS,
+		bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA,
+		bzpb.CacheEntryType_CACHE_ENTRY_BAZEL_RELEASE,
+		bzpb.CacheEntryType_CACHE_ENTRY_SOURCE_COMMIT,
+	} {
+		got, err := ParseEntryType(FormatEntryType(typ))
+		if err != nil || got != typ {
+			t.Errorf("ParseEntryType(%q) = %v, %v", FormatEntryType(typ), got, err)
+		}
+	}
+	if _, err := ParseEntryType("type_unknown"); err == nil {
+		t.Error("expected an error for the unknown type")
+	}
+}
@@ -0,0 +1,126 @@
+package cachestore
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+// Class groups the entries that share a TTL.
+type Class string
+
+const (
+	// ClassResourceStatus are the urls that exist.
+	ClassResourceStatus Class = "resource_status"
+	// ClassResourceFailure are the urls that do not exist or could not be
+	// checked.
+	ClassResourceFailure Class = "resource_failure"
+	// ClassRepositoryMetadata is the repository metadata (stars,
+	// languages, ...).
+	ClassRepositoryMetadata Class = "repository_metadata"
+	// ClassBazelRelease are the Bazel releases.
+	ClassBazelRelease Class = "bazel_release"
+	// ClassSourceCommit are the commit SHAs of the source urls.
+	ClassSourceCommit Class = "source_commit"
+)
+
+// Classes are all entry classes.
+var Classes = []Class{ClassResourceStatus, ClassResourceFailure, ClassRepositoryMetadata, ClassBazelRelease, ClassSourceCommit}
+
+// ClassOf returns the class of the entry.
+func ClassOf(entry *bzpb.CacheEntry) Class {
+	switch entry.Type {
+	case bzpb.CacheEntryType_CACHE_ENTRY_RESOURCE_STATUS:
+		if code := entry.GetResourceStatus().GetCode(); code < 200 || code >= 300 {
+			return ClassResourceFailure
+		}
+		return ClassResourceStatus
+	case bzpb.CacheEntryType_CACHE_ENTRY_REPOSITORY_METADATA:
+		return ClassRepositoryMetadata
+	case bzpb.CacheEntryType_CACHE_ENTRY_BAZEL_RELEASE:
+		return ClassBazelRelease
+	case bzpb.CacheEntryType_CACHE_ENTRY_SOURCE_COMMIT:
+		return ClassSourceCommit
+	}
+	return ""
+}
+
+// TTLs are the times to live by class.  A zero (or missing) TTL never
+// expires.  TTLs implements flag.Value, parsing CLASS=DURATION.
+type TTLs map[Class]time.Duration
+
+// DefaultTTLs returns the default times to live: failed url checks are
+// retried after a day, repository metadata (stars) is refreshed after a
+// week, the url statuses after a month.  The list of Bazel releases is
+// refetched daily.  Source commits do not expire (a released tag is not
+// expected to move).
+func DefaultTTLs() TTLs {
+	return TTLs{
+		ClassResourceStatus:     30 * 24 * time.Hour,
+		ClassResourceFailure:    24 * time.Hour,
+		ClassRepositoryMetadata: 7 * 24 * time.Hour,
+		ClassBazelRelease:       24 * time.Hour,
+	}
+}
+
+// String implements flag.Value.
+func (t TTLs) String() string {
+	var parts []string
+	for _, class := range slices.Sorted(maps.Keys(t)) {
+		parts = append(parts, fmt.Sprintf("%s=%s", class, FormatDuration(t[class])))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value.  The value is CLASS=DURATION (e.g.,
+// 'repository_metadata=7d').
+func (t TTLs) Set(value string) error {
+	name, duration, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("invalid TTL %q (want CLASS=DURATION)", value)
+	}
+	class := Class(name)
+	if !slices.Contains(Classes, class) {
+		return fmt.Errorf("unknown cache class %q (want one of %v)", name, Classes)
+	}
+	ttl, err := ParseDuration(duration)
+	if err != nil {
+		return err
+	}
+	t[class] = ttl
+	return nil
+}
+
+// ParseDuration parses a duration like time.ParseDuration, with the
+// additional unit 'd' for days (e.g., '7d').
+func ParseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// FormatDuration formats the duration in days if it is a whole number of
+// days.
+func FormatDuration(d time.Duration) string {
+	if d > 0 && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
@@ -201,6 +201,28 @@ func FetchRepositoryMetadataBatch(ctx context.Context, token string, repos []*bz
         }
       }
     }
+    licenseInfo {
+      spdxId
+    }
+    repositoryTopics(first: 20) {
+      nodes {
+        topic {
+          name
+        }
+      }
+    }
+    isArchived
+    isDisabled
+    defaultBranchRef {
+      name
+    }
+    pushedAt
+    issues(states: OPEN) {
+      totalCount
+    }
+    latestRelease {
+      tagName
+    }
   }
 `, i, repo.Organization, repo.Name))
 	}
@@ -342,12 +364,12 @@ func parseRepositoryMetadataResponse(data map[string]any, repos []*bzpb.Reposito
 
 					size, sizeOk := edgeMap["size"].(float64)
 					if !sizeOk {
-						log.Printf("WARN %s: graphql response edge size parse issue: %v/%v", canonicalName, edgeMap["size"])
+						log.Printf("WARN %s: graphql response edge size parse issue: %v", canonicalName, edgeMap["size"])
 						continue
 					}
 					node, nodeOk := edgeMap["node"].(map[string]any)
 					if !nodeOk {
-						log.Printf("WARN %s: graphql response node parse issue: %v/%v", canonicalName, edgeMap["node"])
+						log.Printf("WARN %s: graphql response node parse issue: %v", canonicalName, edgeMap["node"])
 						continue
 					}
 					name, nameOk := node["name"].(string)
@@ -364,11 +386,54 @@ func parseRepositoryMetadataResponse(data map[string]any, repos []*bzpb.Reposito
 		} else {
 			log.Printf("WARN %s: graphql response languages parse issue: %v", canonicalName, repoData["languages"])
 		}
+
+		parseRepositoryDetails(repo, repoData)
 	}
 
 	return nil
 }
 
+// parseRepositoryDetails populates the license, topics, status flags and
+// activity fields of the repo proto.  All of them are optional in the
+// response (e.g. repositories without a license or releases have null
+// values), so missing fields are left empty.
+func parseRepositoryDetails(repo *bzpb.RepositoryMetadata, repoData map[string]any) {
+	if license, ok := repoData["licenseInfo"].(map[string]any); ok {
+		repo.License, _ = license["spdxId"].(string)
+	}
+
+	repo.Topics = nil
+	if topics, ok := repoData["repositoryTopics"].(map[string]any); ok {
+		nodes, _ := topics["nodes"].([]any)
+		for _, node := range nodes {
+			nodeMap, _ := node.(map[string]any)
+			topic, _ := nodeMap["topic"].(map[string]any)
+			if name, ok := topic["name"].(string); ok {
+				repo.Topics = append(repo.Topics, name)
+			}
+		}
+	}
+
+	repo.Archived, _ = repoData["isArchived"].(bool)
+	repo.Disabled, _ = repoData["isDisabled"].(bool)
+
+	if ref, ok := repoData["defaultBranchRef"].(map[string]any); ok {
+		repo.DefaultBranch, _ = ref["name"].(string)
+	}
+
+	repo.PushedAt, _ = repoData["pushedAt"].(string)
+
+	if issues, ok := repoData["issues"].(map[string]any); ok {
+		if count, ok := issues["totalCount"].(float64); ok {
+			repo.OpenIssues = int32(count)
+		}
+	}
+
+	if release, ok := repoData["latestRelease"].(map[string]any); ok {
+		repo.LatestRelease, _ = release["tagName"].(string)
+	}
+}
+
 // formatRepository prints a canonical form of a repository string
 // e.g., "github:org/repo"
 func formatRepository(md *bzpb.RepositoryMetadata) string {
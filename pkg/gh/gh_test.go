@@ -0,0 +1,70 @@
+package gh
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestParseRepositoryMetadataResponse(t *testing.T) {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"repo0": {
+			"description": "Go rules for Bazel",
+			"stargazerCount": 1400,
+			"languages": {"edges": [{"size": 2000, "node": {"name": "Go"}}, {"size": 1000, "node": {"name": "Starlark"}}]},
+			"licenseInfo": {"spdxId": "Apache-2.0"},
+			"repositoryTopics": {"nodes": [{"topic": {"name": "bazel"}}, {"topic": {"name": "golang"}}]},
+			"isArchived": false,
+			"isDisabled": false,
+			"defaultBranchRef": {"name": "master"},
+			"pushedAt": "2026-10-01T12:00:00Z",
+			"issues": {"totalCount": 321},
+			"latestRelease": {"tagName": "v0.50.0"}
+		},
+		"repo1": {
+			"description": null,
+			"stargazerCount": 3,
+			"languages": {"edges": []},
+			"licenseInfo": null,
+			"repositoryTopics": {"nodes": []},
+			"isArchived": true,
+			"isDisabled": false,
+			"defaultBranchRef": null,
+			"pushedAt": "2019-01-01T00:00:00Z",
+			"issues": {"totalCount": 0},
+			"latestRelease": null
+		}
+	}`), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	repos := []*bzpb.RepositoryMetadata{
+		{Type: bzpb.RepositoryType_GITHUB, Organization: "bazel-contrib", Name: "rules_go"},
+		{Type: bzpb.RepositoryType_GITHUB, Organization: "example", Name: "archived"},
+	}
+	if err := parseRepositoryMetadataResponse(data, repos); err != nil {
+		t.Fatal(err)
+	}
+
+	rulesGo := repos[0]
+	if rulesGo.Stargazers != 1400 || rulesGo.Languages["Go"] != 2000 {
+		t.Errorf("unexpected basic metadata: %v", rulesGo)
+	}
+	if rulesGo.License != "Apache-2.0" || !slices.Equal(rulesGo.Topics, []string{"bazel", "golang"}) {
+		t.Errorf("license = %q, topics = %v", rulesGo.License, rulesGo.Topics)
+	}
+	if rulesGo.Archived || rulesGo.DefaultBranch != "master" || rulesGo.PushedAt != "2026-10-01T12:00:00Z" {
+		t.Errorf("archived = %v, default branch = %q, pushed at = %q", rulesGo.Archived, rulesGo.DefaultBranch, rulesGo.PushedAt)
+	}
+	if rulesGo.OpenIssues != 321 || rulesGo.LatestRelease != "v0.50.0" {
+		t.Errorf("open issues = %d, latest release = %q", rulesGo.OpenIssues, rulesGo.LatestRelease)
+	}
+
+	archived := repos[1]
+	if !archived.Archived || archived.License != "" || archived.DefaultBranch != "" || archived.LatestRelease != "" || len(archived.Topics) != 0 {
+		t.Errorf("unexpected metadata for the archived repository: %v", archived)
+	}
+}
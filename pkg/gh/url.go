@@ -31,14 +31,26 @@ var (
 	// Matches: https://github.com/{org}/{repo}/archive/{sha}.{ext}
 	commitArchivePattern = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/archive/([a-f0-9]{40})\.(tar\.gz|zip)$`)
 
+	// Matches: https://github.com/{org}/{repo}/archive/{tag}.{ext}
+	refArchivePattern = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/archive/([^/]+)\.(tar\.gz|zip)$`)
+
 	// Matches: https://github.com/{org}/{repo}/releases/download/{version}/{filename}
 	releaseDownloadPattern = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/releases/download/([^/]+)/[^/]+$`)
+
+	// Matches: https://codeload.github.com/{org}/{repo}/{tar.gz|zip}/refs/tags/{tag}
+	codeloadTagPattern = regexp.MustCompile(`^https://codeload\.github\.com/([^/]+)/([^/]+)/(?:tar\.gz|zip)/refs/tags/([^/]+)$`)
+
+	// Matches: https://codeload.github.com/{org}/{repo}/{tar.gz|zip}/{sha}
+	codeloadCommitPattern = regexp.MustCompile(`^https://codeload\.github\.com/([^/]+)/([^/]+)/(?:tar\.gz|zip)/([a-f0-9]{40})$`)
+
+	// Matches: https://codeload.github.com/{org}/{repo}/{tar.gz|zip}/{tag}
+	codeloadRefPattern = regexp.MustCompile(`^https://codeload\.github\.com/([^/]+)/([^/]+)/(?:tar\.gz|zip)/([^/]+)$`)
 )
 
 // ParseGitHubSourceURL parses a GitHub source URL and extracts organization, repository, and reference information
 func ParseGitHubSourceURL(url string) (*SourceURLInfo, error) {
 	// Try tag archive pattern
-	if matches := tagArchivePattern.FindStringSubmatch(url); matches != nil {
+	if matches := firstMatch(url, tagArchivePattern, codeloadTagPattern); matches != nil {
 		return &SourceURLInfo{
 			Organization: matches[1],
 			Repository:   matches[2],
@@ -48,7 +60,7 @@ func ParseGitHubSourceURL(url string) (*SourceURLInfo, error) {
 	}
 
 	// Try commit SHA archive pattern
-	if matches := commitArchivePattern.FindStringSubmatch(url); matches != nil {
+	if matches := firstMatch(url, commitArchivePattern, codeloadCommitPattern); matches != nil {
 		return &SourceURLInfo{
 			Organization: matches[1],
 			Repository:   matches[2],
@@ -67,9 +79,30 @@ func ParseGitHubSourceURL(url string) (*SourceURLInfo, error) {
 		}, nil
 	}
 
+	// Try the archive patterns without refs/tags, the reference is assumed
+	// to be a tag
+	if matches := firstMatch(url, refArchivePattern, codeloadRefPattern); matches != nil {
+		return &SourceURLInfo{
+			Organization: matches[1],
+			Repository:   matches[2],
+			Type:         URLTypeTag,
+			Reference:    matches[3],
+		}, nil
+	}
+
 	return nil, fmt.Errorf("URL does not match any known GitHub source URL pattern: %s", url)
 }
 
+// firstMatch returns the submatches of the first pattern that matches the url
+func firstMatch(url string, patterns ...*regexp.Regexp) []string {
+	for _, pattern := range patterns {
+		if matches := pattern.FindStringSubmatch(url); matches != nil {
+			return matches
+		}
+	}
+	return nil
+}
+
 // IsGitHubURL checks if a URL is a GitHub URL
 func IsGitHubURL(url string) bool {
 	return strings.HasPrefix(url, "https://github.com/")
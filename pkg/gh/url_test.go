@@ -88,6 +88,50 @@ func TestParseGitHubSourceURL(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "archive without refs/tags",
+			url:  "https://github.com/bazelbuild/rules_cc/archive/0.0.9.tar.gz",
+			want: &SourceURLInfo{
+				Organization: "bazelbuild",
+				Repository:   "rules_cc",
+				Type:         URLTypeTag,
+				Reference:    "0.0.9",
+			},
+			wantErr: false,
+		},
+		{
+			name: "codeload tag",
+			url:  "https://codeload.github.com/google/glog/tar.gz/refs/tags/v0.7.1",
+			want: &SourceURLInfo{
+				Organization: "google",
+				Repository:   "glog",
+				Type:         URLTypeTag,
+				Reference:    "v0.7.1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "codeload commit SHA",
+			url:  "https://codeload.github.com/grpc/grpc/zip/b73dbd94df4bd9f9362d16b76f34e4c7c2358409",
+			want: &SourceURLInfo{
+				Organization: "grpc",
+				Repository:   "grpc",
+				Type:         URLTypeCommitSHA,
+				Reference:    "b73dbd94df4bd9f9362d16b76f34e4c7c2358409",
+			},
+			wantErr: false,
+		},
+		{
+			name: "codeload ref",
+			url:  "https://codeload.github.com/madler/zlib/tar.gz/v1.3.1",
+			want: &SourceURLInfo{
+				Organization: "madler",
+				Repository:   "zlib",
+				Type:         URLTypeTag,
+				Reference:    "v1.3.1",
+			},
+			wantErr: false,
+		},
 		{
 			name:    "invalid URL - not github",
 			url:     "https://example.com/foo/bar.tar.gz",
@@ -56,6 +56,32 @@ func GetRegistryCommit(ctx context.Context, repoPath string) (sha, date string,
 	return parts[0], parts[1], nil
 }
 
+// GetChangedFiles returns the files below pathspec that differ between the
+// given commit and the working tree, including uncommitted changes and
+// untracked files (unless ignored).  Paths are relative to repoPath.
+func GetChangedFiles(ctx context.Context, repoPath, fromCommit, pathspec string) ([]string, error) {
+	output, err := exec.CommandContext(ctx, "git", "-C", repoPath,
+		"diff", "--name-only", "--no-renames", "--relative", fromCommit, "--", pathspec).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s against the working tree: %w", fromCommit, err)
+	}
+	untracked, err := exec.CommandContext(ctx, "git", "-C", repoPath,
+		"ls-files", "--others", "--exclude-standard", "--", pathspec).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	var files []string
+	for _, out := range [][]byte{output, untracked} {
+		for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+			if line != "" {
+				files = append(files, line)
+			}
+		}
+	}
+	return files, nil
+}
+
 // GetRemoteURL returns the remote origin URL for a repository
 func GetRemoteURL(ctx context.Context, repoPath string) (string, error) {
 	output, err := exec.CommandContext(ctx, "git", "-C", repoPath, "config", "--get", "remote.origin.url").Output()
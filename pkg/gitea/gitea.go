@@ -0,0 +1,85 @@
+// Package gitea fetches repository metadata and resolves source archive
+// commits from Gitea and Forgejo instances (e.g. codeberg.org).
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+// DefaultHost is the host of the largest public Forgejo instance.
+const DefaultHost = "codeberg.org"
+
+// httpClient is used for all requests to Gitea instances.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// BaseURL returns the url of the Gitea instance at the given host.
+func BaseURL(host string) string {
+	return "https://" + host
+}
+
+// FetchRepositoryMetadata fetches the description, star count and language
+// breakdown of the repository from the REST API of the instance at baseURL.
+func FetchRepositoryMetadata(ctx context.Context, baseURL, token string, md *bzpb.RepositoryMetadata) error {
+	repoURL := fmt.Sprintf("%s/api/v1/repos/%s/%s", baseURL, url.PathEscape(md.Organization), url.PathEscape(md.Name))
+
+	var repo struct {
+		Description string `json:"description"`
+		StarsCount  int32  `json:"stars_count"`
+	}
+	if err := getJSON(ctx, repoURL, token, &repo); err != nil {
+		return err
+	}
+
+	// Map of language name to the number of bytes
+	var languages map[string]int64
+	if err := getJSON(ctx, repoURL+"/languages", token, &languages); err != nil {
+		return err
+	}
+
+	md.Description = repo.Description
+	md.Stargazers = repo.StarsCount
+	// Initialize Languages map to indicate metadata was fetched (even if empty)
+	md.Languages = make(map[string]int32, len(languages))
+	for name, size := range languages {
+		md.Languages[name] = int32(min(size, math.MaxInt32))
+	}
+
+	return nil
+}
+
+// getJSON decodes the JSON response of a GET request into v.
+func getJSON(ctx context.Context, endpoint, token string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GET %s failed with status %d: %s", endpoint, resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestFetchRepositoryMetadata(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/forgejo/forgejo", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token secret" {
+			t.Errorf("Authorization = %q, want the access token", got)
+		}
+		w.Write([]byte(`{"full_name": "forgejo/forgejo", "description": "Beyond coding. We forge.", "stars_count": 42}`))
+	})
+	mux.HandleFunc("/api/v1/repos/forgejo/forgejo/languages", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Go": 12345678, "JavaScript": 4321}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	md := &bzpb.RepositoryMetadata{Type: bzpb.RepositoryType_GITEA, Host: "codeberg.org", Organization: "forgejo", Name: "forgejo"}
+	if err := FetchRepositoryMetadata(context.Background(), server.URL, "secret", md); err != nil {
+		t.Fatal(err)
+	}
+	if md.Description != "Beyond coding. We forge." || md.Stargazers != 42 {
+		t.Errorf("unexpected metadata: %v", md)
+	}
+	if md.Languages["Go"] != 12345678 || md.Languages["JavaScript"] != 4321 {
+		t.Errorf("unexpected languages: %v", md.Languages)
+	}
+
+	missing := &bzpb.RepositoryMetadata{Type: bzpb.RepositoryType_GITEA, Host: "codeberg.org", Organization: "forgejo", Name: "missing"}
+	if err := FetchRepositoryMetadata(context.Background(), server.URL, "secret", missing); err == nil {
+		t.Error("expected an error for a missing repository")
+	}
+	if missing.Languages != nil {
+		t.Errorf("expected no metadata for the missing repository, got %v", missing)
+	}
+}
@@ -0,0 +1,71 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// SourceURLInfo contains parsed information from a Gitea source URL
+type SourceURLInfo struct {
+	Host       string // e.g. codeberg.org
+	Owner      string
+	Repository string
+	Reference  string // tag name or commit SHA
+}
+
+var (
+	// Matches: https://{host}/{owner}/{repo}/archive/{ref}.{ext}
+	archivePattern = regexp.MustCompile(`^https://([^/]+)/([^/]+)/([^/]+)/archive/([^/]+)\.(tar\.gz|zip)$`)
+
+	// Matches: https://{host}/{owner}/{repo}/releases/download/{tag}/{filename}
+	releaseDownloadPattern = regexp.MustCompile(`^https://([^/]+)/([^/]+)/([^/]+)/releases/download/([^/]+)/[^/]+$`)
+
+	// Matches a full commit SHA
+	commitSHAPattern = regexp.MustCompile(`^[a-f0-9]{40}$`)
+)
+
+// ParseGiteaSourceURL parses a Gitea source URL and extracts the host,
+// owner, repository and reference.  The host is not checked: the url layout
+// is the same as GitHub's, so callers should only pass urls of known Gitea
+// instances.
+func ParseGiteaSourceURL(sourceURL string) (*SourceURLInfo, error) {
+	for _, pattern := range []*regexp.Regexp{archivePattern, releaseDownloadPattern} {
+		if matches := pattern.FindStringSubmatch(sourceURL); matches != nil {
+			return &SourceURLInfo{
+				Host:       matches[1],
+				Owner:      matches[2],
+				Repository: matches[3],
+				Reference:  matches[4],
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("URL does not match any known Gitea source URL pattern: %s", sourceURL)
+}
+
+// GetCommitSHA resolves a tag (or commit) of the repository to a commit SHA
+// using the REST API of the instance at baseURL.
+func GetCommitSHA(ctx context.Context, baseURL, token, owner, repo, ref string) (string, error) {
+	if commitSHAPattern.MatchString(ref) {
+		// The reference is already a commit SHA
+		return ref, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s/tags/%s",
+		baseURL, url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(ref))
+
+	var tag struct {
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+	if err := getJSON(ctx, endpoint, token, &tag); err != nil {
+		return "", err
+	}
+	if tag.Commit.SHA == "" {
+		return "", fmt.Errorf("no commit found for %s/%s@%s", owner, repo, ref)
+	}
+
+	return tag.Commit.SHA, nil
+}
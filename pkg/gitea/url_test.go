@@ -0,0 +1,71 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseGiteaSourceURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    *SourceURLInfo
+		wantErr bool
+	}{
+		{
+			name: "tag archive",
+			url:  "https://codeberg.org/forgejo/forgejo/archive/v9.0.0.tar.gz",
+			want: &SourceURLInfo{Host: "codeberg.org", Owner: "forgejo", Repository: "forgejo", Reference: "v9.0.0"},
+		},
+		{
+			name: "commit archive",
+			url:  "https://gitea.com/gitea/tea/archive/0123456789abcdef0123456789abcdef01234567.zip",
+			want: &SourceURLInfo{Host: "gitea.com", Owner: "gitea", Repository: "tea", Reference: "0123456789abcdef0123456789abcdef01234567"},
+		},
+		{
+			name: "release download",
+			url:  "https://codeberg.org/owner/rules_foo/releases/download/v1.2.3/rules_foo-v1.2.3.tar.gz",
+			want: &SourceURLInfo{Host: "codeberg.org", Owner: "owner", Repository: "rules_foo", Reference: "v1.2.3"},
+		},
+		{
+			name:    "not an archive",
+			url:     "https://codeberg.org/owner/repo/src/branch/main/README.md",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGiteaSourceURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGiteaSourceURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGiteaSourceURL() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCommitSHA(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/owner/repo/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "v1.0.0", "id": "ffffffffffffffffffffffffffffffffffffffff", "commit": {"sha": "0123456789abcdef0123456789abcdef01234567"}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	if sha, err := GetCommitSHA(ctx, server.URL, "", "owner", "repo", "v1.0.0"); err != nil || sha != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("GetCommitSHA(v1.0.0) = %q, %v", sha, err)
+	}
+	if sha, err := GetCommitSHA(ctx, server.URL, "", "owner", "repo", "89abcdef0123456789abcdef0123456789abcdef"); err != nil || sha != "89abcdef0123456789abcdef0123456789abcdef" {
+		t.Errorf("GetCommitSHA(sha) = %q, %v", sha, err)
+	}
+	if _, err := GetCommitSHA(ctx, server.URL, "", "owner", "repo", "v2.0.0"); err == nil {
+		t.Error("expected an error for a missing tag")
+	}
+}
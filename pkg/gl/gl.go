@@ -8,13 +8,30 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"time"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
 )
 
+// DefaultHost is the host of the public GitLab instance.
+const DefaultHost = "gitlab.com"
+
+// httpClient is used for all requests to GitLab instances.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// BaseURL returns the url of the GitLab instance at the given host, or of
+// gitlab.com if the host is empty.
+func BaseURL(host string) string {
+	if host == "" {
+		host = DefaultHost
+	}
+	return "https://" + host
+}
+
 // FetchRepositoryMetadataBatch fetches repository metadata using GitLab GraphQL API
+// of the instance at baseURL (e.g. https://gitlab.com).
 // Fetches up to 100 repositories in a single GraphQL query
-func FetchRepositoryMetadataBatch(ctx context.Context, token string, repos []*bzpb.RepositoryMetadata) error {
+func FetchRepositoryMetadataBatch(ctx context.Context, baseURL, token string, repos []*bzpb.RepositoryMetadata) error {
 	if len(repos) == 0 {
 		return nil
 	}
@@ -51,7 +68,7 @@ func FetchRepositoryMetadataBatch(ctx context.Context, token string, repos []*bz
 	queryBuilder.WriteString("}\n")
 
 	// Execute raw GraphQL query
-	response, err := executeRawGraphQL(ctx, token, queryBuilder.String())
+	response, err := executeRawGraphQL(ctx, baseURL+"/api/graphql", token, queryBuilder.String())
 	if err != nil {
 		return fmt.Errorf("failed to execute GraphQL query: %w", err)
 	}
@@ -61,7 +78,7 @@ func FetchRepositoryMetadataBatch(ctx context.Context, token string, repos []*bz
 }
 
 // executeRawGraphQL executes a raw GraphQL query against GitLab's API
-func executeRawGraphQL(ctx context.Context, token, query string) (map[string]any, error) {
+func executeRawGraphQL(ctx context.Context, endpoint, token, query string) (map[string]any, error) {
 	reqBody := map[string]string{
 		"query": query,
 	}
@@ -71,7 +88,7 @@ func executeRawGraphQL(ctx context.Context, token, query string) (map[string]any
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://gitlab.com/api/graphql", bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -81,8 +98,7 @@ func executeRawGraphQL(ctx context.Context, token, query string) (map[string]any
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -189,6 +205,9 @@ func parseRepositoryMetadataResponse(data map[string]any, repos []*bzpb.Reposito
 func formatRepository(md *bzpb.RepositoryMetadata) string {
 	switch md.Type {
 	case bzpb.RepositoryType_GITLAB:
+		if md.Host != "" {
+			return fmt.Sprintf("gitlab:%s/%s/%s", md.Host, md.Organization, md.Name)
+		}
 		return fmt.Sprintf("gitlab:%s/%s", md.Organization, md.Name)
 	default:
 		return fmt.Sprintf("%s/%s", md.Organization, md.Name)
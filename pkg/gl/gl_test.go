@@ -0,0 +1,76 @@
+package gl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestFetchRepositoryMetadataBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want the bearer token", got)
+		}
+		var req struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		if !strings.Contains(req.Query, `repo0: project(fullPath: "xorg/lib/libxtrans")`) {
+			t.Errorf("unexpected query: %s", req.Query)
+		}
+		w.Write([]byte(`{"data": {
+			"repo0": {
+				"description": "X Network Transport layer",
+				"starCount": 3,
+				"languages": [{"name": "C", "share": 97.5}, {"name": "Meson", "share": 2.5}]
+			},
+			"repo1": null
+		}}`))
+	}))
+	defer server.Close()
+
+	repos := []*bzpb.RepositoryMetadata{
+		{Type: bzpb.RepositoryType_GITLAB, Host: "gitlab.freedesktop.org", Organization: "xorg", Name: "lib/libxtrans"},
+		{Type: bzpb.RepositoryType_GITLAB, Host: "gitlab.freedesktop.org", Organization: "xorg", Name: "missing"},
+	}
+	if err := FetchRepositoryMetadataBatch(context.Background(), server.URL, "secret", repos); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := repos[0]; got.Description != "X Network Transport layer" || got.Stargazers != 3 || got.Languages["C"] != 975000 {
+		t.Errorf("unexpected metadata: %v", got)
+	}
+	if repos[1].Languages != nil {
+		t.Errorf("expected no metadata for the missing repository, got %v", repos[1])
+	}
+}
+
+func TestFetchRepositoryMetadataBatchTooLarge(t *testing.T) {
+	repos := make([]*bzpb.RepositoryMetadata, 101)
+	if err := FetchRepositoryMetadataBatch(context.Background(), "http://invalid.test", "", repos); err == nil {
+		t.Error("expected an error for more than 100 repositories")
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	for host, want := range map[string]string{
+		"":               "https://gitlab.com",
+		"gitlab.arm.com": "https://gitlab.arm.com",
+	} {
+		if got := BaseURL(host); got != want {
+			t.Errorf("BaseURL(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package gl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// SourceURLInfo contains parsed information from a GitLab source URL
+type SourceURLInfo struct {
+	Host      string // e.g. gitlab.com or gitlab.freedesktop.org
+	Project   string // full path of the project, including nested groups
+	Reference string // tag name, branch name or commit SHA
+}
+
+var (
+	// Matches: https://{host}/{project}/-/archive/{ref}/{filename}
+	archivePattern = regexp.MustCompile(`^https://([^/]+)/(.+?)/-/archive/([^/]+)/[^/]+$`)
+
+	// Matches: https://{host}/{project}/-/releases/{tag}/downloads/{filename}
+	releaseDownloadPattern = regexp.MustCompile(`^https://([^/]+)/(.+?)/-/releases/([^/]+)/downloads/.+$`)
+
+	// Matches a full commit SHA
+	commitSHAPattern = regexp.MustCompile(`^[a-f0-9]{40}$`)
+)
+
+// ParseGitLabSourceURL parses a GitLab source URL and extracts the host,
+// project and reference.  The host is not checked since self-hosted
+// instances can live anywhere; the /-/ path separator is specific to GitLab.
+func ParseGitLabSourceURL(sourceURL string) (*SourceURLInfo, error) {
+	for _, pattern := range []*regexp.Regexp{archivePattern, releaseDownloadPattern} {
+		if matches := pattern.FindStringSubmatch(sourceURL); matches != nil {
+			return &SourceURLInfo{
+				Host:      matches[1],
+				Project:   matches[2],
+				Reference: matches[3],
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("URL does not match any known GitLab source URL pattern: %s", sourceURL)
+}
+
+// GetCommitSHA resolves a reference (tag, branch or commit) of the project to
+// a commit SHA using the REST API of the instance at baseURL.
+func GetCommitSHA(ctx context.Context, baseURL, token, project, ref string) (string, error) {
+	if commitSHAPattern.MatchString(ref) {
+		// The reference is already a commit SHA
+		return ref, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/repository/commits/%s",
+		baseURL, url.PathEscape(project), url.PathEscape(ref))
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("commit request for %s@%s failed with status %d: %s", project, ref, resp.StatusCode, string(body))
+	}
+
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&commit); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if commit.ID == "" {
+		return "", fmt.Errorf("no commit found for %s@%s", project, ref)
+	}
+
+	return commit.ID, nil
+}
@@ -0,0 +1,90 @@
+package gl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseGitLabSourceURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    *SourceURLInfo
+		wantErr bool
+	}{
+		{
+			name: "tag archive",
+			url:  "https://gitlab.com/libeigen/eigen/-/archive/3.4.0/eigen-3.4.0.tar.gz",
+			want: &SourceURLInfo{Host: "gitlab.com", Project: "libeigen/eigen", Reference: "3.4.0"},
+		},
+		{
+			name: "self-hosted nested groups",
+			url:  "https://gitlab.freedesktop.org/xorg/lib/libxtrans/-/archive/xtrans-1.5.0/libxtrans-xtrans-1.5.0.tar.bz2",
+			want: &SourceURLInfo{Host: "gitlab.freedesktop.org", Project: "xorg/lib/libxtrans", Reference: "xtrans-1.5.0"},
+		},
+		{
+			name: "release download",
+			url:  "https://gitlab.arm.com/bazel/ape/-/releases/v1.0.0/downloads/src.tar.gz",
+			want: &SourceURLInfo{Host: "gitlab.arm.com", Project: "bazel/ape", Reference: "v1.0.0"},
+		},
+		{
+			name:    "github url",
+			url:     "https://github.com/google/glog/archive/refs/tags/v0.7.1.tar.gz",
+			wantErr: true,
+		},
+		{
+			name:    "raw file",
+			url:     "https://gitlab.com/libeigen/eigen/-/raw/master/README.md",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGitLabSourceURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGitLabSourceURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("ParseGitLabSourceURL() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCommitSHA(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/xorg%2Flib%2Flibxtrans/repository/commits/xtrans-1.5.0" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id": "` + sha + `"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	got, err := GetCommitSHA(ctx, server.URL, "", "xorg/lib/libxtrans", "xtrans-1.5.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != sha {
+		t.Errorf("GetCommitSHA() = %q, want %q", got, sha)
+	}
+
+	if _, err := GetCommitSHA(ctx, server.URL, "", "xorg/lib/libxtrans", "missing"); err == nil {
+		t.Error("expected an error for an unknown reference")
+	}
+
+	// commit SHAs are returned without a request
+	if got, err := GetCommitSHA(ctx, "http://invalid.test", "", "xorg/lib/libxtrans", sha); err != nil || got != sha {
+		t.Errorf("GetCommitSHA(sha) = %q, %v, want %q", got, err, sha)
+	}
+}
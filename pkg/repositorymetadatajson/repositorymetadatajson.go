@@ -12,6 +12,7 @@ import (
 // jsonRepositoryMetadata is the intermediate JSON structure
 type jsonRepositoryMetadata struct {
 	Type            string            `json:"type"`
+	Host            string            `json:"host"`
 	Organization    string            `json:"organization"`
 	Name            string            `json:"name"`
 	Description     string            `json:"description"`
@@ -19,6 +20,14 @@ type jsonRepositoryMetadata struct {
 	Languages       map[string]string `json:"languages"`
 	CanonicalName   string            `json:"canonical_name"`
 	PrimaryLanguage string            `json:"primary_language"`
+	License         string            `json:"license"`
+	Topics          []string          `json:"topics"`
+	Archived        bool              `json:"archived"`
+	Disabled        bool              `json:"disabled"`
+	DefaultBranch   string            `json:"default_branch"`
+	PushedAt        string            `json:"pushed_at"`
+	OpenIssues      int32             `json:"open_issues"`
+	LatestRelease   string            `json:"latest_release"`
 }
 
 // ReadFile reads and parses a repository metadata JSON file into a RepositoryMetadata protobuf
@@ -36,11 +45,20 @@ func ReadFile(filename string) (*bzpb.RepositoryMetadata, error) {
 	// Convert to proto
 	md := &bzpb.RepositoryMetadata{
 		CanonicalName:   jsonMeta.CanonicalName,
+		Host:            jsonMeta.Host,
 		Organization:    jsonMeta.Organization,
 		Name:            jsonMeta.Name,
 		Description:     jsonMeta.Description,
 		PrimaryLanguage: jsonMeta.PrimaryLanguage,
 		Stargazers:      jsonMeta.Stargazers,
+		License:         jsonMeta.License,
+		Topics:          jsonMeta.Topics,
+		Archived:        jsonMeta.Archived,
+		Disabled:        jsonMeta.Disabled,
+		DefaultBranch:   jsonMeta.DefaultBranch,
+		PushedAt:        jsonMeta.PushedAt,
+		OpenIssues:      jsonMeta.OpenIssues,
+		LatestRelease:   jsonMeta.LatestRelease,
 	}
 
 	// Parse type string to enum
@@ -49,6 +67,12 @@ func ReadFile(filename string) (*bzpb.RepositoryMetadata, error) {
 		md.Type = bzpb.RepositoryType_GITHUB
 	case "GITLAB", "gitlab":
 		md.Type = bzpb.RepositoryType_GITLAB
+	case "GITEA", "gitea":
+		md.Type = bzpb.RepositoryType_GITEA
+	case "BITBUCKET", "bitbucket":
+		md.Type = bzpb.RepositoryType_BITBUCKET
+	case "SOURCEHUT", "sourcehut":
+		md.Type = bzpb.RepositoryType_SOURCEHUT
 	case "REPOSITORY_TYPE_UNKNOWN", "":
 		md.Type = bzpb.RepositoryType_REPOSITORY_TYPE_UNKNOWN
 	default:
@@ -0,0 +1,354 @@
+package sigstore
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// InTotoPayloadType is the DSSE payload type of in-toto statements.
+const InTotoPayloadType = "application/vnd.in-toto+json"
+
+// ErrMalformedBundle is returned (wrapped) for bundles that cannot be parsed.
+var ErrMalformedBundle = errors.New("malformed bundle")
+
+// Statement is an in-toto statement, the signed payload of a bundle.
+type Statement struct {
+	Type          string          `json:"_type"`
+	Subject       []Subject       `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// Subject is an artifact attested by a statement.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// HasSubjectDigest reports whether the statement attests an artifact with
+// the given sha256 digest.
+func (s *Statement) HasSubjectDigest(sha256Digest []byte) bool {
+	want := hex.EncodeToString(sha256Digest)
+	for _, subject := range s.Subject {
+		if subject.Digest["sha256"] == want {
+			return true
+		}
+	}
+	return false
+}
+
+// bundleJSON is the JSON encoding of a sigstore bundle with a DSSE envelope
+// (application/vnd.dev.sigstore.bundle+json, versions 0.1 to 0.3).
+type bundleJSON struct {
+	MediaType            string `json:"mediaType"`
+	VerificationMaterial struct {
+		Certificate          *rawBytesJSON `json:"certificate"`
+		X509CertificateChain *struct {
+			Certificates []rawBytesJSON `json:"certificates"`
+		} `json:"x509CertificateChain"`
+		TlogEntries []tlogEntryJSON `json:"tlogEntries"`
+	} `json:"verificationMaterial"`
+	DSSEEnvelope *struct {
+		Payload     []byte `json:"payload"`
+		PayloadType string `json:"payloadType"`
+		Signatures  []struct {
+			Sig []byte `json:"sig"`
+		} `json:"signatures"`
+	} `json:"dsseEnvelope"`
+}
+
+type tlogEntryJSON struct {
+	LogIndex int64 `json:"logIndex,string"`
+	LogID    struct {
+		KeyID []byte `json:"keyId"`
+	} `json:"logId"`
+	IntegratedTime   int64 `json:"integratedTime,string"`
+	InclusionPromise *struct {
+		SignedEntryTimestamp []byte `json:"signedEntryTimestamp"`
+	} `json:"inclusionPromise"`
+	CanonicalizedBody []byte `json:"canonicalizedBody"`
+}
+
+// VerifyBundle verifies a sigstore bundle against the trusted root and
+// returns the signed statement, and the identity of the signing certificate.
+// The bundle must be logged in a trusted transparency log (the signed entry
+// timestamp is checked, the inclusion proof is not) with its payload,
+// signature and signing certificate, its signing certificate
+// must chain up to a trusted certificate authority at the time it was
+// logged, and the certificate must have signed the DSSE envelope.  Any
+// certificate of a trusted authority verifies: the caller checks whom it was
+// issued to (see IdentityPolicy).
+//
+// The content of the first line is verified if data is a JSON lines file.
+func VerifyBundle(data []byte, root *TrustedRoot) (*Statement, *CertificateIdentity, error) {
+	if line, _, ok := bytes.Cut(bytes.TrimSpace(data), []byte("\n")); ok {
+		data = line
+	}
+
+	var bundle bundleJSON
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrMalformedBundle, err)
+	}
+	envelope := bundle.DSSEEnvelope
+	if envelope == nil || len(envelope.Signatures) == 0 {
+		return nil, nil, fmt.Errorf("%w: no signed DSSE envelope", ErrMalformedBundle)
+	}
+
+	var rawCerts []rawBytesJSON
+	if material := bundle.VerificationMaterial; material.Certificate != nil {
+		rawCerts = []rawBytesJSON{*material.Certificate}
+	} else if material.X509CertificateChain != nil {
+		rawCerts = material.X509CertificateChain.Certificates
+	}
+	certs, err := parseCertificates(rawCerts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrMalformedBundle, err)
+	}
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("%w: no signing certificate", ErrMalformedBundle)
+	}
+	leaf := certs[0]
+	identity, err := parseCertificateIdentity(leaf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrMalformedBundle, err)
+	}
+
+	payloadHash := sha256.Sum256(envelope.Payload)
+	var sigs [][]byte
+	for _, sig := range envelope.Signatures {
+		sigs = append(sigs, sig.Sig)
+	}
+	signedAt, err := verifyTlogEntries(bundle.VerificationMaterial.TlogEntries, root, payloadHash[:], sigs, leaf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := verifyCertificate(leaf, certs[1:], root, signedAt); err != nil {
+		return nil, nil, err
+	}
+
+	pae := preAuthEncoding(envelope.PayloadType, envelope.Payload)
+	var sigErr error
+	for _, sig := range envelope.Signatures {
+		if sigErr = verifySignature(leaf.PublicKey, pae, sig.Sig); sigErr == nil {
+			break
+		}
+	}
+	if sigErr != nil {
+		return nil, nil, fmt.Errorf("DSSE signature: %w", sigErr)
+	}
+
+	if envelope.PayloadType != InTotoPayloadType {
+		return nil, nil, fmt.Errorf("%w: unexpected payload type %q", ErrMalformedBundle, envelope.PayloadType)
+	}
+	var statement Statement
+	if err := json.Unmarshal(envelope.Payload, &statement); err != nil {
+		return nil, nil, fmt.Errorf("%w: parsing statement: %v", ErrMalformedBundle, err)
+	}
+	return &statement, identity, nil
+}
+
+// verifyTlogEntries checks that one of the entries was promised inclusion by
+// a trusted transparency log, and returns the time it was logged.
+func verifyTlogEntries(entries []tlogEntryJSON, root *TrustedRoot, payloadHash []byte, sigs [][]byte, leaf *x509.Certificate) (time.Time, error) {
+	if len(entries) == 0 {
+		return time.Time{}, fmt.Errorf("no transparency log entry")
+	}
+	var errs []error
+	for _, entry := range entries {
+		t, err := verifyTlogEntry(entry, root, payloadHash, sigs, leaf)
+		if err == nil {
+			return t, nil
+		}
+		errs = append(errs, err)
+	}
+	return time.Time{}, errors.Join(errs...)
+}
+
+func verifyTlogEntry(entry tlogEntryJSON, root *TrustedRoot, payloadHash []byte, sigs [][]byte, leaf *x509.Certificate) (time.Time, error) {
+	logID := hex.EncodeToString(entry.LogID.KeyID)
+	tlog, ok := root.tlogs[logID]
+	if !ok {
+		return time.Time{}, fmt.Errorf("transparency log %s is not trusted", logID)
+	}
+	integratedTime := time.Unix(entry.IntegratedTime, 0)
+	if !tlog.validFor.contains(integratedTime) {
+		return time.Time{}, fmt.Errorf("transparency log %s was not valid at %v", logID, integratedTime)
+	}
+	if entry.InclusionPromise == nil {
+		return time.Time{}, fmt.Errorf("transparency log entry %d has no inclusion promise", entry.LogIndex)
+	}
+
+	// The signed entry timestamp signs the canonical JSON of these fields
+	// (in this order)
+	set, err := json.Marshal(struct {
+		Body           []byte `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{entry.CanonicalizedBody, entry.IntegratedTime, logID, entry.LogIndex})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := verifySignature(tlog.publicKey, set, entry.InclusionPromise.SignedEntryTimestamp); err != nil {
+		return time.Time{}, fmt.Errorf("signed entry timestamp of transparency log entry %d: %w", entry.LogIndex, err)
+	}
+
+	if err := checkTlogBody(entry.CanonicalizedBody, payloadHash, sigs, leaf); err != nil {
+		return time.Time{}, fmt.Errorf("transparency log entry %d: %w", entry.LogIndex, err)
+	}
+	return integratedTime, nil
+}
+
+// checkTlogBody checks that the logged entry (of kind dsse or intoto) is the
+// one of the envelope: the same payload, signed with one of the envelope
+// signatures by the leaf certificate.
+func checkTlogBody(body, payloadHash []byte, sigs [][]byte, leaf *x509.Certificate) error {
+	type hash struct {
+		Algorithm string `json:"algorithm"`
+		Value     string `json:"value"`
+	}
+	// the certificates are PEM-encoded; the signatures of intoto entries
+	// are base64-encoded twice
+	type signature struct {
+		// dsse
+		Signature []byte `json:"signature"`
+		Verifier  []byte `json:"verifier"`
+		// intoto
+		Sig       []byte `json:"sig"`
+		PublicKey []byte `json:"publicKey"`
+	}
+	var entry struct {
+		Kind string `json:"kind"`
+		Spec struct {
+			// dsse
+			PayloadHash *hash       `json:"payloadHash"`
+			Signatures  []signature `json:"signatures"`
+			// intoto
+			Content struct {
+				PayloadHash *hash `json:"payloadHash"`
+				Envelope    struct {
+					Signatures []signature `json:"signatures"`
+				} `json:"envelope"`
+			} `json:"content"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return fmt.Errorf("parsing body: %v", err)
+	}
+	logged := entry.Spec.PayloadHash
+	if logged == nil {
+		logged = entry.Spec.Content.PayloadHash
+	}
+	if logged == nil {
+		return fmt.Errorf("unsupported entry kind %q", entry.Kind)
+	}
+	if logged.Algorithm != "sha256" || logged.Value != hex.EncodeToString(payloadHash) {
+		return fmt.Errorf("the logged payload hash does not match the envelope")
+	}
+
+	for _, s := range slices.Concat(entry.Spec.Signatures, entry.Spec.Content.Envelope.Signatures) {
+		sig, cert := s.Signature, s.Verifier
+		if s.Sig != nil {
+			decoded, err := base64.StdEncoding.DecodeString(string(s.Sig))
+			if err != nil {
+				continue
+			}
+			sig, cert = decoded, s.PublicKey
+		}
+		block, _ := pem.Decode(cert)
+		if block == nil || !bytes.Equal(block.Bytes, leaf.Raw) {
+			continue
+		}
+		if slices.ContainsFunc(sigs, func(envelopeSig []byte) bool { return bytes.Equal(envelopeSig, sig) }) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the logged signature or certificate does not match the envelope")
+}
+
+// verifyCertificate checks that the signing certificate was issued by a
+// trusted certificate authority and was valid at the given time.
+func verifyCertificate(leaf *x509.Certificate, chain []*x509.Certificate, root *TrustedRoot, at time.Time) error {
+	var errs []error
+	for _, ca := range root.certificateAuthorities {
+		if !ca.validFor.contains(at) {
+			continue
+		}
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.root)
+		intermediates := x509.NewCertPool()
+		for _, cert := range slices.Concat(ca.intermediates, chain) {
+			intermediates.AddCert(cert)
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   at,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		})
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("no certificate authority was valid at %v", at)
+	}
+	return fmt.Errorf("signing certificate: %w", errors.Join(errs...))
+}
+
+// preAuthEncoding returns the DSSE pre-authentication encoding of the
+// payload, which is what the signatures sign.
+func preAuthEncoding(payloadType string, payload []byte) []byte {
+	return fmt.Appendf(nil, "DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload)
+}
+
+// verifySignature verifies the signature of the message by the public key
+// (ECDSA with the hash of its curve size, Ed25519, or RSA PKCS #1 v1.5 with
+// SHA-256).
+func verifySignature(publicKey crypto.PublicKey, message, sig []byte) error {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		var digest []byte
+		switch key.Curve {
+		case elliptic.P384():
+			h := sha512.Sum384(message)
+			digest = h[:]
+		case elliptic.P521():
+			h := sha512.Sum512(message)
+			digest = h[:]
+		default:
+			h := sha256.Sum256(message)
+			digest = h[:]
+		}
+		if !ecdsa.VerifyASN1(key, digest, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, message, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		h := sha256.Sum256(message)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, h[:], sig)
+	}
+	return fmt.Errorf("unsupported public key type %T", publicKey)
+}
@@ -0,0 +1,136 @@
+package sigstore
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GitHubActionsIssuer is the OIDC issuer of the GitHub Actions workflow
+// tokens that Fulcio issues certificates for.
+const GitHubActionsIssuer = "https://token.actions.githubusercontent.com"
+
+// OIDs of the Fulcio certificate extensions
+// (https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md)
+var (
+	// the deprecated issuer extension holds the raw string, the others
+	// hold a DER-encoded UTF8String
+	oidIssuer                 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2               = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+	oidBuildSignerURI         = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 9}
+	oidSourceRepositoryURI    = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 12}
+	oidSourceRepositoryDigest = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 13}
+	oidSourceRepositoryRef    = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 14}
+	oidBuildConfigURI         = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 18}
+)
+
+// CertificateIdentity is the identity a signing certificate was issued to.
+type CertificateIdentity struct {
+	// Issuer is the OIDC issuer of the token the certificate was issued
+	// for, e.g. GitHubActionsIssuer.
+	Issuer string
+	// SubjectAlternativeName is the URI or email the certificate was issued
+	// to.  For GitHub Actions, it is the ref of the workflow that signed,
+	// which is the reusable workflow when the run calls one
+	// ("https://github.com/bazel-contrib/publish-to-bcr/.github/workflows/publish.yaml@refs/tags/v0.2.2").
+	SubjectAlternativeName string
+
+	// The build claims of CI certificates, which Fulcio copies from the
+	// token: the workflow that signed (e.g. the reusable workflow of
+	// publish-to-bcr), the repository and commit it ran for, and the
+	// workflow that started the run
+	// ("https://github.com/org/repo/.github/workflows/release.yml@refs/tags/v1.0.0").
+	BuildSignerURI         string
+	SourceRepositoryURI    string
+	SourceRepositoryDigest string
+	SourceRepositoryRef    string
+	BuildConfigURI         string
+}
+
+// String returns the identity as "SAN (issued by ISSUER)".
+func (id *CertificateIdentity) String() string {
+	return fmt.Sprintf("%s (issued by %s)", id.SubjectAlternativeName, id.Issuer)
+}
+
+// parseCertificateIdentity reads the identity from the subject alternative
+// name and the Fulcio extensions of the certificate.
+func parseCertificateIdentity(cert *x509.Certificate) (*CertificateIdentity, error) {
+	id := &CertificateIdentity{}
+	switch {
+	case len(cert.URIs) > 0:
+		id.SubjectAlternativeName = cert.URIs[0].String()
+	case len(cert.EmailAddresses) > 0:
+		id.SubjectAlternativeName = cert.EmailAddresses[0]
+	}
+
+	for _, ext := range cert.Extensions {
+		var value *string
+		switch {
+		case ext.Id.Equal(oidIssuer):
+			if id.Issuer == "" {
+				id.Issuer = string(ext.Value)
+			}
+			continue
+		case ext.Id.Equal(oidIssuerV2):
+			value = &id.Issuer
+		case ext.Id.Equal(oidBuildSignerURI):
+			value = &id.BuildSignerURI
+		case ext.Id.Equal(oidSourceRepositoryURI):
+			value = &id.SourceRepositoryURI
+		case ext.Id.Equal(oidSourceRepositoryDigest):
+			value = &id.SourceRepositoryDigest
+		case ext.Id.Equal(oidSourceRepositoryRef):
+			value = &id.SourceRepositoryRef
+		case ext.Id.Equal(oidBuildConfigURI):
+			value = &id.BuildConfigURI
+		default:
+			continue
+		}
+		if rest, err := asn1.UnmarshalWithParams(ext.Value, value, "utf8"); err != nil || len(rest) > 0 {
+			return nil, fmt.Errorf("certificate extension %v is not a UTF8String", ext.Id)
+		}
+	}
+	return id, nil
+}
+
+// workflowPath returns the path of the workflow that started the run, e.g.
+// ".github/workflows/release.yml", or "" if the build config is not a
+// workflow of the source repository.
+func (id *CertificateIdentity) workflowPath() string {
+	config, _, _ := strings.Cut(id.BuildConfigURI, "@")
+	path, ok := strings.CutPrefix(config, id.SourceRepositoryURI+"/")
+	if !ok || id.SourceRepositoryURI == "" {
+		return ""
+	}
+	return path
+}
+
+// IdentityPolicy is the expected identity of the signing certificates, like
+// the --certificate-oidc-issuer, --certificate-identity-regexp and
+// --certificate-github-workflow-repository flags of cosign.
+type IdentityPolicy struct {
+	// Issuer is the expected OIDC issuer.
+	Issuer string
+	// SubjectAlternativeName matches the expected subject alternative
+	// names, if set.
+	SubjectAlternativeName *regexp.Regexp
+	// SourceRepository matches the expected source repository URIs, if
+	// set.
+	SourceRepository *regexp.Regexp
+}
+
+// Check returns an error if the identity does not match the policy.
+func (p *IdentityPolicy) Check(id *CertificateIdentity) error {
+	if id.Issuer != p.Issuer {
+		return fmt.Errorf("the signing certificate was issued by %q, want %q", id.Issuer, p.Issuer)
+	}
+	if p.SubjectAlternativeName != nil && !p.SubjectAlternativeName.MatchString(id.SubjectAlternativeName) {
+		return fmt.Errorf("the signing certificate was issued to %q, which does not match %q", id.SubjectAlternativeName, p.SubjectAlternativeName)
+	}
+	if p.SourceRepository != nil && !p.SourceRepository.MatchString(id.SourceRepositoryURI) {
+		return fmt.Errorf("the signing certificate was issued for the source repository %q, which does not match %q", id.SourceRepositoryURI, p.SourceRepository)
+	}
+	return nil
+}
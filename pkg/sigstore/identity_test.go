@@ -0,0 +1,135 @@
+package sigstore
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseCertificateIdentity(t *testing.T) {
+	utf8 := func(s string) []byte {
+		der, err := asn1.MarshalWithParams(s, "utf8")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+	san, _ := url.Parse(testSAN)
+
+	for _, tc := range []struct {
+		name    string
+		cert    *x509.Certificate
+		want    CertificateIdentity
+		wantErr bool
+	}{
+		{
+			name: "workflow",
+			cert: &x509.Certificate{
+				URIs:       []*url.URL{san},
+				Extensions: []pkix.Extension{{Id: oidIssuerV2, Value: utf8(GitHubActionsIssuer)}},
+			},
+			want: CertificateIdentity{Issuer: GitHubActionsIssuer, SubjectAlternativeName: testSAN},
+		},
+		{
+			name: "build claims",
+			cert: &x509.Certificate{
+				URIs: []*url.URL{san},
+				Extensions: []pkix.Extension{
+					{Id: oidIssuer, Value: []byte(GitHubActionsIssuer)},
+					{Id: oidIssuerV2, Value: utf8(GitHubActionsIssuer)},
+					{Id: oidBuildSignerURI, Value: utf8(testIdentity.BuildSignerURI)},
+					{Id: oidSourceRepositoryURI, Value: utf8(testIdentity.SourceRepositoryURI)},
+					{Id: oidSourceRepositoryDigest, Value: utf8(testIdentity.SourceRepositoryDigest)},
+					{Id: oidSourceRepositoryRef, Value: utf8(testIdentity.SourceRepositoryRef)},
+					{Id: oidBuildConfigURI, Value: utf8(testIdentity.BuildConfigURI)},
+				},
+			},
+			want: testIdentity,
+		},
+		{
+			name: "deprecated issuer extension",
+			cert: &x509.Certificate{
+				EmailAddresses: []string{"someone@example.com"},
+				Extensions:     []pkix.Extension{{Id: oidIssuer, Value: []byte("https://accounts.google.com")}},
+			},
+			want: CertificateIdentity{Issuer: "https://accounts.google.com", SubjectAlternativeName: "someone@example.com"},
+		},
+		{
+			name: "malformed issuer",
+			cert: &x509.Certificate{
+				Extensions: []pkix.Extension{{Id: oidIssuerV2, Value: []byte(GitHubActionsIssuer)}},
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseCertificateIdentity(tc.cert)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tc.want {
+				t.Errorf("parseCertificateIdentity() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCertificateIdentityWorkflowPath(t *testing.T) {
+	for _, tc := range []struct {
+		id   CertificateIdentity
+		want string
+	}{
+		{id: testIdentity, want: ".github/workflows/release.yml"},
+		{id: CertificateIdentity{SourceRepositoryURI: "https://github.com/org/rules_foo", BuildConfigURI: "https://github.com/org/rules_bar/.github/workflows/release.yml@refs/heads/main"}},
+		{id: CertificateIdentity{BuildConfigURI: testIdentity.BuildConfigURI}},
+	} {
+		if got := tc.id.workflowPath(); got != tc.want {
+			t.Errorf("workflowPath(%s) = %q, want %q", tc.id.BuildConfigURI, got, tc.want)
+		}
+	}
+}
+
+func TestIdentityPolicyCheck(t *testing.T) {
+	policy := &IdentityPolicy{
+		Issuer:                 GitHubActionsIssuer,
+		SubjectAlternativeName: regexp.MustCompile(`^https://github\.com/(?:org/rules_foo|bazel-contrib/publish-to-bcr)/`),
+		SourceRepository:       regexp.MustCompile(`^https://github\.com/org/rules_foo$`),
+	}
+	withSAN := func(san string) CertificateIdentity {
+		id := testIdentity
+		id.SubjectAlternativeName = san
+		return id
+	}
+	withSourceRepository := func(uri string) CertificateIdentity {
+		id := testIdentity
+		id.SourceRepositoryURI = uri
+		return id
+	}
+	for _, tc := range []struct {
+		id      CertificateIdentity
+		wantErr string
+	}{
+		{id: testIdentity},
+		{id: withSAN(testIdentity.BuildConfigURI)},
+		{id: CertificateIdentity{Issuer: "https://gitlab.com", SubjectAlternativeName: testSAN, SourceRepositoryURI: testIdentity.SourceRepositoryURI}, wantErr: "issued by"},
+		{id: withSAN("https://github.com/org/rules_foobar/.github/workflows/release.yml@refs/tags/v1.0.0"), wantErr: "does not match"},
+		{id: withSourceRepository("https://github.com/org/rules_foobar"), wantErr: "source repository"},
+		{id: withSourceRepository(""), wantErr: "source repository"},
+		{id: CertificateIdentity{Issuer: GitHubActionsIssuer}, wantErr: "does not match"},
+	} {
+		err := policy.Check(&tc.id)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Errorf("Check(%v) = %v, want %q", &tc.id, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package sigstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+// Predicate types of the SLSA provenance
+const (
+	SLSAProvenanceV1  = "https://slsa.dev/provenance/v1"
+	SLSAProvenanceV02 = "https://slsa.dev/provenance/v0.2"
+)
+
+// digestJSON is the digest set of a resource descriptor.
+type digestJSON map[string]string
+
+// gitCommit returns the git commit of the digest set, if any.
+func (d digestJSON) gitCommit() string {
+	if commit := d["gitCommit"]; commit != "" {
+		return commit
+	}
+	return d["sha1"]
+}
+
+// slsaV1JSON is the predicate of SLSA provenance v1, with the external
+// parameters of the GitHub Actions build type
+// (https://actions.github.io/buildtypes/workflow/v1).
+type slsaV1JSON struct {
+	BuildDefinition struct {
+		ExternalParameters struct {
+			Workflow struct {
+				Ref        string `json:"ref"`
+				Repository string `json:"repository"`
+				Path       string `json:"path"`
+			} `json:"workflow"`
+		} `json:"externalParameters"`
+		ResolvedDependencies []struct {
+			URI    string     `json:"uri"`
+			Digest digestJSON `json:"digest"`
+		} `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+		Metadata struct {
+			InvocationID string `json:"invocationId"`
+		} `json:"metadata"`
+	} `json:"runDetails"`
+}
+
+// slsaV02JSON is the predicate of SLSA provenance v0.2.
+type slsaV02JSON struct {
+	Builder struct {
+		ID string `json:"id"`
+	} `json:"builder"`
+	Invocation struct {
+		ConfigSource struct {
+			URI        string     `json:"uri"`
+			Digest     digestJSON `json:"digest"`
+			EntryPoint string     `json:"entryPoint"`
+		} `json:"configSource"`
+	} `json:"invocation"`
+	Metadata struct {
+		BuildInvocationID string `json:"buildInvocationId"`
+	} `json:"metadata"`
+}
+
+// ParseProvenance returns the build provenance of a statement with a SLSA
+// provenance predicate (v0.2 or v1), or nil if the predicate is of another
+// type.
+func ParseProvenance(statement *Statement) (*bzpb.SlsaProvenance, error) {
+	provenance := &bzpb.SlsaProvenance{PredicateType: statement.PredicateType}
+
+	switch statement.PredicateType {
+	case SLSAProvenanceV1:
+		var predicate slsaV1JSON
+		if err := json.Unmarshal(statement.Predicate, &predicate); err != nil {
+			return nil, fmt.Errorf("parsing %s predicate: %v", statement.PredicateType, err)
+		}
+		workflow := predicate.BuildDefinition.ExternalParameters.Workflow
+		provenance.BuilderId = predicate.RunDetails.Builder.ID
+		provenance.SourceRepository = workflow.Repository
+		provenance.SourceRef = workflow.Ref
+		provenance.WorkflowPath = workflow.Path
+		provenance.BuildInvocationId = predicate.RunDetails.Metadata.InvocationID
+		// The source is the first git dependency (the one of the workflow
+		// for the GitHub Actions build type)
+		for _, dep := range predicate.BuildDefinition.ResolvedDependencies {
+			repository, ref, ok := parseGitURI(dep.URI)
+			if !ok {
+				continue
+			}
+			if provenance.SourceRepository == "" {
+				provenance.SourceRepository = repository
+			}
+			if provenance.SourceRef == "" {
+				provenance.SourceRef = ref
+			}
+			provenance.SourceCommit = dep.Digest.gitCommit()
+			break
+		}
+
+	case SLSAProvenanceV02:
+		var predicate slsaV02JSON
+		if err := json.Unmarshal(statement.Predicate, &predicate); err != nil {
+			return nil, fmt.Errorf("parsing %s predicate: %v", statement.PredicateType, err)
+		}
+		configSource := predicate.Invocation.ConfigSource
+		provenance.BuilderId = predicate.Builder.ID
+		provenance.SourceRepository, provenance.SourceRef, _ = parseGitURI(configSource.URI)
+		provenance.SourceCommit = configSource.Digest.gitCommit()
+		provenance.WorkflowPath = configSource.EntryPoint
+		provenance.BuildInvocationId = predicate.Metadata.BuildInvocationID
+
+	default:
+		return nil, nil
+	}
+
+	return provenance, nil
+}
+
+// ErrProvenanceMismatch is returned by VerifyProvenance when the predicate
+// of a statement disagrees with the certificate that signed it.
+var ErrProvenanceMismatch = errors.New("the provenance disagrees with the signing certificate")
+
+// VerifyProvenance returns the build provenance of a verified statement (see
+// ParseProvenance), bound to the certificate that signed it.  The predicate
+// is written by the signer; the source repository, ref, commit and workflow
+// are taken from the build claims of the certificate instead, which Fulcio
+// copies from the OIDC token of the run.  A predicate that states other
+// values is rejected with ErrProvenanceMismatch.  Claims that are not in the
+// certificate are left empty.  The builder is recorded as stated: it names
+// the build platform (e.g. https://github.com/actions/runner/github-hosted
+// for actions/attest-build-provenance), which the certificate has no claim
+// for.
+func VerifyProvenance(statement *Statement, signer *CertificateIdentity) (*bzpb.SlsaProvenance, error) {
+	provenance, err := ParseProvenance(statement)
+	if provenance == nil || err != nil {
+		return provenance, err
+	}
+
+	for _, claim := range []struct {
+		name            string
+		predicate       *string
+		certificate     string
+		caseInsensitive bool
+	}{
+		{name: "source repository", predicate: &provenance.SourceRepository, certificate: signer.SourceRepositoryURI},
+		{name: "source ref", predicate: &provenance.SourceRef, certificate: signer.SourceRepositoryRef},
+		{name: "source commit", predicate: &provenance.SourceCommit, certificate: signer.SourceRepositoryDigest, caseInsensitive: true},
+		{name: "workflow", predicate: &provenance.WorkflowPath, certificate: signer.workflowPath()},
+	} {
+		got := *claim.predicate
+		switch {
+		case got == "" || got == claim.certificate || claim.caseInsensitive && strings.EqualFold(got, claim.certificate):
+		case claim.certificate == "":
+			return nil, fmt.Errorf("%w: the %s is %q, the certificate has none", ErrProvenanceMismatch, claim.name, got)
+		default:
+			return nil, fmt.Errorf("%w: the %s is %q, the certificate has %q", ErrProvenanceMismatch, claim.name, got, claim.certificate)
+		}
+		*claim.predicate = claim.certificate
+	}
+	return provenance, nil
+}
+
+// parseGitURI splits a git resource URI such as
+// "git+https://github.com/org/repo@refs/tags/v1.0.0" into the repository URL
+// and the ref.
+func parseGitURI(uri string) (repository, ref string, ok bool) {
+	rest, ok := strings.CutPrefix(uri, "git+")
+	if !ok {
+		return "", "", false
+	}
+	// the ref follows the last '@' of the path (the host may contain one)
+	scheme, path, ok := strings.Cut(rest, "://")
+	if !ok {
+		return "", "", false
+	}
+	if i := strings.LastIndex(path, "@"); i > strings.Index(path, "/") {
+		path, ref = path[:i], path[i+1:]
+	}
+	return scheme + "://" + strings.TrimSuffix(path, ".git"), ref, true
+}
@@ -0,0 +1,234 @@
+package sigstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/archive"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseProvenance(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		predicateType string
+		predicate     string
+		want          *bzpb.SlsaProvenance
+	}{
+		{
+			name:          "github actions v1",
+			predicateType: SLSAProvenanceV1,
+			predicate: `{
+				"buildDefinition": {
+					"buildType": "https://actions.github.io/buildtypes/workflow/v1",
+					"externalParameters": {
+						"workflow": {
+							"ref": "refs/tags/v1.2.0",
+							"repository": "https://github.com/org/rules_foo",
+							"path": ".github/workflows/release.yml"
+						}
+					},
+					"internalParameters": {"github": {"event_name": "push"}},
+					"resolvedDependencies": [{
+						"uri": "git+https://github.com/org/rules_foo@refs/tags/v1.2.0",
+						"digest": {"gitCommit": "0123456789abcdef0123456789abcdef01234567"}
+					}]
+				},
+				"runDetails": {
+					"builder": {"id": "https://github.com/bazel-contrib/publish-to-bcr/.github/workflows/publish.yaml@refs/tags/v0.2.2"},
+					"metadata": {"invocationId": "https://github.com/org/rules_foo/actions/runs/1234/attempts/1"}
+				}
+			}`,
+			want: &bzpb.SlsaProvenance{
+				PredicateType:     SLSAProvenanceV1,
+				BuilderId:         "https://github.com/bazel-contrib/publish-to-bcr/.github/workflows/publish.yaml@refs/tags/v0.2.2",
+				SourceRepository:  "https://github.com/org/rules_foo",
+				SourceRef:         "refs/tags/v1.2.0",
+				SourceCommit:      "0123456789abcdef0123456789abcdef01234567",
+				WorkflowPath:      ".github/workflows/release.yml",
+				BuildInvocationId: "https://github.com/org/rules_foo/actions/runs/1234/attempts/1",
+			},
+		},
+		{
+			name:          "v1 without workflow parameters",
+			predicateType: SLSAProvenanceV1,
+			predicate: `{
+				"buildDefinition": {
+					"buildType": "https://example.com/buildtypes/make/v1",
+					"resolvedDependencies": [
+						{"uri": "pkg:generic/make@4.4", "digest": {"sha256": "00"}},
+						{"uri": "git+https://gitlab.com/org/rules_foo.git@v1.2.0", "digest": {"sha1": "89abcdef0123456789abcdef0123456789abcdef"}}
+					]
+				},
+				"runDetails": {"builder": {"id": "https://example.com/builder"}}
+			}`,
+			want: &bzpb.SlsaProvenance{
+				PredicateType:    SLSAProvenanceV1,
+				BuilderId:        "https://example.com/builder",
+				SourceRepository: "https://gitlab.com/org/rules_foo",
+				SourceRef:        "v1.2.0",
+				SourceCommit:     "89abcdef0123456789abcdef0123456789abcdef",
+			},
+		},
+		{
+			name:          "slsa-github-generator v0.2",
+			predicateType: SLSAProvenanceV02,
+			predicate: `{
+				"builder": {"id": "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@refs/tags/v2.0.0"},
+				"buildType": "https://github.com/slsa-framework/slsa-github-generator/generic@v1",
+				"invocation": {
+					"configSource": {
+						"uri": "git+https://github.com/org/rules_foo@refs/tags/v1.2.0",
+						"digest": {"sha1": "0123456789abcdef0123456789abcdef01234567"},
+						"entryPoint": ".github/workflows/release.yml"
+					}
+				},
+				"metadata": {"buildInvocationId": "1234-1"}
+			}`,
+			want: &bzpb.SlsaProvenance{
+				PredicateType:     SLSAProvenanceV02,
+				BuilderId:         "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@refs/tags/v2.0.0",
+				SourceRepository:  "https://github.com/org/rules_foo",
+				SourceRef:         "refs/tags/v1.2.0",
+				SourceCommit:      "0123456789abcdef0123456789abcdef01234567",
+				WorkflowPath:      ".github/workflows/release.yml",
+				BuildInvocationId: "1234-1",
+			},
+		},
+		{
+			name:          "other predicate type",
+			predicateType: "https://in-toto.io/attestation/release/v0.1",
+			predicate:     `{"purl": "pkg:bazel/rules_foo@1.2.0"}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseProvenance(&Statement{PredicateType: tc.predicateType, Predicate: json.RawMessage(tc.predicate)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, tc.want) {
+				t.Errorf("ParseProvenance() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := ParseProvenance(&Statement{PredicateType: SLSAProvenanceV1, Predicate: json.RawMessage(`[]`)}); err == nil {
+		t.Errorf("expected an error for a malformed predicate")
+	}
+}
+
+func TestVerifyProvenance(t *testing.T) {
+	pki := newTestPKI(t)
+	statement, digest := testStatement(`{"url": "https://example.com/a.tar.gz"}`)
+	v := &Verifier{
+		TrustedRoot: pki.trustedRoot(t),
+		Identity: &IdentityPolicy{
+			Issuer:                 GitHubActionsIssuer,
+			SubjectAlternativeName: regexp.MustCompile(`^https://github\.com/bazel-contrib/publish-to-bcr/`),
+		},
+	}
+	// the builder of actions/attest-build-provenance
+	const githubHosted = "https://github.com/actions/runner/github-hosted"
+	predicate := func(builderID, commit string) json.RawMessage {
+		return json.RawMessage(`{
+			"buildDefinition": {
+				"externalParameters": {"workflow": {
+					"ref": "refs/tags/v1.0.0",
+					"repository": "https://github.com/org/rules_foo",
+					"path": ".github/workflows/release.yml"
+				}},
+				"resolvedDependencies": [{
+					"uri": "git+https://github.com/org/rules_foo@refs/tags/v1.0.0",
+					"digest": {"gitCommit": "` + commit + `"}
+				}]
+			},
+			"runDetails": {"builder": {"id": "` + builderID + `"}}
+		}`)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		predicate json.RawMessage
+		identity  *CertificateIdentity
+		want      *bzpb.SlsaProvenance
+		wantErr   string
+	}{
+		{
+			name:      "matches the certificate",
+			predicate: predicate(githubHosted, strings.ToUpper(testIdentity.SourceRepositoryDigest)),
+			want: &bzpb.SlsaProvenance{
+				PredicateType:    SLSAProvenanceV1,
+				BuilderId:        githubHosted,
+				SourceRepository: "https://github.com/org/rules_foo",
+				SourceRef:        "refs/tags/v1.0.0",
+				SourceCommit:     testIdentity.SourceRepositoryDigest,
+				WorkflowPath:     ".github/workflows/release.yml",
+			},
+		},
+		{
+			name:      "records the builder as stated",
+			predicate: predicate("https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@refs/tags/v2.0.0", testIdentity.SourceRepositoryDigest),
+			want: &bzpb.SlsaProvenance{
+				PredicateType:    SLSAProvenanceV1,
+				BuilderId:        "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@refs/tags/v2.0.0",
+				SourceRepository: "https://github.com/org/rules_foo",
+				SourceRef:        "refs/tags/v1.0.0",
+				SourceCommit:     testIdentity.SourceRepositoryDigest,
+				WorkflowPath:     ".github/workflows/release.yml",
+			},
+		},
+		{
+			name:      "claims another commit",
+			predicate: predicate(githubHosted, "89abcdef0123456789abcdef0123456789abcdef"),
+			wantErr:   "the source commit is",
+		},
+		{
+			name:      "certificate without build claims",
+			predicate: predicate(githubHosted, testIdentity.SourceRepositoryDigest),
+			identity:  &CertificateIdentity{Issuer: GitHubActionsIssuer, SubjectAlternativeName: testSAN},
+			wantErr:   "the certificate has none",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			statement := *statement
+			statement.Predicate = tc.predicate
+			bundle := pki.bundle(t, &statement, bundleOptions{identity: tc.identity})
+			integrity, err := sri.Compute("sha256", bytes.NewReader(bundle))
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.Bundles = &archive.Cache{Dir: t.TempDir()}
+			if err := v.put(integrity, bundle); err != nil {
+				t.Fatal(err)
+			}
+			att := &bzpb.Attestations_Attestation{Integrity: integrity.String()}
+
+			// the bundle is validly signed, whatever the predicate states
+			result, verified, signer := v.Verify(context.Background(), att, digest)
+			if result.Status != bzpb.AttestationVerificationStatus_ATTESTATION_VERIFIED {
+				t.Fatalf("Verify() = %v (%s)", result.Status, result.Message)
+			}
+
+			got, err := VerifyProvenance(verified, signer)
+			if tc.wantErr != "" {
+				if !errors.Is(err, ErrProvenanceMismatch) || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("VerifyProvenance() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, tc.want) {
+				t.Errorf("VerifyProvenance() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package sigstore verifies the sigstore bundles listed in the
+// attestations.json files of a registry.  Verification is offline: the trust
+// roots are read from a trusted_root.json file, and the bundles from a local
+// content-addressed directory (or downloaded, and added to it).
+package sigstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/archive"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
+)
+
+const downloadTimeout = time.Minute
+
+// maxBundleSize limits the size of downloaded bundles.
+const maxBundleSize = 10 << 20
+
+// Verifier verifies the attestations of a module version.
+type Verifier struct {
+	// TrustedRoot are the trusted certificate authorities and transparency
+	// logs.
+	TrustedRoot *TrustedRoot
+	// Bundles is the optional directory of bundles, in the layout of the
+	// archive cache (content_addressable/ALGORITHM/HEXDIGEST/file).
+	Bundles *archive.Cache
+	// Download enables downloading bundles that are not in the directory.
+	Download bool
+	// Client is the http client used for downloads (http.DefaultClient if
+	// nil).
+	Client *http.Client
+	// Identity is the expected identity of the signing certificates.  If
+	// nil, bundles that verify are reported as
+	// ATTESTATION_IDENTITY_UNCHECKED: any certificate of a trusted authority
+	// would do.
+	Identity *IdentityPolicy
+}
+
+// Verify checks the bundle of the attestation: its digest must match the
+// integrity, it must verify against the trusted root (see VerifyBundle), its
+// signing certificate must be issued to the expected identity, and the
+// signed statement must attest the given sha256 digest of the file.  If the
+// digest is nil, the bundle is verified but the subject is reported as
+// unavailable.  The statement, and the identity of its signing certificate,
+// are returned if the bundle verified.
+func (v *Verifier) Verify(ctx context.Context, att *bzpb.Attestations_Attestation, digest []byte) (*bzpb.AttestationVerification, *Statement, *CertificateIdentity) {
+	want, err := sri.Parse(att.Integrity)
+	if err != nil {
+		return &bzpb.AttestationVerification{
+			Status:  bzpb.AttestationVerificationStatus_ATTESTATION_MALFORMED_INTEGRITY,
+			Message: err.Error(),
+		}, nil, nil
+	}
+
+	data, downloaded, err := v.fetch(ctx, want, att.Url)
+	if err != nil {
+		return &bzpb.AttestationVerification{
+			Status:  bzpb.AttestationVerificationStatus_ATTESTATION_UNAVAILABLE,
+			Message: err.Error(),
+		}, nil, nil
+	}
+
+	got, err := sri.Compute(want.Algorithm, bytes.NewReader(data))
+	if err != nil {
+		return &bzpb.AttestationVerification{
+			Status:  bzpb.AttestationVerificationStatus_ATTESTATION_MALFORMED_INTEGRITY,
+			Message: err.Error(),
+		}, nil, nil
+	}
+	if !got.Equal(want) {
+		return &bzpb.AttestationVerification{
+			Status:  bzpb.AttestationVerificationStatus_ATTESTATION_INTEGRITY_MISMATCH,
+			Message: fmt.Sprintf("the bundle has integrity %s", got),
+		}, nil, nil
+	}
+
+	// only bundles that match their integrity may enter the directory (best
+	// effort, the bundle is verified either way)
+	if downloaded && v.Bundles != nil {
+		v.put(want, data)
+	}
+
+	statement, identity, err := VerifyBundle(data, v.TrustedRoot)
+	if errors.Is(err, ErrMalformedBundle) {
+		return &bzpb.AttestationVerification{
+			Status:  bzpb.AttestationVerificationStatus_ATTESTATION_MALFORMED_BUNDLE,
+			Message: err.Error(),
+		}, nil, nil
+	}
+	if err != nil {
+		return &bzpb.AttestationVerification{
+			Status:  bzpb.AttestationVerificationStatus_ATTESTATION_SIGNATURE_INVALID,
+			Message: err.Error(),
+		}, nil, nil
+	}
+
+	if v.Identity != nil {
+		if err := v.Identity.Check(identity); err != nil {
+			return &bzpb.AttestationVerification{
+				Status:  bzpb.AttestationVerificationStatus_ATTESTATION_IDENTITY_MISMATCH,
+				Message: err.Error(),
+			}, nil, nil
+		}
+	}
+
+	if digest == nil {
+		return &bzpb.AttestationVerification{
+			Status:  bzpb.AttestationVerificationStatus_ATTESTATION_SUBJECT_UNAVAILABLE,
+			Message: "the digest of the attested file is not known",
+		}, statement, identity
+	}
+	if !statement.HasSubjectDigest(digest) {
+		var names []string
+		for _, subject := range statement.Subject {
+			names = append(names, subject.Name)
+		}
+		return &bzpb.AttestationVerification{
+			Status:  bzpb.AttestationVerificationStatus_ATTESTATION_SUBJECT_MISMATCH,
+			Message: fmt.Sprintf("the statement does not attest the digest of the file (subjects: %s)", strings.Join(names, ", ")),
+		}, statement, identity
+	}
+
+	if v.Identity == nil {
+		return &bzpb.AttestationVerification{
+			Status:  bzpb.AttestationVerificationStatus_ATTESTATION_IDENTITY_UNCHECKED,
+			Message: fmt.Sprintf("the signing certificate was issued to %s, which is not checked", identity),
+		}, statement, identity
+	}
+	return &bzpb.AttestationVerification{Status: bzpb.AttestationVerificationStatus_ATTESTATION_VERIFIED}, statement, identity
+}
+
+// fetch returns the content of the bundle, from the directory or downloaded.
+func (v *Verifier) fetch(ctx context.Context, integrity *sri.Integrity, url string) (data []byte, downloaded bool, err error) {
+	if v.Bundles != nil {
+		if filename, ok := v.Bundles.Get(integrity); ok {
+			data, err := os.ReadFile(filename)
+			return data, false, err
+		}
+	}
+	if !v.Download {
+		return nil, false, fmt.Errorf("%s is not in the bundle directory (downloads are disabled)", integrity)
+	}
+	if url == "" {
+		return nil, false, fmt.Errorf("the attestation has no url")
+	}
+	data, err = v.download(ctx, url)
+	return data, err == nil, err
+}
+
+// download returns the response body of the url.
+func (v *Verifier) download(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Add a User-Agent to avoid being blocked by some servers
+	req.Header.Set("User-Agent", "Bazel-Central-Registry-Gazelle/1.0")
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBundleSize))
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// put adds the bundle to the directory.
+func (v *Verifier) put(integrity *sri.Integrity, data []byte) error {
+	if err := os.MkdirAll(v.Bundles.Dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(v.Bundles.Dir, "bundle-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if _, err := v.Bundles.Put(integrity, f.Name()); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
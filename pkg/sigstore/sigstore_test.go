@@ -0,0 +1,527 @@
+package sigstore
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+	"github.com/bazel-contrib/bcr-frontend/pkg/archive"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
+)
+
+// signedAt is when the test bundles are logged
+var signedAt = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// testPKI is a certificate authority and a transparency log
+type testPKI struct {
+	caKey   *ecdsa.PrivateKey
+	caCert  *x509.Certificate
+	tlogKey *ecdsa.PrivateKey
+	logID   []byte
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	caKey := mustGenerateKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             signedAt.AddDate(-1, 0, 0),
+		NotAfter:              signedAt.AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tlogKey := mustGenerateKey(t)
+	publicKey, err := x509.MarshalPKIXPublicKey(&tlogKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logID := sha256.Sum256(publicKey)
+
+	return &testPKI{caKey: caKey, caCert: caCert, tlogKey: tlogKey, logID: logID[:]}
+}
+
+func mustGenerateKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func mustSign(t *testing.T, key *ecdsa.PrivateKey, message []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(message)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+// trustedRoot returns the trusted_root.json of the PKI.
+func (p *testPKI) trustedRoot(t *testing.T) *TrustedRoot {
+	t.Helper()
+	publicKey, err := x509.MarshalPKIXPublicKey(&p.tlogKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := mustMarshal(t, map[string]any{
+		"mediaType": "application/vnd.dev.sigstore.trustedroot+json;version=0.1",
+		"tlogs": []any{map[string]any{
+			"baseUrl":       "https://rekor.example.com",
+			"hashAlgorithm": "SHA2_256",
+			"publicKey": map[string]any{
+				"rawBytes":   publicKey,
+				"keyDetails": "PKIX_ECDSA_P256_SHA_256",
+				"validFor":   map[string]any{"start": signedAt.AddDate(-1, 0, 0)},
+			},
+			"logId": map[string]any{"keyId": p.logID},
+		}},
+		"certificateAuthorities": []any{map[string]any{
+			"uri": "https://fulcio.example.com",
+			"certChain": map[string]any{
+				"certificates": []any{map[string]any{"rawBytes": p.caCert.Raw}},
+			},
+			"validFor": map[string]any{"start": signedAt.AddDate(-1, 0, 0)},
+		}},
+	})
+	root, err := ParseTrustedRoot(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// testSAN is the subject alternative name of the test certificates: the
+// reusable workflow of publish-to-bcr that signs
+const testSAN = "https://github.com/bazel-contrib/publish-to-bcr/.github/workflows/publish.yaml@refs/tags/v0.2.2"
+
+// testIdentity is the identity of the test certificates, with the claims
+// Fulcio issues for a release workflow of the module repository that calls
+// the reusable workflow of publish-to-bcr
+var testIdentity = CertificateIdentity{
+	Issuer:                 GitHubActionsIssuer,
+	SubjectAlternativeName: testSAN,
+	BuildSignerURI:         testSAN,
+	SourceRepositoryURI:    "https://github.com/org/rules_foo",
+	SourceRepositoryDigest: "0123456789abcdef0123456789abcdef01234567",
+	SourceRepositoryRef:    "refs/tags/v1.0.0",
+	BuildConfigURI:         "https://github.com/org/rules_foo/.github/workflows/release.yml@refs/tags/v1.0.0",
+}
+
+type bundleOptions struct {
+	integratedTime time.Time
+	payloadType    string
+	tamperPayload  bool
+	noTlogEntries  bool
+	// intotoEntry logs an entry of kind intoto instead of dsse
+	intotoEntry bool
+	// loggedCertificate and loggedSignature are logged instead of the ones
+	// of the bundle
+	loggedCertificate []byte
+	loggedSignature   []byte
+	// ca issues the signing certificate instead of the PKI (which still
+	// logs the bundle)
+	ca *testPKI
+	// signer signs the envelope instead of the key of the certificate
+	signer *ecdsa.PrivateKey
+	// identity is the identity of the certificate (testIdentity by default)
+	identity *CertificateIdentity
+}
+
+// bundle returns a bundle signing the statement with a short-lived
+// certificate issued by the PKI.
+func (p *testPKI) bundle(t *testing.T, statement *Statement, opts bundleOptions) []byte {
+	t.Helper()
+	if opts.integratedTime.IsZero() {
+		opts.integratedTime = signedAt
+	}
+	if opts.payloadType == "" {
+		opts.payloadType = InTotoPayloadType
+	}
+	if opts.ca == nil {
+		opts.ca = p
+	}
+	if opts.identity == nil {
+		opts.identity = &testIdentity
+	}
+
+	leafKey := mustGenerateKey(t)
+	san, err := url.Parse(opts.identity.SubjectAlternativeName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var extensions []pkix.Extension
+	for _, ext := range []struct {
+		id    asn1.ObjectIdentifier
+		value string
+	}{
+		{oidIssuerV2, opts.identity.Issuer},
+		{oidBuildSignerURI, opts.identity.BuildSignerURI},
+		{oidSourceRepositoryURI, opts.identity.SourceRepositoryURI},
+		{oidSourceRepositoryDigest, opts.identity.SourceRepositoryDigest},
+		{oidSourceRepositoryRef, opts.identity.SourceRepositoryRef},
+		{oidBuildConfigURI, opts.identity.BuildConfigURI},
+	} {
+		if ext.value == "" {
+			continue
+		}
+		der, err := asn1.MarshalWithParams(ext.value, "utf8")
+		if err != nil {
+			t.Fatal(err)
+		}
+		extensions = append(extensions, pkix.Extension{Id: ext.id, Value: der})
+	}
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       signedAt.Add(-time.Minute),
+		NotAfter:        signedAt.Add(10 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{san},
+		ExtraExtensions: extensions,
+	}
+	leaf, err := x509.CreateCertificate(rand.Reader, template, opts.ca.caCert, &leafKey.PublicKey, opts.ca.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := leafKey
+	if opts.signer != nil {
+		signer = opts.signer
+	}
+	payload := mustMarshal(t, statement)
+	sig := mustSign(t, signer, preAuthEncoding(opts.payloadType, payload))
+	payloadHash := sha256.Sum256(payload)
+
+	loggedCertificate, loggedSignature := leaf, sig
+	if opts.loggedCertificate != nil {
+		loggedCertificate = opts.loggedCertificate
+	}
+	if opts.loggedSignature != nil {
+		loggedSignature = opts.loggedSignature
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: loggedCertificate})
+	kind := "dsse"
+	spec := map[string]any{
+		"payloadHash": map[string]any{"algorithm": "sha256", "value": hex.EncodeToString(payloadHash[:])},
+		"signatures":  []any{map[string]any{"signature": loggedSignature, "verifier": certPEM}},
+	}
+	if opts.intotoEntry {
+		kind = "intoto"
+		spec = map[string]any{"content": map[string]any{
+			"payloadHash": spec["payloadHash"],
+			"envelope": map[string]any{
+				"payloadType": opts.payloadType,
+				"signatures": []any{map[string]any{
+					"sig":       []byte(base64.StdEncoding.EncodeToString(loggedSignature)),
+					"publicKey": certPEM,
+				}},
+			},
+		}}
+	}
+	body := mustMarshal(t, map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       kind,
+		"spec":       spec,
+	})
+	set := mustSign(t, p.tlogKey, mustMarshal(t, map[string]any{
+		"body":           body,
+		"integratedTime": opts.integratedTime.Unix(),
+		"logID":          hex.EncodeToString(p.logID),
+		"logIndex":       42,
+	}))
+	tlogEntries := []any{map[string]any{
+		"logIndex":          "42",
+		"logId":             map[string]any{"keyId": p.logID},
+		"kindVersion":       map[string]any{"kind": kind, "version": "0.0.1"},
+		"integratedTime":    strconv.FormatInt(opts.integratedTime.Unix(), 10),
+		"inclusionPromise":  map[string]any{"signedEntryTimestamp": set},
+		"canonicalizedBody": body,
+	}}
+	if opts.noTlogEntries {
+		tlogEntries = nil
+	}
+
+	if opts.tamperPayload {
+		payload = []byte(strings.Replace(string(payload), "sha256", "sha512", 1))
+	}
+
+	return mustMarshal(t, map[string]any{
+		"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json",
+		"verificationMaterial": map[string]any{
+			"certificate": map[string]any{"rawBytes": leaf},
+			"tlogEntries": tlogEntries,
+		},
+		"dsseEnvelope": map[string]any{
+			"payload":     payload,
+			"payloadType": opts.payloadType,
+			"signatures":  []any{map[string]any{"sig": sig}},
+		},
+	})
+}
+
+func testStatement(content string) (*Statement, []byte) {
+	digest := sha256.Sum256([]byte(content))
+	return &Statement{
+		Type:          "https://in-toto.io/Statement/v1",
+		Subject:       []Subject{{Name: "source.json", Digest: map[string]string{"sha256": hex.EncodeToString(digest[:])}}},
+		PredicateType: "https://slsa.dev/provenance/v1",
+		Predicate:     json.RawMessage(`{"buildDefinition": {}}`),
+	}, digest[:]
+}
+
+func TestVerifyBundle(t *testing.T) {
+	pki := newTestPKI(t)
+	root := pki.trustedRoot(t)
+	statement, digest := testStatement(`{"url": "https://example.com/a.tar.gz"}`)
+
+	// the certificate of another valid bundle of the same statement
+	var other bundleJSON
+	if err := json.Unmarshal(pki.bundle(t, statement, bundleOptions{}), &other); err != nil {
+		t.Fatal(err)
+	}
+	otherCertificate := other.VerificationMaterial.Certificate.RawBytes
+
+	for _, tc := range []struct {
+		name      string
+		bundle    []byte
+		root      *TrustedRoot
+		wantErr   string
+		malformed bool
+	}{
+		{
+			name:   "valid",
+			bundle: pki.bundle(t, statement, bundleOptions{}),
+		},
+		{
+			name:   "jsonl",
+			bundle: append(pki.bundle(t, statement, bundleOptions{}), '\n'),
+		},
+		{
+			name:    "untrusted transparency log",
+			bundle:  pki.bundle(t, statement, bundleOptions{}),
+			root:    newTestPKI(t).trustedRoot(t),
+			wantErr: "is not trusted",
+		},
+		{
+			name:    "untrusted certificate authority",
+			bundle:  pki.bundle(t, statement, bundleOptions{ca: newTestPKI(t)}),
+			wantErr: "signing certificate",
+		},
+		{
+			name:    "signed by another key",
+			bundle:  pki.bundle(t, statement, bundleOptions{signer: mustGenerateKey(t)}),
+			wantErr: "DSSE signature",
+		},
+		{
+			name:    "logged after the certificate expired",
+			bundle:  pki.bundle(t, statement, bundleOptions{integratedTime: signedAt.Add(time.Hour)}),
+			wantErr: "signing certificate",
+		},
+		{
+			name:    "tampered payload",
+			bundle:  pki.bundle(t, statement, bundleOptions{tamperPayload: true}),
+			wantErr: "payload hash does not match",
+		},
+		{
+			name:   "logged as intoto",
+			bundle: pki.bundle(t, statement, bundleOptions{intotoEntry: true}),
+		},
+		{
+			name:    "logged with another certificate",
+			bundle:  pki.bundle(t, statement, bundleOptions{loggedCertificate: otherCertificate}),
+			wantErr: "logged signature or certificate does not match",
+		},
+		{
+			name:    "logged as intoto with another certificate",
+			bundle:  pki.bundle(t, statement, bundleOptions{intotoEntry: true, loggedCertificate: otherCertificate}),
+			wantErr: "logged signature or certificate does not match",
+		},
+		{
+			name:    "logged with another signature",
+			bundle:  pki.bundle(t, statement, bundleOptions{loggedSignature: []byte("other")}),
+			wantErr: "logged signature or certificate does not match",
+		},
+		{
+			name:    "not logged",
+			bundle:  pki.bundle(t, statement, bundleOptions{noTlogEntries: true}),
+			wantErr: "no transparency log entry",
+		},
+		{
+			name:      "not an in-toto statement",
+			bundle:    pki.bundle(t, statement, bundleOptions{payloadType: "text/plain"}),
+			wantErr:   "unexpected payload type",
+			malformed: true,
+		},
+		{
+			name:      "not a bundle",
+			bundle:    []byte(`{"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json"}`),
+			wantErr:   "no signed DSSE envelope",
+			malformed: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.root == nil {
+				tc.root = root
+			}
+			got, identity, err := VerifyBundle(tc.bundle, tc.root)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !got.HasSubjectDigest(digest) || got.PredicateType != statement.PredicateType {
+					t.Errorf("unexpected statement: %+v", got)
+				}
+				if *identity != testIdentity {
+					t.Errorf("unexpected identity: %v", identity)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("VerifyBundle() error = %v, want %q", err, tc.wantErr)
+			}
+			if errors.Is(err, ErrMalformedBundle) != tc.malformed {
+				t.Errorf("VerifyBundle() error = %v, malformed = %v", err, tc.malformed)
+			}
+		})
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	pki := newTestPKI(t)
+	statement, digest := testStatement(`{"url": "https://example.com/a.tar.gz"}`)
+	bundle := pki.bundle(t, statement, bundleOptions{})
+	integrity, err := sri.Compute("sha256", strings.NewReader(string(bundle)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/source.json.intoto.jsonl" {
+			http.NotFound(w, r)
+			return
+		}
+		downloads++
+		w.Write(bundle)
+	}))
+	defer server.Close()
+
+	att := &bzpb.Attestations_Attestation{Url: server.URL + "/source.json.intoto.jsonl", Integrity: integrity.String()}
+	otherDigest := sha256.Sum256([]byte("other"))
+
+	v := &Verifier{
+		TrustedRoot: pki.trustedRoot(t),
+		Bundles:     &archive.Cache{Dir: t.TempDir()},
+		Download:    true,
+		Identity: &IdentityPolicy{
+			Issuer:           GitHubActionsIssuer,
+			SourceRepository: regexp.MustCompile(`^https://github\.com/org/rules_foo$`),
+		},
+	}
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name   string
+		att    *bzpb.Attestations_Attestation
+		digest []byte
+		want   bzpb.AttestationVerificationStatus
+	}{
+		{"verified", att, digest, bzpb.AttestationVerificationStatus_ATTESTATION_VERIFIED},
+		{"subject mismatch", att, otherDigest[:], bzpb.AttestationVerificationStatus_ATTESTATION_SUBJECT_MISMATCH},
+		{"subject unavailable", att, nil, bzpb.AttestationVerificationStatus_ATTESTATION_SUBJECT_UNAVAILABLE},
+		{
+			"integrity mismatch",
+			&bzpb.Attestations_Attestation{Url: att.Url, Integrity: "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="},
+			digest,
+			bzpb.AttestationVerificationStatus_ATTESTATION_INTEGRITY_MISMATCH,
+		},
+		{
+			"malformed integrity",
+			&bzpb.Attestations_Attestation{Url: att.Url, Integrity: "sha256-"},
+			digest,
+			bzpb.AttestationVerificationStatus_ATTESTATION_MALFORMED_INTEGRITY,
+		},
+		{
+			"unavailable",
+			&bzpb.Attestations_Attestation{Url: server.URL + "/missing.intoto.jsonl", Integrity: "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="},
+			digest,
+			bzpb.AttestationVerificationStatus_ATTESTATION_UNAVAILABLE,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, _ := v.Verify(ctx, tc.att, tc.digest)
+			if got.Status != tc.want {
+				t.Errorf("Verify() = %v (%s), want %v", got.Status, got.Message, tc.want)
+			}
+		})
+	}
+
+	// the verified bundle was added to the directory, the mismatched one was
+	// downloaded again
+	if downloads != 2 {
+		t.Errorf("expected the bundle to be downloaded twice, got %d", downloads)
+	}
+	offline := *v
+	offline.Download = false
+	if got, statement, identity := offline.Verify(ctx, att, digest); got.Status != bzpb.AttestationVerificationStatus_ATTESTATION_VERIFIED || statement == nil || *identity != testIdentity {
+		t.Errorf("Verify() from the bundle directory = %v (%s)", got.Status, got.Message)
+	}
+
+	// the certificate must be issued to the expected identity
+	other := offline
+	other.Identity = &IdentityPolicy{Issuer: GitHubActionsIssuer, SourceRepository: regexp.MustCompile(`^https://github\.com/org/rules_bar$`)}
+	if got, statement, _ := other.Verify(ctx, att, digest); got.Status != bzpb.AttestationVerificationStatus_ATTESTATION_IDENTITY_MISMATCH || statement != nil {
+		t.Errorf("Verify() with another identity = %v (%s)", got.Status, got.Message)
+	}
+	other.Identity = &IdentityPolicy{Issuer: "https://gitlab.com", SourceRepository: v.Identity.SourceRepository}
+	if got, _, _ := other.Verify(ctx, att, digest); got.Status != bzpb.AttestationVerificationStatus_ATTESTATION_IDENTITY_MISMATCH {
+		t.Errorf("Verify() with another issuer = %v (%s)", got.Status, got.Message)
+	}
+	other.Identity = nil
+	if got, statement, _ := other.Verify(ctx, att, digest); got.Status != bzpb.AttestationVerificationStatus_ATTESTATION_IDENTITY_UNCHECKED || statement == nil {
+		t.Errorf("Verify() without identity = %v (%s)", got.Status, got.Message)
+	}
+}
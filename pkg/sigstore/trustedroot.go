@@ -0,0 +1,142 @@
+package sigstore
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// TrustedRoot holds the certificate authorities and transparency logs that
+// are trusted to issue signing certificates and log signatures.  It is read
+// from a trusted_root.json file (application/vnd.dev.sigstore.trustedroot+json),
+// such as the one of the Sigstore public good instance, or of a private
+// deployment.
+type TrustedRoot struct {
+	certificateAuthorities []*certificateAuthority
+	// transparency logs by hex log ID
+	tlogs map[string]*transparencyLog
+}
+
+type certificateAuthority struct {
+	root          *x509.Certificate
+	intermediates []*x509.Certificate
+	validFor      timeRange
+}
+
+type transparencyLog struct {
+	publicKey crypto.PublicKey
+	validFor  timeRange
+}
+
+// timeRange is a validity period, without an end if end is zero.
+type timeRange struct {
+	start, end time.Time
+}
+
+func (r timeRange) contains(t time.Time) bool {
+	return !t.Before(r.start) && (r.end.IsZero() || !t.After(r.end))
+}
+
+// trustedRootJSON is the JSON encoding of the TrustedRoot message of the
+// sigstore protobuf specs (bytes fields are base64 encoded).
+type trustedRootJSON struct {
+	MediaType string `json:"mediaType"`
+	Tlogs     []struct {
+		BaseURL   string `json:"baseUrl"`
+		PublicKey struct {
+			RawBytes []byte       `json:"rawBytes"`
+			ValidFor validForJSON `json:"validFor"`
+		} `json:"publicKey"`
+		LogID struct {
+			KeyID []byte `json:"keyId"`
+		} `json:"logId"`
+	} `json:"tlogs"`
+	CertificateAuthorities []struct {
+		URI       string `json:"uri"`
+		CertChain struct {
+			Certificates []rawBytesJSON `json:"certificates"`
+		} `json:"certChain"`
+		ValidFor validForJSON `json:"validFor"`
+	} `json:"certificateAuthorities"`
+}
+
+type validForJSON struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type rawBytesJSON struct {
+	RawBytes []byte `json:"rawBytes"`
+}
+
+// ReadTrustedRoot reads a trusted_root.json file.
+func ReadTrustedRoot(filename string) (*TrustedRoot, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	root, err := ParseTrustedRoot(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return root, nil
+}
+
+// ParseTrustedRoot parses the content of a trusted_root.json file.
+func ParseTrustedRoot(data []byte) (*TrustedRoot, error) {
+	var tr trustedRootJSON
+	if err := json.Unmarshal(data, &tr); err != nil {
+		return nil, fmt.Errorf("parsing trusted root: %w", err)
+	}
+
+	root := &TrustedRoot{tlogs: make(map[string]*transparencyLog)}
+
+	for _, ca := range tr.CertificateAuthorities {
+		// The chain is ordered from the issuing certificate to the root
+		certs, err := parseCertificates(ca.CertChain.Certificates)
+		if err != nil {
+			return nil, fmt.Errorf("certificate authority %s: %w", ca.URI, err)
+		}
+		if len(certs) == 0 {
+			return nil, fmt.Errorf("certificate authority %s: empty certificate chain", ca.URI)
+		}
+		root.certificateAuthorities = append(root.certificateAuthorities, &certificateAuthority{
+			root:          certs[len(certs)-1],
+			intermediates: certs[:len(certs)-1],
+			validFor:      timeRange{ca.ValidFor.Start, ca.ValidFor.End},
+		})
+	}
+
+	for _, tlog := range tr.Tlogs {
+		publicKey, err := x509.ParsePKIXPublicKey(tlog.PublicKey.RawBytes)
+		if err != nil {
+			return nil, fmt.Errorf("transparency log %s: parsing public key: %w", tlog.BaseURL, err)
+		}
+		root.tlogs[hex.EncodeToString(tlog.LogID.KeyID)] = &transparencyLog{
+			publicKey: publicKey,
+			validFor:  timeRange{tlog.PublicKey.ValidFor.Start, tlog.PublicKey.ValidFor.End},
+		}
+	}
+
+	if len(root.certificateAuthorities) == 0 || len(root.tlogs) == 0 {
+		return nil, fmt.Errorf("trusted root has no certificate authorities or transparency logs")
+	}
+
+	return root, nil
+}
+
+func parseCertificates(raw []rawBytesJSON) ([]*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, 0, len(raw))
+	for _, cert := range raw {
+		c, err := x509.ParseCertificate(cert.RawBytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate: %w", err)
+		}
+		certs = append(certs, c)
+	}
+	return certs, nil
+}
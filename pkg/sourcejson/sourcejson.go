@@ -2,9 +2,11 @@ package sourcejson
 
 import (
 	"fmt"
+	"path/filepath"
 
 	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
 	"github.com/bazel-contrib/bcr-frontend/pkg/protoutil"
+	"github.com/bazel-contrib/bcr-frontend/pkg/sri"
 )
 
 // ReadFile reads and parses a source.json file into a Source protobuf
@@ -15,3 +17,47 @@ func ReadFile(filename string) (*bzpb.ModuleSource, error) {
 	}
 	return &src, nil
 }
+
+// VerifyFiles checks the files of the patches/ and overlay/ directories next
+// to the source.json file against the integrity listed in the source, and
+// records the results in source.PatchIntegrity and source.OverlayIntegrity.
+// Names that are not local paths (e.g. "../../other/1.0/patches/fix.patch")
+// are malformed: they would escape the directory.
+func VerifyFiles(versionDir string, source *bzpb.ModuleSource) {
+	source.PatchIntegrity = verifyFiles(filepath.Join(versionDir, "patches"), source.Patches)
+	source.OverlayIntegrity = verifyFiles(filepath.Join(versionDir, "overlay"), source.Overlay)
+}
+
+func verifyFiles(dir string, files map[string]string) map[string]*bzpb.FileIntegrity {
+	if len(files) == 0 {
+		return nil
+	}
+	results := make(map[string]*bzpb.FileIntegrity, len(files))
+	for filename, integrity := range files {
+		if !filepath.IsLocal(filename) {
+			results[filename] = &bzpb.FileIntegrity{Status: bzpb.FileIntegrityStatus_FILE_INTEGRITY_MALFORMED}
+			continue
+		}
+		results[filename] = VerifyFile(filepath.Join(dir, filename), integrity)
+	}
+	return results
+}
+
+// VerifyFile checks the file against the integrity.
+func VerifyFile(filename, integrity string) *bzpb.FileIntegrity {
+	want, err := sri.Parse(integrity)
+	if err != nil {
+		return &bzpb.FileIntegrity{Status: bzpb.FileIntegrityStatus_FILE_INTEGRITY_MALFORMED}
+	}
+	got, err := sri.ComputeFile(want.Algorithm, filename)
+	if err != nil {
+		return &bzpb.FileIntegrity{Status: bzpb.FileIntegrityStatus_FILE_INTEGRITY_FILE_MISSING}
+	}
+	if !got.Equal(want) {
+		return &bzpb.FileIntegrity{
+			Status:          bzpb.FileIntegrityStatus_FILE_INTEGRITY_MISMATCH,
+			ActualIntegrity: got.String(),
+		}
+	}
+	return &bzpb.FileIntegrity{Status: bzpb.FileIntegrityStatus_FILE_INTEGRITY_VERIFIED}
+}
@@ -0,0 +1,57 @@
+package sourcejson
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestVerifyFiles(t *testing.T) {
+	versionDir := t.TempDir()
+	for _, name := range []string{"patches/fix.patch", "patches/other.patch", "overlay/sub/BUILD.bazel"} {
+		filename := filepath.Join(versionDir, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte("hello"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const helloIntegrity = "sha256-LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="
+	source := &bzpb.ModuleSource{
+		Patches: map[string]string{
+			"fix.patch":              helloIntegrity,
+			"other.patch":            "sha256-ShAT7rtQ9yj8YBvdgzsLKHAzPDs+WoFu66kh2VvsbxU=",
+			"missing.patch":          helloIntegrity,
+			"bad.patch":              "sha256-",
+			"../../../../etc/passwd": helloIntegrity,
+			"/etc/passwd":            helloIntegrity,
+		},
+		Overlay: map[string]string{
+			"sub/BUILD.bazel": helloIntegrity,
+		},
+	}
+	VerifyFiles(versionDir, source)
+
+	for filename, want := range map[string]bzpb.FileIntegrityStatus{
+		"fix.patch":              bzpb.FileIntegrityStatus_FILE_INTEGRITY_VERIFIED,
+		"other.patch":            bzpb.FileIntegrityStatus_FILE_INTEGRITY_MISMATCH,
+		"missing.patch":          bzpb.FileIntegrityStatus_FILE_INTEGRITY_FILE_MISSING,
+		"bad.patch":              bzpb.FileIntegrityStatus_FILE_INTEGRITY_MALFORMED,
+		"../../../../etc/passwd": bzpb.FileIntegrityStatus_FILE_INTEGRITY_MALFORMED,
+		"/etc/passwd":            bzpb.FileIntegrityStatus_FILE_INTEGRITY_MALFORMED,
+	} {
+		if got := source.PatchIntegrity[filename].GetStatus(); got != want {
+			t.Errorf("%s: status = %v, want %v", filename, got, want)
+		}
+	}
+	if got := source.PatchIntegrity["other.patch"].GetActualIntegrity(); got != helloIntegrity {
+		t.Errorf("other.patch: actual integrity = %q, want %q", got, helloIntegrity)
+	}
+	if got := source.OverlayIntegrity["sub/BUILD.bazel"].GetStatus(); got != bzpb.FileIntegrityStatus_FILE_INTEGRITY_VERIFIED {
+		t.Errorf("overlay sub/BUILD.bazel: status = %v", got)
+	}
+}
@@ -0,0 +1,123 @@
+// Package srht fetches repository metadata from the sourcehut git service
+// (git.sr.ht).
+package srht
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+// BaseURL is the url of the sourcehut git service.
+const BaseURL = "https://git.sr.ht"
+
+// MaxBatchSize is the maximum number of repositories fetched in a single
+// GraphQL query.
+const MaxBatchSize = 100
+
+// httpClient is used for all requests to sourcehut.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// FetchRepositoryMetadataBatch fetches the descriptions of up to 100
+// repositories in a single GraphQL query against the git service at baseURL.
+// The GraphQL API requires a personal access token.  sourcehut has neither
+// stars nor a language breakdown, so only the description is filled in.
+func FetchRepositoryMetadataBatch(ctx context.Context, baseURL, token string, repos []*bzpb.RepositoryMetadata) error {
+	if len(repos) == 0 {
+		return nil
+	}
+	if len(repos) > MaxBatchSize {
+		return fmt.Errorf("maximum %d repositories per batch, got %d", MaxBatchSize, len(repos))
+	}
+
+	var query bytes.Buffer
+	query.WriteString("query {\n")
+	for i, repo := range repos {
+		fmt.Fprintf(&query, "  repo%d: user(username: %q) {\n    repository(name: %q) {\n      description\n    }\n  }\n",
+			i, repo.Organization, repo.Name)
+	}
+	query.WriteString("}\n")
+
+	data, err := executeGraphQL(ctx, baseURL+"/query", token, query.String())
+	if err != nil {
+		return fmt.Errorf("failed to execute GraphQL query: %w", err)
+	}
+
+	for i, repo := range repos {
+		var user struct {
+			Repository *struct {
+				Description *string `json:"description"`
+			} `json:"repository"`
+		}
+		raw, ok := data[fmt.Sprintf("repo%d", i)]
+		if !ok || string(raw) == "null" {
+			log.Printf("WARN sourcehut:~%s/%s: graphql response user not found", repo.Organization, repo.Name)
+			continue
+		}
+		if err := json.Unmarshal(raw, &user); err != nil || user.Repository == nil {
+			log.Printf("WARN sourcehut:~%s/%s: graphql response repository not found", repo.Organization, repo.Name)
+			continue
+		}
+
+		// Initialize Languages map to indicate metadata was fetched
+		repo.Languages = make(map[string]int32)
+		if user.Repository.Description != nil {
+			repo.Description = *user.Repository.Description
+		}
+	}
+
+	return nil
+}
+
+// executeGraphQL executes a GraphQL query and returns the fields of the
+// response data.
+func executeGraphQL(ctx context.Context, endpoint, token, query string) (map[string]json.RawMessage, error) {
+	jsonBody, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GraphQL request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Data   map[string]json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Missing users and repositories are reported as errors along with the
+	// data of the others, so errors only fail the whole batch if there is
+	// no data at all.
+	if len(result.Errors) > 0 && len(result.Data) == 0 {
+		return nil, fmt.Errorf("GraphQL errors: %v", result.Errors)
+	}
+
+	return result.Data, nil
+}
@@ -0,0 +1,72 @@
+package srht
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	bzpb "github.com/bazel-contrib/bcr-frontend/build/stack/bazel/registry/v1"
+)
+
+func TestFetchRepositoryMetadataBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/query" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want the bearer token", got)
+		}
+		var req struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		if !strings.Contains(req.Query, `repo0: user(username: "sircmpwn")`) || !strings.Contains(req.Query, `repository(name: "scdoc")`) {
+			t.Errorf("unexpected query: %s", req.Query)
+		}
+		w.Write([]byte(`{
+			"data": {
+				"repo0": {"repository": {"description": "Tool for generating roff manual pages"}},
+				"repo1": {"repository": null},
+				"repo2": null
+			},
+			"errors": [{"message": "no such user"}]
+		}`))
+	}))
+	defer server.Close()
+
+	repos := []*bzpb.RepositoryMetadata{
+		{Type: bzpb.RepositoryType_SOURCEHUT, Organization: "sircmpwn", Name: "scdoc"},
+		{Type: bzpb.RepositoryType_SOURCEHUT, Organization: "sircmpwn", Name: "missing"},
+		{Type: bzpb.RepositoryType_SOURCEHUT, Organization: "missing", Name: "scdoc"},
+	}
+	if err := FetchRepositoryMetadataBatch(context.Background(), server.URL, "secret", repos); err != nil {
+		t.Fatal(err)
+	}
+	if got := repos[0]; got.Description != "Tool for generating roff manual pages" || got.Languages == nil {
+		t.Errorf("unexpected metadata: %v", got)
+	}
+	for _, missing := range repos[1:] {
+		if missing.Languages != nil {
+			t.Errorf("expected no metadata for %v", missing)
+		}
+	}
+}
+
+func TestFetchRepositoryMetadataBatchErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors": [{"message": "Invalid authorization"}]}`))
+	}))
+	defer server.Close()
+
+	repos := []*bzpb.RepositoryMetadata{{Type: bzpb.RepositoryType_SOURCEHUT, Organization: "sircmpwn", Name: "scdoc"}}
+	if err := FetchRepositoryMetadataBatch(context.Background(), server.URL, "", repos); err == nil {
+		t.Error("expected an error for a response without data")
+	}
+}
@@ -0,0 +1,99 @@
+// Package sri parses Subresource Integrity strings as used by the registry
+// (e.g., "sha256-ShAT7rtQ9yj8YBvdgzsLKHAzPDs+WoFu66kh2VvsbxU=").
+package sri
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// digestSizes maps the hash algorithms supported by bazel to the size of
+// their digests in bytes.
+var digestSizes = map[string]int{
+	"sha1":   20,
+	"sha256": 32,
+	"sha384": 48,
+	"sha512": 64,
+}
+
+// newHash returns the hash function of the algorithm.
+func newHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha384":
+		return sha512.New384(), nil
+	case "sha512":
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm %q", algorithm)
+}
+
+// Integrity is a parsed SRI string.
+type Integrity struct {
+	Algorithm string
+	Digest    []byte
+}
+
+// Parse parses an SRI string of the form ALGORITHM-BASE64DIGEST.
+func Parse(integrity string) (*Integrity, error) {
+	algorithm, encoded, ok := strings.Cut(integrity, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid integrity %q: expected ALGORITHM-DIGEST", integrity)
+	}
+	size, ok := digestSizes[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("invalid integrity %q: unsupported algorithm %q", integrity, algorithm)
+	}
+	digest, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integrity %q: %v", integrity, err)
+	}
+	if len(digest) != size {
+		return nil, fmt.Errorf("invalid integrity %q: %s digest must be %d bytes, got %d", integrity, algorithm, size, len(digest))
+	}
+	return &Integrity{Algorithm: algorithm, Digest: digest}, nil
+}
+
+// String formats the integrity as an SRI string.
+func (i *Integrity) String() string {
+	return i.Algorithm + "-" + base64.StdEncoding.EncodeToString(i.Digest)
+}
+
+// Equal reports whether both integrities have the same algorithm and digest.
+func (i *Integrity) Equal(other *Integrity) bool {
+	return i.Algorithm == other.Algorithm && bytes.Equal(i.Digest, other.Digest)
+}
+
+// Compute returns the integrity of the data read from r, using the given
+// algorithm.
+func Compute(algorithm string, r io.Reader) (*Integrity, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return &Integrity{Algorithm: algorithm, Digest: h.Sum(nil)}, nil
+}
+
+// ComputeFile returns the integrity of the file, using the given algorithm.
+func ComputeFile(algorithm, filename string) (*Integrity, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Compute(algorithm, f)
+}
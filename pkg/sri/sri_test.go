@@ -0,0 +1,55 @@
+package sri
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for name, tc := range map[string]struct {
+		integrity string
+		wantErr   bool
+	}{
+		"sha256":            {integrity: "sha256-ShAT7rtQ9yj8YBvdgzsLKHAzPDs+WoFu66kh2VvsbxU="},
+		"sha512":            {integrity: "sha512-" + "z4PhNX7vuL3xVChQ1m2AB9Yg5AULVxXcg/SpIdNs6c5H0NE8XYXysP+DGNKHfuwvY7kxvUdBeoGlODJ6+SfaPg=="},
+		"empty":             {integrity: "", wantErr: true},
+		"no separator":      {integrity: "sha256", wantErr: true},
+		"unknown algorithm": {integrity: "md5-1B2M2Y8AsgTpgAmY7PhCfg==", wantErr: true},
+		"bad base64":        {integrity: "sha256-not base64!", wantErr: true},
+		"wrong size":        {integrity: "sha256-1B2M2Y8AsgTpgAmY7PhCfg==", wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tc.integrity)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Parse(%q) = %v, want error", tc.integrity, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tc.integrity {
+				t.Errorf("String() = %q, want %q", got.String(), tc.integrity)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	want, err := Parse("sha256-LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Compute("sha256", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("Compute() = %s, want %s", got, want)
+	}
+
+	if _, err := Compute("md5", strings.NewReader("hello")); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+}
@@ -0,0 +1,211 @@
+package versionutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// versionPattern mirrors the grammar accepted by Bazel's bzlmod Version class:
+// RELEASE[-PRERELEASE][+BUILD], where RELEASE and PRERELEASE are
+// dot-separated identifiers.  Release identifiers are either numeric or start
+// with a letter. See
+// https://github.com/bazelbuild/bazel/blob/master/src/main/java/com/google/devtools/build/lib/bazel/bzlmod/Version.java
+var versionPattern = regexp.MustCompile(`^(?P<release>(?:\d+|[A-Za-z]\w*)(?:\.(?:\d+|[A-Za-z]\w*))*)(?:-(?P<prerelease>(?:\d+|[\w-]+)(?:\.(?:\d+|[\w-]+))*))?(?:\+(?P<build>[\w.-]*))?$`)
+
+// Identifier is a single dot-separated segment of a version's release or
+// prerelease part.
+type Identifier struct {
+	// Value is the raw identifier string
+	Value string
+	// Numeric is true if the identifier is composed only of digits
+	Numeric bool
+}
+
+// Version is a parsed module version string.
+type Version struct {
+	// Original is the unparsed version string
+	Original string
+	// Release is the list of release identifiers (e.g. 1.2.3 -> [1 2 3])
+	Release []Identifier
+	// Prerelease is the list of prerelease identifiers (e.g. -rc.1 -> [rc 1])
+	Prerelease []Identifier
+	// Build is the build metadata, which is ignored for comparison
+	Build string
+}
+
+// Parse parses a version string according to the Bazel module version
+// grammar. The empty string is a valid version that sorts higher than every
+// other version.
+func Parse(s string) (*Version, error) {
+	if s == "" {
+		return &Version{}, nil
+	}
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("bad version (should be X.Y.Z-pre+build): %q", s)
+	}
+	return &Version{
+		Original:   s,
+		Release:    parseIdentifiers(m[versionPattern.SubexpIndex("release")]),
+		Prerelease: parseIdentifiers(m[versionPattern.SubexpIndex("prerelease")]),
+		Build:      m[versionPattern.SubexpIndex("build")],
+	}, nil
+}
+
+// MustParse is like Parse but panics if the version is invalid.
+func MustParse(s string) *Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func parseIdentifiers(s string) []Identifier {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ".")
+	ids := make([]Identifier, len(parts))
+	for i, part := range parts {
+		ids[i] = Identifier{Value: part, Numeric: isDigits(part)}
+	}
+	return ids
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty returns true if this is the empty version.
+func (v *Version) IsEmpty() bool {
+	return len(v.Release) == 0
+}
+
+// IsPrerelease returns true if this version has prerelease identifiers.
+func (v *Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// String returns the original version string
+func (v *Version) String() string {
+	return v.Original
+}
+
+// Compare compares two parsed versions and returns -1, 0 or 1.  The empty
+// version sorts highest, then versions are compared by release identifiers,
+// then a version without a prerelease sorts above one with a prerelease, and
+// finally by prerelease identifiers.  Build metadata is ignored.
+func (v *Version) Compare(other *Version) int {
+	if v.IsEmpty() || other.IsEmpty() {
+		switch {
+		case v.IsEmpty() && other.IsEmpty():
+			return 0
+		case v.IsEmpty():
+			return 1
+		default:
+			return -1
+		}
+	}
+	if c := compareIdentifiers(v.Release, other.Release); c != 0 {
+		return c
+	}
+	if v.IsPrerelease() != other.IsPrerelease() {
+		if v.IsPrerelease() {
+			return -1
+		}
+		return 1
+	}
+	return compareIdentifiers(v.Prerelease, other.Prerelease)
+}
+
+// compareIdentifiers compares two identifier lists lexicographically.
+func compareIdentifiers(a, b []Identifier) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier compares single identifiers.  Numeric identifiers sort
+// below non-numeric ones and are compared by value; non-numeric identifiers
+// are compared as ASCII strings.
+func compareIdentifier(a, b Identifier) int {
+	if a.Numeric != b.Numeric {
+		if a.Numeric {
+			return -1
+		}
+		return 1
+	}
+	if a.Numeric {
+		return compareNumeric(a.Value, b.Value)
+	}
+	return strings.Compare(a.Value, b.Value)
+}
+
+// compareNumeric compares two digit strings by numeric value without
+// overflowing on very long identifiers (e.g. date-based versions).  Like
+// Bazel, identifiers of equal value that differ in leading zeros are then
+// compared as strings (so "01" < "1").
+func compareNumeric(a, b string) int {
+	trimmedA := strings.TrimLeft(a, "0")
+	trimmedB := strings.TrimLeft(b, "0")
+	if len(trimmedA) != len(trimmedB) {
+		if len(trimmedA) < len(trimmedB) {
+			return -1
+		}
+		return 1
+	}
+	if c := strings.Compare(trimmedA, trimmedB); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// Compare parses and compares two version strings, returning -1 if a < b, 0
+// if a == b and 1 if a > b.  Strings that are not valid versions sort below
+// valid ones and are ordered lexicographically amongst themselves.
+func Compare(a, b string) int {
+	if a == b {
+		return 0
+	}
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return va.Compare(vb)
+}
+
+// Max returns the highest version in the list, or "" if the list is empty.
+func Max(versions []string) string {
+	var highest string
+	for i, v := range versions {
+		if i == 0 || Compare(v, highest) > 0 {
+			highest = v
+		}
+	}
+	return highest
+}
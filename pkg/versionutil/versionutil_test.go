@@ -0,0 +1,276 @@
+package versionutil
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantRelease    []string
+		wantPrerelease []string
+		wantBuild      string
+		wantErr        bool
+	}{
+		{
+			name:        "empty",
+			input:       "",
+			wantRelease: nil,
+		},
+		{
+			name:        "semver",
+			input:       "1.2.3",
+			wantRelease: []string{"1", "2", "3"},
+		},
+		{
+			name:        "single segment",
+			input:       "17",
+			wantRelease: []string{"17"},
+		},
+		{
+			name:        "bcr suffix",
+			input:       "20230802.0.bcr.1",
+			wantRelease: []string{"20230802", "0", "bcr", "1"},
+		},
+		{
+			name:           "prerelease",
+			input:          "8.0.0-rc1",
+			wantRelease:    []string{"8", "0", "0"},
+			wantPrerelease: []string{"rc1"},
+		},
+		{
+			name:           "prerelease with hyphens",
+			input:          "0.0.0-20220923-a547704",
+			wantRelease:    []string{"0", "0", "0"},
+			wantPrerelease: []string{"20220923-a547704"},
+		},
+		{
+			name:           "dotted prerelease",
+			input:          "8.0.0-pre.20240101.1",
+			wantRelease:    []string{"8", "0", "0"},
+			wantPrerelease: []string{"pre", "20240101", "1"},
+		},
+		{
+			name:        "build metadata",
+			input:       "1.0.0+build.5",
+			wantRelease: []string{"1", "0", "0"},
+			wantBuild:   "build.5",
+		},
+		{
+			name:    "leading dot",
+			input:   ".1.2",
+			wantErr: true,
+		},
+		{
+			name:    "double dot",
+			input:   "1..2",
+			wantErr: true,
+		},
+		{
+			name:    "whitespace",
+			input:   "1.2 3",
+			wantErr: true,
+		},
+		{
+			name:    "release identifier starting with a digit",
+			input:   "1a",
+			wantErr: true,
+		},
+		{
+			name:    "release identifier starting with an underscore",
+			input:   "1.0._x",
+			wantErr: true,
+		},
+		{
+			name:        "release identifier starting with a letter",
+			input:       "1.0.b2",
+			wantRelease: []string{"1", "0", "b2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if release := identifierValues(got.Release); !slices.Equal(release, tt.wantRelease) {
+				t.Errorf("Parse(%q) release = %v, want %v", tt.input, release, tt.wantRelease)
+			}
+			if prerelease := identifierValues(got.Prerelease); !slices.Equal(prerelease, tt.wantPrerelease) {
+				t.Errorf("Parse(%q) prerelease = %v, want %v", tt.input, prerelease, tt.wantPrerelease)
+			}
+			if got.Build != tt.wantBuild {
+				t.Errorf("Parse(%q) build = %q, want %q", tt.input, got.Build, tt.wantBuild)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		// numeric segments compare by value, not lexicographically
+		{"1.10.0", "1.9.0", 1},
+		{"0.10.1", "0.9.0", 1},
+		{"2.0.0", "10.0.0", -1},
+		// equal versions
+		{"1.2.3", "1.2.3", 0},
+		// build metadata is ignored
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+		{"1.0.0+build.1", "1.0.0", 0},
+		// longer release sorts higher when prefix is equal
+		{"1.2", "1.2.0", -1},
+		{"1.2.3.4", "1.2.3", 1},
+		// .bcr.N suffixes (non-numeric identifiers sort above numeric)
+		{"20230802.0.bcr.1", "20230802.0", 1},
+		{"20230802.0.bcr.2", "20230802.0.bcr.1", 1},
+		{"1.3.1.bcr.1", "1.3.1.1", 1},
+		{"3.4.0.bcr.1", "3.4.1", -1},
+		// prereleases sort below the release
+		{"8.0.0-rc1", "8.0.0", -1},
+		{"8.0.0-rc1", "7.4.1", 1},
+		{"6.0.0-rc1", "6.0.0-rc2", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha", 1},
+		{"1.0.0-alpha.2", "1.0.0-alpha.10", -1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"8.0.0-pre.20240101.1", "8.0.0-pre.20240101.2", -1},
+		{"0.0.0-20220923-a547704", "0.0.0-20221010-0b2e1f9", -1},
+		// empty version sorts highest
+		{"", "999.0.0", 1},
+		{"1.0", "", -1},
+		{"", "", 0},
+		// very long numeric identifiers
+		{"100000000000000000000", "99999999999999999999", 1},
+		// leading zeros compare by value, then as strings
+		{"1.01", "1.1", -1},
+		{"1.01", "1.2", -1},
+		{"1.010", "1.9", 1},
+		{"2024.07.03", "2024.7.3", -1},
+		// real registry versions
+		{"5.3.0-21.7", "5.3.0-21.5", 1},
+		{"3.1.0.bcr.1", "3.1.0", 1},
+		{"0.0.0-20241220-5e258e33", "0.0.0", -1},
+		{"1.86.0.bcr.1", "1.87.0", -1},
+		{"29.0-rc2", "29.0", -1},
+		{"29.0-rc2", "28.3", 1},
+		{"2024.07.03.bcr.1", "2024.07.03", 1},
+		// invalid versions sort below valid ones
+		{"not a version", "0.0.1", -1},
+		{"0.0.1", "not a version", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestSortRegistryVersions(t *testing.T) {
+	// versions taken from modules/protobuf/metadata.json in the BCR, shuffled
+	got := []string{
+		"29.0",
+		"3.19.6",
+		"21.7",
+		"29.0-rc2",
+		"30.0",
+		"3.19.0",
+		"27.0-rc1",
+		"28.0-rc1",
+		"23.1",
+		"27.0",
+		"29.0-rc3",
+		"3.19.2",
+		"28.3",
+		"27.3",
+		"27.1",
+		"28.0",
+		"29.0-rc2.bcr.1",
+		"30.0-rc1",
+	}
+	want := []string{
+		"3.19.0",
+		"3.19.2",
+		"3.19.6",
+		"21.7",
+		"23.1",
+		"27.0-rc1",
+		"27.0",
+		"27.1",
+		"27.3",
+		"28.0-rc1",
+		"28.0",
+		"28.3",
+		"29.0-rc2",
+		"29.0-rc2.bcr.1",
+		"29.0-rc3",
+		"29.0",
+		"30.0-rc1",
+		"30.0",
+	}
+
+	slices.SortFunc(got, Compare)
+	if !slices.Equal(got, want) {
+		t.Errorf("sorted versions mismatch:\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestMax(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     string
+	}{
+		{
+			name:     "empty",
+			versions: nil,
+			want:     "",
+		},
+		{
+			name:     "rules_go",
+			versions: []string{"0.41.0", "0.50.1", "0.9.0", "0.48.0"},
+			want:     "0.50.1",
+		},
+		{
+			name:     "bcr suffix is latest",
+			versions: []string{"1.3.1", "1.3.1.bcr.1", "1.3.0"},
+			want:     "1.3.1.bcr.1",
+		},
+		{
+			name:     "release beats prerelease",
+			versions: []string{"8.0.0-rc1", "8.0.0", "8.0.0-rc2"},
+			want:     "8.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Max(tt.versions); got != tt.want {
+				t.Errorf("Max(%v) = %q, want %q", tt.versions, got, tt.want)
+			}
+		})
+	}
+}
+
+func identifierValues(ids []Identifier) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.Value
+	}
+	return values
+}
@@ -9,6 +9,16 @@ def _module_attestations_impl(ctx):
             urls = ctx.attr.urls,
             integrities = ctx.attr.integrities,
             attestations_json = ctx.file.attestations_json,
+            verification_status = ctx.attr.verification_status,
+            verification_message = ctx.attr.verification_message,
+            provenance_predicate_type = ctx.attr.provenance_predicate_type,
+            provenance_builder_id = ctx.attr.provenance_builder_id,
+            provenance_source_repository = ctx.attr.provenance_source_repository,
+            provenance_source_ref = ctx.attr.provenance_source_ref,
+            provenance_source_commit = ctx.attr.provenance_source_commit,
+            provenance_workflow_path = ctx.attr.provenance_workflow_path,
+            provenance_build_invocation_id = ctx.attr.provenance_build_invocation_id,
+            provenance_attested_files = ctx.attr.provenance_attested_files,
         ),
     ]
 
@@ -29,6 +39,36 @@ module_attestations = rule(
             doc = "File: The attestations.json file",
             allow_single_file = [".json"],
         ),
+        "verification_status": attr.string_dict(
+            doc = "dict[str, str]: Mapping of filename to the verification status of its sigstore bundle (empty if not verified)",
+        ),
+        "verification_message": attr.string_dict(
+            doc = "dict[str, str]: Mapping of filename to the details of its verification",
+        ),
+        "provenance_predicate_type": attr.string(
+            doc = "str: SLSA provenance predicate type of the verified statements (empty if none)",
+        ),
+        "provenance_builder_id": attr.string(
+            doc = "str: Build platform that built the release (e.g., https://github.com/actions/runner/github-hosted)",
+        ),
+        "provenance_source_repository": attr.string(
+            doc = "str: Repository the release was built from",
+        ),
+        "provenance_source_ref": attr.string(
+            doc = "str: Git ref the release was built from",
+        ),
+        "provenance_source_commit": attr.string(
+            doc = "str: Git commit the release was built from",
+        ),
+        "provenance_workflow_path": attr.string(
+            doc = "str: Path of the workflow in the source repository",
+        ),
+        "provenance_build_invocation_id": attr.string(
+            doc = "str: Identifier of the build run",
+        ),
+        "provenance_attested_files": attr.string_list(
+            doc = "list[str]: Files whose verified statements carry the provenance",
+        ),
     },
     provides = [ModuleAttestationsInfo],
 )
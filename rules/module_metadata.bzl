@@ -38,6 +38,10 @@ def _compile_action(ctx, repository_metadata, versions):
         args.add(repository_metadata.json_file)
     args.add("--output_file")
     args.add(proto_out)
+    if ctx.attr.registry:
+        args.add("--registry")
+        args.add(ctx.attr.registry)
+    args.add_all(["%s=%s" % (k, v) for k, v in ctx.attr.reverse_dependency_counts.items()], before_each = "--reverse_dependency_count")
     args.add_all(versions)
 
     # Collect all input files
@@ -76,10 +80,12 @@ def _module_metadata_impl(ctx):
             versions = ctx.attr.versions,
             yanked_versions = ctx.attr.yanked_versions,
             deprecated = ctx.attr.deprecated,
+            reverse_dependency_counts = ctx.attr.reverse_dependency_counts,
             deps = deps,
             metadata_json = ctx.file.metadata_json,
             build_bazel = ctx.file.build_bazel if ctx.file.build_bazel else None,
             proto = proto_out,
+            registry = ctx.attr.registry,
         ),
         OutputGroupInfo(
             metadata_json = depset([metadata_json]),
@@ -109,6 +115,12 @@ module_metadata = rule(
         "deprecated": attr.string(
             doc = "str: Deprecation message (empty string if not deprecated)",
         ),
+        "registry": attr.string(
+            doc = "str: Name of the registry providing the module (when several registries are combined)",
+        ),
+        "reverse_dependency_counts": attr.string_dict(
+            doc = "dict[str, str]: Reverse dependency counts keyed by ReverseDependencyCounts field name (computed by gazelle)",
+        ),
         "deps": attr.label_list(
             doc = "list[Target]: Module version targets providing ModuleVersionInfo",
             providers = [ModuleVersionInfo],
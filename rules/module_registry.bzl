@@ -271,13 +271,25 @@ def _compile_documentation(ctx, deps):
     all_mv_by_id = {}
     for m in deps:
         for mv in m.deps:
-            all_mv_by_id[mv.id] = mv
+            all_mv_by_id.setdefault(mv.id, mv)
 
     results = []
+    seen = {}
     for module in deps:
+        # when registries are combined, only the module version of the
+        # registry with the highest precedence is documented
+        module = struct(deps = [mv for mv in module.deps if not seen.get(mv.id)])
+        for mv in module.deps:
+            seen[mv.id] = True
         results.extend(_compile_documentation_for_module(ctx, module, all_mv_by_id))
     return results
 
+def _order_by_registry_precedence(ctx, deps):
+    if not ctx.attr.registries:
+        return deps
+    precedence = {name: i for i, name in enumerate(ctx.attr.registries)}
+    return sorted(deps, key = lambda d: precedence.get(d.registry, len(precedence)))
+
 def _compile_colors_action(ctx, colors_json, languages_json):
     output = ctx.actions.declare_file(ctx.label.name + ".colors.css")
 
@@ -324,6 +336,42 @@ def _compile_sitemap_action(ctx, registry_pb):
 
     return output
 
+def _compile_dependency_trees_action(ctx, registry_pb, deps):
+    output = ctx.actions.declare_directory("dependencytrees")
+
+    args = ctx.actions.args()
+    args.use_param_file("@%s", use_always = True)
+    args.set_param_file_format("multiline")
+
+    args.add("--registry_file")
+    args.add(registry_pb)
+    args.add("--output_dir")
+    args.add(output.path)
+
+    # when registries are combined, the same module version appears once per
+    # registry: only the one of the registry with the highest precedence
+    # (which bazel selects) is used
+    seen = {}
+    for module in deps:
+        for mv in module.deps:
+            if seen.get(mv.id):
+                continue
+            seen[mv.id] = True
+            args.add_all(["%s=%s@%s" % (mv.id, name, version) for name, version in mv.mvs.items()], before_each = "--mvs")
+            args.add_all(["%s=%s@%s" % (mv.id, name, version) for name, version in mv.mvs_merged.items()], before_each = "--mvs_merged")
+            args.add_all(["%s=%s=%s" % (mv.id, name, override_type) for name, override_type in mv.mvs_overrides.items()], before_each = "--mvs_override")
+
+    ctx.actions.run(
+        executable = ctx.executable._dependencytreecompiler,
+        arguments = [args],
+        inputs = [registry_pb],
+        outputs = [output],
+        mnemonic = "CompileDependencyTrees",
+        progress_message = "Compiling dependency trees for %{label}",
+    )
+
+    return output
+
 def _write_robots_txt_action(ctx):
     output = ctx.actions.declare_file("robots.txt")
 
@@ -362,6 +410,7 @@ def _compile_registry_action(ctx, filename, modules, docRegistry = None):
     if ctx.attr.commit_date:
         args.add("--commit_date")
         args.add(ctx.attr.commit_date)
+    args.add_all(ctx.attr.registries, before_each = "--registry_precedence")
     args.add_all(modules)
 
     ctx.actions.run(
@@ -376,7 +425,7 @@ def _compile_registry_action(ctx, filename, modules, docRegistry = None):
     return output
 
 def _module_registry_impl(ctx):
-    deps = [d[ModuleMetadataInfo] for d in ctx.attr.deps]
+    deps = _order_by_registry_precedence(ctx, [d[ModuleMetadataInfo] for d in ctx.attr.deps])
     cycles = [d[ModuleDependencyCycleInfo] for d in ctx.attr.cycles]
     bazel_versions = [d[BazelVersionInfo] for d in ctx.attr.bazel_versions]
 
@@ -394,7 +443,9 @@ def _module_registry_impl(ctx):
     registrylite_pb = _compile_registry_action(ctx, "registrylite.pb", modules)
 
     sitemap_xml = _compile_sitemap_action(ctx, registry_pb)
+    dependency_trees = _compile_dependency_trees_action(ctx, registrylite_pb, deps)
     bazel_help = _compile_bazel_help_registry_action(ctx, bazel_versions)
+    reverse_dependencies = ctx.file.reverse_dependencies
 
     return [
         DefaultInfo(files = depset([registry_pb])),
@@ -406,6 +457,8 @@ def _module_registry_impl(ctx):
             robots_txt = [robots_txt],
             registry_pb = [registry_pb],
             registrylite_pb = [registrylite_pb],
+            dependency_trees = [dependency_trees],
+            reverse_dependencies = [reverse_dependencies] if reverse_dependencies else [],
             codesearch_index = [codesearch_index],
             docs = depset([r.output for r in doc_results]),
             documentation_registry_pb = depset([documentation_registry_pb]),
@@ -416,6 +469,8 @@ def _module_registry_impl(ctx):
             deps = depset(deps),
             cycles = depset(cycles),
             proto = registry_pb,
+            dependency_trees = dependency_trees,
+            reverse_dependencies = reverse_dependencies,
             repository_url = ctx.attr.repository_url,
             registry_url = ctx.attr.registry_url,
             branch = ctx.attr.branch,
@@ -438,6 +493,13 @@ module_registry = rule(
         "branch": attr.string(doc = "Branch name of the repository data (e.g. 'main')"),
         "commit": attr.string(doc = "Commit sha1 of the repository data"),
         "commit_date": attr.string(doc = "Timestamp of the commit date (same format as: git log --format='%ci')"),
+        "registries": attr.string_list(
+            doc = "Names of the combined registries in order of precedence (first registry wins, like bazel's --registry)",
+        ),
+        "reverse_dependencies": attr.label(
+            doc = "ReverseDependencyIndex protobuf file (written by gazelle)",
+            allow_single_file = [".pb"],
+        ),
         "_colors_json": attr.label(
             default = "@com_github_ozh_github_colors//:colors_json",
             allow_single_file = True,
@@ -452,6 +514,11 @@ module_registry = rule(
             executable = True,
             cfg = "exec",
         ),
+        "_dependencytreecompiler": attr.label(
+            default = "//cmd/dependencytreecompiler",
+            executable = True,
+            cfg = "exec",
+        ),
         "_sitemapcompiler": attr.label(
             default = "//cmd/sitemapcompiler",
             executable = True,
@@ -17,6 +17,11 @@ def _module_source_impl(ctx):
             docs_url_status_code = ctx.attr.docs_url_status_code,
             docs_url_status_message = ctx.attr.docs_url_status_message,
             commit_sha = ctx.attr.commit_sha,
+            patch_integrity = ctx.attr.patch_integrity,
+            overlay_integrity = ctx.attr.overlay_integrity,
+            archive_verification_status = ctx.attr.archive_verification_status,
+            archive_actual_integrity = ctx.attr.archive_actual_integrity,
+            archive_verification_message = ctx.attr.archive_verification_message,
         ),
     ]
 
@@ -58,6 +63,21 @@ module_source = rule(
         "commit_sha": attr.string(
             doc = "str: Git commit SHA for the source URL (resolved from tags/releases)",
         ),
+        "patch_integrity": attr.string_dict(
+            doc = "dict[str, str]: Mapping of patch filename to its verification result ('STATUS' or 'STATUS ACTUAL_INTEGRITY')",
+        ),
+        "overlay_integrity": attr.string_dict(
+            doc = "dict[str, str]: Mapping of overlay filename to its verification result ('STATUS' or 'STATUS ACTUAL_INTEGRITY')",
+        ),
+        "archive_verification_status": attr.string(
+            doc = "str: Verification status of the source archive (empty if not verified)",
+        ),
+        "archive_actual_integrity": attr.string(
+            doc = "str: Integrity computed from the source archive (set on mismatch)",
+        ),
+        "archive_verification_message": attr.string(
+            doc = "str: Details of the source archive verification",
+        ),
         "source_json": attr.label(
             doc = "File: The source.json file (required)",
             allow_single_file = [".json"],
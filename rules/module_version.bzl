@@ -33,6 +33,29 @@ def _compile_action(ctx, source, deps, attestations, presubmit, commit):
         args.add("--unresolved_deps")
         args.add(",".join(unresolved_deps))
 
+    # Compatibility level conflicts are discovered during MVS in gazelle.
+    for conflict in ctx.attr.resolution_conflicts:
+        args.add("--resolution_conflict")
+        args.add(conflict)
+
+    # Dev-only upgrades are discovered during MVS in gazelle.
+    for upgrade in ctx.attr.mvs_dev_upgrades:
+        args.add("--mvs_dev_upgrade")
+        args.add(upgrade)
+
+    # Bazel compatibility across the MVS closure is also computed in gazelle.
+    if ctx.attr.compatible_bazel_version_count >= 0:
+        args.add("--compatible_bazel_version_count=" + str(ctx.attr.compatible_bazel_version_count))
+        if ctx.attr.compatible_bazel_min_version:
+            args.add("--compatible_bazel_min_version")
+            args.add(ctx.attr.compatible_bazel_min_version)
+        if ctx.attr.compatible_bazel_max_version:
+            args.add("--compatible_bazel_max_version")
+            args.add(ctx.attr.compatible_bazel_max_version)
+        for narrowing in ctx.attr.bazel_compatibility_narrowed_by:
+            args.add("--bazel_compatibility_narrowed_by")
+            args.add(narrowing)
+
     # Collect all input files
     inputs = [ctx.file.module_bazel]
 
@@ -51,6 +74,16 @@ def _compile_action(ctx, source, deps, attestations, presubmit, commit):
             args.add("--source_commit_sha")
             args.add(source.commit_sha)
 
+        for filename, result in source.patch_integrity.items():
+            args.add("--patch_integrity=%s=%s" % (filename, result))
+        for filename, result in source.overlay_integrity.items():
+            args.add("--overlay_integrity=%s=%s" % (filename, result))
+
+        if source.archive_verification_status:
+            args.add("--archive_verification_status=" + source.archive_verification_status)
+            args.add("--archive_actual_integrity=" + source.archive_actual_integrity)
+            args.add("--archive_verification_message=" + source.archive_verification_message)
+
     # Add optional presubmit.yml file
     if presubmit and presubmit.presubmit_yml:
         args.add("--presubmit_yml_file")
@@ -63,6 +96,22 @@ def _compile_action(ctx, source, deps, attestations, presubmit, commit):
         args.add(attestations.attestations_json)
         inputs.append(attestations.attestations_json)
 
+        for filename, status in attestations.verification_status.items():
+            args.add("--attestation_verification=%s=%s" % (filename, status))
+        for filename, message in attestations.verification_message.items():
+            args.add("--attestation_verification_message=%s=%s" % (filename, message))
+
+        if attestations.provenance_predicate_type:
+            args.add("--provenance_predicate_type=" + attestations.provenance_predicate_type)
+            args.add("--provenance_builder_id=" + attestations.provenance_builder_id)
+            args.add("--provenance_source_repository=" + attestations.provenance_source_repository)
+            args.add("--provenance_source_ref=" + attestations.provenance_source_ref)
+            args.add("--provenance_source_commit=" + attestations.provenance_source_commit)
+            args.add("--provenance_workflow_path=" + attestations.provenance_workflow_path)
+            args.add("--provenance_build_invocation_id=" + attestations.provenance_build_invocation_id)
+            for filename in attestations.provenance_attested_files:
+                args.add("--provenance_attested_file=" + filename)
+
     # Add optional commit metadata
     if commit:
         args.add("--commit_sha1")
@@ -115,6 +164,10 @@ def _module_version_impl(ctx):
             deps = deps,
             is_latest_version = ctx.attr.is_latest_version,
             module_bazel = ctx.file.module_bazel if ctx.file.module_bazel else None,
+            mvs = ctx.attr.mvs,
+            mvs_dev = ctx.attr.mvs_dev,
+            mvs_merged = ctx.attr.mvs_merged,
+            mvs_overrides = ctx.attr.mvs_overrides,
             presubmit = presubmit,
             proto = compilation.module,
             published_docs = ctx.files.published_docs if ctx.attr.published_docs else [],
@@ -153,11 +206,36 @@ module_version = rule(
             providers = [ModuleDependencyInfo],
         ),
         "mvs": attr.string_dict(
-            doc = "dict[str, str]: MVS result for non-dev dependencies (module name -> version)",
+            doc = "dict[str, str]: MVS result for non-dev dependencies (module name -> version, empty if replaced by a non-registry override)",
         ),
         "mvs_dev": attr.string_dict(
             doc = "dict[str, str]: MVS result for dev dependencies (module name -> version)",
         ),
+        "mvs_merged": attr.string_dict(
+            doc = "dict[str, str]: MVS result for non-dev and this module's own dev dependencies (module name -> version)",
+        ),
+        "mvs_dev_upgrades": attr.string_list(
+            doc = "list[str]: Modules selected at a higher version only because of dev dependencies (e.g. 'rules_cc@0.1.0 -> rules_cc@0.2.0')",
+        ),
+        "mvs_overrides": attr.string_dict(
+            doc = "dict[str, str]: Override type applied by this module's own overrides during MVS (module name -> 'single_version', 'git', 'archive' or 'local_path')",
+        ),
+        "resolution_conflicts": attr.string_list(
+            doc = "list[str]: Dependency paths that require conflicting compatibility levels of the same module (e.g. 'a@1.0 -> b@2.0 (compatibility_level=2)')",
+        ),
+        "compatible_bazel_min_version": attr.string(
+            doc = "str: Lowest known Bazel release compatible with this module version and its MVS closure",
+        ),
+        "compatible_bazel_max_version": attr.string(
+            doc = "str: Highest known Bazel release compatible with this module version and its MVS closure",
+        ),
+        "compatible_bazel_version_count": attr.int(
+            doc = "int: Number of known Bazel releases compatible with this module version and its MVS closure (-1 if not evaluated)",
+            default = -1,
+        ),
+        "bazel_compatibility_narrowed_by": attr.string_list(
+            doc = "list[str]: Dependencies whose bazel_compatibility excludes releases this module version allows (e.g. 'rules_cc@0.1.0 >=7.0.0 -7.1.0')",
+        ),
         "bzl_src": attr.label(
             doc = "Target]: Starlark repository labels providing StarlarkModuleLibraryInfo for the bzl files for this moduleversion",
             providers = [StarlarkModuleLibraryInfo],
@@ -22,10 +22,12 @@ ModuleMetadataInfo = provider(
         "versions": "list[str]: Module version strings",
         "yanked_versions": "dict[str, str]: Mapping of yanked version to reason",
         "deprecated": "str: Deprecation message (empty string if not deprecated)",
+        "reverse_dependency_counts": "dict[str, str]: ReverseDependencyCounts field name -> count",
         "deps": "List[ModuleVersionInfo]: Module version providers",
         "metadata_json": "File: The metadata.json file",
         "proto": "File: The compiled Module protobuf file",
         "build_bazel": "File | None: The BUILD.bazel file",
+        "registry": "str: Name of the registry providing the module",
     },
 )
 
@@ -59,6 +61,11 @@ ModuleSourceInfo = provider(
         "docs_url_status_code": "int: HTTP status code of the docs URL",
         "docs_url_status_message": "str: HTTP status message of the docs URL",
         "commit_sha": "str: Git commit SHA for the source URL (resolved from tags/releases)",
+        "patch_integrity": "dict[str, str]: Mapping of patch filename to its verification result ('STATUS' or 'STATUS ACTUAL_INTEGRITY')",
+        "overlay_integrity": "dict[str, str]: Mapping of overlay filename to its verification result ('STATUS' or 'STATUS ACTUAL_INTEGRITY')",
+        "archive_verification_status": "str: Verification status of the source archive (empty if not verified)",
+        "archive_actual_integrity": "str: Integrity computed from the source archive (set on mismatch)",
+        "archive_verification_message": "str: Details of the source archive verification",
     },
 )
 
@@ -69,6 +76,16 @@ ModuleAttestationsInfo = provider(
         "urls": "dict[str, str]: Mapping of filename to attestation URL",
         "integrities": "dict[str, str]: Mapping of filename to attestation integrity hash",
         "attestations_json": "File: The attestations.json file",
+        "verification_status": "dict[str, str]: Mapping of filename to the verification status of its sigstore bundle (empty if not verified)",
+        "verification_message": "dict[str, str]: Mapping of filename to the details of its verification",
+        "provenance_predicate_type": "str: SLSA provenance predicate type of the verified statements (empty if none)",
+        "provenance_builder_id": "str: Build platform that built the release (e.g., https://github.com/actions/runner/github-hosted)",
+        "provenance_source_repository": "str: Repository the release was built from",
+        "provenance_source_ref": "str: Git ref the release was built from",
+        "provenance_source_commit": "str: Git commit the release was built from",
+        "provenance_workflow_path": "str: Path of the workflow in the source repository",
+        "provenance_build_invocation_id": "str: Identifier of the build run",
+        "provenance_attested_files": "list[str]: Files whose verified statements carry the provenance",
     },
 )
 
@@ -83,6 +100,10 @@ ModuleVersionInfo = provider(
         "bazel_compatibility": "list[str]: Compatible Bazel version ranges",
         "repo_name": "str: Repository name",
         "deps": "list[ModuleDependencyInfo]: Direct dependency providers",
+        "mvs": "dict[str, str]: MVS result for non-dev dependencies (module name -> version)",
+        "mvs_dev": "dict[str, str]: MVS result for dev dependencies (module name -> version)",
+        "mvs_merged": "dict[str, str]: MVS result for non-dev and own dev dependencies (module name -> version)",
+        "mvs_overrides": "dict[str, str]: Override types applied during MVS (module name -> override type)",
         "source": "ModuleSourceInfo: Source provider",
         "attestations": "ModuleAttestationsInfo | None: Attestations provider",
         "presubmit": "ModulePresubmitInfo | None: Presubmit provider",
@@ -117,6 +138,8 @@ ModuleRegistryInfo = provider(
         "deps": "depset[ModuleMetadataInfo]: Module metadata providers",
         "cycles": "depset[ModuleDependencyCycleInfo]: Dependency cycle providers",
         "proto": "File: The compiled Registry protobuf file",
+        "dependency_trees": "File: Directory of NAME/VERSION/dependencytree.pb files (MVS-resolved DependencyTree per module version)",
+        "reverse_dependencies": "File | None: The ReverseDependencyIndex protobuf file",
         "repository_url": "str: Git repository URL (e.g., 'https://github.com/bazelbuild/bazel-central-registry')",
         "registry_url": "str: Registry UI URL (e.g., 'https://registry.bazel.build')",
         "branch": "str: Git branch name (e.g., 'main')",
@@ -177,7 +200,8 @@ LocalPathOverrideInfo = provider(
 RepositoryMetadataInfo = provider(
     doc = "Metadata about a source code repository (e.g., GitHub, GitLab).",
     fields = {
-        "type": "str: Repository type (e.g., 'github', 'gitlab')",
+        "type": "str: Repository type (e.g., 'github', 'gitlab', 'gitea', 'bitbucket', 'sourcehut')",
+        "host": "str: Host of a self-hosted instance (empty for github.com, gitlab.com, bitbucket.org and git.sr.ht)",
         "canonical_name": "str: Canonical repository name (e.g., 'github:org/repo')",
         "json_file": "File: The emitted JSON metadata file",
         "organization": "str: Organization or owner name",
@@ -186,6 +210,14 @@ RepositoryMetadataInfo = provider(
         "stargazers": "int: Number of stars/stargazers",
         "languages": "dict[str, str]: Mapping of programming language to line count (as string)",
         "primary_language": "str: Primary language based on line counts",
+        "license": "str: SPDX identifier of the license",
+        "topics": "list[str]: Repository topics",
+        "archived": "bool: Whether the repository is archived",
+        "disabled": "bool: Whether the repository is disabled",
+        "default_branch": "str: Name of the default branch",
+        "pushed_at": "str: Time of the last push (RFC 3339)",
+        "open_issues": "int: Number of open issues",
+        "latest_release": "str: Tag name of the latest release",
     },
 )
 
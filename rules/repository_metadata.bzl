@@ -7,6 +7,7 @@ def _repository_metadata_write_action(ctx):
 
     data = struct(
         type = ctx.attr.type,
+        host = ctx.attr.host,
         organization = ctx.attr.organization,
         name = ctx.attr.repo_name,
         description = ctx.attr.description,
@@ -14,6 +15,14 @@ def _repository_metadata_write_action(ctx):
         languages = ctx.attr.languages,
         canonical_name = ctx.attr.canonical_name,
         primary_language = ctx.attr.primary_language,
+        license = ctx.attr.license,
+        topics = ctx.attr.topics,
+        archived = ctx.attr.archived,
+        disabled = ctx.attr.disabled,
+        default_branch = ctx.attr.default_branch,
+        pushed_at = ctx.attr.pushed_at,
+        open_issues = ctx.attr.open_issues,
+        latest_release = ctx.attr.latest_release,
     )
 
     ctx.actions.write(output, json.encode(data))
@@ -27,6 +36,7 @@ def _repository_metadata_impl(ctx):
         DefaultInfo(files = depset([json_file])),
         RepositoryMetadataInfo(
             type = ctx.attr.type,
+            host = ctx.attr.host,
             json_file = json_file,
             organization = ctx.attr.organization,
             canonical_name = ctx.attr.canonical_name,
@@ -35,6 +45,14 @@ def _repository_metadata_impl(ctx):
             stargazers = ctx.attr.stargazers,
             languages = ctx.attr.languages,
             primary_language = ctx.attr.primary_language,
+            license = ctx.attr.license,
+            topics = ctx.attr.topics,
+            archived = ctx.attr.archived,
+            disabled = ctx.attr.disabled,
+            default_branch = ctx.attr.default_branch,
+            pushed_at = ctx.attr.pushed_at,
+            open_issues = ctx.attr.open_issues,
+            latest_release = ctx.attr.latest_release,
         ),
     ]
 
@@ -44,6 +62,9 @@ repository_metadata = rule(
         "type": attr.string(
             doc = "Repository type (e.g., 'GITHUB', 'REPOSITORY_TYPE_UNKNOWN')",
         ),
+        "host": attr.string(
+            doc = "Host of a self-hosted instance (e.g., 'gitlab.arm.com' or 'codeberg.org'), empty for github.com, gitlab.com, bitbucket.org and git.sr.ht",
+        ),
         "organization": attr.string(
             doc = "Organization or owner name",
         ),
@@ -65,6 +86,30 @@ repository_metadata = rule(
         "primary_language": attr.string(
             doc = "Name of the language having the most line counts",
         ),
+        "license": attr.string(
+            doc = "SPDX identifier of the license (e.g., 'Apache-2.0')",
+        ),
+        "topics": attr.string_list(
+            doc = "Repository topics",
+        ),
+        "archived": attr.bool(
+            doc = "Whether the repository is archived",
+        ),
+        "disabled": attr.bool(
+            doc = "Whether the repository is disabled",
+        ),
+        "default_branch": attr.string(
+            doc = "Name of the default branch",
+        ),
+        "pushed_at": attr.string(
+            doc = "Time of the last push (RFC 3339)",
+        ),
+        "open_issues": attr.int(
+            doc = "Number of open issues",
+        ),
+        "latest_release": attr.string(
+            doc = "Tag name of the latest release",
+        ),
     },
     provides = [RepositoryMetadataInfo],
 )